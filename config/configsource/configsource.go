@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configsource allows values and whole sections of the configuration to be
+// retrieved from places other than the main config file.
+package configsource
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const defaultHTTPTimeout = 10 * time.Second
+
+// Source retrieves configuration data from a location. Sources are referenced from the
+// configuration as ${scheme:location}, the scheme identifies the source.
+type Source interface {
+	// Retrieve returns the content at the given location.
+	Retrieve(ctx context.Context, location string) ([]byte, error)
+}
+
+// DefaultSources returns the sources available by default, keyed by their scheme:
+//   - file: reads a local file, e.g. ${file:/etc/otel/exporters.yaml}.
+//   - http and https: gets the content from an HTTP(S) endpoint, e.g. ${https://config.local/otel.yaml}.
+func DefaultSources() map[string]Source {
+	client := &http.Client{Timeout: defaultHTTPTimeout}
+	return map[string]Source{
+		"file":  &fileSource{},
+		"http":  &httpSource{scheme: "http", client: client},
+		"https": &httpSource{scheme: "https", client: client},
+	}
+}
+
+// fileSource reads the content of local files.
+type fileSource struct{}
+
+func (fs *fileSource) Retrieve(_ context.Context, location string) ([]byte, error) {
+	return ioutil.ReadFile(location)
+}
+
+// httpSource gets the content with an HTTP GET request. The location is the URL without
+// the scheme, e.g. //config.local/otel.yaml.
+type httpSource struct {
+	scheme string
+	client *http.Client
+}
+
+func (hs *httpSource) Retrieve(ctx context.Context, location string) ([]byte, error) {
+	url := hs.scheme + ":" + location
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hs.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %q getting %q", resp.Status, url)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,363 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configsource
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+// maxReferenceDepth is the maximum number of nested references to config sources, a deeper
+// nesting is most likely a cycle.
+const maxReferenceDepth = 10
+
+var (
+	schemeRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*$`)
+
+	errReferenceDepth = errors.New("too many nested config source references, there may be a cycle")
+)
+
+// Manager reads the configuration and resolves the references to config sources in its values.
+// It remembers the locations retrieved by the last call to ReadConfig, so that they can be
+// watched for changes.
+type Manager struct {
+	sources map[string]Source
+
+	mu      sync.Mutex
+	tracked map[string]*trackedLocation
+}
+
+// trackedLocation is a location retrieved while reading the configuration.
+type trackedLocation struct {
+	retrieve func(ctx context.Context) ([]byte, error)
+	digest   [sha256.Size]byte
+}
+
+// NewManager creates a Manager that resolves references to the given sources, keyed by scheme.
+func NewManager(sources map[string]Source) *Manager {
+	return &Manager{
+		sources: sources,
+		tracked: make(map[string]*trackedLocation),
+	}
+}
+
+// ReadConfig reads the YAML configuration at location into v, replacing its content. The location
+// can be a local file, a directory whose *.yaml and *.yml files are merged in lexical order, or a
+// reference to a source such as an http(s) URL.
+//
+// Values of the form ${scheme:location} are replaced by the content of the location retrieved
+// from the source registered for the scheme. If the reference is the whole value and the content
+// is a YAML map or list, the value is replaced by it, allowing whole sections to be retrieved from
+// a source, otherwise the content is used as a string, without its trailing newlines. References
+// can be escaped as $${scheme:location}.
+func (m *Manager) ReadConfig(ctx context.Context, location string, v *viper.Viper) error {
+	tracked := make(map[string]*trackedLocation)
+	settings, err := m.readRoot(ctx, location, tracked)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := m.resolveValue(ctx, settings, tracked, 0)
+	if err != nil {
+		return err
+	}
+
+	content, err := yaml.Marshal(resolved)
+	if err != nil {
+		return err
+	}
+	v.SetConfigType("yaml")
+	if err = v.ReadConfig(bytes.NewReader(content)); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.tracked = tracked
+	m.mu.Unlock()
+	return nil
+}
+
+// WatchForUpdates checks every interval if the content of the locations retrieved by the last call
+// to ReadConfig changed, until ctx is done. onChange is called with the location every time its
+// content changes, or with the error if it can no longer be retrieved.
+func (m *Manager) WatchForUpdates(ctx context.Context, interval time.Duration, onChange func(location string, err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkForUpdates(ctx, onChange)
+		}
+	}
+}
+
+func (m *Manager) checkForUpdates(ctx context.Context, onChange func(location string, err error)) {
+	m.mu.Lock()
+	tracked := m.tracked
+	m.mu.Unlock()
+
+	locations := make([]string, 0, len(tracked))
+	for location := range tracked {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	for _, location := range locations {
+		tl := tracked[location]
+		content, err := tl.retrieve(ctx)
+		if err != nil {
+			// Errors are tracked as content to only report them once.
+			content = []byte("error: " + err.Error())
+		}
+		digest := sha256.Sum256(content)
+
+		m.mu.Lock()
+		changed := digest != tl.digest
+		tl.digest = digest
+		m.mu.Unlock()
+
+		if changed {
+			onChange(location, err)
+		}
+	}
+}
+
+// readRoot reads the configuration at location and returns its settings.
+func (m *Manager) readRoot(ctx context.Context, location string, tracked map[string]*trackedLocation) (map[interface{}]interface{}, error) {
+	if scheme, sourceLocation, ok := splitReference(location); ok {
+		if source, exists := m.sources[scheme]; exists {
+			content, err := track(ctx, tracked, location, func(ctx context.Context) ([]byte, error) {
+				return source.Retrieve(ctx, sourceLocation)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return parseSettings(content, location)
+		}
+	}
+
+	info, err := os.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		content, err := track(ctx, tracked, "file:"+location, readFile(location))
+		if err != nil {
+			return nil, err
+		}
+		return parseSettings(content, location)
+	}
+
+	listing, err := track(ctx, tracked, "dir:"+location, listFragments(location))
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[interface{}]interface{})
+	for _, name := range strings.Fields(string(listing)) {
+		file := filepath.Join(location, name)
+		content, err := track(ctx, tracked, "file:"+file, readFile(file))
+		if err != nil {
+			return nil, err
+		}
+		fragment, err := parseSettings(content, file)
+		if err != nil {
+			return nil, err
+		}
+		mergeSettings(settings, fragment)
+	}
+	return settings, nil
+}
+
+func (m *Manager) resolveValue(ctx context.Context, value interface{}, tracked map[string]*trackedLocation, depth int) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return m.resolveString(ctx, v, tracked, depth)
+	case []interface{}:
+		resolved := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			r, err := m.resolveValue(ctx, item, tracked, depth)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, r)
+		}
+		return resolved, nil
+	case map[interface{}]interface{}:
+		resolved := make(map[interface{}]interface{}, len(v))
+		for key, item := range v {
+			r, err := m.resolveValue(ctx, item, tracked, depth)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = r
+		}
+		return resolved, nil
+	default:
+		return v, nil
+	}
+}
+
+func (m *Manager) resolveString(ctx context.Context, s string, tracked map[string]*trackedLocation, depth int) (interface{}, error) {
+	if scheme, location, end, ok := parseReference(s, 0); ok && end == len(s) {
+		content, err := m.retrieve(ctx, scheme, location, tracked, depth)
+		if err != nil {
+			return nil, err
+		}
+		var section interface{}
+		if yaml.Unmarshal(content, &section) == nil {
+			switch section.(type) {
+			case map[interface{}]interface{}, []interface{}:
+				return m.resolveValue(ctx, section, tracked, depth+1)
+			}
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$$") {
+			// Escaped references are left for the expansion of environment variables.
+			buf.WriteString("$$")
+			i += 2
+			continue
+		}
+		scheme, location, end, ok := parseReference(s, i)
+		if !ok {
+			buf.WriteByte(s[i])
+			i++
+			continue
+		}
+		content, err := m.retrieve(ctx, scheme, location, tracked, depth)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(strings.TrimRight(string(content), "\r\n"))
+		i = end
+	}
+	return buf.String(), nil
+}
+
+func (m *Manager) retrieve(ctx context.Context, scheme, location string, tracked map[string]*trackedLocation, depth int) ([]byte, error) {
+	if depth >= maxReferenceDepth {
+		return nil, errReferenceDepth
+	}
+	source, ok := m.sources[scheme]
+	if !ok {
+		return nil, fmt.Errorf("config source %q not found", scheme)
+	}
+	content, err := track(ctx, tracked, scheme+":"+location, func(ctx context.Context) ([]byte, error) {
+		return source.Retrieve(ctx, location)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve %s:%s: %w", scheme, location, err)
+	}
+	return content, nil
+}
+
+// track retrieves the content of a location and remembers it to watch it for changes.
+func track(ctx context.Context, tracked map[string]*trackedLocation, location string, retrieve func(context.Context) ([]byte, error)) ([]byte, error) {
+	content, err := retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tracked[location] = &trackedLocation{retrieve: retrieve, digest: sha256.Sum256(content)}
+	return content, nil
+}
+
+func readFile(file string) func(context.Context) ([]byte, error) {
+	return func(context.Context) ([]byte, error) {
+		return ioutil.ReadFile(file)
+	}
+}
+
+// listFragments returns a function that lists the names of the YAML files in a directory, in
+// lexical order and separated by newlines.
+func listFragments(dir string) func(context.Context) ([]byte, error) {
+	return func(context.Context) ([]byte, error) {
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, info := range infos {
+			ext := filepath.Ext(info.Name())
+			if !info.IsDir() && (ext == ".yaml" || ext == ".yml") {
+				names = append(names, info.Name())
+			}
+		}
+		return []byte(strings.Join(names, "\n")), nil
+	}
+}
+
+func parseSettings(content []byte, location string) (map[interface{}]interface{}, error) {
+	settings := make(map[interface{}]interface{})
+	if err := yaml.Unmarshal(content, &settings); err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %w", location, err)
+	}
+	return settings, nil
+}
+
+// mergeSettings merges src into dst. Maps are merged recursively, any other value in src
+// replaces the one in dst.
+func mergeSettings(dst, src map[interface{}]interface{}) {
+	for key, srcValue := range src {
+		srcMap, srcIsMap := srcValue.(map[interface{}]interface{})
+		dstMap, dstIsMap := dst[key].(map[interface{}]interface{})
+		if srcIsMap && dstIsMap {
+			mergeSettings(dstMap, srcMap)
+			continue
+		}
+		dst[key] = srcValue
+	}
+}
+
+// splitReference splits a scheme:location reference.
+func splitReference(s string) (scheme, location string, ok bool) {
+	i := strings.IndexByte(s, ':')
+	if i <= 0 || !schemeRegexp.MatchString(s[:i]) {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
+}
+
+// parseReference parses a ${scheme:location} reference starting at s[start]. It returns the
+// index after the end of the reference.
+func parseReference(s string, start int) (scheme, location string, end int, ok bool) {
+	if !strings.HasPrefix(s[start:], "${") {
+		return "", "", 0, false
+	}
+	closing := strings.IndexByte(s[start:], '}')
+	if closing < 0 {
+		return "", "", 0, false
+	}
+	scheme, location, ok = splitReference(s[start+2 : start+closing])
+	return scheme, location, start + closing + 1, ok
+}
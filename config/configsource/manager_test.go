@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configsource
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "configsource")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func writeFile(t *testing.T, file, content string) string {
+	require.NoError(t, ioutil.WriteFile(file, []byte(content), 0600))
+	return file
+}
+
+func newTestViper() *viper.Viper {
+	return viper.NewWithOptions(viper.KeyDelimiter("::"))
+}
+
+func TestReadConfig_File(t *testing.T) {
+	dir := newTestDir(t)
+	exporters := writeFile(t, filepath.Join(dir, "exporters.yaml"), "logging:\n  loglevel: debug\n")
+	token := writeFile(t, filepath.Join(dir, "token"), "secret\n")
+	config := writeFile(t, filepath.Join(dir, "config.yaml"), fmt.Sprintf(`
+receivers:
+  otlp:
+    auth: ${file:%s}
+    endpoint: "localhost:${file:%s}"
+    escaped: $${file:%s}
+    env: ${HOME}
+exporters: ${file:%s}
+`, token, token, token, exporters))
+
+	v := newTestViper()
+	require.NoError(t, NewManager(DefaultSources()).ReadConfig(context.Background(), config, v))
+	assert.Equal(t, "secret", v.Get("receivers::otlp::auth"))
+	assert.Equal(t, "localhost:secret", v.Get("receivers::otlp::endpoint"))
+	assert.Equal(t, "$${file:"+token+"}", v.Get("receivers::otlp::escaped"))
+	assert.Equal(t, "${HOME}", v.Get("receivers::otlp::env"))
+	assert.Equal(t, "debug", v.Get("exporters::logging::loglevel"))
+}
+
+func TestReadConfig_Directory(t *testing.T) {
+	dir := newTestDir(t)
+	writeFile(t, filepath.Join(dir, "01-receivers.yaml"), "receivers:\n  otlp:\n    endpoint: first\n  jaeger:\n")
+	writeFile(t, filepath.Join(dir, "02-override.yml"), "receivers:\n  otlp:\n    endpoint: second\nexporters:\n  logging:\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "not: yaml: at all")
+
+	v := newTestViper()
+	require.NoError(t, NewManager(DefaultSources()).ReadConfig(context.Background(), dir, v))
+	assert.Equal(t, "second", v.Get("receivers::otlp::endpoint"))
+	assert.Contains(t, v.GetStringMap("receivers"), "jaeger")
+	assert.Contains(t, v.GetStringMap("exporters"), "logging")
+}
+
+func TestReadConfig_HTTP(t *testing.T) {
+	var mu sync.Mutex
+	processors := "batch:\n  timeout: 5s\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.yaml":
+			fmt.Fprintf(w, "processors: ${http://%s/processors.yaml}\n", r.Host)
+		case "/processors.yaml":
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprint(w, processors)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	manager := NewManager(DefaultSources())
+	v := newTestViper()
+	require.NoError(t, manager.ReadConfig(context.Background(), server.URL+"/config.yaml", v))
+	assert.Equal(t, "5s", v.Get("processors::batch::timeout"))
+
+	err := manager.ReadConfig(context.Background(), server.URL+"/missing.yaml", newTestViper())
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "404"))
+
+	// Changes are reported once.
+	var changed []string
+	onChange := func(location string, err error) {
+		assert.NoError(t, err)
+		changed = append(changed, location)
+	}
+	manager.checkForUpdates(context.Background(), onChange)
+	assert.Empty(t, changed)
+
+	mu.Lock()
+	processors = "batch:\n  timeout: 10s\n"
+	mu.Unlock()
+	manager.checkForUpdates(context.Background(), onChange)
+	manager.checkForUpdates(context.Background(), onChange)
+	assert.Equal(t, []string{server.URL + "/processors.yaml"}, changed)
+}
+
+func TestReadConfig_Errors(t *testing.T) {
+	dir := newTestDir(t)
+	cycle := filepath.Join(dir, "cycle.yaml")
+	writeFile(t, cycle, fmt.Sprintf("receivers: ${file:%s}\n", cycle))
+	unknown := writeFile(t, filepath.Join(dir, "unknown.yaml"), "receivers: ${vault:secret/otel}\n")
+	missing := writeFile(t, filepath.Join(dir, "missing.yaml"), fmt.Sprintf("receivers: ${file:%s}\n", filepath.Join(dir, "none.yaml")))
+	invalid := writeFile(t, filepath.Join(dir, "invalid.yaml"), "receivers: [")
+
+	tests := []struct {
+		location string
+		err      string
+	}{
+		{location: cycle, err: errReferenceDepth.Error()},
+		{location: unknown, err: `config source "vault" not found`},
+		{location: missing, err: "cannot retrieve file:"},
+		{location: invalid, err: "cannot parse"},
+		{location: filepath.Join(dir, "none.yaml"), err: "no such file"},
+	}
+	for _, test := range tests {
+		t.Run(filepath.Base(test.location), func(t *testing.T) {
+			err := NewManager(DefaultSources()).ReadConfig(context.Background(), test.location, newTestViper())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestWatchForUpdates(t *testing.T) {
+	dir := newTestDir(t)
+	token := writeFile(t, filepath.Join(dir, "token"), "first")
+	writeFile(t, filepath.Join(dir, "config.yaml"), fmt.Sprintf("receivers:\n  otlp:\n    auth: ${file:%s}\n", token))
+
+	manager := NewManager(DefaultSources())
+	require.NoError(t, manager.ReadConfig(context.Background(), dir, newTestViper()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan string, 10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		manager.WatchForUpdates(ctx, 10*time.Millisecond, func(location string, err error) {
+			if err != nil {
+				changes <- "error " + location
+				return
+			}
+			changes <- location
+		})
+	}()
+
+	writeFile(t, token, "second")
+	assert.Equal(t, "file:"+token, <-changes)
+
+	// New fragments in the directory are detected.
+	writeFile(t, filepath.Join(dir, "exporters.yaml"), "exporters:\n")
+	assert.Equal(t, "dir:"+dir, <-changes)
+
+	require.NoError(t, os.Remove(token))
+	assert.Equal(t, "error file:"+token, <-changes)
+
+	cancel()
+	<-done
+}
@@ -56,6 +56,8 @@ type request interface {
 	onPartialError(consumererror.PartialError) request
 	// Returns the count of spans/metric points or log records.
 	count() int
+	// marshal serializes the data of the request, used to persist the request in the sending_queue storage.
+	marshal() ([]byte, error)
 }
 
 // requestSender is an abstraction of a sender for a request independent of the type of the data (traces, metrics, logs).
@@ -185,7 +187,7 @@ func newBaseExporter(cfg configmodels.Exporter, logger *zap.Logger, options ...E
 		convertResourceToTelemetry: opts.ResourceToTelemetrySettings.Enabled,
 	}
 
-	be.qrSender = newQueuedRetrySender(opts.QueueSettings, opts.RetrySettings, &timeoutSender{cfg: opts.TimeoutSettings}, logger)
+	be.qrSender = newQueuedRetrySender(cfg.Name(), opts.QueueSettings, opts.RetrySettings, &timeoutSender{cfg: opts.TimeoutSettings}, logger)
 	be.sender = be.qrSender
 
 	return be
@@ -197,6 +199,13 @@ func (be *baseExporter) wrapConsumerSender(f func(consumer requestSender) reques
 	be.qrSender.consumerSender = f(be.qrSender.consumerSender)
 }
 
+// setRequestUnmarshaler sets the function used to recreate the requests of the data type replayed from
+// the sending_queue storage.
+func (be *baseExporter) setRequestUnmarshaler(dataType configmodels.DataType, unmarshaler requestUnmarshaler) {
+	be.qrSender.dataType = dataType
+	be.qrSender.unmarshaler = unmarshaler
+}
+
 // Start all senders and exporter and is invoked during service start.
 func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 	err := componenterror.ErrAlreadyStarted
@@ -209,7 +218,7 @@ func (be *baseExporter) Start(ctx context.Context, host component.Host) error {
 		}
 
 		// If no error then start the queuedRetrySender.
-		be.qrSender.start()
+		err = be.qrSender.start()
 	})
 	return err
 }
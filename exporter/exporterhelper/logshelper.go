@@ -56,6 +56,10 @@ func (req *logsRequest) count() int {
 	return req.ld.LogRecordCount()
 }
 
+func (req *logsRequest) marshal() ([]byte, error) {
+	return req.ld.ToOtlpProtoBytes()
+}
+
 type logsExporter struct {
 	*baseExporter
 	pushLogsData PushLogsData
@@ -89,6 +93,13 @@ func NewLogsExporter(
 			nextSender:   nextSender,
 		}
 	})
+	be.setRequestUnmarshaler(configmodels.LogsDataType, func(ctx context.Context, data []byte) (request, error) {
+		ld := pdata.NewLogs()
+		if err := ld.FromOtlpProtoBytes(data); err != nil {
+			return nil, err
+		}
+		return newLogsRequest(ctx, ld, pushLogsData), nil
+	})
 
 	return &logsExporter{
 		baseExporter: be,
@@ -67,6 +67,10 @@ func (req *metricsRequest) count() int {
 	return numPoints
 }
 
+func (req *metricsRequest) marshal() ([]byte, error) {
+	return req.md.ToOtlpProtoBytes()
+}
+
 type metricsExporter struct {
 	*baseExporter
 	pusher PushMetricsData
@@ -104,6 +108,13 @@ func NewMetricsExporter(
 			nextSender:   nextSender,
 		}
 	})
+	be.setRequestUnmarshaler(configmodels.MetricsDataType, func(ctx context.Context, data []byte) (request, error) {
+		md := pdata.NewMetrics()
+		if err := md.FromOtlpProtoBytes(data); err != nil {
+			return nil, err
+		}
+		return newMetricsRequest(ctx, md, pushMetricsData), nil
+	})
 
 	return &metricsExporter{
 		baseExporter: be,
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// requestUnmarshaler recreates a request from the bytes produced by request.marshal.
+type requestUnmarshaler func(ctx context.Context, data []byte) (request, error)
+
+type persistedRequest struct {
+	seq uint64
+	req request
+}
+
+// persistentQueue is a bounded queue of requests backed by a write-ahead log. Every request is written to
+// the log before it is queued and it is only removed from the log once a consumer is done with it. Requests
+// that are still in the log when the queue is started are replayed.
+type persistentQueue struct {
+	logger   *zap.Logger
+	wal      *writeAheadLog
+	capacity int
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	items   []persistedRequest
+	stopped bool
+	stopWG  sync.WaitGroup
+}
+
+// newPersistentQueue opens the write-ahead log in the given directory and queues all the requests that were
+// not acknowledged in a previous run.
+func newPersistentQueue(ctx context.Context, dir string, capacity int, unmarshaler requestUnmarshaler, logger *zap.Logger) (*persistentQueue, error) {
+	wal, records, err := openWriteAheadLog(dir, defaultWALSegmentSize)
+	if err != nil {
+		return nil, err
+	}
+
+	pq := &persistentQueue{
+		logger:   logger,
+		wal:      wal,
+		capacity: capacity,
+	}
+	pq.cond = sync.NewCond(&pq.mu)
+
+	for _, rec := range records {
+		req, err := unmarshaler(ctx, rec.payload)
+		if err != nil {
+			logger.Error("Dropping persisted request that cannot be decoded.", zap.Error(err))
+			if ackErr := wal.ack(rec.seq); ackErr != nil {
+				return nil, ackErr
+			}
+			continue
+		}
+		// Replayed requests are queued even if the capacity is exceeded, they were already accepted.
+		pq.items = append(pq.items, persistedRequest{seq: rec.seq, req: req})
+	}
+	if len(pq.items) > 0 {
+		logger.Info("Replaying requests from sending_queue storage.", zap.Int("requests", len(pq.items)))
+	}
+	return pq, nil
+}
+
+// startConsumers starts the given number of goroutines consuming from the queue. The consume function returns
+// true if the request is done (either sent or dropped) and can be removed from the storage.
+func (pq *persistentQueue) startConsumers(num int, consume func(req request) bool) {
+	for i := 0; i < num; i++ {
+		pq.stopWG.Add(1)
+		go func() {
+			defer pq.stopWG.Done()
+			for {
+				item, ok := pq.next()
+				if !ok {
+					return
+				}
+				if !consume(item.req) {
+					continue
+				}
+				if err := pq.wal.ack(item.seq); err != nil {
+					pq.logger.Error("Failed to acknowledge request in sending_queue storage.", zap.Error(err))
+				}
+			}
+		}()
+	}
+}
+
+// produce persists the request and adds it to the queue. It returns false if the queue is full.
+func (pq *persistentQueue) produce(req request) (bool, error) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+
+	if pq.stopped {
+		return false, errWALClosed
+	}
+	if len(pq.items) >= pq.capacity {
+		return false, nil
+	}
+
+	data, err := req.marshal()
+	if err != nil {
+		return false, err
+	}
+	seq, err := pq.wal.append(data)
+	if err != nil {
+		return false, err
+	}
+	pq.items = append(pq.items, persistedRequest{seq: seq, req: req})
+	pq.cond.Signal()
+	return true, nil
+}
+
+// stop waits for the consumers to finish the requests they are sending and closes the storage. Requests left
+// in the queue stay in the storage and are replayed on the next start.
+func (pq *persistentQueue) stop() {
+	pq.mu.Lock()
+	pq.stopped = true
+	pq.cond.Broadcast()
+	pq.mu.Unlock()
+
+	pq.stopWG.Wait()
+	if err := pq.wal.close(); err != nil {
+		pq.logger.Error("Failed to close sending_queue storage.", zap.Error(err))
+	}
+}
+
+// size returns the number of requests waiting in the queue.
+func (pq *persistentQueue) size() int {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	return len(pq.items)
+}
+
+func (pq *persistentQueue) next() (persistedRequest, bool) {
+	pq.mu.Lock()
+	defer pq.mu.Unlock()
+	for len(pq.items) == 0 && !pq.stopped {
+		pq.cond.Wait()
+	}
+	if pq.stopped {
+		return persistedRequest{}, false
+	}
+	item := pq.items[0]
+	pq.items[0] = persistedRequest{}
+	pq.items = pq.items[1:]
+	return item, true
+}
@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cenkalti/backoff"
@@ -25,7 +27,9 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/obsreport"
 )
 
 // QueueSettings defines configuration for queueing batches before sending to the consumerSender.
@@ -36,6 +40,18 @@ type QueueSettings struct {
 	NumConsumers int `mapstructure:"num_consumers"`
 	// QueueSize is the maximum number of batches allowed in queue at a given time.
 	QueueSize int `mapstructure:"queue_size"`
+	// Storage configures persisting the queued batches to the local disk.
+	Storage StorageSettings `mapstructure:"storage"`
+}
+
+// StorageSettings defines configuration for persisting queued batches in a write-ahead log on the local disk,
+// so they survive a restart or a crash of the collector.
+type StorageSettings struct {
+	// Enabled indicates whether to persist the queued batches.
+	Enabled bool `mapstructure:"enabled"`
+	// Directory is the directory where the write-ahead log is stored. Every exporter uses a subdirectory
+	// named after the exporter.
+	Directory string `mapstructure:"directory"`
 }
 
 // CreateDefaultQueueSettings returns the default settings for QueueSettings.
@@ -77,11 +93,16 @@ func CreateDefaultRetrySettings() RetrySettings {
 }
 
 type queuedRetrySender struct {
-	cfg            QueueSettings
-	consumerSender requestSender
-	queue          *queue.BoundedQueue
-	retryStopCh    chan struct{}
-	logger         *zap.Logger
+	cfg             QueueSettings
+	exporterName    string
+	consumerSender  requestSender
+	queue           *queue.BoundedQueue
+	persistentQueue *persistentQueue
+	// dataType is the data type of the requests replayed by unmarshaler.
+	dataType    configmodels.DataType
+	unmarshaler requestUnmarshaler
+	retryStopCh     chan struct{}
+	logger          *zap.Logger
 }
 
 func createSampledLogger(logger *zap.Logger) *zap.Logger {
@@ -103,11 +124,12 @@ func createSampledLogger(logger *zap.Logger) *zap.Logger {
 	return logger.WithOptions(opts)
 }
 
-func newQueuedRetrySender(qCfg QueueSettings, rCfg RetrySettings, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
+func newQueuedRetrySender(exporterName string, qCfg QueueSettings, rCfg RetrySettings, nextSender requestSender, logger *zap.Logger) *queuedRetrySender {
 	retryStopCh := make(chan struct{})
 	sampledLogger := createSampledLogger(logger)
 	return &queuedRetrySender{
-		cfg: qCfg,
+		cfg:          qCfg,
+		exporterName: exporterName,
 		consumerSender: &retrySender{
 			cfg:        rCfg,
 			nextSender: nextSender,
@@ -121,11 +143,57 @@ func newQueuedRetrySender(qCfg QueueSettings, rCfg RetrySettings, nextSender req
 }
 
 // start is invoked during service startup.
-func (qrs *queuedRetrySender) start() {
+func (qrs *queuedRetrySender) start() error {
+	if qrs.cfg.Enabled && qrs.cfg.Storage.Enabled {
+		return qrs.startPersistentQueue()
+	}
+
 	qrs.queue.StartConsumers(qrs.cfg.NumConsumers, func(item interface{}) {
 		value := item.(request)
 		_, _ = qrs.consumerSender.send(value)
 	})
+	return nil
+}
+
+func (qrs *queuedRetrySender) startPersistentQueue() error {
+	if qrs.cfg.Storage.Directory == "" {
+		return errors.New("sending_queue.storage.directory must be set when storage is enabled")
+	}
+	if qrs.unmarshaler == nil {
+		return errors.New("sending_queue.storage is not supported by this exporter")
+	}
+
+	// An exporter configuration used by pipelines of several data types creates one exporter per data
+	// type, each needs its own log.
+	dir := filepath.Join(qrs.cfg.Storage.Directory, storageDirName(qrs.exporterName+"_"+string(qrs.dataType)))
+	ctx := obsreport.ExporterContext(context.Background(), qrs.exporterName)
+	pq, err := newPersistentQueue(ctx, dir, qrs.cfg.QueueSize, qrs.unmarshaler, qrs.logger)
+	if err != nil {
+		return err
+	}
+	qrs.persistentQueue = pq
+	pq.startConsumers(qrs.cfg.NumConsumers, func(req request) bool {
+		_, err := qrs.consumerSender.send(req)
+		// Requests that failed only because the exporter is shutting down are kept in the
+		// storage, they are sent again after the next start.
+		return err == nil || !qrs.stopping()
+	})
+	return nil
+}
+
+// stopping returns true once the shutdown of the sender started.
+func (qrs *queuedRetrySender) stopping() bool {
+	select {
+	case <-qrs.retryStopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// storageDirName returns the name of a storage directory, exporter names may contain a "/".
+func storageDirName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(name)
 }
 
 // send implements the requestSender interface
@@ -145,6 +213,10 @@ func (qrs *queuedRetrySender) send(req request) (int, error) {
 	// The grpc/http based receivers will cancel the request context after this function returns.
 	req.setContext(noCancellationContext{Context: req.context()})
 
+	if qrs.persistentQueue != nil {
+		return qrs.producePersistent(req)
+	}
+
 	if !qrs.queue.Produce(req) {
 		qrs.logger.Error(
 			"Dropping data because sending_queue is full. Try increasing queue_size.",
@@ -156,11 +228,37 @@ func (qrs *queuedRetrySender) send(req request) (int, error) {
 	return 0, nil
 }
 
+func (qrs *queuedRetrySender) producePersistent(req request) (int, error) {
+	ok, err := qrs.persistentQueue.produce(req)
+	if err != nil {
+		qrs.logger.Error(
+			"Dropping data because it cannot be written to sending_queue storage.",
+			zap.Error(err),
+			zap.Int("dropped_items", req.count()),
+		)
+		return req.count(), err
+	}
+	if !ok {
+		qrs.logger.Error(
+			"Dropping data because sending_queue is full. Try increasing queue_size.",
+			zap.Int("dropped_items", req.count()),
+		)
+		return req.count(), errors.New("sending_queue is full")
+	}
+	return 0, nil
+}
+
 // shutdown is invoked during service shutdown.
 func (qrs *queuedRetrySender) shutdown() {
 	// First stop the retry goroutines, so that unblocks the queue workers.
 	close(qrs.retryStopCh)
 
+	if qrs.persistentQueue != nil {
+		// Requests still in the queue are kept in the storage, no need to drain it.
+		qrs.persistentQueue.stop()
+		return
+	}
+
 	// Stop the queued sender, this will drain the queue and will call the retry (which is stopped) that will only
 	// try once every request.
 	qrs.queue.Stop()
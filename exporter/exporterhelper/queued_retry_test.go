@@ -17,6 +17,7 @@ package exporterhelper
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -27,7 +28,9 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/data/testdata"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 )
@@ -338,6 +341,164 @@ func TestQueuedRetryHappyPath(t *testing.T) {
 	ocs.checkDroppedItemsCount(t, 0)
 }
 
+func TestQueuedRetryPersistentQueue_ReplayAfterRestart(t *testing.T) {
+	qCfg := CreateDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.Storage = StorageSettings{Enabled: true, Directory: newTestWALDir(t)}
+	rCfg := CreateDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+
+	// First run: the backend is down, the data must stay in the storage when the exporter is stopped.
+	var attempts int64
+	failing, err := NewTraceExporter(fakeTraceExporterConfig, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		atomic.AddInt64(&attempts, 1)
+		return 0, errors.New("backend unavailable")
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, failing.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, failing.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&attempts) > 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, failing.Shutdown(context.Background()))
+
+	// Second run: the persisted request is replayed and acknowledged once sent.
+	received := make(chan pdata.Traces, 1)
+	succeeding, err := NewTraceExporter(fakeTraceExporterConfig, zap.NewNop(), func(_ context.Context, td pdata.Traces) (int, error) {
+		received <- td
+		return 0, nil
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, succeeding.Start(context.Background(), componenttest.NewNopHost()))
+	select {
+	case td := <-received:
+		assert.Equal(t, testdata.GenerateTraceDataTwoSpansSameResource(), td)
+	case <-time.After(time.Second):
+		t.Fatal("persisted request was not replayed")
+	}
+	require.NoError(t, succeeding.Shutdown(context.Background()))
+
+	// Third run: nothing left to replay.
+	_, records, err := openWriteAheadLog(filepath.Join(qCfg.Storage.Directory, storageDirName(fakeTraceExporterName+"_traces")), defaultWALSegmentSize)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestQueuedRetryPersistentQueue_DataTypesNotShared(t *testing.T) {
+	qCfg := CreateDefaultQueueSettings()
+	qCfg.NumConsumers = 1
+	qCfg.Storage = StorageSettings{Enabled: true, Directory: newTestWALDir(t)}
+	rCfg := CreateDefaultRetrySettings()
+	rCfg.InitialInterval = time.Millisecond
+	unavailable := errors.New("backend unavailable")
+
+	// First run: the backend is down for the traces and the metrics exporters created from the
+	// same configuration.
+	texp, err := NewTraceExporter(fakeTraceExporterConfig, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		return 0, unavailable
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	mexp, err := NewMetricsExporter(fakeTraceExporterConfig, zap.NewNop(), func(context.Context, pdata.Metrics) (int, error) {
+		return 0, unavailable
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, texp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, mexp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, texp.ConsumeTraces(context.Background(), testdata.GenerateTraceDataTwoSpansSameResource()))
+	require.NoError(t, mexp.ConsumeMetrics(context.Background(), testdata.GenerateMetricsOneMetric()))
+	require.NoError(t, texp.Shutdown(context.Background()))
+	require.NoError(t, mexp.Shutdown(context.Background()))
+
+	// Second run: each exporter replays only its own data.
+	var mu sync.Mutex
+	var traces []pdata.Traces
+	var metrics []pdata.Metrics
+	texp, err = NewTraceExporter(fakeTraceExporterConfig, zap.NewNop(), func(_ context.Context, td pdata.Traces) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		traces = append(traces, td)
+		return 0, nil
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	mexp, err = NewMetricsExporter(fakeTraceExporterConfig, zap.NewNop(), func(_ context.Context, md pdata.Metrics) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		metrics = append(metrics, md)
+		return 0, nil
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, texp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, mexp.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(traces) > 0 && len(metrics) > 0
+	}, time.Second, time.Millisecond)
+	require.NoError(t, texp.Shutdown(context.Background()))
+	require.NoError(t, mexp.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, traces, 1)
+	assert.Equal(t, testdata.GenerateTraceDataTwoSpansSameResource(), traces[0])
+	require.Len(t, metrics, 1)
+	assert.Equal(t, testdata.GenerateMetricsOneMetric(), metrics[0])
+}
+
+func TestQueuedRetryPersistentQueue_DropOnPermanentError(t *testing.T) {
+	qCfg := CreateDefaultQueueSettings()
+	qCfg.Storage = StorageSettings{Enabled: true, Directory: newTestWALDir(t)}
+	rCfg := CreateDefaultRetrySettings()
+
+	done := make(chan struct{})
+	texp, err := NewTraceExporter(fakeTraceExporterConfig, zap.NewNop(), func(context.Context, pdata.Traces) (int, error) {
+		close(done)
+		return 0, consumererror.Permanent(errors.New("bad data"))
+	}, WithRetry(rCfg), WithQueue(qCfg))
+	require.NoError(t, err)
+	require.NoError(t, texp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, texp.ConsumeTraces(context.Background(), testdata.GenerateTraceDataOneSpan()))
+	<-done
+	require.NoError(t, texp.Shutdown(context.Background()))
+
+	_, records, err := openWriteAheadLog(filepath.Join(qCfg.Storage.Directory, storageDirName(fakeTraceExporterName+"_traces")), defaultWALSegmentSize)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestQueuedRetryPersistentQueue_DropOnFull(t *testing.T) {
+	qCfg := CreateDefaultQueueSettings()
+	qCfg.QueueSize = 0
+	qCfg.Storage = StorageSettings{Enabled: true, Directory: newTestWALDir(t)}
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(qCfg))
+	be.setRequestUnmarshaler(configmodels.TracesDataType, func(ctx context.Context, data []byte) (request, error) {
+		return newMockRequest(ctx, int(data[0]), nil), nil
+	})
+	require.NoError(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		assert.NoError(t, be.Shutdown(context.Background()))
+	})
+	droppedItems, err := be.sender.send(newMockRequest(context.Background(), 2, errors.New("transient error")))
+	require.Error(t, err)
+	assert.Equal(t, 2, droppedItems)
+}
+
+func TestQueuedRetryPersistentQueue_InvalidConfig(t *testing.T) {
+	qCfg := CreateDefaultQueueSettings()
+	qCfg.Storage = StorageSettings{Enabled: true}
+	be := newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(qCfg))
+	be.setRequestUnmarshaler(configmodels.TracesDataType, func(ctx context.Context, data []byte) (request, error) {
+		return newMockRequest(ctx, int(data[0]), nil), nil
+	})
+	require.Error(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, be.Shutdown(context.Background()))
+
+	qCfg.Storage.Directory = newTestWALDir(t)
+	be = newBaseExporter(defaultExporterCfg, zap.NewNop(), WithQueue(qCfg))
+	require.Error(t, be.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, be.Shutdown(context.Background()))
+}
+
 func TestNoCancellationContext(t *testing.T) {
 	deadline := time.Now().Add(1 * time.Second)
 	ctx, cancelFunc := context.WithDeadline(context.Background(), deadline)
@@ -370,6 +531,10 @@ func (mer *mockErrorRequest) count() int {
 	return 7
 }
 
+func (mer *mockErrorRequest) marshal() ([]byte, error) {
+	return nil, nil
+}
+
 func newErrorRequest(ctx context.Context) request {
 	return &mockErrorRequest{
 		baseRequest: baseRequest{ctx: ctx},
@@ -416,6 +581,10 @@ func (m *mockRequest) count() int {
 	return m.cnt
 }
 
+func (m *mockRequest) marshal() ([]byte, error) {
+	return []byte{byte(m.cnt)}, nil
+}
+
 func newMockRequest(ctx context.Context, cnt int, consumeError error) *mockRequest {
 	return &mockRequest{
 		baseRequest:  baseRequest{ctx: ctx},
@@ -56,6 +56,10 @@ func (req *tracesRequest) count() int {
 	return req.td.SpanCount()
 }
 
+func (req *tracesRequest) marshal() ([]byte, error) {
+	return req.td.ToOtlpProtoBytes()
+}
+
 type traceExporter struct {
 	*baseExporter
 	pusher traceDataPusher
@@ -91,6 +95,13 @@ func NewTraceExporter(
 			nextSender:   nextSender,
 		}
 	})
+	be.setRequestUnmarshaler(configmodels.TracesDataType, func(ctx context.Context, data []byte) (request, error) {
+		td := pdata.NewTraces()
+		if err := td.FromOtlpProtoBytes(data); err != nil {
+			return nil, err
+		}
+		return newTracesRequest(ctx, td, dataPusher), nil
+	})
 
 	return &traceExporter{
 		baseExporter: be,
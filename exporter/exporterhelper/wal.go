@@ -0,0 +1,365 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	walSegmentSuffix = ".wal"
+	// walHeaderSize is the size of the header of every record: type (1 byte), sequence number (8 bytes),
+	// payload length (4 bytes) and CRC32 checksum (4 bytes).
+	walHeaderSize = 17
+	// defaultWALSegmentSize is the size after which the active segment is closed and a new one is started.
+	defaultWALSegmentSize = 8 * 1024 * 1024
+
+	walRecordData byte = 1
+	walRecordAck  byte = 2
+)
+
+var (
+	errWALClosed        = errors.New("write-ahead log is closed")
+	errWALCorruptRecord = errors.New("corrupt write-ahead log record")
+)
+
+// walRecord is a data record that was written to the log but not yet acknowledged.
+type walRecord struct {
+	seq     uint64
+	payload []byte
+}
+
+// walSegment is a single file of the log. Segments are only removed from the head of the log,
+// once all the data records they contain are acknowledged.
+type walSegment struct {
+	index   uint64
+	path    string
+	pending map[uint64]struct{}
+}
+
+// writeAheadLog is an append-only log of data and acknowledgement records split across segment files.
+// Data records are replayed when the log is reopened unless an acknowledgement for them was written.
+type writeAheadLog struct {
+	mu             sync.Mutex
+	dir            string
+	maxSegmentSize int64
+	segments       []*walSegment
+	bySeq          map[uint64]*walSegment
+	active         *os.File
+	activeWriter   *bufio.Writer
+	activeSize     int64
+	nextSeq        uint64
+	closed         bool
+}
+
+// openWriteAheadLog opens (or creates) the log in the given directory and returns all the data records that
+// were not acknowledged, in the order they were appended.
+func openWriteAheadLog(dir string, maxSegmentSize int64) (*writeAheadLog, []walRecord, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, nil, fmt.Errorf("failed to create write-ahead log directory: %w", err)
+	}
+
+	indexes, err := listWALSegments(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wal := &writeAheadLog{
+		dir:            dir,
+		maxSegmentSize: maxSegmentSize,
+		bySeq:          make(map[uint64]*walSegment),
+		nextSeq:        1,
+	}
+
+	payloads := make(map[uint64][]byte)
+	for _, index := range indexes {
+		seg := &walSegment{
+			index:   index,
+			path:    wal.segmentPath(index),
+			pending: make(map[uint64]struct{}),
+		}
+		if err = wal.replaySegment(seg, payloads); err != nil {
+			return nil, nil, err
+		}
+		wal.segments = append(wal.segments, seg)
+	}
+
+	records := make([]walRecord, 0, len(payloads))
+	for seq, payload := range payloads {
+		records = append(records, walRecord{seq: seq, payload: payload})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].seq < records[j].seq })
+
+	// Always start appending to a new segment, a previous crash may have left a partial record at the
+	// end of the last one.
+	nextIndex := uint64(1)
+	if len(indexes) > 0 {
+		nextIndex = indexes[len(indexes)-1] + 1
+	}
+	if err = wal.openSegment(nextIndex); err != nil {
+		return nil, nil, err
+	}
+	if err = wal.compact(); err != nil {
+		return nil, nil, err
+	}
+	return wal, records, nil
+}
+
+// append writes a new data record to the log and returns its sequence number.
+func (wal *writeAheadLog) append(payload []byte) (uint64, error) {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	if wal.closed {
+		return 0, errWALClosed
+	}
+
+	if wal.activeSize >= wal.maxSegmentSize {
+		if err := wal.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	seq := wal.nextSeq
+	if err := wal.writeRecord(walRecordData, seq, payload); err != nil {
+		return 0, err
+	}
+	wal.nextSeq++
+
+	seg := wal.segments[len(wal.segments)-1]
+	seg.pending[seq] = struct{}{}
+	wal.bySeq[seq] = seg
+	return seq, nil
+}
+
+// ack writes an acknowledgement for the data record with the given sequence number, after which the record
+// is not replayed anymore. Segments with no pending data records are removed.
+func (wal *writeAheadLog) ack(seq uint64) error {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	if wal.closed {
+		return errWALClosed
+	}
+
+	seg, ok := wal.bySeq[seq]
+	if !ok {
+		return nil
+	}
+	if err := wal.writeRecord(walRecordAck, seq, nil); err != nil {
+		return err
+	}
+	delete(wal.bySeq, seq)
+	delete(seg.pending, seq)
+	return wal.compact()
+}
+
+// close flushes and closes the active segment.
+func (wal *writeAheadLog) close() error {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	if wal.closed {
+		return nil
+	}
+	wal.closed = true
+	return wal.closeActive()
+}
+
+func (wal *writeAheadLog) segmentPath(index uint64) string {
+	return filepath.Join(wal.dir, fmt.Sprintf("%020d%s", index, walSegmentSuffix))
+}
+
+func (wal *writeAheadLog) replaySegment(seg *walSegment, payloads map[uint64][]byte) error {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return fmt.Errorf("failed to open write-ahead log segment: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read write-ahead log segment %q: %w", seg.path, err)
+	}
+
+	r := bufio.NewReader(f)
+	remaining := info.Size()
+	for {
+		typ, seq, payload, err := readWALRecord(r, remaining)
+		if err == io.EOF {
+			return nil
+		}
+		if err == io.ErrUnexpectedEOF || errors.Is(err, errWALCorruptRecord) {
+			// A partially written record at the end of a segment means the process was killed while
+			// writing it. The request was never acknowledged to the caller, so it is safe to ignore it.
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read write-ahead log segment %q: %w", seg.path, err)
+		}
+		remaining -= walHeaderSize + int64(len(payload))
+
+		if seq >= wal.nextSeq {
+			wal.nextSeq = seq + 1
+		}
+		switch typ {
+		case walRecordData:
+			payloads[seq] = payload
+			seg.pending[seq] = struct{}{}
+			wal.bySeq[seq] = seg
+		case walRecordAck:
+			delete(payloads, seq)
+			if owner, ok := wal.bySeq[seq]; ok {
+				delete(owner.pending, seq)
+				delete(wal.bySeq, seq)
+			}
+		}
+	}
+}
+
+func (wal *writeAheadLog) writeRecord(typ byte, seq uint64, payload []byte) error {
+	var header [walHeaderSize]byte
+	header[0] = typ
+	binary.BigEndian.PutUint64(header[1:9], seq)
+	binary.BigEndian.PutUint32(header[9:13], uint32(len(payload)))
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[:13])
+	_, _ = crc.Write(payload)
+	binary.BigEndian.PutUint32(header[13:17], crc.Sum32())
+
+	if _, err := wal.activeWriter.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := wal.activeWriter.Write(payload); err != nil {
+		return err
+	}
+	// Flush every record to the OS so it survives a crash of the process.
+	if err := wal.activeWriter.Flush(); err != nil {
+		return err
+	}
+	wal.activeSize += int64(walHeaderSize + len(payload))
+	return nil
+}
+
+func (wal *writeAheadLog) openSegment(index uint64) error {
+	path := wal.segmentPath(index)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create write-ahead log segment: %w", err)
+	}
+	wal.active = f
+	wal.activeWriter = bufio.NewWriter(f)
+	wal.activeSize = 0
+	wal.segments = append(wal.segments, &walSegment{
+		index:   index,
+		path:    path,
+		pending: make(map[uint64]struct{}),
+	})
+	return nil
+}
+
+func (wal *writeAheadLog) rotate() error {
+	if err := wal.closeActive(); err != nil {
+		return err
+	}
+	return wal.openSegment(wal.segments[len(wal.segments)-1].index + 1)
+}
+
+func (wal *writeAheadLog) closeActive() error {
+	if err := wal.activeWriter.Flush(); err != nil {
+		return err
+	}
+	if err := wal.active.Sync(); err != nil {
+		return err
+	}
+	return wal.active.Close()
+}
+
+// compact removes the fully acknowledged segments from the head of the log. Segments are never removed
+// out of order, because acknowledgements for records in a segment may be stored in the following ones.
+func (wal *writeAheadLog) compact() error {
+	for len(wal.segments) > 1 && len(wal.segments[0].pending) == 0 {
+		if err := os.Remove(wal.segments[0].path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove write-ahead log segment: %w", err)
+		}
+		wal.segments = wal.segments[1:]
+	}
+	return nil
+}
+
+// readWALRecord reads the next record of a segment, remaining is the number of bytes left in the
+// segment. A payload length that exceeds it can only come from a corrupt header, it is rejected
+// before the payload is allocated.
+func readWALRecord(r io.Reader, remaining int64) (byte, uint64, []byte, error) {
+	var header [walHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, 0, nil, err
+	}
+	typ := header[0]
+	seq := binary.BigEndian.Uint64(header[1:9])
+	size := binary.BigEndian.Uint32(header[9:13])
+	if typ != walRecordData && typ != walRecordAck {
+		return 0, 0, nil, errWALCorruptRecord
+	}
+	if int64(size) > remaining-walHeaderSize {
+		return 0, 0, nil, errWALCorruptRecord
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, 0, nil, err
+	}
+
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[:13])
+	_, _ = crc.Write(payload)
+	if crc.Sum32() != binary.BigEndian.Uint32(header[13:17]) {
+		return 0, 0, nil, errWALCorruptRecord
+	}
+	return typ, seq, payload, nil
+}
+
+// listWALSegments returns the indexes of the segments found in the directory in ascending order.
+func listWALSegments(dir string) ([]uint64, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read write-ahead log directory: %w", err)
+	}
+
+	var indexes []uint64
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), walSegmentSuffix) {
+			continue
+		}
+		index, err := strconv.ParseUint(strings.TrimSuffix(f.Name(), walSegmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		indexes = append(indexes, index)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	return indexes, nil
+}
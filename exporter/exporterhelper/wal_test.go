@@ -0,0 +1,175 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exporterhelper
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWALDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "wal")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestWriteAheadLog_ReplayUnacked(t *testing.T) {
+	dir := newTestWALDir(t)
+
+	wal, records, err := openWriteAheadLog(dir, defaultWALSegmentSize)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	seq1, err := wal.append([]byte("first"))
+	require.NoError(t, err)
+	seq2, err := wal.append([]byte("second"))
+	require.NoError(t, err)
+	_, err = wal.append([]byte("third"))
+	require.NoError(t, err)
+	require.NoError(t, wal.ack(seq2))
+	require.NoError(t, wal.close())
+
+	wal, records, err = openWriteAheadLog(dir, defaultWALSegmentSize)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, seq1, records[0].seq)
+	assert.Equal(t, []byte("first"), records[0].payload)
+	assert.Equal(t, []byte("third"), records[1].payload)
+
+	// New records must not reuse sequence numbers of the replayed ones.
+	seq4, err := wal.append([]byte("fourth"))
+	require.NoError(t, err)
+	assert.Greater(t, seq4, records[1].seq)
+	require.NoError(t, wal.close())
+}
+
+func TestWriteAheadLog_RemovesAckedSegments(t *testing.T) {
+	dir := newTestWALDir(t)
+
+	// A tiny segment size forces a rotation for every record.
+	wal, _, err := openWriteAheadLog(dir, 1)
+	require.NoError(t, err)
+
+	var seqs []uint64
+	for i := 0; i < 5; i++ {
+		seq, err := wal.append([]byte{byte(i)})
+		require.NoError(t, err)
+		seqs = append(seqs, seq)
+	}
+	indexes, err := listWALSegments(dir)
+	require.NoError(t, err)
+	assert.Len(t, indexes, 5)
+
+	// Acknowledging a segment in the middle cannot remove it before the older ones.
+	require.NoError(t, wal.ack(seqs[2]))
+	indexes, err = listWALSegments(dir)
+	require.NoError(t, err)
+	assert.Len(t, indexes, 5)
+
+	require.NoError(t, wal.ack(seqs[0]))
+	require.NoError(t, wal.ack(seqs[1]))
+	indexes, err = listWALSegments(dir)
+	require.NoError(t, err)
+	assert.Len(t, indexes, 2)
+	require.NoError(t, wal.close())
+
+	_, records, err := openWriteAheadLog(dir, 1)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, seqs[3], records[0].seq)
+	assert.Equal(t, seqs[4], records[1].seq)
+}
+
+func TestWriteAheadLog_IgnoresPartialRecord(t *testing.T) {
+	dir := newTestWALDir(t)
+
+	wal, _, err := openWriteAheadLog(dir, defaultWALSegmentSize)
+	require.NoError(t, err)
+	_, err = wal.append([]byte("complete"))
+	require.NoError(t, err)
+	_, err = wal.append([]byte("truncated"))
+	require.NoError(t, err)
+	require.NoError(t, wal.close())
+
+	// Simulate a crash while the last record was written.
+	indexes, err := listWALSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+	path := wal.segmentPath(indexes[0])
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(path, info.Size()-3))
+
+	_, records, err := openWriteAheadLog(dir, defaultWALSegmentSize)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []byte("complete"), records[0].payload)
+}
+
+func TestWriteAheadLog_IgnoresOversizeRecord(t *testing.T) {
+	dir := newTestWALDir(t)
+
+	wal, _, err := openWriteAheadLog(dir, defaultWALSegmentSize)
+	require.NoError(t, err)
+	_, err = wal.append([]byte("complete"))
+	require.NoError(t, err)
+	_, err = wal.append([]byte("oversize"))
+	require.NoError(t, err)
+	require.NoError(t, wal.close())
+
+	// Corrupt the payload length of the last record, it must not be allocated.
+	indexes, err := listWALSegments(dir)
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+	path := wal.segmentPath(indexes[0])
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	last := walHeaderSize + len("complete")
+	binary.BigEndian.PutUint32(data[last+9:last+13], math.MaxUint32)
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+
+	_, records, err := openWriteAheadLog(dir, defaultWALSegmentSize)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, []byte("complete"), records[0].payload)
+}
+
+func TestReadWALRecord_Oversize(t *testing.T) {
+	var header [walHeaderSize]byte
+	header[0] = walRecordData
+	binary.BigEndian.PutUint32(header[9:13], 1024)
+
+	_, _, _, err := readWALRecord(bytes.NewReader(header[:]), int64(len(header))+1023)
+	assert.Equal(t, errWALCorruptRecord, err)
+}
+
+func TestWriteAheadLog_Closed(t *testing.T) {
+	wal, _, err := openWriteAheadLog(newTestWALDir(t), defaultWALSegmentSize)
+	require.NoError(t, err)
+	require.NoError(t, wal.close())
+	require.NoError(t, wal.close())
+
+	_, err = wal.append([]byte("data"))
+	assert.Equal(t, errWALClosed, err)
+	assert.Equal(t, errWALClosed, wal.ack(1))
+}
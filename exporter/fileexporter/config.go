@@ -15,13 +15,46 @@
 package fileexporter
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
+const (
+	// FormatJSON writes each message as a line of Protobuf JSON.
+	FormatJSON = "json"
+	// FormatProto writes each message as binary Protobuf prefixed by its length.
+	FormatProto = "proto"
+)
+
+// RotationConfig defines when the file is rotated and how many rotated files are kept.
+type RotationConfig struct {
+	// MaxMegabytes is the maximum size in megabytes of the file before it is rotated.
+	// The file is not rotated by size if it is 0.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// MaxAge is the maximum time the file is written to before it is rotated.
+	// The file is not rotated by age if it is 0.
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// MaxBackups is the maximum number of rotated files kept, the oldest ones are
+	// removed. All the rotated files are kept if it is 0.
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// Compress compresses the rotated files with gzip.
+	Compress bool `mapstructure:"compress"`
+}
+
 // Config defines configuration for file exporter.
 type Config struct {
 	configmodels.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
 
 	// Path of the file to write to. Path is relative to current directory.
 	Path string `mapstructure:"path"`
+
+	// Format is the encoding of the messages written, either "json" or "proto".
+	Format string `mapstructure:"format"`
+
+	// Rotation configures the rotation of the file, it is never rotated if it is not set.
+	Rotation *RotationConfig `mapstructure:"rotation"`
 }
@@ -16,7 +16,10 @@ package fileexporter
 
 import (
 	"context"
-	"os"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -44,34 +47,35 @@ func createDefaultConfig() configmodels.Exporter {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
+		Format: FormatJSON,
 	}
 }
 
 func createTraceExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.TracesExporter, error) {
-	return createExporter(cfg)
+	return createExporter(params.Logger, cfg, configmodels.TracesDataType)
 }
 
 func createMetricsExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.MetricsExporter, error) {
-	return createExporter(cfg)
+	return createExporter(params.Logger, cfg, configmodels.MetricsDataType)
 }
 
 func createLogsExporter(
 	_ context.Context,
-	_ component.ExporterCreateParams,
+	params component.ExporterCreateParams,
 	cfg configmodels.Exporter,
 ) (component.LogsExporter, error) {
-	return createExporter(cfg)
+	return createExporter(params.Logger, cfg, configmodels.LogsDataType)
 }
 
-func createExporter(config configmodels.Exporter) (*fileExporter, error) {
+func createExporter(logger *zap.Logger, config configmodels.Exporter, dataType configmodels.DataType) (*fileExporter, error) {
 	cfg := config.(*Config)
 
 	// There must be one exporter for metrics, traces, and logs. We maintain a
@@ -81,18 +85,43 @@ func createExporter(config configmodels.Exporter) (*fileExporter, error) {
 	exporter, ok := exporters[cfg]
 
 	if !ok {
-		file, err := os.OpenFile(cfg.Path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+		if err := validateConfig(cfg); err != nil {
+			return nil, err
+		}
+		file, err := newFileWriter(logger, cfg.Path, cfg.Rotation)
 		if err != nil {
 			return nil, err
 		}
-		exporter = &fileExporter{file: file}
+		exporter = &fileExporter{file: file, format: cfg.Format, dataType: dataType}
 
 		// Remember the receiver in the map
 		exporters[cfg] = exporter
+	} else if cfg.Format == FormatProto && exporter.dataType != dataType {
+		// The type of the binary Protobuf requests is not written, the requests of
+		// different types could not be told apart when the file is read.
+		return nil, fmt.Errorf("the %q format can only be used in pipelines of a single data type", FormatProto)
 	}
 	return exporter, nil
 }
 
+func validateConfig(cfg *Config) error {
+	if cfg.Format != FormatJSON && cfg.Format != FormatProto {
+		return fmt.Errorf("invalid format %q, must be %q or %q", cfg.Format, FormatJSON, FormatProto)
+	}
+	if cfg.Rotation != nil {
+		if cfg.Rotation.MaxMegabytes < 0 {
+			return errors.New("rotation max_megabytes must not be negative")
+		}
+		if cfg.Rotation.MaxAge < 0 {
+			return errors.New("rotation max_age must not be negative")
+		}
+		if cfg.Rotation.MaxBackups < 0 {
+			return errors.New("rotation max_backups must not be negative")
+		}
+	}
+	return nil
+}
+
 // This is the map of already created File exporters for particular configurations.
 // We maintain this map because the Factory is asked trace and metric receivers separately
 // when it gets CreateTracesReceiver() and CreateMetricsReceiver() but they must not
@@ -16,7 +16,11 @@ package fileexporter
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,3 +66,92 @@ func TestCreateLogsExporter(t *testing.T) {
 	assert.Error(t, err)
 	require.Nil(t, exp)
 }
+
+func TestCreateExporterInvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "invalid_format",
+			modify:      func(cfg *Config) { cfg.Format = "xml" },
+			errorString: `invalid format "xml", must be "json" or "proto"`,
+		},
+		{
+			name:        "negative_max_megabytes",
+			modify:      func(cfg *Config) { cfg.Rotation = &RotationConfig{MaxMegabytes: -1} },
+			errorString: "rotation max_megabytes must not be negative",
+		},
+		{
+			name:        "negative_max_age",
+			modify:      func(cfg *Config) { cfg.Rotation = &RotationConfig{MaxAge: -time.Second} },
+			errorString: "rotation max_age must not be negative",
+		},
+		{
+			name:        "negative_max_backups",
+			modify:      func(cfg *Config) { cfg.Rotation = &RotationConfig{MaxBackups: -1} },
+			errorString: "rotation max_backups must not be negative",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Path = filepath.Join(os.TempDir(), "fileexporter.json")
+			tc.modify(cfg)
+			exp, err := createTraceExporter(
+				context.Background(),
+				component.ExporterCreateParams{Logger: zap.NewNop()},
+				cfg)
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, exp)
+		})
+	}
+}
+
+func TestCreateExporterSharedByConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "data.json")
+	cfg.Rotation = &RotationConfig{MaxMegabytes: 10}
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	te, err := createTraceExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	me, err := createMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	le, err := createLogsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.Same(t, te, me)
+	assert.Same(t, te, le)
+	assert.NoError(t, te.Shutdown(context.Background()))
+}
+
+func TestCreateExporterProtoSingleDataType(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "data.pb")
+	cfg.Format = FormatProto
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	me, err := createMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, FormatProto, me.(*fileExporter).format)
+	other, err := createMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	assert.Same(t, me, other)
+
+	te, err := createTraceExporter(context.Background(), params, cfg)
+	assert.EqualError(t, err, `the "proto" format can only be used in pipelines of a single data type`)
+	assert.Nil(t, te)
+	le, err := createLogsExporter(context.Background(), params, cfg)
+	assert.Error(t, err)
+	assert.Nil(t, le)
+	assert.NoError(t, me.Shutdown(context.Background()))
+}
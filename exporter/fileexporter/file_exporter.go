@@ -15,6 +15,7 @@
 package fileexporter
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"sync"
@@ -23,6 +24,7 @@ import (
 	"github.com/gogo/protobuf/proto"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal"
 	otlplogs "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/logs/v1"
@@ -34,44 +36,73 @@ import (
 var marshaler = &jsonpb.Marshaler{}
 
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON or binary Protobuf format.
 type fileExporter struct {
-	file  io.WriteCloser
-	mutex sync.Mutex
+	file   io.WriteCloser
+	format string
+	// dataType is the type of the pipeline the exporter was first created for.
+	dataType configmodels.DataType
+	mutex    sync.Mutex
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td pdata.Traces) error {
 	request := otlptrace.ExportTraceServiceRequest{
 		ResourceSpans: pdata.TracesToOtlp(td),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(&request)
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
 	request := otlpmetrics.ExportMetricsServiceRequest{
 		ResourceMetrics: pdata.MetricsToOtlp(md),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(&request)
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
 	request := otlplogs.ExportLogsServiceRequest{
 		ResourceLogs: internal.LogsToOtlp(ld.InternalRep()),
 	}
-	return exportMessageAsLine(e, &request)
+	return e.exportMessage(&request)
 }
 
-func exportMessageAsLine(e *fileExporter, message proto.Message) error {
-	// Ensure only one write operation happens at a time.
+func (e *fileExporter) exportMessage(message proto.Message) error {
+	var buf []byte
+	var err error
+	if e.format == FormatProto {
+		buf, err = marshalDelimited(message)
+	} else {
+		buf, err = marshalLine(message)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Ensure only one write operation happens at a time. The message is written at
+	// once, so that it is never split between two files when the file is rotated.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if err := marshaler.Marshal(e.file, message); err != nil {
-		return err
+	_, err = e.file.Write(buf)
+	return err
+}
+
+// marshalLine marshals the message to Protobuf-JSON followed by a new line.
+func marshalLine(message proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshaler.Marshal(&buf, message); err != nil {
+		return nil, err
 	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
-		return err
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// marshalDelimited marshals the message to binary Protobuf prefixed by its length as a varint.
+func marshalDelimited(message proto.Message) ([]byte, error) {
+	buf := proto.NewBuffer(nil)
+	if err := buf.EncodeMessage(message); err != nil {
+		return nil, err
 	}
-	return nil
+	return buf.Bytes(), nil
 }
 
 func (e *fileExporter) Start(ctx context.Context, host component.Host) error {
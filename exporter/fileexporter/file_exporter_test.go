@@ -15,10 +15,12 @@ package fileexporter
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -51,6 +53,46 @@ func TestFileTraceExporterNoErrors(t *testing.T) {
 	assert.EqualValues(t, pdata.TracesToOtlp(td), j.ResourceSpans)
 }
 
+func TestFileExporterProtoFormat(t *testing.T) {
+	mf := &testutil.LimitedWriter{}
+	exporter := &fileExporter{file: mf, format: FormatProto}
+
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	md := testdata.GenerateMetricsTwoMetrics()
+	assert.NoError(t, exporter.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, exporter.ConsumeMetrics(context.Background(), md))
+	assert.NoError(t, exporter.Shutdown(context.Background()))
+
+	buf := proto.NewBuffer(mf.Bytes())
+	var traces collectortrace.ExportTraceServiceRequest
+	require.NoError(t, buf.DecodeMessage(&traces))
+	assert.EqualValues(t, pdata.TracesToOtlp(td), traces.ResourceSpans)
+	var metrics collectormetrics.ExportMetricsServiceRequest
+	require.NoError(t, buf.DecodeMessage(&metrics))
+	assert.EqualValues(t, pdata.MetricsToOtlp(md), metrics.ResourceMetrics)
+	// There are no more messages.
+	assert.Error(t, buf.DecodeMessage(&metrics))
+}
+
+func TestFileExporterJSONLines(t *testing.T) {
+	mf := &testutil.LimitedWriter{}
+	exporter := &fileExporter{file: mf, format: FormatJSON}
+
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	assert.NoError(t, exporter.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, exporter.ConsumeTraces(context.Background(), td))
+	assert.NoError(t, exporter.Shutdown(context.Background()))
+
+	lines := strings.Split(mf.String(), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "", lines[2])
+	for _, line := range lines[:2] {
+		var j collectortrace.ExportTraceServiceRequest
+		assert.NoError(t, jsonpb.UnmarshalString(line, &j))
+		assert.EqualValues(t, pdata.TracesToOtlp(td), j.ResourceSpans)
+	}
+}
+
 func TestFileMetricsExporterNoErrors(t *testing.T) {
 	mf := &testutil.LimitedWriter{}
 	lme := &fileExporter{file: mf}
@@ -0,0 +1,277 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// backupTimeFormat is the format of the rotation time in the name of the rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// fileWriter writes to a file, that it rotates according to the rotation settings.
+type fileWriter struct {
+	logger   *zap.Logger
+	path     string
+	rotation *RotationConfig
+	maxSize  int64
+	now      func() time.Time
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+
+	// millLock serializes the compression and the removal of the rotated files, which
+	// run in the background so that the writes are not blocked.
+	millLock sync.Mutex
+	millWG   sync.WaitGroup
+}
+
+func newFileWriter(logger *zap.Logger, path string, rotation *RotationConfig) (*fileWriter, error) {
+	w := &fileWriter{
+		logger:   logger,
+		path:     path,
+		rotation: rotation,
+		now:      time.Now,
+	}
+	if rotation != nil {
+		w.maxSize = int64(rotation.MaxMegabytes) * 1024 * 1024
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *fileWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	w.file = file
+	w.size = 0
+	w.openedAt = w.now()
+	return nil
+}
+
+// Write writes p to the file, after rotating it if writing p would make it exceed its
+// maximum size or if it is older than its maximum age. p is never split between two files.
+func (w *fileWriter) Write(p []byte) (int, error) {
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			// The data is still written to the current file.
+			w.logger.Warn("Failed to rotate the file", zap.String("path", w.path), zap.Error(err))
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *fileWriter) shouldRotate(n int) bool {
+	// Empty files are not rotated, so that a message larger than the maximum size
+	// is written to its own file.
+	if w.rotation == nil || w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+int64(n) > w.maxSize {
+		return true
+	}
+	return w.rotation.MaxAge > 0 && w.now().Sub(w.openedAt) >= w.rotation.MaxAge
+}
+
+// rotate renames the file to a backup and creates a new file. The backup is then
+// compressed and the oldest backups are removed in the background.
+func (w *fileWriter) rotate() error {
+	backup := w.backupPath(w.now())
+	err := w.file.Close()
+	if err == nil {
+		err = os.Rename(w.path, backup)
+	}
+	if err != nil {
+		if file, openErr := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600); openErr == nil {
+			w.file = file
+		}
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.millWG.Add(1)
+	go func() {
+		defer w.millWG.Done()
+		w.mill(backup)
+	}()
+	return nil
+}
+
+// mill compresses the backup and removes the oldest backups, their errors are only
+// logged since they do not prevent writing.
+func (w *fileWriter) mill(backup string) {
+	w.millLock.Lock()
+	defer w.millLock.Unlock()
+	if w.rotation.Compress {
+		if err := compressFile(backup); err != nil {
+			w.logger.Warn("Failed to compress the rotated file", zap.String("path", backup), zap.Error(err))
+		}
+	}
+	if err := w.removeOldBackups(); err != nil {
+		w.logger.Warn("Failed to remove the old rotated files", zap.String("path", w.path), zap.Error(err))
+	}
+}
+
+// backupPrefixAndExt returns the prefix and the extension of the names of the rotated
+// files, which are the name of the file followed by the rotation time.
+func (w *fileWriter) backupPrefixAndExt() (string, string) {
+	name := filepath.Base(w.path)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "-", ext
+}
+
+// backupPath returns the path of a new backup rotated at t. A counter is appended to the
+// rotation time if a backup rotated within the same millisecond already exists.
+func (w *fileWriter) backupPath(t time.Time) string {
+	prefix, ext := w.backupPrefixAndExt()
+	name := prefix + t.UTC().Format(backupTimeFormat)
+	path := filepath.Join(filepath.Dir(w.path), name+ext)
+	for i := 1; fileExists(path) || fileExists(path+".gz"); i++ {
+		path = filepath.Join(filepath.Dir(w.path), name+"-"+strconv.Itoa(i)+ext)
+	}
+	return path
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+type backup struct {
+	path    string
+	time    time.Time
+	counter int
+}
+
+// parseBackupTime parses the rotation time and the counter of a backup name, without its
+// prefix and extension.
+func parseBackupTime(s string) (time.Time, int, error) {
+	counter := 0
+	if len(s) > len(backupTimeFormat) {
+		suffix := s[len(backupTimeFormat):]
+		if suffix[0] != '-' {
+			return time.Time{}, 0, fmt.Errorf("invalid backup counter %q", suffix)
+		}
+		var err error
+		if counter, err = strconv.Atoi(suffix[1:]); err != nil {
+			return time.Time{}, 0, err
+		}
+		s = s[:len(backupTimeFormat)]
+	}
+	t, err := time.Parse(backupTimeFormat, s)
+	return t, counter, err
+}
+
+// backups returns the rotated files, compressed or not, from the oldest to the newest.
+func (w *fileWriter) backups() ([]backup, error) {
+	dir := filepath.Dir(w.path)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix, ext := w.backupPrefixAndExt()
+	var backups []backup
+	for _, info := range infos {
+		name := strings.TrimSuffix(info.Name(), ".gz")
+		if info.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		t, counter, err := parseBackupTime(name[len(prefix) : len(name)-len(ext)])
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, info.Name()), time: t, counter: counter})
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		if backups[i].time.Equal(backups[j].time) {
+			return backups[i].counter < backups[j].counter
+		}
+		return backups[i].time.Before(backups[j].time)
+	})
+	return backups, nil
+}
+
+func (w *fileWriter) removeOldBackups() error {
+	if w.rotation.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := w.backups()
+	if err != nil {
+		return err
+	}
+	for len(backups) > w.rotation.MaxBackups {
+		if err := os.Remove(backups[0].path); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Close closes the file, and waits for the rotated files to be compressed.
+func (w *fileWriter) Close() error {
+	err := w.file.Close()
+	w.millWG.Wait()
+	return err
+}
+
+// compressFile replaces the file at path by its gzip compressed copy with the .gz extension.
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	_, err = io.Copy(gz, src)
+	if err == nil {
+		err = gz.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz")
+		return fmt.Errorf("error compressing %s: %v", path, err)
+	}
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestFileWriter(t *testing.T, rotation *RotationConfig) (*fileWriter, string, *time.Time) {
+	dir, err := ioutil.TempDir("", "fileexporter")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	now := time.Date(2020, 11, 5, 12, 0, 0, 0, time.UTC)
+	w, err := newFileWriter(zap.NewNop(), filepath.Join(dir, "data.json"), rotation)
+	require.NoError(t, err)
+	w.now = func() time.Time { return now }
+	w.openedAt = now
+	if rotation != nil {
+		w.maxSize = 10
+	}
+	t.Cleanup(func() { w.Close() })
+	return w, dir, &now
+}
+
+// files returns the content of the files in dir by name, the compressed files are decompressed.
+func files(t *testing.T, dir string) map[string]string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	contents := map[string]string{}
+	for _, info := range infos {
+		f, err := os.Open(filepath.Join(dir, info.Name()))
+		require.NoError(t, err)
+		var data []byte
+		if filepath.Ext(info.Name()) == ".gz" {
+			gz, err := gzip.NewReader(f)
+			require.NoError(t, err)
+			data, err = ioutil.ReadAll(gz)
+			require.NoError(t, err)
+		} else {
+			data, err = ioutil.ReadAll(f)
+			require.NoError(t, err)
+		}
+		f.Close()
+		contents[info.Name()] = string(data)
+	}
+	return contents
+}
+
+func write(t *testing.T, w *fileWriter, data string) {
+	n, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.Equal(t, len(data), n)
+}
+
+func TestFileWriter_NoRotation(t *testing.T) {
+	w, dir, now := newTestFileWriter(t, nil)
+	write(t, w, "0123456789\n")
+	*now = now.Add(24 * time.Hour)
+	write(t, w, "0123456789\n")
+
+	assert.Equal(t, map[string]string{
+		"data.json": "0123456789\n0123456789\n",
+	}, files(t, dir))
+}
+
+func TestFileWriter_RotateBySize(t *testing.T) {
+	w, dir, now := newTestFileWriter(t, &RotationConfig{MaxMegabytes: 1})
+	write(t, w, "abcd\n")
+	write(t, w, "efgh\n")
+	*now = now.Add(time.Second)
+	// Larger than the maximum size, it is written to its own file.
+	write(t, w, "0123456789\n")
+	*now = now.Add(time.Second)
+	write(t, w, "ijkl\n")
+
+	assert.Equal(t, map[string]string{
+		"data-2020-11-05T12-00-01.000.json": "abcd\nefgh\n",
+		"data-2020-11-05T12-00-02.000.json": "0123456789\n",
+		"data.json":                         "ijkl\n",
+	}, files(t, dir))
+}
+
+func TestFileWriter_RotateByAge(t *testing.T) {
+	w, dir, now := newTestFileWriter(t, &RotationConfig{MaxAge: time.Minute})
+	w.maxSize = 0
+	write(t, w, "0123456789\n")
+	*now = now.Add(59 * time.Second)
+	write(t, w, "0123456789\n")
+	*now = now.Add(time.Second)
+	write(t, w, "abcd\n")
+	// The age is reset by the rotation.
+	*now = now.Add(59 * time.Second)
+	write(t, w, "efgh\n")
+
+	assert.Equal(t, map[string]string{
+		"data-2020-11-05T12-01-00.000.json": "0123456789\n0123456789\n",
+		"data.json":                         "abcd\nefgh\n",
+	}, files(t, dir))
+}
+
+func TestFileWriter_MaxBackupsAndCompress(t *testing.T) {
+	w, dir, now := newTestFileWriter(t, &RotationConfig{MaxMegabytes: 1, MaxBackups: 2, Compress: true})
+	// Files that are not backups are not removed.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "data-old.json"), []byte("other"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other-2020-01-01T00-00-00.000.json"), []byte("other"), 0600))
+
+	for _, data := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		*now = now.Add(time.Second)
+		write(t, w, data)
+	}
+	// The rotated files are compressed in the background.
+	w.millWG.Wait()
+
+	assert.Equal(t, map[string]string{
+		"data-old.json":                        "other",
+		"other-2020-01-01T00-00-00.000.json":   "other",
+		"data-2020-11-05T12-00-03.000.json.gz": "second\n",
+		"data-2020-11-05T12-00-04.000.json.gz": "third\n",
+		"data.json":                            "fourth\n",
+	}, files(t, dir))
+}
+
+func TestFileWriter_RotateSameMillisecond(t *testing.T) {
+	w, dir, _ := newTestFileWriter(t, &RotationConfig{MaxMegabytes: 1, MaxBackups: 2, Compress: true})
+	for _, data := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		write(t, w, data)
+	}
+	w.millWG.Wait()
+
+	assert.Equal(t, map[string]string{
+		"data-2020-11-05T12-00-00.000-1.json.gz": "second\n",
+		"data-2020-11-05T12-00-00.000-2.json.gz": "third\n",
+		"data.json":                              "fourth\n",
+	}, files(t, dir))
+}
+
+func TestFileWriter_Backups(t *testing.T) {
+	w, dir, _ := newTestFileWriter(t, &RotationConfig{})
+	for _, name := range []string{
+		"data-2020-11-05T12-00-03.000.json.gz",
+		"data-2020-11-05T12-00-01.000-1.json",
+		"data-2020-11-05T12-00-01.000.json",
+		"data-2020-11-05T12-00-02.000.json.gz",
+		"data-2020-11-05T12-00-01.000-x.json",
+		"data-invalid.json",
+		"data-2020-11-05T12-00-00.000.txt",
+	} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0600))
+	}
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	var names []string
+	for _, b := range backups {
+		names = append(names, filepath.Base(b.path))
+	}
+	assert.Equal(t, []string{
+		"data-2020-11-05T12-00-01.000.json",
+		"data-2020-11-05T12-00-01.000-1.json",
+		"data-2020-11-05T12-00-02.000.json.gz",
+		"data-2020-11-05T12-00-03.000.json.gz",
+	}, names)
+}
+
+func TestNewFileWriter_Error(t *testing.T) {
+	w, err := newFileWriter(zap.NewNop(), filepath.Join("nonexistent", "data.json"), nil)
+	assert.Error(t, err)
+	assert.Nil(t, w)
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	// GzipCompression compresses the requests with gzip.
+	GzipCompression = "gzip"
+	// NoCompression sends the requests uncompressed.
+	NoCompression = "none"
+)
+
+// Config defines the configuration for the InfluxDB exporter.
+type Config struct {
+	configmodels.ExporterSettings `mapstructure:",squash"`
+
+	// HTTPClientSettings are the settings of the HTTP client, the endpoint is the base
+	// URL of the InfluxDB API, e.g. http://localhost:8086.
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+
+	exporterhelper.QueueSettings `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings `mapstructure:"retry_on_failure"`
+
+	// Database is the database the points are written to.
+	Database string `mapstructure:"database"`
+
+	// RetentionPolicy is the retention policy the points are written to, the default
+	// retention policy of the database if not set.
+	RetentionPolicy string `mapstructure:"retention_policy"`
+
+	// Compression is the compression of the requests, either "gzip" or "none".
+	Compression string `mapstructure:"compression"`
+}
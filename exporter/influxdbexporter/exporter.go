@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	headerRetryAfter         = "Retry-After"
+	maxHTTPResponseReadBytes = 64 * 1024
+)
+
+type influxDBExporter struct {
+	logger      *zap.Logger
+	client      *http.Client
+	writeURL    string
+	compression string
+}
+
+func newInfluxDBExporter(logger *zap.Logger, cfg *Config) (*influxDBExporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("endpoint must be specified")
+	}
+	if cfg.Database == "" {
+		return nil, errors.New("database must be specified")
+	}
+	if cfg.Compression != GzipCompression && cfg.Compression != NoCompression {
+		return nil, fmt.Errorf("invalid compression %q, must be %q or %q", cfg.Compression, GzipCompression, NoCompression)
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, errors.New("endpoint must be a valid URL")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/write"
+	query := url.Values{}
+	query.Set("db", cfg.Database)
+	if cfg.RetentionPolicy != "" {
+		query.Set("rp", cfg.RetentionPolicy)
+	}
+	query.Set("precision", "ns")
+	u.RawQuery = query.Encode()
+
+	client, err := cfg.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+	return &influxDBExporter{
+		logger:      logger,
+		client:      client,
+		writeURL:    u.String(),
+		compression: cfg.Compression,
+	}, nil
+}
+
+func (e *influxDBExporter) pushMetrics(ctx context.Context, md pdata.Metrics) (int, error) {
+	var lines bytes.Buffer
+	dropped := writeMetrics(&lines, md)
+	if lines.Len() == 0 {
+		return dropped, nil
+	}
+
+	body := &lines
+	if e.compression == GzipCompression {
+		body = &bytes.Buffer{}
+		gw := gzip.NewWriter(body)
+		if _, err := gw.Write(lines.Bytes()); err != nil {
+			return md.MetricCount(), consumererror.Permanent(err)
+		}
+		if err := gw.Close(); err != nil {
+			return md.MetricCount(), consumererror.Permanent(err)
+		}
+	}
+	if err := e.write(ctx, body); err != nil {
+		return md.MetricCount(), err
+	}
+	return dropped, nil
+}
+
+func (e *influxDBExporter) write(ctx context.Context, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.writeURL, body)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if e.compression == GzipCompression {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make an HTTP request: %w", err)
+	}
+	defer func() {
+		// Discard any remaining response body when we are done reading.
+		io.CopyN(ioutil.Discard, resp.Body, maxHTTPResponseReadBytes)
+		resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	formattedErr := fmt.Errorf("error writing points, request to %s responded with HTTP Status Code %d", e.writeURL, resp.StatusCode)
+	if message := readError(resp); message != "" {
+		formattedErr = fmt.Errorf("%v, Message=%s", formattedErr, message)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter := 0
+		if val := resp.Header.Get(headerRetryAfter); val != "" {
+			if seconds, err2 := strconv.Atoi(val); err2 == nil {
+				retryAfter = seconds
+			}
+		}
+		return exporterhelper.NewThrottleRetry(formattedErr, time.Duration(retryAfter)*time.Second)
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode <= 499 {
+		// The points are rejected, or the database does not exist or cannot be written
+		// with the credentials, writing them again would fail the same.
+		return consumererror.Permanent(formattedErr)
+	}
+	return formattedErr
+}
+
+// readError returns the error message of a response of the InfluxDB API, of the form
+// {"error": "<message>"}, or the body of the response if it is not.
+func readError(resp *http.Response) string {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseReadBytes))
+	if err != nil {
+		return ""
+	}
+	var influxErr struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &influxErr); err == nil && influxErr.Error != "" {
+		return influxErr.Error
+	}
+	return strings.TrimSpace(string(body))
+}
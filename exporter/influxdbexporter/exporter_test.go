@@ -0,0 +1,261 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/receiver/influxdbreceiver"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+// request is a write request received by the test server.
+type request struct {
+	url     string
+	headers http.Header
+	body    string
+}
+
+// newTestServer answers the write requests with the given status and body.
+func newTestServer(t *testing.T, status int, body string) (*httptest.Server, chan request) {
+	requests := make(chan request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reader := r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gr, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			reader = gr
+		}
+		data, err := ioutil.ReadAll(reader)
+		require.NoError(t, err)
+		requests <- request{url: r.URL.String(), headers: r.Header, body: string(data)}
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func newTestExporter(t *testing.T, endpoint string, modify func(cfg *Config)) *influxDBExporter {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint
+	cfg.Database = "telegraf"
+	if modify != nil {
+		modify(cfg)
+	}
+	e, err := newInfluxDBExporter(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return e
+}
+
+func newTestGauge() pdata.Metrics {
+	md, metrics := newTestMetrics()
+	gauge := newTestMetric(metrics, "temperature", pdata.MetricDataTypeDoubleGauge)
+	gauge.DoubleGauge().InitEmpty()
+	gauge.DoubleGauge().DataPoints().Resize(1)
+	gauge.DoubleGauge().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"room": "kitchen"})
+	gauge.DoubleGauge().DataPoints().At(0).SetTimestamp(testTimestamp)
+	gauge.DoubleGauge().DataPoints().At(0).SetValue(21.5)
+	return md
+}
+
+func TestNewInfluxDBExporter_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "empty_endpoint",
+			modify:      func(cfg *Config) { cfg.Endpoint = "" },
+			errorString: "endpoint must be specified",
+		},
+		{
+			name:        "invalid_endpoint",
+			modify:      func(cfg *Config) { cfg.Endpoint = "http://local host:8086" },
+			errorString: "endpoint must be a valid URL",
+		},
+		{
+			name:        "empty_database",
+			modify:      func(cfg *Config) { cfg.Database = "" },
+			errorString: "database must be specified",
+		},
+		{
+			name:        "invalid_compression",
+			modify:      func(cfg *Config) { cfg.Compression = "snappy" },
+			errorString: `invalid compression "snappy", must be "gzip" or "none"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Endpoint = "http://localhost:8086"
+			cfg.Database = "telegraf"
+			tc.modify(cfg)
+			e, err := newInfluxDBExporter(zap.NewNop(), cfg)
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, e)
+		})
+	}
+}
+
+func TestInfluxDBExporter_Write(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusNoContent, "")
+	e := newTestExporter(t, server.URL+"/influx/", func(cfg *Config) {
+		cfg.RetentionPolicy = "one_week"
+		cfg.Headers = map[string]string{"Authorization": "Token secret"}
+	})
+
+	dropped, err := e.pushMetrics(context.Background(), newTestGauge())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	req := <-requests
+	assert.Equal(t, "/influx/write?db=telegraf&precision=ns&rp=one_week", req.url)
+	assert.Equal(t, "gzip", req.headers.Get("Content-Encoding"))
+	assert.Equal(t, "text/plain; charset=utf-8", req.headers.Get("Content-Type"))
+	assert.Equal(t, "Token secret", req.headers.Get("Authorization"))
+	assert.Equal(t, "temperature,host.name=server01,pid=1234,room=kitchen value=21.5 1604577600000000000\n", req.body)
+}
+
+func TestInfluxDBExporter_NoCompression(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusNoContent, "")
+	e := newTestExporter(t, server.URL, func(cfg *Config) {
+		cfg.Compression = NoCompression
+	})
+
+	_, err := e.pushMetrics(context.Background(), newTestGauge())
+	require.NoError(t, err)
+
+	req := <-requests
+	assert.Equal(t, "/write?db=telegraf&precision=ns", req.url)
+	assert.Equal(t, "", req.headers.Get("Content-Encoding"))
+	assert.Equal(t, "temperature,host.name=server01,pid=1234,room=kitchen value=21.5 1604577600000000000\n", req.body)
+}
+
+func TestInfluxDBExporter_NothingToWrite(t *testing.T) {
+	server, requests := newTestServer(t, http.StatusNoContent, "")
+	e := newTestExporter(t, server.URL, nil)
+
+	md, metrics := newTestMetrics()
+	newTestMetric(metrics, "unknown", pdata.MetricDataTypeNone)
+	dropped, err := e.pushMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, requests, 0)
+}
+
+func TestInfluxDBExporter_Errors(t *testing.T) {
+	testcases := []struct {
+		name        string
+		status      int
+		body        string
+		permanent   bool
+		throttle    bool
+		errorString string
+	}{
+		{
+			name:        "bad_request",
+			status:      http.StatusBadRequest,
+			body:        `{"error":"partial write: field type conflict"}`,
+			permanent:   true,
+			errorString: "responded with HTTP Status Code 400, Message=partial write: field type conflict",
+		},
+		{
+			name:        "database_not_found",
+			status:      http.StatusNotFound,
+			body:        `{"error":"database not found: \"telegraf\""}`,
+			permanent:   true,
+			errorString: `responded with HTTP Status Code 404, Message=database not found: "telegraf"`,
+		},
+		{
+			name:        "too_many_requests",
+			status:      http.StatusTooManyRequests,
+			throttle:    true,
+			errorString: "responded with HTTP Status Code 429",
+		},
+		{
+			name:        "server_error",
+			status:      http.StatusInternalServerError,
+			body:        "timeout\n",
+			errorString: "responded with HTTP Status Code 500, Message=timeout",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, _ := newTestServer(t, tc.status, tc.body)
+			e := newTestExporter(t, server.URL, nil)
+
+			md := newTestGauge()
+			dropped, err := e.pushMetrics(context.Background(), md)
+			require.Error(t, err)
+			assert.Equal(t, md.MetricCount(), dropped)
+			assert.Contains(t, err.Error(), tc.errorString)
+			assert.Equal(t, tc.permanent, consumererror.IsPermanent(err))
+			if tc.throttle {
+				expected := errors.New("error writing points, request to " + server.URL + "/write?db=telegraf&precision=ns responded with HTTP Status Code 429")
+				assert.Equal(t, exporterhelper.NewThrottleRetry(expected, 30*time.Second), err)
+			}
+		})
+	}
+}
+
+func TestInfluxDBExporter_ConnectionError(t *testing.T) {
+	e := newTestExporter(t, "http://"+testutil.GetAvailableLocalAddress(t), nil)
+	_, err := e.pushMetrics(context.Background(), newTestGauge())
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+}
+
+func TestInfluxDBExporter_RoundTrip(t *testing.T) {
+	rcfg := influxdbreceiver.NewFactory().CreateDefaultConfig().(*influxdbreceiver.Config)
+	rcfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.MetricsSink)
+	r, err := influxdbreceiver.NewFactory().CreateMetricsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, rcfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+
+	e := newTestExporter(t, "http://"+rcfg.Endpoint, nil)
+	_, err = e.pushMetrics(context.Background(), newTestGauge())
+	require.NoError(t, err)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	metrics := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, "temperature", metrics.At(0).Name())
+	point := metrics.At(0).DoubleGauge().DataPoints().At(0)
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"host.name": "server01", "pid": "1234", "room": "kitchen"}).Sort(), point.LabelsMap().Sort())
+	assert.Equal(t, testTimestamp, point.Timestamp())
+	assert.Equal(t, 21.5, point.Value())
+}
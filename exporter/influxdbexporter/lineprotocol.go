@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// The fields of the points written for each type of metric.
+const (
+	valueField = "value"
+	countField = "count"
+	sumField   = "sum"
+	infBound   = "+Inf"
+)
+
+// The escapers of the measurements, and of the tag keys, tag values and field keys. The
+// line breaks cannot be escaped in the line protocol, they are replaced with spaces.
+var (
+	measurementEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, " ", `\ `, "\n", `\ `, "\r", `\ `)
+	keyEscaper         = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `, "\n", `\ `, "\r", `\ `)
+)
+
+// lineField is a field with its value formatted.
+type lineField struct {
+	key   string
+	value string
+}
+
+// linesWriter writes the data points of metrics as lines of the line protocol, a point
+// per data point. The measurement of the points is the name of the metric and their tags
+// are the resource attributes and the labels of the data point. Their fields are:
+//   - a value field for the gauges and the sums,
+//   - a count and a sum fields for the histograms, and a field per bucket named by its
+//     upper bound, either a number or +Inf, with the cumulative count of the bucket,
+//   - a count and a sum fields for the summaries, and a field per quantile named by the
+//     quantile.
+type linesWriter struct {
+	buf *bytes.Buffer
+	// resourceTags are the tags of the resource of the metrics being written.
+	resourceTags map[string]string
+}
+
+// writeMetrics writes the metrics and returns the number of metrics dropped because of
+// their unknown type.
+func writeMetrics(buf *bytes.Buffer, md pdata.Metrics) int {
+	w := &linesWriter{buf: buf}
+	dropped := 0
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		w.resourceTags = map[string]string{}
+		rm.Resource().Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+			w.resourceTags[k] = tracetranslator.AttributeValueToString(v, false)
+		})
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+				if !w.writeMetric(metric) {
+					dropped++
+				}
+			}
+		}
+	}
+	return dropped
+}
+
+func (w *linesWriter) writeMetric(metric pdata.Metric) bool {
+	name := metric.Name()
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		w.writeIntDataPoints(name, metric.IntGauge().DataPoints())
+	case pdata.MetricDataTypeDoubleGauge:
+		w.writeDoubleDataPoints(name, metric.DoubleGauge().DataPoints())
+	case pdata.MetricDataTypeIntSum:
+		w.writeIntDataPoints(name, metric.IntSum().DataPoints())
+	case pdata.MetricDataTypeDoubleSum:
+		w.writeDoubleDataPoints(name, metric.DoubleSum().DataPoints())
+	case pdata.MetricDataTypeIntHistogram:
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.IsNil() {
+				continue
+			}
+			fields := histogramFields(dp.Count(), float64(dp.Sum()), dp.ExplicitBounds(), dp.BucketCounts())
+			w.writeLine(name, dp.LabelsMap(), fields, dp.Timestamp())
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.IsNil() {
+				continue
+			}
+			fields := histogramFields(dp.Count(), dp.Sum(), dp.ExplicitBounds(), dp.BucketCounts())
+			w.writeLine(name, dp.LabelsMap(), fields, dp.Timestamp())
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		dps := metric.DoubleSummary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.IsNil() {
+				continue
+			}
+			fields := []lineField{{key: countField, value: formatFloat(float64(dp.Count()))}}
+			fields = appendFloatField(fields, sumField, dp.Sum())
+			quantiles := dp.QuantileValues()
+			for j := 0; j < quantiles.Len(); j++ {
+				q := quantiles.At(j)
+				if !q.IsNil() {
+					fields = appendFloatField(fields, strconv.FormatFloat(q.Quantile(), 'f', -1, 64), q.Value())
+				}
+			}
+			w.writeLine(name, dp.LabelsMap(), fields, dp.Timestamp())
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (w *linesWriter) writeIntDataPoints(name string, dps pdata.IntDataPointSlice) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		fields := []lineField{{key: valueField, value: strconv.FormatInt(dp.Value(), 10) + "i"}}
+		w.writeLine(name, dp.LabelsMap(), fields, dp.Timestamp())
+	}
+}
+
+func (w *linesWriter) writeDoubleDataPoints(name string, dps pdata.DoubleDataPointSlice) {
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		w.writeLine(name, dp.LabelsMap(), appendFloatField(nil, valueField, dp.Value()), dp.Timestamp())
+	}
+}
+
+// histogramFields returns the fields of a histogram, the bucket counts are converted to
+// cumulative counts.
+func histogramFields(count uint64, sum float64, bounds []float64, bucketCounts []uint64) []lineField {
+	fields := []lineField{{key: countField, value: formatFloat(float64(count))}}
+	fields = appendFloatField(fields, sumField, sum)
+	var cumulative uint64
+	for i, bound := range bounds {
+		if i >= len(bucketCounts) {
+			break
+		}
+		cumulative += bucketCounts[i]
+		fields = append(fields, lineField{key: strconv.FormatFloat(bound, 'f', -1, 64), value: formatFloat(float64(cumulative))})
+	}
+	return append(fields, lineField{key: infBound, value: formatFloat(float64(count))})
+}
+
+// appendFloatField appends a float field, unless its value is not finite as it cannot be
+// written.
+func appendFloatField(fields []lineField, key string, value float64) []lineField {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fields
+	}
+	return append(fields, lineField{key: key, value: formatFloat(value)})
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// writeLine writes a point, the points without fields are skipped. The tags are sorted
+// by key, the labels override the resource attributes with the same key, and the empty
+// tags are skipped as InfluxDB rejects them.
+func (w *linesWriter) writeLine(measurement string, labels pdata.StringMap, fields []lineField, timestamp pdata.TimestampUnixNano) {
+	if len(fields) == 0 || measurement == "" {
+		return
+	}
+	tags := make(map[string]string, len(w.resourceTags)+labels.Len())
+	for k, v := range w.resourceTags {
+		tags[k] = v
+	}
+	labels.ForEach(func(k string, v string) {
+		tags[k] = v
+	})
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if k != "" && v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	w.buf.WriteString(measurementEscaper.Replace(measurement))
+	for _, k := range keys {
+		w.buf.WriteByte(',')
+		w.buf.WriteString(keyEscaper.Replace(k))
+		w.buf.WriteByte('=')
+		w.buf.WriteString(keyEscaper.Replace(tags[k]))
+	}
+	for i, f := range fields {
+		if i == 0 {
+			w.buf.WriteByte(' ')
+		} else {
+			w.buf.WriteByte(',')
+		}
+		w.buf.WriteString(keyEscaper.Replace(f.key))
+		w.buf.WriteByte('=')
+		w.buf.WriteString(f.value)
+	}
+	if timestamp != 0 {
+		w.buf.WriteByte(' ')
+		w.buf.WriteString(strconv.FormatUint(uint64(timestamp), 10))
+	}
+	w.buf.WriteByte('\n')
+}
@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbexporter
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const testTimestamp = pdata.TimestampUnixNano(1604577600000000000)
+
+func newTestMetric(metrics pdata.MetricSlice, name string, dataType pdata.MetricDataType) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	metrics.Append(metric)
+	return metric
+}
+
+func newTestMetrics() (pdata.Metrics, pdata.MetricSlice) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().Attributes().InsertString("host.name", "server01")
+	rm.Resource().Attributes().InsertInt("pid", 1234)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	return md, rm.InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func TestWriteMetrics(t *testing.T) {
+	md, metrics := newTestMetrics()
+
+	gauge := newTestMetric(metrics, "temperature", pdata.MetricDataTypeDoubleGauge)
+	gauge.DoubleGauge().InitEmpty()
+	gauge.DoubleGauge().DataPoints().Resize(2)
+	gauge.DoubleGauge().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"room": "kitchen", "host.name": "sensor"})
+	gauge.DoubleGauge().DataPoints().At(0).SetTimestamp(testTimestamp)
+	gauge.DoubleGauge().DataPoints().At(0).SetValue(21.5)
+	// The data points without a finite value are dropped.
+	gauge.DoubleGauge().DataPoints().At(1).SetValue(math.NaN())
+
+	sum := newTestMetric(metrics, "requests", pdata.MetricDataTypeIntSum)
+	sum.IntSum().InitEmpty()
+	sum.IntSum().DataPoints().Resize(1)
+	sum.IntSum().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"code": "200", "empty": ""})
+	sum.IntSum().DataPoints().At(0).SetTimestamp(testTimestamp)
+	sum.IntSum().DataPoints().At(0).SetValue(42)
+
+	intGauge := newTestMetric(metrics, "threads", pdata.MetricDataTypeIntGauge)
+	intGauge.IntGauge().InitEmpty()
+	intGauge.IntGauge().DataPoints().Resize(1)
+	intGauge.IntGauge().DataPoints().At(0).SetValue(-3)
+
+	doubleSum := newTestMetric(metrics, "cpu time", pdata.MetricDataTypeDoubleSum)
+	doubleSum.DoubleSum().InitEmpty()
+	doubleSum.DoubleSum().DataPoints().Resize(1)
+	doubleSum.DoubleSum().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"state,mode": "user=1 2"})
+	doubleSum.DoubleSum().DataPoints().At(0).SetTimestamp(testTimestamp)
+	doubleSum.DoubleSum().DataPoints().At(0).SetValue(1e21)
+
+	histogram := newTestMetric(metrics, "latency", pdata.MetricDataTypeDoubleHistogram)
+	histogram.DoubleHistogram().InitEmpty()
+	histogram.DoubleHistogram().DataPoints().Resize(1)
+	hdp := histogram.DoubleHistogram().DataPoints().At(0)
+	hdp.SetTimestamp(testTimestamp)
+	hdp.SetCount(10)
+	hdp.SetSum(1234.5)
+	hdp.SetExplicitBounds([]float64{0.5, 100})
+	hdp.SetBucketCounts([]uint64{2, 5, 3})
+
+	intHistogram := newTestMetric(metrics, "size", pdata.MetricDataTypeIntHistogram)
+	intHistogram.IntHistogram().InitEmpty()
+	intHistogram.IntHistogram().DataPoints().Resize(1)
+	ihdp := intHistogram.IntHistogram().DataPoints().At(0)
+	ihdp.SetTimestamp(testTimestamp)
+	ihdp.SetCount(3)
+	ihdp.SetSum(30)
+	ihdp.SetExplicitBounds([]float64{10})
+	ihdp.SetBucketCounts([]uint64{1, 2})
+
+	summary := newTestMetric(metrics, "rpc_duration", pdata.MetricDataTypeDoubleSummary)
+	summary.DoubleSummary().InitEmpty()
+	summary.DoubleSummary().DataPoints().Resize(1)
+	sdp := summary.DoubleSummary().DataPoints().At(0)
+	sdp.SetTimestamp(testTimestamp)
+	sdp.SetCount(25)
+	sdp.SetSum(100)
+	sdp.QuantileValues().Resize(2)
+	sdp.QuantileValues().At(0).SetQuantile(0.5)
+	sdp.QuantileValues().At(0).SetValue(3)
+	sdp.QuantileValues().At(1).SetQuantile(0.99)
+	sdp.QuantileValues().At(1).SetValue(9)
+
+	newTestMetric(metrics, "unknown", pdata.MetricDataTypeNone)
+
+	var buf bytes.Buffer
+	assert.Equal(t, 1, writeMetrics(&buf, md))
+	assert.Equal(t, `temperature,host.name=sensor,pid=1234,room=kitchen value=21.5 1604577600000000000
+requests,code=200,host.name=server01,pid=1234 value=42i 1604577600000000000
+threads,host.name=server01,pid=1234 value=-3i
+cpu\ time,host.name=server01,pid=1234,state\,mode=user\=1\ 2 value=1e+21 1604577600000000000
+latency,host.name=server01,pid=1234 count=10,sum=1234.5,0.5=2,100=7,+Inf=10 1604577600000000000
+size,host.name=server01,pid=1234 count=3,sum=30,10=1,+Inf=3 1604577600000000000
+rpc_duration,host.name=server01,pid=1234 count=25,sum=100,0.5=3,0.99=9 1604577600000000000
+`, buf.String())
+}
+
+func TestWriteMetrics_Escaping(t *testing.T) {
+	md, metrics := newTestMetrics()
+	gauge := newTestMetric(metrics, "disk\\free\nspace", pdata.MetricDataTypeIntGauge)
+	gauge.IntGauge().InitEmpty()
+	gauge.IntGauge().DataPoints().Resize(1)
+	gauge.IntGauge().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{
+		"path":          `C:\Data\`,
+		"line\r\nbreak": "first\nsecond",
+		`back\slash`:    "value",
+	})
+	gauge.IntGauge().DataPoints().At(0).SetTimestamp(testTimestamp)
+	gauge.IntGauge().DataPoints().At(0).SetValue(5)
+
+	var buf bytes.Buffer
+	assert.Equal(t, 0, writeMetrics(&buf, md))
+	assert.Equal(t, `disk\\free\ space,back\\slash=value,host.name=server01,line\ \ break=first\ second,path=C:\\Data\\,pid=1234 value=5i 1604577600000000000
+`, buf.String())
+}
+
+func TestWriteMetrics_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Equal(t, 0, writeMetrics(&buf, pdata.NewMetrics()))
+
+	md, metrics := newTestMetrics()
+	metrics.Resize(1)
+	metrics.At(0).SetName("empty")
+	metrics.At(0).SetDataType(pdata.MetricDataTypeDoubleGauge)
+	metrics.At(0).DoubleGauge().InitEmpty()
+	md.ResourceMetrics().Append(pdata.NewResourceMetrics())
+	assert.Equal(t, 0, writeMetrics(&buf, md))
+	assert.Equal(t, 0, buf.Len())
+}
@@ -32,10 +32,18 @@ type Config struct {
 	Brokers []string `mapstructure:"brokers"`
 	// Kafka protocol version
 	ProtocolVersion string `mapstructure:"protocol_version"`
-	// The name of the kafka topic to export to (default "otlp_spans")
+	// The name of the kafka topic to export to (default "otlp_spans" for traces,
+	// "otlp_metrics" for metrics and "otlp_logs" for logs)
 	Topic string `mapstructure:"topic"`
 	// Encoding of the messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
+	// PartitionTracesByID sends the spans of every trace in separate messages keyed by the trace ID,
+	// so that all the spans of a trace are sent to the same partition (default false).
+	PartitionTracesByID bool `mapstructure:"partition_traces_by_id"`
+	// PartitionByResourceAttribute is the name of the resource attribute used to key the metrics and
+	// logs messages, so that the data of a resource is sent to the same partition (e.g. "service.name").
+	// By default the messages are not keyed.
+	PartitionByResourceAttribute string `mapstructure:"partition_by_resource_attribute"`
 
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
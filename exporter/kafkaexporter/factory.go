@@ -24,10 +24,13 @@ import (
 )
 
 const (
-	typeStr         = "kafka"
-	defaultTopic    = "otlp_spans"
-	defaultEncoding = "otlp_proto"
-	defaultBroker   = "localhost:9092"
+	typeStr = "kafka"
+	// default topics, used when the topic is not configured, one for each signal.
+	defaultTracesTopic  = "otlp_spans"
+	defaultMetricsTopic = "otlp_metrics"
+	defaultLogsTopic    = "otlp_logs"
+	defaultEncoding     = "otlp_proto"
+	defaultBroker       = "localhost:9092"
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
 	// default from sarama.NewConfig()
@@ -39,7 +42,7 @@ const (
 // FactoryOption applies changes to kafkaExporterFactory.
 type FactoryOption func(factory *kafkaExporterFactory)
 
-// WithAddMarshallers adds marshallers.
+// WithAddMarshallers adds traces marshallers.
 func WithAddMarshallers(encodingMarshaller map[string]Marshaller) FactoryOption {
 	return func(factory *kafkaExporterFactory) {
 		for encoding, marshaller := range encodingMarshaller {
@@ -48,10 +51,30 @@ func WithAddMarshallers(encodingMarshaller map[string]Marshaller) FactoryOption
 	}
 }
 
+// WithAddMetricsMarshallers adds metrics marshallers.
+func WithAddMetricsMarshallers(encodingMarshaller map[string]MetricsMarshaller) FactoryOption {
+	return func(factory *kafkaExporterFactory) {
+		for encoding, marshaller := range encodingMarshaller {
+			factory.metricsMarshallers[encoding] = marshaller
+		}
+	}
+}
+
+// WithAddLogsMarshallers adds logs marshallers.
+func WithAddLogsMarshallers(encodingMarshaller map[string]LogsMarshaller) FactoryOption {
+	return func(factory *kafkaExporterFactory) {
+		for encoding, marshaller := range encodingMarshaller {
+			factory.logsMarshallers[encoding] = marshaller
+		}
+	}
+}
+
 // NewFactory creates Kafka exporter factory.
 func NewFactory(options ...FactoryOption) component.ExporterFactory {
 	f := &kafkaExporterFactory{
-		marshallers: defaultMarshallers(),
+		marshallers:        defaultMarshallers(),
+		metricsMarshallers: defaultMetricsMarshallers(),
+		logsMarshallers:    defaultLogsMarshallers(),
 	}
 	for _, o := range options {
 		o(f)
@@ -59,7 +82,9 @@ func NewFactory(options ...FactoryOption) component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithTraces(f.createTraceExporter))
+		exporterhelper.WithTraces(f.createTraceExporter),
+		exporterhelper.WithMetrics(f.createMetricsExporter),
+		exporterhelper.WithLogs(f.createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -72,8 +97,8 @@ func createDefaultConfig() configmodels.Exporter {
 		RetrySettings:   exporterhelper.CreateDefaultRetrySettings(),
 		QueueSettings:   exporterhelper.CreateDefaultQueueSettings(),
 		Brokers:         []string{defaultBroker},
-		Topic:           defaultTopic,
-		Encoding:        defaultEncoding,
+		// Topic is left empty, so that the default topic of each signal is used.
+		Encoding: defaultEncoding,
 		Metadata: Metadata{
 			Full: defaultMetadataFull,
 			Retry: MetadataRetry{
@@ -85,7 +110,9 @@ func createDefaultConfig() configmodels.Exporter {
 }
 
 type kafkaExporterFactory struct {
-	marshallers map[string]Marshaller
+	marshallers        map[string]Marshaller
+	metricsMarshallers map[string]MetricsMarshaller
+	logsMarshallers    map[string]LogsMarshaller
 }
 
 func (f *kafkaExporterFactory) createTraceExporter(
@@ -94,7 +121,7 @@ func (f *kafkaExporterFactory) createTraceExporter(
 	cfg configmodels.Exporter,
 ) (component.TracesExporter, error) {
 	oCfg := cfg.(*Config)
-	exp, err := newExporter(*oCfg, params, f.marshallers)
+	exp, err := newTracesExporter(withDefaultTopic(*oCfg, defaultTracesTopic), params, f.marshallers)
 	if err != nil {
 		return nil, err
 	}
@@ -109,3 +136,56 @@ func (f *kafkaExporterFactory) createTraceExporter(
 		exporterhelper.WithQueue(oCfg.QueueSettings),
 		exporterhelper.WithShutdown(exp.Close))
 }
+
+func (f *kafkaExporterFactory) createMetricsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.MetricsExporter, error) {
+	oCfg := cfg.(*Config)
+	exp, err := newMetricsExporter(withDefaultTopic(*oCfg, defaultMetricsTopic), params, f.metricsMarshallers)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewMetricsExporter(
+		cfg,
+		params.Logger,
+		exp.metricsDataPusher,
+		// Disable exporterhelper Timeout, because we cannot pass a Context to the Producer,
+		// and will rely on the sarama Producer Timeout logic.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+		exporterhelper.WithRetry(oCfg.RetrySettings),
+		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithShutdown(exp.Close))
+}
+
+func (f *kafkaExporterFactory) createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.LogsExporter, error) {
+	oCfg := cfg.(*Config)
+	exp, err := newLogsExporter(withDefaultTopic(*oCfg, defaultLogsTopic), params, f.logsMarshallers)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewLogsExporter(
+		cfg,
+		params.Logger,
+		exp.logsDataPusher,
+		// Disable exporterhelper Timeout, because we cannot pass a Context to the Producer,
+		// and will rely on the sarama Producer Timeout logic.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+		exporterhelper.WithRetry(oCfg.RetrySettings),
+		exporterhelper.WithQueue(oCfg.QueueSettings),
+		exporterhelper.WithShutdown(exp.Close))
+}
+
+// withDefaultTopic returns a copy of the config using the given topic if none was configured.
+// The config is copied because the same config can be used by pipelines of different signals.
+func withDefaultTopic(cfg Config, topic string) Config {
+	if cfg.Topic == "" {
+		cfg.Topic = topic
+	}
+	return cfg
+}
@@ -32,7 +32,7 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NotNil(t, cfg, "failed to create default config")
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 	assert.Equal(t, []string{defaultBroker}, cfg.Brokers)
-	assert.Equal(t, defaultTopic, cfg.Topic)
+	assert.Empty(t, cfg.Topic)
 }
 
 func TestCreateTracesExporter(t *testing.T) {
@@ -47,6 +47,42 @@ func TestCreateTracesExporter(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
+func TestCreateMetricsExporter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	cfg.Metadata.Full = false
+	f := kafkaExporterFactory{metricsMarshallers: defaultMetricsMarshallers()}
+	r, err := f.createMetricsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestCreateLogsExporter(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	// this disables contacting the broker so we can successfully create the exporter
+	cfg.Metadata.Full = false
+	f := kafkaExporterFactory{logsMarshallers: defaultLogsMarshallers()}
+	r, err := f.createLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
+
+func TestWithDefaultTopic(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	assert.Equal(t, defaultTracesTopic, withDefaultTopic(*cfg, defaultTracesTopic).Topic)
+	assert.Equal(t, defaultMetricsTopic, withDefaultTopic(*cfg, defaultMetricsTopic).Topic)
+	assert.Equal(t, defaultLogsTopic, withDefaultTopic(*cfg, defaultLogsTopic).Topic)
+	// The shared config must not be modified.
+	assert.Empty(t, cfg.Topic)
+
+	cfg.Topic = "custom"
+	assert.Equal(t, "custom", withDefaultTopic(*cfg, defaultMetricsTopic).Topic)
+}
+
 func TestCreateTracesExporter_err(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
 	cfg.Brokers = []string{"invalid:9092"}
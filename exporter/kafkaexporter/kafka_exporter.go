@@ -28,21 +28,133 @@ import (
 
 var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 
-// kafkaProducer uses sarama to produce messages to Kafka.
-type kafkaProducer struct {
-	producer   sarama.SyncProducer
-	topic      string
-	marshaller Marshaller
-	logger     *zap.Logger
+// kafkaTracesProducer uses sarama to produce trace messages to Kafka.
+type kafkaTracesProducer struct {
+	producer            sarama.SyncProducer
+	topic               string
+	marshaller          Marshaller
+	partitionTracesByID bool
+	logger              *zap.Logger
 }
 
-// newExporter creates Kafka exporter.
-func newExporter(config Config, params component.ExporterCreateParams, marshallers map[string]Marshaller) (*kafkaProducer, error) {
-	marshaller := marshallers[config.Encoding]
-	if marshaller == nil {
-		return nil, errUnrecognizedEncoding
+func (e *kafkaTracesProducer) traceDataPusher(_ context.Context, td pdata.Traces) (int, error) {
+	messages, err := e.marshal(td)
+	if err != nil {
+		return td.SpanCount(), consumererror.Permanent(err)
+	}
+	err = e.producer.SendMessages(producerMessages(messages, e.topic))
+	if err != nil {
+		return td.SpanCount(), err
+	}
+	return 0, nil
+}
+
+// marshal serializes the traces, when partitioning by trace ID the spans of every trace are
+// serialized separately and the messages are keyed by the trace ID.
+func (e *kafkaTracesProducer) marshal(td pdata.Traces) ([]Message, error) {
+	if !e.partitionTracesByID {
+		return e.marshaller.Marshal(td)
+	}
+	var messages []Message
+	for _, batch := range splitTracesByTraceID(td) {
+		batchMessages, err := e.marshaller.Marshal(batch.traces)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, withKey(batchMessages, batch.key)...)
+	}
+	return messages, nil
+}
+
+func (e *kafkaTracesProducer) Close(context.Context) error {
+	return e.producer.Close()
+}
+
+// kafkaMetricsProducer uses sarama to produce metric messages to Kafka.
+type kafkaMetricsProducer struct {
+	producer                     sarama.SyncProducer
+	topic                        string
+	marshaller                   MetricsMarshaller
+	partitionByResourceAttribute string
+	logger                       *zap.Logger
+}
+
+func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pdata.Metrics) (int, error) {
+	messages, err := e.marshal(md)
+	if err != nil {
+		return md.MetricCount(), consumererror.Permanent(err)
+	}
+	err = e.producer.SendMessages(producerMessages(messages, e.topic))
+	if err != nil {
+		return md.MetricCount(), err
+	}
+	return 0, nil
+}
+
+// marshal serializes the metrics, when partitioning by a resource attribute the metrics of every
+// attribute value are serialized separately and the messages are keyed by the attribute value.
+func (e *kafkaMetricsProducer) marshal(md pdata.Metrics) ([]Message, error) {
+	if e.partitionByResourceAttribute == "" {
+		return e.marshaller.Marshal(md)
+	}
+	var messages []Message
+	for _, batch := range splitMetricsByResourceAttribute(md, e.partitionByResourceAttribute) {
+		batchMessages, err := e.marshaller.Marshal(batch.metrics)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, withKey(batchMessages, batch.key)...)
+	}
+	return messages, nil
+}
+
+func (e *kafkaMetricsProducer) Close(context.Context) error {
+	return e.producer.Close()
+}
+
+// kafkaLogsProducer uses sarama to produce log messages to Kafka.
+type kafkaLogsProducer struct {
+	producer                     sarama.SyncProducer
+	topic                        string
+	marshaller                   LogsMarshaller
+	partitionByResourceAttribute string
+	logger                       *zap.Logger
+}
+
+func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld pdata.Logs) (int, error) {
+	messages, err := e.marshal(ld)
+	if err != nil {
+		return ld.LogRecordCount(), consumererror.Permanent(err)
 	}
+	err = e.producer.SendMessages(producerMessages(messages, e.topic))
+	if err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
+
+// marshal serializes the logs, when partitioning by a resource attribute the logs of every
+// attribute value are serialized separately and the messages are keyed by the attribute value.
+func (e *kafkaLogsProducer) marshal(ld pdata.Logs) ([]Message, error) {
+	if e.partitionByResourceAttribute == "" {
+		return e.marshaller.Marshal(ld)
+	}
+	var messages []Message
+	for _, batch := range splitLogsByResourceAttribute(ld, e.partitionByResourceAttribute) {
+		batchMessages, err := e.marshaller.Marshal(batch.logs)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, withKey(batchMessages, batch.key)...)
+	}
+	return messages, nil
+}
 
+func (e *kafkaLogsProducer) Close(context.Context) error {
+	return e.producer.Close()
+}
+
+func newSaramaProducer(config Config) (sarama.SyncProducer, error) {
 	c := sarama.NewConfig()
 	// These setting are required by the sarama.SyncProducer implementation.
 	c.Producer.Return.Successes = true
@@ -64,32 +176,64 @@ func newExporter(config Config, params component.ExporterCreateParams, marshalle
 	if err := ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	producer, err := sarama.NewSyncProducer(config.Brokers, c)
+	return sarama.NewSyncProducer(config.Brokers, c)
+}
+
+// newTracesExporter creates Kafka exporter for traces.
+func newTracesExporter(config Config, params component.ExporterCreateParams, marshallers map[string]Marshaller) (*kafkaTracesProducer, error) {
+	marshaller := marshallers[config.Encoding]
+	if marshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	producer, err := newSaramaProducer(config)
 	if err != nil {
 		return nil, err
 	}
-	return &kafkaProducer{
-		producer:   producer,
-		topic:      config.Topic,
-		marshaller: marshaller,
-		logger:     params.Logger,
+	return &kafkaTracesProducer{
+		producer:            producer,
+		topic:               config.Topic,
+		marshaller:          marshaller,
+		partitionTracesByID: config.PartitionTracesByID,
+		logger:              params.Logger,
 	}, nil
 }
 
-func (e *kafkaProducer) traceDataPusher(_ context.Context, td pdata.Traces) (int, error) {
-	messages, err := e.marshaller.Marshal(td)
-	if err != nil {
-		return td.SpanCount(), consumererror.Permanent(err)
+// newMetricsExporter creates Kafka exporter for metrics.
+func newMetricsExporter(config Config, params component.ExporterCreateParams, marshallers map[string]MetricsMarshaller) (*kafkaMetricsProducer, error) {
+	marshaller := marshallers[config.Encoding]
+	if marshaller == nil {
+		return nil, errUnrecognizedEncoding
 	}
-	err = e.producer.SendMessages(producerMessages(messages, e.topic))
+	producer, err := newSaramaProducer(config)
 	if err != nil {
-		return td.SpanCount(), err
+		return nil, err
 	}
-	return 0, nil
+	return &kafkaMetricsProducer{
+		producer:                     producer,
+		topic:                        config.Topic,
+		marshaller:                   marshaller,
+		partitionByResourceAttribute: config.PartitionByResourceAttribute,
+		logger:                       params.Logger,
+	}, nil
 }
 
-func (e *kafkaProducer) Close(context.Context) error {
-	return e.producer.Close()
+// newLogsExporter creates Kafka exporter for logs.
+func newLogsExporter(config Config, params component.ExporterCreateParams, marshallers map[string]LogsMarshaller) (*kafkaLogsProducer, error) {
+	marshaller := marshallers[config.Encoding]
+	if marshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	producer, err := newSaramaProducer(config)
+	if err != nil {
+		return nil, err
+	}
+	return &kafkaLogsProducer{
+		producer:                     producer,
+		topic:                        config.Topic,
+		marshaller:                   marshaller,
+		partitionByResourceAttribute: config.PartitionByResourceAttribute,
+		logger:                       params.Logger,
+	}, nil
 }
 
 func producerMessages(messages []Message, topic string) []*sarama.ProducerMessage {
@@ -99,6 +243,20 @@ func producerMessages(messages []Message, topic string) []*sarama.ProducerMessag
 			Topic: topic,
 			Value: sarama.ByteEncoder(messages[i].Value),
 		}
+		// Messages without key are assigned to a random partition.
+		if messages[i].Key != nil {
+			producerMessages[i].Key = sarama.ByteEncoder(messages[i].Key)
+		}
 	}
 	return producerMessages
 }
+
+// withKey sets the key of the messages that do not have one.
+func withKey(messages []Message, key []byte) []Message {
+	for i := range messages {
+		if messages[i].Key == nil {
+			messages[i].Key = key
+		}
+	}
+	return messages
+}
@@ -26,6 +26,7 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/data/testdata"
@@ -33,14 +34,14 @@ import (
 
 func TestNewExporter_err_version(t *testing.T) {
 	c := Config{ProtocolVersion: "0.0.0", Encoding: defaultEncoding}
-	exp, err := newExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMarshallers())
+	exp, err := newTracesExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMarshallers())
 	assert.Error(t, err)
 	assert.Nil(t, exp)
 }
 
 func TestNewExporter_err_encoding(t *testing.T) {
 	c := Config{Encoding: "foo"}
-	exp, err := newExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMarshallers())
+	exp, err := newTracesExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMarshallers())
 	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
 	assert.Nil(t, exp)
 }
@@ -60,7 +61,7 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 			Full: false,
 		},
 	}
-	exp, err := newExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMarshallers())
+	exp, err := newTracesExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMarshallers())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to load TLS config")
 	assert.Nil(t, exp)
@@ -71,7 +72,7 @@ func TestTraceDataPusher(t *testing.T) {
 	producer := mocks.NewSyncProducer(t, c)
 	producer.ExpectSendMessageAndSucceed()
 
-	p := kafkaProducer{
+	p := kafkaTracesProducer{
 		producer:   producer,
 		marshaller: &otlpProtoMarshaller{},
 	}
@@ -89,7 +90,7 @@ func TestTraceDataPusher_err(t *testing.T) {
 	expErr := fmt.Errorf("failed to send")
 	producer.ExpectSendMessageAndFail(expErr)
 
-	p := kafkaProducer{
+	p := kafkaTracesProducer{
 		producer:   producer,
 		marshaller: &otlpProtoMarshaller{},
 		logger:     zap.NewNop(),
@@ -105,7 +106,7 @@ func TestTraceDataPusher_err(t *testing.T) {
 
 func TestTraceDataPusher_marshall_error(t *testing.T) {
 	expErr := fmt.Errorf("failed to marshall")
-	p := kafkaProducer{
+	p := kafkaTracesProducer{
 		marshaller: &errorMarshaller{err: expErr},
 		logger:     zap.NewNop(),
 	}
@@ -116,6 +117,222 @@ func TestTraceDataPusher_marshall_error(t *testing.T) {
 	assert.Equal(t, td.SpanCount(), droppedSpans)
 }
 
+func TestTraceDataPusher_partitionTracesByID(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+	producer.ExpectSendMessageAndSucceed()
+
+	p := kafkaTracesProducer{
+		producer:            producer,
+		marshaller:          &otlpProtoMarshaller{},
+		partitionTracesByID: true,
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).SetTraceID(testTraceIDA)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(1).SetTraceID(testTraceIDB)
+
+	messages, err := p.marshal(td)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, []byte(testTraceIDA.HexString()), messages[0].Key)
+	assert.Equal(t, []byte(testTraceIDB.HexString()), messages[1].Key)
+
+	droppedSpans, err := p.traceDataPusher(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, droppedSpans)
+}
+
+func TestTraceDataPusher_partitionTracesByID_marshall_error(t *testing.T) {
+	expErr := fmt.Errorf("failed to marshall")
+	p := kafkaTracesProducer{
+		marshaller:          &errorMarshaller{err: expErr},
+		partitionTracesByID: true,
+	}
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	droppedSpans, err := p.traceDataPusher(context.Background(), td)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), expErr.Error())
+	assert.Equal(t, td.SpanCount(), droppedSpans)
+}
+
+func TestMetricsProducer_partitionByResourceAttribute(t *testing.T) {
+	p := kafkaMetricsProducer{
+		marshaller:                   &otlpMetricsProtoMarshaller{},
+		partitionByResourceAttribute: "service.name",
+	}
+	md := testdata.GenerateMetricsOneMetric()
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("service.name", "svc")
+	messages, err := p.marshal(md)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, []byte("svc"), messages[0].Key)
+
+	p.partitionByResourceAttribute = ""
+	messages, err = p.marshal(md)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Nil(t, messages[0].Key)
+}
+
+func TestLogsProducer_partitionByResourceAttribute(t *testing.T) {
+	p := kafkaLogsProducer{
+		marshaller:                   &otlpLogsProtoMarshaller{},
+		partitionByResourceAttribute: "service.name",
+	}
+	ld := testdata.GenerateLogDataOneLog()
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("service.name", "svc")
+	messages, err := p.marshal(ld)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, []byte("svc"), messages[0].Key)
+}
+
+func TestProducerMessages(t *testing.T) {
+	messages := producerMessages([]Message{
+		{Value: []byte("no key")},
+		{Key: []byte("key"), Value: []byte("with key")},
+	}, "topic")
+	require.Len(t, messages, 2)
+	assert.Equal(t, "topic", messages[0].Topic)
+	assert.Nil(t, messages[0].Key)
+	assert.Equal(t, sarama.ByteEncoder("no key"), messages[0].Value)
+	assert.Equal(t, sarama.ByteEncoder("key"), messages[1].Key)
+	assert.Equal(t, sarama.ByteEncoder("with key"), messages[1].Value)
+}
+
+func TestNewMetricsExporter_err_encoding(t *testing.T) {
+	c := Config{Encoding: "jaeger_proto"}
+	exp, err := newMetricsExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultMetricsMarshallers())
+	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	assert.Nil(t, exp)
+}
+
+func TestNewLogsExporter_err_encoding(t *testing.T) {
+	c := Config{Encoding: "jaeger_proto"}
+	exp, err := newLogsExporter(c, component.ExporterCreateParams{Logger: zap.NewNop()}, defaultLogsMarshallers())
+	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	assert.Nil(t, exp)
+}
+
+func TestMetricsDataPusher(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		md := pdata.NewMetrics()
+		return md.FromOtlpProtoBytes(val)
+	})
+
+	p := kafkaMetricsProducer{
+		producer:   producer,
+		marshaller: &otlpMetricsProtoMarshaller{},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	dropped, err := p.metricsDataPusher(context.Background(), testdata.GenerateMetricsTwoMetrics())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+}
+
+func TestMetricsDataPusher_err(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	expErr := fmt.Errorf("failed to send")
+	producer.ExpectSendMessageAndFail(expErr)
+
+	p := kafkaMetricsProducer{
+		producer:   producer,
+		marshaller: &otlpMetricsProtoMarshaller{},
+		logger:     zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	md := testdata.GenerateMetricsTwoMetrics()
+	dropped, err := p.metricsDataPusher(context.Background(), md)
+	assert.EqualError(t, err, expErr.Error())
+	assert.Equal(t, md.MetricCount(), dropped)
+}
+
+func TestLogsDataPusher(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		ld := pdata.NewLogs()
+		return ld.FromOtlpProtoBytes(val)
+	})
+
+	p := kafkaLogsProducer{
+		producer:   producer,
+		marshaller: &otlpLogsProtoMarshaller{},
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	dropped, err := p.logsDataPusher(context.Background(), testdata.GenerateLogDataOneLog())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+}
+
+func TestLogsDataPusher_err(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	expErr := fmt.Errorf("failed to send")
+	producer.ExpectSendMessageAndFail(expErr)
+
+	p := kafkaLogsProducer{
+		producer:   producer,
+		marshaller: &otlpLogsProtoMarshaller{},
+		logger:     zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	ld := testdata.GenerateLogDataOneLog()
+	dropped, err := p.logsDataPusher(context.Background(), ld)
+	assert.EqualError(t, err, expErr.Error())
+	assert.Equal(t, ld.LogRecordCount(), dropped)
+}
+
+func TestMetricsExporter_mockBroker(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(defaultMetricsTopic, 0, broker.BrokerID()),
+		// The default sarama version sends v3 produce requests.
+		"ProduceRequest": sarama.NewMockProduceResponse(t).SetVersion(3),
+	})
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{broker.Addr()}
+	// Send synchronously, so that the produce request is received when ConsumeMetrics returns.
+	cfg.QueueSettings.Enabled = false
+	cfg.RetrySettings.Enabled = false
+	f := kafkaExporterFactory{metricsMarshallers: defaultMetricsMarshallers()}
+	exp, err := f.createMetricsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, exp.Shutdown(context.Background()))
+	}()
+
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), testdata.GenerateMetricsTwoMetrics()))
+	produced := 0
+	for _, req := range broker.History() {
+		if produceReq, ok := req.Request.(*sarama.ProduceRequest); ok {
+			produced++
+			assert.NotNil(t, produceReq)
+		}
+	}
+	assert.Equal(t, 1, produced)
+}
+
 type errorMarshaller struct {
 	err error
 }
@@ -27,8 +27,29 @@ type Marshaller interface {
 	Encoding() string
 }
 
-// Message encapsulates Kafka's message payload.
+// MetricsMarshaller marshals metrics into Message array.
+type MetricsMarshaller interface {
+	// Marshal serializes metrics into Messages
+	Marshal(metrics pdata.Metrics) ([]Message, error)
+
+	// Encoding returns encoding name
+	Encoding() string
+}
+
+// LogsMarshaller marshals logs into Message array.
+type LogsMarshaller interface {
+	// Marshal serializes logs into Messages
+	Marshal(logs pdata.Logs) ([]Message, error)
+
+	// Encoding returns encoding name
+	Encoding() string
+}
+
+// Message encapsulates Kafka's message payload and key.
 type Message struct {
+	// Key is used by kafka to assign the message to a partition, messages with the same key are
+	// sent to the same partition. Messages without key are assigned to a random partition.
+	Key   []byte
 	Value []byte
 }
 
@@ -43,3 +64,19 @@ func defaultMarshallers() map[string]Marshaller {
 		jaegerJSON.Encoding():  jaegerJSON,
 	}
 }
+
+// defaultMetricsMarshallers returns map of supported encodings with MetricsMarshaller.
+func defaultMetricsMarshallers() map[string]MetricsMarshaller {
+	otlp := &otlpMetricsProtoMarshaller{}
+	return map[string]MetricsMarshaller{
+		otlp.Encoding(): otlp,
+	}
+}
+
+// defaultLogsMarshallers returns map of supported encodings with LogsMarshaller.
+func defaultLogsMarshallers() map[string]LogsMarshaller {
+	otlp := &otlpLogsProtoMarshaller{}
+	return map[string]LogsMarshaller{
+		otlp.Encoding(): otlp,
+	}
+}
@@ -37,3 +37,19 @@ func TestDefaultMarshallers(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultMetricsMarshallers(t *testing.T) {
+	marshallers := defaultMetricsMarshallers()
+	assert.Equal(t, 1, len(marshallers))
+	m, ok := marshallers["otlp_proto"]
+	require.True(t, ok)
+	assert.NotNil(t, m)
+}
+
+func TestDefaultLogsMarshallers(t *testing.T) {
+	marshallers := defaultLogsMarshallers()
+	assert.Equal(t, 1, len(marshallers))
+	m, ok := marshallers["otlp_proto"]
+	require.True(t, ok)
+	assert.NotNil(t, m)
+}
@@ -38,3 +38,37 @@ func (m *otlpProtoMarshaller) Marshal(traces pdata.Traces) ([]Message, error) {
 	}
 	return []Message{{Value: bts}}, nil
 }
+
+type otlpMetricsProtoMarshaller struct {
+}
+
+var _ MetricsMarshaller = (*otlpMetricsProtoMarshaller)(nil)
+
+func (m *otlpMetricsProtoMarshaller) Encoding() string {
+	return defaultEncoding
+}
+
+func (m *otlpMetricsProtoMarshaller) Marshal(metrics pdata.Metrics) ([]Message, error) {
+	bts, err := metrics.ToOtlpProtoBytes()
+	if err != nil {
+		return nil, err
+	}
+	return []Message{{Value: bts}}, nil
+}
+
+type otlpLogsProtoMarshaller struct {
+}
+
+var _ LogsMarshaller = (*otlpLogsProtoMarshaller)(nil)
+
+func (m *otlpLogsProtoMarshaller) Encoding() string {
+	return defaultEncoding
+}
+
+func (m *otlpLogsProtoMarshaller) Marshal(logs pdata.Logs) ([]Message, error) {
+	bts, err := logs.ToOtlpProtoBytes()
+	if err != nil {
+		return nil, err
+	}
+	return []Message{{Value: bts}}, nil
+}
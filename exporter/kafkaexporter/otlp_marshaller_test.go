@@ -22,6 +22,7 @@ import (
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/data/testdata"
 )
 
 func TestOTLPMarshaller(t *testing.T) {
@@ -41,3 +42,27 @@ func TestOTLPMarshaller(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []Message{{Value: expected}}, messages)
 }
+
+func TestOTLPMetricsMarshaller(t *testing.T) {
+	md := testdata.GenerateMetricsOneMetric()
+	expected, err := md.ToOtlpProtoBytes()
+	require.NoError(t, err)
+
+	m := otlpMetricsProtoMarshaller{}
+	assert.Equal(t, "otlp_proto", m.Encoding())
+	messages, err := m.Marshal(md)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{{Value: expected}}, messages)
+}
+
+func TestOTLPLogsMarshaller(t *testing.T) {
+	ld := testdata.GenerateLogDataOneLog()
+	expected, err := ld.ToOtlpProtoBytes()
+	require.NoError(t, err)
+
+	m := otlpLogsProtoMarshaller{}
+	assert.Equal(t, "otlp_proto", m.Encoding())
+	messages, err := m.Marshal(ld)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{{Value: expected}}, messages)
+}
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// keyedTraces holds the traces that are sent to kafka with the same message key.
+type keyedTraces struct {
+	key    []byte
+	traces pdata.Traces
+}
+
+// keyedMetrics holds the metrics that are sent to kafka with the same message key.
+type keyedMetrics struct {
+	key     []byte
+	metrics pdata.Metrics
+}
+
+// keyedLogs holds the logs that are sent to kafka with the same message key.
+type keyedLogs struct {
+	key  []byte
+	logs pdata.Logs
+}
+
+// splitTracesByTraceID splits the traces in one pdata.Traces per trace ID, keyed by the hex
+// representation of the trace ID. The resource and instrumentation library of the spans are kept,
+// and the batches are returned in the order in which their trace IDs first appear.
+func splitTracesByTraceID(td pdata.Traces) []keyedTraces {
+	var result []keyedTraces
+	indexByID := make(map[pdata.TraceID]int)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			// Tracks the spans of the current instrumentation library per trace ID.
+			ilsByID := make(map[pdata.TraceID]pdata.InstrumentationLibrarySpans)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				id := span.TraceID()
+				dest, ok := ilsByID[id]
+				if !ok {
+					idx, ok := indexByID[id]
+					if !ok {
+						idx = len(result)
+						indexByID[id] = idx
+						result = append(result, keyedTraces{key: []byte(id.HexString()), traces: pdata.NewTraces()})
+					}
+					dest = appendResourceAndLibrary(result[idx].traces, rs, ils)
+					ilsByID[id] = dest
+				}
+				dest.Spans().Append(span)
+			}
+		}
+	}
+	return result
+}
+
+// appendResourceAndLibrary adds a copy of the resource and instrumentation library to the traces
+// and returns the new instrumentation library spans.
+func appendResourceAndLibrary(td pdata.Traces, rs pdata.ResourceSpans, ils pdata.InstrumentationLibrarySpans) pdata.InstrumentationLibrarySpans {
+	destRss := td.ResourceSpans()
+	destRss.Resize(destRss.Len() + 1)
+	destRs := destRss.At(destRss.Len() - 1)
+	rs.Resource().CopyTo(destRs.Resource())
+	destIlss := destRs.InstrumentationLibrarySpans()
+	destIlss.Resize(1)
+	destIls := destIlss.At(0)
+	ils.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+	return destIls
+}
+
+// splitMetricsByResourceAttribute groups the resource metrics by the value of the given resource
+// attribute, which is used as message key. Resources without the attribute are grouped with a nil key,
+// so that kafka assigns their messages to a random partition.
+func splitMetricsByResourceAttribute(md pdata.Metrics, attribute string) []keyedMetrics {
+	var result []keyedMetrics
+	indexByKey := make(map[string]int)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		key, ok := resourceAttributeKey(rm.Resource(), attribute)
+		idx, found := indexByKey[key]
+		if !found {
+			idx = len(result)
+			indexByKey[key] = idx
+			result = append(result, keyedMetrics{metrics: pdata.NewMetrics()})
+			if ok {
+				result[idx].key = []byte(key)
+			}
+		}
+		result[idx].metrics.ResourceMetrics().Append(rm)
+	}
+	return result
+}
+
+// splitLogsByResourceAttribute groups the resource logs by the value of the given resource
+// attribute, which is used as message key. Resources without the attribute are grouped with a nil key,
+// so that kafka assigns their messages to a random partition.
+func splitLogsByResourceAttribute(ld pdata.Logs, attribute string) []keyedLogs {
+	var result []keyedLogs
+	indexByKey := make(map[string]int)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		key, ok := resourceAttributeKey(rl.Resource(), attribute)
+		idx, found := indexByKey[key]
+		if !found {
+			idx = len(result)
+			indexByKey[key] = idx
+			result = append(result, keyedLogs{logs: pdata.NewLogs()})
+			if ok {
+				result[idx].key = []byte(key)
+			}
+		}
+		result[idx].logs.ResourceLogs().Append(rl)
+	}
+	return result
+}
+
+// resourceAttributeKey returns the string value of the resource attribute and whether it was found.
+// An empty string is returned for resources without the attribute, which is not a valid key.
+func resourceAttributeKey(resource pdata.Resource, attribute string) (string, bool) {
+	v, ok := resource.Attributes().Get(attribute)
+	if !ok {
+		return "", false
+	}
+	key := tracetranslator.AttributeValueToString(v, false)
+	return key, key != ""
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var (
+	testTraceIDA = pdata.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	testTraceIDB = pdata.NewTraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+)
+
+func TestSplitTracesByTraceID(t *testing.T) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(2)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", "svc1")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.InstrumentationLibrary().InitEmpty()
+	ils.InstrumentationLibrary().SetName("lib")
+	ils.Spans().Resize(3)
+	ils.Spans().At(0).SetTraceID(testTraceIDA)
+	ils.Spans().At(0).SetName("a1")
+	ils.Spans().At(1).SetTraceID(testTraceIDB)
+	ils.Spans().At(1).SetName("b1")
+	ils.Spans().At(2).SetTraceID(testTraceIDA)
+	ils.Spans().At(2).SetName("a2")
+	rs = td.ResourceSpans().At(1)
+	rs.Resource().Attributes().InsertString("service.name", "svc2")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils = rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	ils.Spans().At(0).SetTraceID(testTraceIDA)
+	ils.Spans().At(0).SetName("a3")
+
+	batches := splitTracesByTraceID(td)
+	require.Len(t, batches, 2)
+
+	assert.Equal(t, []byte(testTraceIDA.HexString()), batches[0].key)
+	assert.Equal(t, 3, batches[0].traces.SpanCount())
+	rss := batches[0].traces.ResourceSpans()
+	require.Equal(t, 2, rss.Len())
+	svc, _ := rss.At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "svc1", svc.StringVal())
+	assert.Equal(t, "lib", rss.At(0).InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
+	spans := rss.At(0).InstrumentationLibrarySpans().At(0).Spans()
+	require.Equal(t, 2, spans.Len())
+	assert.Equal(t, "a1", spans.At(0).Name())
+	assert.Equal(t, "a2", spans.At(1).Name())
+	svc, _ = rss.At(1).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "svc2", svc.StringVal())
+
+	assert.Equal(t, []byte(testTraceIDB.HexString()), batches[1].key)
+	assert.Equal(t, 1, batches[1].traces.SpanCount())
+	assert.Equal(t, "b1", batches[1].traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
+func TestSplitTracesByTraceID_empty(t *testing.T) {
+	assert.Empty(t, splitTracesByTraceID(pdata.NewTraces()))
+}
+
+func TestSplitMetricsByResourceAttribute(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(4)
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("service.name", "svc1")
+	md.ResourceMetrics().At(1).Resource().Attributes().InsertString("service.name", "svc2")
+	md.ResourceMetrics().At(2).Resource().Attributes().InsertString("service.name", "svc1")
+	md.ResourceMetrics().At(3).Resource().Attributes().InsertString("other", "value")
+
+	batches := splitMetricsByResourceAttribute(md, "service.name")
+	require.Len(t, batches, 3)
+	assert.Equal(t, []byte("svc1"), batches[0].key)
+	assert.Equal(t, 2, batches[0].metrics.ResourceMetrics().Len())
+	assert.Equal(t, []byte("svc2"), batches[1].key)
+	assert.Equal(t, 1, batches[1].metrics.ResourceMetrics().Len())
+	assert.Nil(t, batches[2].key)
+	assert.Equal(t, 1, batches[2].metrics.ResourceMetrics().Len())
+}
+
+func TestSplitLogsByResourceAttribute(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(3)
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertInt("shard", 1)
+	ld.ResourceLogs().At(1).Resource().Attributes().InsertInt("shard", 2)
+	ld.ResourceLogs().At(2).Resource().Attributes().InsertInt("shard", 1)
+
+	batches := splitLogsByResourceAttribute(ld, "shard")
+	require.Len(t, batches, 2)
+	assert.Equal(t, []byte("1"), batches[0].key)
+	assert.Equal(t, 2, batches[0].logs.ResourceLogs().Len())
+	assert.Equal(t, []byte("2"), batches[1].key)
+	assert.Equal(t, 1, batches[1].logs.ResourceLogs().Len())
+}
@@ -64,6 +64,10 @@ func TestLoadConfig(t *testing.T) {
 				Enabled:      true,
 				NumConsumers: 2,
 				QueueSize:    10,
+				Storage: exporterhelper.StorageSettings{
+					Enabled:   true,
+					Directory: "/var/lib/otelcol/queue",
+				},
 			},
 			GRPCClientSettings: configgrpc.GRPCClientSettings{
 				Headers: map[string]string{
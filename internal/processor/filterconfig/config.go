@@ -74,9 +74,14 @@ type MatchProperties struct {
 	// Note: For spans, one of Services, SpanNames, Attributes, Resources or Libraries must be specified with a
 	// non-empty value for a valid configuration.
 
-	// For logs, one of LogNames, Attributes, Resources or Libraries must be specified with a
+	// For logs, one of LogNames, LogBodies, Attributes, Resources or Libraries must be specified with a
 	// non-empty value for a valid configuration.
 
+	// For metrics, one of MetricNames, Resources or Libraries must be specified with a
+	// non-empty value for a valid configuration.
+
+	// With match_type=expr, only Expressions must be specified.
+
 	// Services specify the list of of items to match service name against.
 	// A match occurs if the span's service name matches at least one item in this list.
 	// This is an optional field.
@@ -91,6 +96,21 @@ type MatchProperties struct {
 	// against.
 	LogNames []string `mapstructure:"log_names"`
 
+	// LogBodies is a list of strings that the LogRecord's body field must match
+	// against. Non-string bodies are matched against their string representation.
+	// This is an optional field.
+	LogBodies []string `mapstructure:"log_bodies"`
+
+	// MetricNames is a list of strings that the metric name must match against.
+	// This is an optional field.
+	MetricNames []string `mapstructure:"metric_names"`
+
+	// Expressions specifies the list of expr expressions to match span/logs against.
+	// A match occurs if the span/log matches at least one expression in this list.
+	// Expressions can only be specified, and are required, with match_type=expr,
+	// which doesn't allow any other property to be specified.
+	Expressions []string `mapstructure:"expressions"`
+
 	// Attributes specifies the list of attributes to match against.
 	// All of these attributes must match exactly for a match to occur.
 	// Only match_type=strict is allowed if "attributes" are specified.
@@ -109,8 +129,20 @@ type MatchProperties struct {
 }
 
 func (mp *MatchProperties) ValidateForSpans() error {
-	if len(mp.LogNames) > 0 {
-		return errors.New("log_names should not be specified for trace spans")
+	if len(mp.LogNames) > 0 || len(mp.LogBodies) > 0 {
+		return errors.New("neither log_names nor log_bodies should be specified for trace spans")
+	}
+
+	if len(mp.MetricNames) > 0 {
+		return errors.New("metric_names should not be specified for trace spans")
+	}
+
+	if mp.MatchType == Expr {
+		return mp.validateForExpr()
+	}
+
+	if len(mp.Expressions) > 0 {
+		return errors.New(`expressions can only be specified with match_type "expr"`)
 	}
 
 	if len(mp.Services) == 0 && len(mp.SpanNames) == 0 && len(mp.Attributes) == 0 &&
@@ -126,13 +158,67 @@ func (mp *MatchProperties) ValidateForLogs() error {
 		return errors.New("neither services nor span_names should be specified for log records")
 	}
 
-	if len(mp.LogNames) == 0 && len(mp.Attributes) == 0 && len(mp.Libraries) == 0 && len(mp.Resources) == 0 {
-		return errors.New(`at least one of "log_names", "attributes", "libraries" or "resources" field must be specified`)
+	if len(mp.MetricNames) > 0 {
+		return errors.New("metric_names should not be specified for log records")
+	}
+
+	if mp.MatchType == Expr {
+		return mp.validateForExpr()
+	}
+
+	if len(mp.Expressions) > 0 {
+		return errors.New(`expressions can only be specified with match_type "expr"`)
+	}
+
+	if len(mp.LogNames) == 0 && len(mp.LogBodies) == 0 && len(mp.Attributes) == 0 &&
+		len(mp.Libraries) == 0 && len(mp.Resources) == 0 {
+		return errors.New(`at least one of "log_names", "log_bodies", "attributes", "libraries" or "resources" field must be specified`)
+	}
+
+	return nil
+}
+
+func (mp *MatchProperties) ValidateForMetrics() error {
+	if len(mp.SpanNames) > 0 || len(mp.Services) > 0 || len(mp.LogNames) > 0 || len(mp.LogBodies) > 0 {
+		return errors.New("none of services, span_names, log_names or log_bodies should be specified for metrics")
+	}
+
+	if len(mp.Attributes) > 0 {
+		return errors.New("attributes should not be specified for metrics, labels are set per data point")
+	}
+
+	if mp.MatchType == Expr {
+		return mp.validateForExpr()
+	}
+
+	if len(mp.Expressions) > 0 {
+		return errors.New(`expressions can only be specified with match_type "expr"`)
+	}
+
+	if len(mp.MetricNames) == 0 && len(mp.Libraries) == 0 && len(mp.Resources) == 0 {
+		return errors.New(`at least one of "metric_names", "libraries" or "resources" field must be specified`)
+	}
+
+	return nil
+}
+
+func (mp *MatchProperties) validateForExpr() error {
+	if len(mp.Expressions) == 0 {
+		return errors.New(`"expressions" field must be specified with match_type "expr"`)
+	}
+
+	if len(mp.Services) > 0 || len(mp.SpanNames) > 0 || len(mp.LogNames) > 0 || len(mp.LogBodies) > 0 ||
+		len(mp.MetricNames) > 0 || len(mp.Attributes) > 0 || len(mp.Libraries) > 0 || len(mp.Resources) > 0 {
+		return errors.New(`only "expressions" field can be specified with match_type "expr"`)
 	}
 
 	return nil
 }
 
+// Expr is the match type to match span/logs/metrics using the expr expressions in MatchProperties.Expressions,
+// see filterexpr for the variables and functions available to the expressions.
+const Expr filterset.MatchType = "expr"
+
 // MatchTypeFieldName is the mapstructure field name for MatchProperties.Attributes field.
 const AttributesFieldName = "attributes"
 
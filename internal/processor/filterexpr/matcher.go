@@ -19,6 +19,8 @@ import (
 	"github.com/antonmedv/expr/vm"
 
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 type Matcher struct {
@@ -33,6 +35,30 @@ type env struct {
 	Label    func(key string) string
 }
 
+// spanEnv is the environment available to the expressions matching spans.
+type spanEnv struct {
+	SpanName    string
+	ServiceName string
+	attributesEnv
+}
+
+// logEnv is the environment available to the expressions matching log records.
+type logEnv struct {
+	LogName      string
+	Body         string
+	SeverityText string
+	attributesEnv
+}
+
+// attributesEnv gives access to the attributes of a span or log record and of its resource.
+// Attribute values are converted to their string representation.
+type attributesEnv struct {
+	HasAttribute         func(key string) bool
+	Attribute            func(key string) string
+	HasResourceAttribute func(key string) bool
+	ResourceAttribute    func(key string) string
+}
+
 func NewMatcher(expression string) (*Matcher, error) {
 	program, err := expr.Compile(expression)
 	if err != nil {
@@ -205,7 +231,59 @@ func createEnv(metricName string, labelsMap pdata.StringMap) env {
 	}
 }
 
-func (m *Matcher) match(env env) (bool, error) {
+// MatchSpan evaluates the expression against a span and the resource it belongs to.
+func (m *Matcher) MatchSpan(span pdata.Span, resource pdata.Resource) (bool, error) {
+	serviceName := ""
+	if service, ok := resource.Attributes().Get(conventions.AttributeServiceName); ok {
+		serviceName = service.StringVal()
+	}
+	return m.match(spanEnv{
+		SpanName:      span.Name(),
+		ServiceName:   serviceName,
+		attributesEnv: createAttributesEnv(span.Attributes(), resource),
+	})
+}
+
+// MatchLogRecord evaluates the expression against a log record and the resource it belongs to.
+func (m *Matcher) MatchLogRecord(lr pdata.LogRecord, resource pdata.Resource) (bool, error) {
+	return m.match(logEnv{
+		LogName:       lr.Name(),
+		Body:          tracetranslator.AttributeValueToString(lr.Body(), false),
+		SeverityText:  lr.SeverityText(),
+		attributesEnv: createAttributesEnv(lr.Attributes(), resource),
+	})
+}
+
+func createAttributesEnv(attributes pdata.AttributeMap, resource pdata.Resource) attributesEnv {
+	resourceAttributes := resource.Attributes()
+	return attributesEnv{
+		HasAttribute: func(key string) bool {
+			_, ok := attributes.Get(key)
+			return ok
+		},
+		Attribute: func(key string) string {
+			return attributeString(attributes, key)
+		},
+		HasResourceAttribute: func(key string) bool {
+			_, ok := resourceAttributes.Get(key)
+			return ok
+		},
+		ResourceAttribute: func(key string) string {
+			return attributeString(resourceAttributes, key)
+		},
+	}
+}
+
+// attributeString returns the string representation of the attribute with the given key, or "" if there is none.
+func attributeString(attributes pdata.AttributeMap, key string) string {
+	v, ok := attributes.Get(key)
+	if !ok {
+		return ""
+	}
+	return tracetranslator.AttributeValueToString(v, false)
+}
+
+func (m *Matcher) match(env interface{}) (bool, error) {
 	result, err := m.v.Run(m.program, env)
 	if err != nil {
 		return false, err
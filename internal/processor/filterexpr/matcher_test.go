@@ -399,3 +399,54 @@ func matchDoubleHistogram(t *testing.T, metricName string) bool {
 	assert.NoError(t, err)
 	return matched
 }
+
+func TestMatchSpan(t *testing.T) {
+	span := pdata.NewSpan()
+	span.InitEmpty()
+	span.SetName("my.span")
+	span.Attributes().InsertInt("http.status_code", 500)
+	resource := pdata.NewResource()
+	resource.Attributes().InsertString("service.name", "my.service")
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`SpanName == "my.span" && ServiceName == "my.service"`, true},
+		{`Attribute("http.status_code") == "500"`, true},
+		{`HasAttribute("missing") || Attribute("missing") != ""`, false},
+		{`HasResourceAttribute("service.name") && ResourceAttribute("service.name") == "my.service"`, true},
+	}
+	for _, test := range tests {
+		matcher, err := NewMatcher(test.expression)
+		require.NoError(t, err)
+		matched, err := matcher.MatchSpan(span, resource)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, matched, test.expression)
+	}
+}
+
+func TestMatchLogRecord(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	lr.SetName("my.log")
+	lr.SetSeverityText("ERROR")
+	lr.Body().SetStringVal("something failed")
+	resource := pdata.NewResource()
+
+	tests := []struct {
+		expression string
+		expected   bool
+	}{
+		{`LogName == "my.log" && SeverityText == "ERROR"`, true},
+		{`Body matches "failed$"`, true},
+		{`HasResourceAttribute("host") || ResourceAttribute("host") != ""`, false},
+	}
+	for _, test := range tests {
+		matcher, err := NewMatcher(test.expression)
+		require.NoError(t, err)
+		matched, err := matcher.MatchLogRecord(lr, resource)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, matched, test.expression)
+	}
+}
@@ -19,8 +19,10 @@ import (
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterexpr"
 	"go.opentelemetry.io/collector/internal/processor/filtermatcher"
 	"go.opentelemetry.io/collector/internal/processor/filterset"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
 )
 
 // TODO: Modify Matcher to invoke both the include and exclude properties so
@@ -37,6 +39,9 @@ type propertiesMatcher struct {
 
 	// log names to compare to.
 	nameFilters filterset.FilterSet
+
+	// log bodies to compare to.
+	bodyFilters filterset.FilterSet
 }
 
 // NewMatcher creates a LogRecord Matcher that matches based on the given MatchProperties.
@@ -49,6 +54,10 @@ func NewMatcher(mp *filterconfig.MatchProperties) (Matcher, error) {
 		return nil, err
 	}
 
+	if mp.MatchType == filterconfig.Expr {
+		return newExprMatcher(mp.Expressions)
+	}
+
 	rm, err := filtermatcher.NewMatcher(mp)
 	if err != nil {
 		return nil, err
@@ -62,23 +71,64 @@ func NewMatcher(mp *filterconfig.MatchProperties) (Matcher, error) {
 		}
 	}
 
+	var bodyFS filterset.FilterSet = nil
+	if len(mp.LogBodies) > 0 {
+		bodyFS, err = filterset.CreateFilterSet(mp.LogBodies, &mp.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating log record body filters: %v", err)
+		}
+	}
+
 	return &propertiesMatcher{
 		PropertiesMatcher: rm,
 		nameFilters:       nameFS,
+		bodyFilters:       bodyFS,
 	}, nil
 }
 
 // MatchLogRecord matches a log record to a set of properties.
-// There are 3 sets of properties to match against.
 // The log record names are matched, if specified.
-// The attributes are then checked, if specified.
-// At least one of log record names or attributes must be specified. It is
-// supported to have more than one of these specified, and all specified must
-// evaluate to true for a match to occur.
+// The log record bodies are then matched, if specified.
+// The attributes, resources and libraries are then checked, if specified.
+// At least one of these properties must be specified. It is supported to have
+// more than one of these specified, and all specified must evaluate to true
+// for a match to occur.
 func (mp *propertiesMatcher) MatchLogRecord(lr pdata.LogRecord, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
 	if mp.nameFilters != nil && !mp.nameFilters.Matches(lr.Name()) {
 		return false
 	}
 
+	if mp.bodyFilters != nil && !mp.bodyFilters.Matches(tracetranslator.AttributeValueToString(lr.Body(), false)) {
+		return false
+	}
+
 	return mp.PropertiesMatcher.Match(lr.Attributes(), resource, library)
 }
+
+// exprMatcher allows matching a log record against a list of expr expressions.
+type exprMatcher struct {
+	matchers []*filterexpr.Matcher
+}
+
+func newExprMatcher(expressions []string) (*exprMatcher, error) {
+	m := &exprMatcher{}
+	for _, expression := range expressions {
+		matcher, err := filterexpr.NewMatcher(expression)
+		if err != nil {
+			return nil, fmt.Errorf("error creating log record expression filters: %v", err)
+		}
+		m.matchers = append(m.matchers, matcher)
+	}
+	return m, nil
+}
+
+// MatchLogRecord matches a log record if any of the expressions evaluates to true.
+// An expression that fails to evaluate doesn't match.
+func (m *exprMatcher) MatchLogRecord(lr pdata.LogRecord, resource pdata.Resource, _ pdata.InstrumentationLibrary) bool {
+	for _, matcher := range m.matchers {
+		if matched, err := matcher.MatchLogRecord(lr, resource); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -40,14 +40,14 @@ func TestLogRecord_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 		{
 			name:        "empty_property",
 			property:    filterconfig.MatchProperties{},
-			errorString: "at least one of \"log_names\", \"attributes\", \"libraries\" or \"resources\" field must be specified",
+			errorString: "at least one of \"log_names\", \"log_bodies\", \"attributes\", \"libraries\" or \"resources\" field must be specified",
 		},
 		{
 			name: "empty_log_names_and_attributes",
 			property: filterconfig.MatchProperties{
 				LogNames: []string{},
 			},
-			errorString: "at least one of \"log_names\", \"attributes\", \"libraries\" or \"resources\" field must be specified",
+			errorString: "at least one of \"log_names\", \"log_bodies\", \"attributes\", \"libraries\" or \"resources\" field must be specified",
 		},
 		{
 			name: "span_properties",
@@ -56,6 +56,13 @@ func TestLogRecord_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "neither services nor span_names should be specified for log records",
 		},
+		{
+			name: "metric_properties",
+			property: filterconfig.MatchProperties{
+				MetricNames: []string{"metric"},
+			},
+			errorString: "metric_names should not be specified for log records",
+		},
 		{
 			name: "invalid_match_type",
 			property: filterconfig.MatchProperties{
@@ -87,6 +94,31 @@ func TestLogRecord_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating log record name filters: error parsing regexp: missing closing ]: `[`",
 		},
+		{
+			name: "invalid_regexp_pattern_body",
+			property: filterconfig.MatchProperties{
+				Config:    *createConfig(filterset.Regexp),
+				LogBodies: []string{"["},
+			},
+			errorString: "error creating log record body filters: error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name: "expr_match_type_with_log_bodies",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				LogBodies:   []string{"abc"},
+				Expressions: []string{`Body == "abc"`},
+			},
+			errorString: `only "expressions" field can be specified with match_type "expr"`,
+		},
+		{
+			name: "invalid_expression",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{""},
+			},
+			errorString: "error creating log record expression filters: unexpected token EOF (1:1)",
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -124,18 +156,37 @@ func TestLogRecord_Matching_False(t *testing.T) {
 				Attributes: []filterconfig.Attribute{},
 			},
 		},
+
+		{
+			name: "log_body_doesnt_match",
+			properties: &filterconfig.MatchProperties{
+				Config:    *createConfig(filterset.Strict),
+				LogNames:  []string{"logName"},
+				LogBodies: []string{"another body"},
+			},
+		},
+
+		{
+			name: "expression_doesnt_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{`LogName == "logName" && SeverityText == "ERROR"`},
+			},
+		},
 	}
 
 	lr := pdata.NewLogRecord()
 	lr.InitEmpty()
 	lr.SetName("logName")
+	lr.Body().SetStringVal("log body")
+	lr.SetSeverityText("INFO")
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			matcher, err := NewMatcher(tc.properties)
 			assert.Nil(t, err)
 			assert.NotNil(t, matcher)
 
-			assert.False(t, matcher.MatchLogRecord(lr, pdata.Resource{}, pdata.InstrumentationLibrary{}))
+			assert.False(t, matcher.MatchLogRecord(lr, pdata.NewResource(), pdata.InstrumentationLibrary{}))
 		})
 	}
 }
@@ -166,11 +217,27 @@ func TestLogRecord_Matching_True(t *testing.T) {
 				Attributes: []filterconfig.Attribute{},
 			},
 		},
+		{
+			name: "log_body_match",
+			properties: &filterconfig.MatchProperties{
+				Config:    *createConfig(filterset.Regexp),
+				LogBodies: []string{"^log.*"},
+			},
+		},
+		{
+			name: "expression_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{`Body matches "^log" && Attribute("keyInt") == "123"`},
+			},
+		},
 	}
 
 	lr := pdata.NewLogRecord()
 	lr.InitEmpty()
 	lr.SetName("logName")
+	lr.Body().SetStringVal("log body")
+	lr.Attributes().InsertInt("keyInt", 123)
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -179,7 +246,7 @@ func TestLogRecord_Matching_True(t *testing.T) {
 			assert.NotNil(t, mp)
 
 			assert.NotNil(t, lr)
-			assert.True(t, mp.MatchLogRecord(lr, pdata.Resource{}, pdata.InstrumentationLibrary{}))
+			assert.True(t, mp.MatchLogRecord(lr, pdata.NewResource(), pdata.InstrumentationLibrary{}))
 		})
 	}
 }
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filtermetric
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filtermatcher"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// PropertiesMatcher is an interface that allows matching a metric against the
+// configuration of a match shared with spans and log records.
+type PropertiesMatcher interface {
+	MatchMetricProperties(metric pdata.Metric, resource pdata.Resource, library pdata.InstrumentationLibrary) bool
+}
+
+// propertiesMatcher allows matching a metric against its name, resource and library.
+type propertiesMatcher struct {
+	filtermatcher.PropertiesMatcher
+
+	// metric names to compare to.
+	nameFilters filterset.FilterSet
+}
+
+// NewPropertiesMatcher creates a metric PropertiesMatcher that matches based on the
+// given MatchProperties.
+func NewPropertiesMatcher(mp *filterconfig.MatchProperties) (PropertiesMatcher, error) {
+	if mp == nil {
+		return nil, nil
+	}
+
+	if err := mp.ValidateForMetrics(); err != nil {
+		return nil, err
+	}
+
+	if mp.MatchType == filterconfig.Expr {
+		m, err := newExprMatcher(mp.Expressions)
+		if err != nil {
+			return nil, fmt.Errorf("error creating metric expression filters: %v", err)
+		}
+		return &exprPropertiesMatcher{exprMatcher: m}, nil
+	}
+
+	rm, err := filtermatcher.NewMatcher(mp)
+	if err != nil {
+		return nil, err
+	}
+
+	var nameFS filterset.FilterSet = nil
+	if len(mp.MetricNames) > 0 {
+		nameFS, err = filterset.CreateFilterSet(mp.MetricNames, &mp.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating metric name filters: %v", err)
+		}
+	}
+
+	return &propertiesMatcher{
+		PropertiesMatcher: rm,
+		nameFilters:       nameFS,
+	}, nil
+}
+
+// MatchMetricProperties matches a metric to a set of properties.
+// The metric names are matched, if specified.
+// The resources and libraries are then checked, if specified.
+func (mp *propertiesMatcher) MatchMetricProperties(metric pdata.Metric, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
+	if mp.nameFilters != nil && !mp.nameFilters.Matches(metric.Name()) {
+		return false
+	}
+
+	return mp.PropertiesMatcher.Match(pdata.NewAttributeMap(), resource, library)
+}
+
+// exprPropertiesMatcher matches a metric if any of its data points matches any of the
+// expressions. An expression that fails to evaluate doesn't match.
+type exprPropertiesMatcher struct {
+	*exprMatcher
+}
+
+func (m *exprPropertiesMatcher) MatchMetricProperties(metric pdata.Metric, _ pdata.Resource, _ pdata.InstrumentationLibrary) bool {
+	matched, err := m.MatchMetric(metric)
+	return err == nil && matched
+}
@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filtermetric
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestNewPropertiesMatcher_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		property    filterconfig.MatchProperties
+		errorString string
+	}{
+		{
+			name:        "empty_property",
+			property:    filterconfig.MatchProperties{},
+			errorString: `at least one of "metric_names", "libraries" or "resources" field must be specified`,
+		},
+		{
+			name: "span_properties",
+			property: filterconfig.MatchProperties{
+				SpanNames: []string{"span"},
+			},
+			errorString: "none of services, span_names, log_names or log_bodies should be specified for metrics",
+		},
+		{
+			name: "attributes",
+			property: filterconfig.MatchProperties{
+				Config:     filterset.Config{MatchType: filterset.Strict},
+				Attributes: []filterconfig.Attribute{{Key: "key", Value: "value"}},
+			},
+			errorString: "attributes should not be specified for metrics, labels are set per data point",
+		},
+		{
+			name: "invalid_regexp_pattern",
+			property: filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterset.Regexp},
+				MetricNames: []string{"["},
+			},
+			errorString: "error creating metric name filters: error parsing regexp: missing closing ]: `[`",
+		},
+		{
+			name: "expr_match_type_with_metric_names",
+			property: filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterconfig.Expr},
+				MetricNames: []string{"abc"},
+				Expressions: []string{`MetricName == "abc"`},
+			},
+			errorString: `only "expressions" field can be specified with match_type "expr"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := NewPropertiesMatcher(&tc.property)
+			assert.Nil(t, output)
+			require.NotNil(t, err)
+			assert.Equal(t, tc.errorString, err.Error())
+		})
+	}
+}
+
+func TestNewPropertiesMatcher_Nil(t *testing.T) {
+	output, err := NewPropertiesMatcher(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, output)
+}
+
+func TestPropertiesMatcher_Matching(t *testing.T) {
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resource.Attributes().InsertString("service.name", "svc")
+	library := pdata.NewInstrumentationLibrary()
+	library.InitEmpty()
+
+	testcases := []struct {
+		name        string
+		properties  *filterconfig.MatchProperties
+		metric      pdata.Metric
+		shouldMatch bool
+	}{
+		{
+			name: "strict_metric_name",
+			properties: &filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterset.Strict},
+				MetricNames: []string{"exact_string_match"},
+			},
+			metric:      createMetric("exact_string_match"),
+			shouldMatch: true,
+		},
+		{
+			name: "regexp_metric_name_mismatch",
+			properties: &filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterset.Regexp},
+				MetricNames: []string{"prefix/.*"},
+			},
+			metric:      createMetric("other/metric"),
+			shouldMatch: false,
+		},
+		{
+			name: "metric_name_and_resource",
+			properties: &filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterset.Regexp},
+				MetricNames: []string{"prefix/.*"},
+				Resources:   []filterconfig.Attribute{{Key: "service.name", Value: "svc"}},
+			},
+			metric:      createMetric("prefix/metric"),
+			shouldMatch: true,
+		},
+		{
+			name: "resource_mismatch",
+			properties: &filterconfig.MatchProperties{
+				Config:    filterset.Config{MatchType: filterset.Strict},
+				Resources: []filterconfig.Attribute{{Key: "service.name", Value: "other"}},
+			},
+			metric:      createMetric("metric"),
+			shouldMatch: false,
+		},
+		{
+			name: "expr_without_data_points",
+			properties: &filterconfig.MatchProperties{
+				Config:      filterset.Config{MatchType: filterconfig.Expr},
+				Expressions: []string{`MetricName == "metric"`},
+			},
+			metric:      createMetric("metric"),
+			shouldMatch: false,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher, err := NewPropertiesMatcher(tc.properties)
+			require.NoError(t, err)
+			assert.Equal(t, tc.shouldMatch, matcher.MatchMetricProperties(tc.metric, resource, library))
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterexpr"
 	"go.opentelemetry.io/collector/internal/processor/filtermatcher"
 	"go.opentelemetry.io/collector/internal/processor/filterset"
 	"go.opentelemetry.io/collector/translator/conventions"
@@ -53,6 +54,10 @@ func NewMatcher(mp *filterconfig.MatchProperties) (Matcher, error) {
 		return nil, err
 	}
 
+	if mp.MatchType == filterconfig.Expr {
+		return newExprMatcher(mp.Expressions)
+	}
+
 	rm, err := filtermatcher.NewMatcher(mp)
 	if err != nil {
 		return nil, err
@@ -132,3 +137,31 @@ func serviceNameForResource(resource pdata.Resource) string {
 
 	return service.StringVal()
 }
+
+// exprMatcher allows matching a span against a list of expr expressions.
+type exprMatcher struct {
+	matchers []*filterexpr.Matcher
+}
+
+func newExprMatcher(expressions []string) (*exprMatcher, error) {
+	m := &exprMatcher{}
+	for _, expression := range expressions {
+		matcher, err := filterexpr.NewMatcher(expression)
+		if err != nil {
+			return nil, fmt.Errorf("error creating span expression filters: %v", err)
+		}
+		m.matchers = append(m.matchers, matcher)
+	}
+	return m, nil
+}
+
+// MatchSpan matches a span if any of the expressions evaluates to true.
+// An expression that fails to evaluate doesn't match.
+func (m *exprMatcher) MatchSpan(span pdata.Span, resource pdata.Resource, _ pdata.InstrumentationLibrary) bool {
+	for _, matcher := range m.matchers {
+		if matched, err := matcher.MatchSpan(span, resource); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -56,7 +56,7 @@ func TestSpan_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			property: filterconfig.MatchProperties{
 				LogNames: []string{"log"},
 			},
-			errorString: "log_names should not be specified for trace spans",
+			errorString: "neither log_names nor log_bodies should be specified for trace spans",
 		},
 		{
 			name: "invalid_match_type",
@@ -89,6 +89,38 @@ func TestSpan_validateMatchesConfiguration_InvalidConfig(t *testing.T) {
 			},
 			errorString: "error creating span name filters: error parsing regexp: missing closing ]: `[`",
 		},
+		{
+			name: "expressions_without_expr_match_type",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Strict),
+				Expressions: []string{`SpanName == "abc"`},
+			},
+			errorString: `expressions can only be specified with match_type "expr"`,
+		},
+		{
+			name: "expr_match_type_without_expressions",
+			property: filterconfig.MatchProperties{
+				Config: *createConfig(filterconfig.Expr),
+			},
+			errorString: `"expressions" field must be specified with match_type "expr"`,
+		},
+		{
+			name: "expr_match_type_with_span_names",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				SpanNames:   []string{"abc"},
+				Expressions: []string{`SpanName == "abc"`},
+			},
+			errorString: `only "expressions" field can be specified with match_type "expr"`,
+		},
+		{
+			name: "invalid_expression",
+			property: filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{""},
+			},
+			errorString: "error creating span expression filters: unexpected token EOF (1:1)",
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -143,6 +175,22 @@ func TestSpan_Matching_False(t *testing.T) {
 				Attributes: []filterconfig.Attribute{},
 			},
 		},
+
+		{
+			name: "expression_doesnt_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{`SpanName == "spanName" && HasAttribute("missing")`},
+			},
+		},
+
+		{
+			name: "expression_fails_to_evaluate",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{`Unknown("x")`},
+			},
+		},
 	}
 
 	span := pdata.NewSpan()
@@ -219,6 +267,23 @@ func TestSpan_Matching_True(t *testing.T) {
 				Attributes: []filterconfig.Attribute{},
 			},
 		},
+		{
+			name: "expression_match",
+			properties: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterconfig.Expr),
+				Expressions: []string{`ServiceName == "svcA" && Attribute("keyInt") == "123"`},
+			},
+		},
+		{
+			name: "expression_second_match",
+			properties: &filterconfig.MatchProperties{
+				Config: *createConfig(filterconfig.Expr),
+				Expressions: []string{
+					`SpanName == "wrongName"`,
+					`HasAttribute("keyExists") && ResourceAttribute("service.name") matches "svc.*"`,
+				},
+			},
+		},
 	}
 
 	span := pdata.NewSpan()
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+// OperatorType is the type of an operator.
+type OperatorType string
+
+const (
+	// RegexParser extracts the named groups of a regular expression into attributes.
+	RegexParser OperatorType = "regex_parser"
+	// JSONParser extracts the fields of a JSON object into attributes.
+	JSONParser OperatorType = "json_parser"
+	// KeyValueParser extracts key value pairs, such as "a=1 b=2", into attributes.
+	KeyValueParser OperatorType = "key_value_parser"
+	// SeverityParser sets the severity number and text of the log record.
+	SeverityParser OperatorType = "severity_parser"
+	// TimeParser sets the timestamp of the log record.
+	TimeParser OperatorType = "time_parser"
+)
+
+// OnErrorAction is the action applied to a log record when an operator fails.
+type OnErrorAction string
+
+const (
+	// OnErrorSend leaves the log record unchanged by the failed operator and continues
+	// with the next operators.
+	OnErrorSend OnErrorAction = "send"
+	// OnErrorDrop drops the log record.
+	OnErrorDrop OnErrorAction = "drop"
+)
+
+// LayoutType is the type of the layout used to parse a timestamp.
+type LayoutType string
+
+const (
+	// GoTimeLayout is a layout of the time package, e.g. "2006-01-02T15:04:05Z07:00".
+	GoTimeLayout LayoutType = "gotime"
+	// StrptimeLayout is a strptime layout, e.g. "%Y-%m-%dT%H:%M:%S%z".
+	StrptimeLayout LayoutType = "strptime"
+	// EpochLayout is a number of seconds ("s"), milliseconds ("ms"), microseconds ("us")
+	// or nanoseconds ("ns") since the Unix epoch.
+	EpochLayout LayoutType = "epoch"
+)
+
+// OperatorConfig holds the configuration of an operator. Only the fields relevant
+// to the Type of the operator can be set.
+type OperatorConfig struct {
+	// Type of the operator.
+	Type OperatorType `mapstructure:"type"`
+
+	// ParseFrom is the field parsed by the operator, either "body" or "attributes.<key>".
+	// Defaults to "body".
+	ParseFrom string `mapstructure:"parse_from"`
+
+	// OnError is the action applied to the log record when the operator fails,
+	// either "send" or "drop". Defaults to "send".
+	OnError OnErrorAction `mapstructure:"on_error"`
+
+	// Regex is the regular expression of the regex_parser operator. Each named group
+	// that matches is inserted or updated as an attribute.
+	Regex string `mapstructure:"regex"`
+
+	// Delimiter separates the key from the value in the key_value_parser operator.
+	// Defaults to "=".
+	Delimiter string `mapstructure:"delimiter"`
+
+	// PairDelimiter separates the pairs in the key_value_parser operator. Defaults to
+	// any whitespace.
+	PairDelimiter string `mapstructure:"pair_delimiter"`
+
+	// Mapping of the severity_parser operator, from a severity name ("trace", "debug",
+	// "info", "warn", "error" or "fatal", optionally followed by 2 to 4) to the values
+	// parsed as this severity. Values are matched case-insensitively and are added to
+	// the default mapping.
+	Mapping map[string][]string `mapstructure:"mapping"`
+
+	// LayoutType is the type of the Layout of the time_parser operator, either "gotime",
+	// "strptime" or "epoch". Defaults to "gotime".
+	LayoutType LayoutType `mapstructure:"layout_type"`
+
+	// Layout of the timestamps parsed by the time_parser operator.
+	Layout string `mapstructure:"layout"`
+
+	// Location is the IANA time zone name used by the time_parser operator when the
+	// timestamps do not include a time zone. Defaults to UTC.
+	Location string `mapstructure:"location"`
+}
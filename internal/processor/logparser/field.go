@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	bodyField       = "body"
+	attributePrefix = "attributes."
+)
+
+// field references the body or an attribute of a log record.
+type field struct {
+	// attribute is the key of the attribute, or empty for the body.
+	attribute string
+}
+
+func newField(s string) (field, error) {
+	switch {
+	case s == "" || s == bodyField:
+		return field{}, nil
+	case strings.HasPrefix(s, attributePrefix) && len(s) > len(attributePrefix):
+		return field{attribute: s[len(attributePrefix):]}, nil
+	default:
+		return field{}, fmt.Errorf("invalid field %q, must be %q or start with %q", s, bodyField, attributePrefix)
+	}
+}
+
+func (f field) String() string {
+	if f.attribute == "" {
+		return bodyField
+	}
+	return attributePrefix + f.attribute
+}
+
+// stringValue returns the value of the field as a string. It fails if the field
+// does not exist or is not a string, an int, a double or a bool.
+func (f field) stringValue(lr pdata.LogRecord) (string, error) {
+	var value pdata.AttributeValue
+	if f.attribute == "" {
+		value = lr.Body()
+		if value.IsNil() {
+			return "", fmt.Errorf("%v not found", f)
+		}
+	} else {
+		var ok bool
+		if value, ok = lr.Attributes().Get(f.attribute); !ok {
+			return "", fmt.Errorf("%v not found", f)
+		}
+	}
+
+	switch value.Type() {
+	case pdata.AttributeValueSTRING:
+		return value.StringVal(), nil
+	case pdata.AttributeValueINT:
+		return strconv.FormatInt(value.IntVal(), 10), nil
+	case pdata.AttributeValueDOUBLE:
+		return strconv.FormatFloat(value.DoubleVal(), 'f', -1, 64), nil
+	case pdata.AttributeValueBOOL:
+		return strconv.FormatBool(value.BoolVal()), nil
+	default:
+		return "", fmt.Errorf("%v has unsupported type %v", f, value.Type())
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// jsonParser inserts or updates an attribute for each field of the JSON object held
+// by the field. Nested objects and arrays are converted to map and array attributes.
+type jsonParser struct {
+	from field
+}
+
+func newJSONParser(from field, _ *OperatorConfig) (*jsonParser, error) {
+	return &jsonParser{from: from}, nil
+}
+
+func (p *jsonParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.stringValue(lr)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return fmt.Errorf("cannot parse %v as a JSON object: %v", p.from, err)
+	}
+	if object == nil {
+		return fmt.Errorf("cannot parse %v as a JSON object: null", p.from)
+	}
+
+	attrs := lr.Attributes()
+	for _, k := range sortedKeys(object) {
+		attrs.Upsert(k, jsonToAttributeValue(object[k]))
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the object sorted, so that the order of the
+// attributes does not depend on the map iteration order.
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonToAttributeValue(v interface{}) pdata.AttributeValue {
+	switch v := v.(type) {
+	case string:
+		return pdata.NewAttributeValueString(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return pdata.NewAttributeValueInt(i)
+		}
+		f, _ := v.Float64()
+		return pdata.NewAttributeValueDouble(f)
+	case bool:
+		return pdata.NewAttributeValueBool(v)
+	case map[string]interface{}:
+		av := pdata.NewAttributeValueMap()
+		m := av.MapVal()
+		for _, k := range sortedKeys(v) {
+			m.Insert(k, jsonToAttributeValue(v[k]))
+		}
+		return av
+	case []interface{}:
+		av := pdata.NewAttributeValueArray()
+		a := av.ArrayVal()
+		for _, e := range v {
+			a.Append(jsonToAttributeValue(e))
+		}
+		return av
+	default:
+		return pdata.NewAttributeValueNull()
+	}
+}
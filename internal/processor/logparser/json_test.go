@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestJSONParser(t *testing.T) {
+	p, err := newJSONParser(field{attribute: "payload"}, &OperatorConfig{})
+	require.NoError(t, err)
+
+	lr := newLogRecord("unchanged")
+	lr.Attributes().InsertString("payload", `{"msg":"started","count":3,"ratio":0.5,"ok":true,"none":null,"tags":["a",1],"http":{"status":200}}`)
+	require.NoError(t, p.process(lr))
+
+	tags := pdata.NewAttributeValueArray()
+	tags.ArrayVal().Append(pdata.NewAttributeValueString("a"))
+	tags.ArrayVal().Append(pdata.NewAttributeValueInt(1))
+	http := pdata.NewAttributeValueMap()
+	http.MapVal().InsertInt("status", 200)
+
+	expected := pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"payload": pdata.NewAttributeValueString(`{"msg":"started","count":3,"ratio":0.5,"ok":true,"none":null,"tags":["a",1],"http":{"status":200}}`),
+		"msg":     pdata.NewAttributeValueString("started"),
+		"count":   pdata.NewAttributeValueInt(3),
+		"ratio":   pdata.NewAttributeValueDouble(0.5),
+		"ok":      pdata.NewAttributeValueBool(true),
+		"none":    pdata.NewAttributeValueNull(),
+		"tags":    tags,
+		"http":    http,
+	})
+	assert.Equal(t, expected.Sort(), lr.Attributes().Sort())
+	assert.Equal(t, "unchanged", lr.Body().StringVal())
+}
+
+func TestJSONParser_Invalid(t *testing.T) {
+	testcases := []struct {
+		name        string
+		body        string
+		errorString string
+	}{
+		{
+			name:        "not_json",
+			body:        "started",
+			errorString: "cannot parse body as a JSON object: invalid character 's' looking for beginning of value",
+		},
+		{
+			name:        "array",
+			body:        `["a"]`,
+			errorString: "cannot parse body as a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}",
+		},
+		{
+			name:        "null",
+			body:        "null",
+			errorString: "cannot parse body as a JSON object: null",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newJSONParser(field{}, &OperatorConfig{})
+			require.NoError(t, err)
+
+			lr := newLogRecord(tc.body)
+			assert.EqualError(t, p.process(lr), tc.errorString)
+			assert.Equal(t, 0, lr.Attributes().Len())
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const defaultKeyValueDelimiter = "="
+
+// keyValueParser inserts or updates an attribute for each key value pair of the
+// field, e.g. "level=info msg=\"started\"". Quotes around the values are removed.
+type keyValueParser struct {
+	from          field
+	delimiter     string
+	pairDelimiter string
+}
+
+func newKeyValueParser(from field, cfg *OperatorConfig) (*keyValueParser, error) {
+	p := &keyValueParser{
+		from:          from,
+		delimiter:     cfg.Delimiter,
+		pairDelimiter: cfg.PairDelimiter,
+	}
+	if p.delimiter == "" {
+		p.delimiter = defaultKeyValueDelimiter
+	}
+	if p.delimiter == p.pairDelimiter {
+		return nil, fmt.Errorf("delimiter and pair_delimiter cannot be both %q", p.delimiter)
+	}
+	return p, nil
+}
+
+func (p *keyValueParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.stringValue(lr)
+	if err != nil {
+		return err
+	}
+
+	pairs := p.splitPairs(value)
+	if len(pairs) == 0 {
+		return fmt.Errorf("no key value pair found in %v", p.from)
+	}
+	keys := make([]string, 0, len(pairs))
+	values := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, p.delimiter, 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || key == "" {
+			return fmt.Errorf("cannot parse %q as a key value pair", pair)
+		}
+		keys = append(keys, key)
+		values = append(values, unquote(strings.TrimSpace(kv[1])))
+	}
+
+	attrs := lr.Attributes()
+	for i, key := range keys {
+		attrs.UpsertString(key, values[i])
+	}
+	return nil
+}
+
+// splitPairs splits the value on the pair delimiter, or on whitespace if it is not
+// set, ignoring the delimiters found between quotes.
+func (p *keyValueParser) splitPairs(value string) []string {
+	var pairs []string
+	var quote rune
+	start := 0
+	for i, r := range value {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			continue
+		case r == '"' || r == '\'':
+			quote = r
+			continue
+		}
+
+		end := -1
+		if p.pairDelimiter == "" {
+			if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+				end = i + 1
+			}
+		} else if strings.HasPrefix(value[i:], p.pairDelimiter) {
+			end = i + len(p.pairDelimiter)
+		}
+		if end < 0 || i < start {
+			continue
+		}
+		if pair := strings.TrimSpace(value[start:i]); pair != "" {
+			pairs = append(pairs, pair)
+		}
+		start = end
+	}
+	if pair := strings.TrimSpace(value[start:]); pair != "" {
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestKeyValueParser(t *testing.T) {
+	testcases := []struct {
+		name          string
+		cfg           OperatorConfig
+		body          string
+		expectedAttrs map[string]string
+		errorString   string
+	}{
+		{
+			name: "default_delimiters",
+			body: "level=info  msg=\"user logged in\" user='alice smith'\tid=42",
+			expectedAttrs: map[string]string{
+				"level": "info",
+				"msg":   "user logged in",
+				"user":  "alice smith",
+				"id":    "42",
+			},
+		},
+		{
+			name: "custom_delimiters",
+			cfg:  OperatorConfig{Delimiter: ":", PairDelimiter: ", "},
+			body: "host: web-1, path: /a,b, status: 200",
+			expectedAttrs: map[string]string{
+				"host":   "web-1",
+				"path":   "/a,b",
+				"status": "200",
+			},
+		},
+		{
+			name: "quoted_pair_delimiter",
+			cfg:  OperatorConfig{PairDelimiter: ";"},
+			body: `a=1;b="x;y";c=`,
+			expectedAttrs: map[string]string{
+				"a": "1",
+				"b": "x;y",
+				"c": "",
+			},
+		},
+		{
+			name: "value_with_delimiter",
+			body: "query=a=b",
+			expectedAttrs: map[string]string{
+				"query": "a=b",
+			},
+		},
+		{
+			name:          "missing_delimiter",
+			body:          "a=1 b",
+			expectedAttrs: map[string]string{},
+			errorString:   `cannot parse "b" as a key value pair`,
+		},
+		{
+			name:          "missing_key",
+			body:          "=1",
+			expectedAttrs: map[string]string{},
+			errorString:   `cannot parse "=1" as a key value pair`,
+		},
+		{
+			name:          "empty",
+			body:          "  ",
+			expectedAttrs: map[string]string{},
+			errorString:   "no key value pair found in body",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newKeyValueParser(field{}, &tc.cfg)
+			require.NoError(t, err)
+
+			lr := newLogRecord(tc.body)
+			err = p.process(lr)
+			if tc.errorString != "" {
+				assert.EqualError(t, err, tc.errorString)
+			} else {
+				assert.NoError(t, err)
+			}
+			expected := pdata.NewAttributeMap()
+			for k, v := range tc.expectedAttrs {
+				expected.InsertString(k, v)
+			}
+			assert.Equal(t, expected.Sort(), lr.Attributes().Sort())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// operator transforms a single log record.
+type operator interface {
+	// process applies the operator to the log record. The log record is left unchanged
+	// when an error is returned.
+	process(lr pdata.LogRecord) error
+}
+
+type pipelineOperator struct {
+	operator
+	typ     OperatorType
+	onError OnErrorAction
+}
+
+// Pipeline applies a chain of operators to the log records.
+type Pipeline struct {
+	operators []pipelineOperator
+}
+
+// NewPipeline builds the operators configured by cfgs. The operators are applied
+// in the same order as cfgs.
+func NewPipeline(cfgs []OperatorConfig) (*Pipeline, error) {
+	p := &Pipeline{operators: make([]pipelineOperator, 0, len(cfgs))}
+	for i := range cfgs {
+		op, err := newPipelineOperator(&cfgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("operator %d: %v", i, err)
+		}
+		p.operators = append(p.operators, op)
+	}
+	return p, nil
+}
+
+func newPipelineOperator(cfg *OperatorConfig) (pipelineOperator, error) {
+	po := pipelineOperator{typ: cfg.Type, onError: cfg.OnError}
+	switch po.onError {
+	case "":
+		po.onError = OnErrorSend
+	case OnErrorSend, OnErrorDrop:
+	default:
+		return po, fmt.Errorf("invalid on_error %q, must be %q or %q", cfg.OnError, OnErrorSend, OnErrorDrop)
+	}
+
+	from, err := newField(cfg.ParseFrom)
+	if err != nil {
+		return po, err
+	}
+
+	switch cfg.Type {
+	case RegexParser:
+		po.operator, err = newRegexParser(from, cfg)
+	case JSONParser:
+		po.operator, err = newJSONParser(from, cfg)
+	case KeyValueParser:
+		po.operator, err = newKeyValueParser(from, cfg)
+	case SeverityParser:
+		po.operator, err = newSeverityParser(from, cfg)
+	case TimeParser:
+		po.operator, err = newTimeParser(from, cfg)
+	default:
+		err = fmt.Errorf("unknown type %q", cfg.Type)
+	}
+	return po, err
+}
+
+// Process applies the operators to the log record. An operator that fails leaves
+// the log record unchanged and, depending on its on_error action, the next operators
+// are applied or the log record must be dropped. It returns false if the log record
+// must be dropped, and the errors of the operators that failed.
+func (p *Pipeline) Process(lr pdata.LogRecord) (bool, []error) {
+	var errs []error
+	for _, op := range p.operators {
+		if err := op.process(lr); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", op.typ, err))
+			if op.onError == OnErrorDrop {
+				return false, errs
+			}
+		}
+	}
+	return true, errs
+}
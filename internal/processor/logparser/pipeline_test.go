@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func newLogRecord(body string) pdata.LogRecord {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	lr.Body().SetStringVal(body)
+	return lr
+}
+
+func TestNewPipeline_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		cfg         OperatorConfig
+		errorString string
+	}{
+		{
+			name:        "missing_type",
+			cfg:         OperatorConfig{},
+			errorString: `operator 0: unknown type ""`,
+		},
+		{
+			name:        "unknown_type",
+			cfg:         OperatorConfig{Type: "xml_parser"},
+			errorString: `operator 0: unknown type "xml_parser"`,
+		},
+		{
+			name:        "invalid_on_error",
+			cfg:         OperatorConfig{Type: JSONParser, OnError: "retry"},
+			errorString: `operator 0: invalid on_error "retry", must be "send" or "drop"`,
+		},
+		{
+			name:        "invalid_parse_from",
+			cfg:         OperatorConfig{Type: JSONParser, ParseFrom: "resource.host"},
+			errorString: `operator 0: invalid field "resource.host", must be "body" or start with "attributes."`,
+		},
+		{
+			name:        "empty_attribute",
+			cfg:         OperatorConfig{Type: JSONParser, ParseFrom: "attributes."},
+			errorString: `operator 0: invalid field "attributes.", must be "body" or start with "attributes."`,
+		},
+		{
+			name:        "missing_regex",
+			cfg:         OperatorConfig{Type: RegexParser},
+			errorString: "operator 0: missing regex",
+		},
+		{
+			name:        "invalid_regex",
+			cfg:         OperatorConfig{Type: RegexParser, Regex: "(?P<a>"},
+			errorString: "operator 0: invalid regex: error parsing regexp: missing closing ): `(?P<a>`",
+		},
+		{
+			name:        "regex_without_named_group",
+			cfg:         OperatorConfig{Type: RegexParser, Regex: "(a)"},
+			errorString: `operator 0: regex "(a)" has no named group`,
+		},
+		{
+			name:        "same_delimiters",
+			cfg:         OperatorConfig{Type: KeyValueParser, PairDelimiter: "="},
+			errorString: `operator 0: delimiter and pair_delimiter cannot be both "="`,
+		},
+		{
+			name:        "unknown_severity",
+			cfg:         OperatorConfig{Type: SeverityParser, Mapping: map[string][]string{"verbose": {"v"}}},
+			errorString: `operator 0: unknown severity "verbose" in mapping`,
+		},
+		{
+			name:        "missing_layout",
+			cfg:         OperatorConfig{Type: TimeParser},
+			errorString: "operator 0: missing layout",
+		},
+		{
+			name:        "invalid_layout_type",
+			cfg:         OperatorConfig{Type: TimeParser, Layout: "%Y", LayoutType: "python"},
+			errorString: `operator 0: invalid layout_type "python"`,
+		},
+		{
+			name:        "invalid_strptime_layout",
+			cfg:         OperatorConfig{Type: TimeParser, Layout: "%Y-%Q", LayoutType: StrptimeLayout},
+			errorString: `operator 0: invalid strptime layout "%Y-%Q": unsupported directive %Q`,
+		},
+		{
+			name:        "invalid_epoch_layout",
+			cfg:         OperatorConfig{Type: TimeParser, Layout: "minutes", LayoutType: EpochLayout},
+			errorString: `operator 0: invalid epoch layout "minutes", must be "s", "ms", "us" or "ns"`,
+		},
+		{
+			name:        "invalid_location",
+			cfg:         OperatorConfig{Type: TimeParser, Layout: "2006", Location: "Mars/Olympus_Mons"},
+			errorString: "operator 0: invalid location: unknown time zone Mars/Olympus_Mons",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewPipeline([]OperatorConfig{tc.cfg})
+			assert.Nil(t, p)
+			assert.EqualError(t, err, tc.errorString)
+		})
+	}
+}
+
+func TestPipeline_Process(t *testing.T) {
+	p, err := NewPipeline([]OperatorConfig{
+		{
+			Type:  RegexParser,
+			Regex: `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`,
+		},
+		{
+			Type:      TimeParser,
+			ParseFrom: "attributes.time",
+			Layout:    "2006-01-02T15:04:05Z07:00",
+		},
+		{
+			Type:      SeverityParser,
+			ParseFrom: "attributes.level",
+		},
+		{
+			Type:      KeyValueParser,
+			ParseFrom: "attributes.message",
+		},
+	})
+	require.NoError(t, err)
+
+	lr := newLogRecord("2020-11-05T10:15:30Z WARN user=alice status=403")
+	keep, errs := p.Process(lr)
+	assert.True(t, keep)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "WARN", lr.SeverityText())
+	assert.Equal(t, pdata.TimestampUnixNano(1604571330000000000), lr.Timestamp())
+	assert.Equal(t, "2020-11-05T10:15:30Z WARN user=alice status=403", lr.Body().StringVal())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"time":    pdata.NewAttributeValueString("2020-11-05T10:15:30Z"),
+		"level":   pdata.NewAttributeValueString("WARN"),
+		"message": pdata.NewAttributeValueString("user=alice status=403"),
+		"user":    pdata.NewAttributeValueString("alice"),
+		"status":  pdata.NewAttributeValueString("403"),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestPipeline_OnError(t *testing.T) {
+	testcases := []struct {
+		name         string
+		onError      OnErrorAction
+		expectedKeep bool
+		expectedSev  pdata.SeverityNumber
+	}{
+		{
+			name:         "default",
+			expectedKeep: true,
+			expectedSev:  pdata.SeverityNumberERROR,
+		},
+		{
+			name:         "send",
+			onError:      OnErrorSend,
+			expectedKeep: true,
+			expectedSev:  pdata.SeverityNumberERROR,
+		},
+		{
+			name:         "drop",
+			onError:      OnErrorDrop,
+			expectedKeep: false,
+			expectedSev:  pdata.SeverityNumberUNDEFINED,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewPipeline([]OperatorConfig{
+				{Type: JSONParser, OnError: tc.onError},
+				{Type: SeverityParser},
+			})
+			require.NoError(t, err)
+
+			lr := newLogRecord("error")
+			keep, errs := p.Process(lr)
+			assert.Equal(t, tc.expectedKeep, keep)
+			require.Len(t, errs, 1)
+			assert.Contains(t, errs[0].Error(), "json_parser: cannot parse body as a JSON object")
+			assert.Equal(t, tc.expectedSev, lr.SeverityNumber())
+			assert.Equal(t, 0, lr.Attributes().Len())
+		})
+	}
+}
+
+func TestPipeline_FieldNotFound(t *testing.T) {
+	p, err := NewPipeline([]OperatorConfig{
+		{Type: JSONParser, ParseFrom: "attributes.payload"},
+		{Type: JSONParser},
+	})
+	require.NoError(t, err)
+
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	keep, errs := p.Process(lr)
+	assert.True(t, keep)
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], "json_parser: attributes.payload not found")
+	assert.EqualError(t, errs[1], "json_parser: body not found")
+}
+
+func TestPipeline_NonStringField(t *testing.T) {
+	p, err := NewPipeline([]OperatorConfig{
+		{Type: TimeParser, ParseFrom: "attributes.ts", LayoutType: EpochLayout, Layout: "s"},
+		{Type: JSONParser, ParseFrom: "attributes.map"},
+	})
+	require.NoError(t, err)
+
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	lr.Attributes().InsertInt("ts", 1604571330)
+	lr.Attributes().Insert("map", pdata.NewAttributeValueMap())
+	keep, errs := p.Process(lr)
+	assert.True(t, keep)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "json_parser: attributes.map has unsupported type MAP")
+	assert.Equal(t, pdata.TimestampUnixNano(1604571330000000000), lr.Timestamp())
+}
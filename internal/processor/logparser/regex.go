@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// regexParser inserts or updates an attribute for each named group of a regular
+// expression that matches the field.
+type regexParser struct {
+	from  field
+	re    *regexp.Regexp
+	names []string
+}
+
+func newRegexParser(from field, cfg *OperatorConfig) (*regexParser, error) {
+	if cfg.Regex == "" {
+		return nil, errors.New("missing regex")
+	}
+	re, err := regexp.Compile(cfg.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %v", err)
+	}
+	hasNamedGroup := false
+	for _, name := range re.SubexpNames() {
+		if name != "" {
+			hasNamedGroup = true
+			break
+		}
+	}
+	if !hasNamedGroup {
+		return nil, fmt.Errorf("regex %q has no named group", cfg.Regex)
+	}
+	return &regexParser{from: from, re: re, names: re.SubexpNames()}, nil
+}
+
+func (p *regexParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.stringValue(lr)
+	if err != nil {
+		return err
+	}
+	match := p.re.FindStringSubmatchIndex(value)
+	if match == nil {
+		return fmt.Errorf("regex does not match %v", p.from)
+	}
+	attrs := lr.Attributes()
+	for i, name := range p.names {
+		// Skip the whole match, the unnamed groups and the groups that did not participate.
+		if name == "" || match[2*i] < 0 {
+			continue
+		}
+		attrs.UpsertString(name, value[match[2*i]:match[2*i+1]])
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestRegexParser(t *testing.T) {
+	testcases := []struct {
+		name          string
+		regex         string
+		body          string
+		expectedAttrs map[string]pdata.AttributeValue
+		errorString   string
+	}{
+		{
+			name:  "named_groups",
+			regex: `^(?P<method>[A-Z]+) (?P<path>\S+) (\d+)$`,
+			body:  "GET /index.html 200",
+			expectedAttrs: map[string]pdata.AttributeValue{
+				"method": pdata.NewAttributeValueString("GET"),
+				"path":   pdata.NewAttributeValueString("/index.html"),
+			},
+		},
+		{
+			name:  "optional_group",
+			regex: `^(?P<method>[A-Z]+)( (?P<path>\S+))?$`,
+			body:  "GET",
+			expectedAttrs: map[string]pdata.AttributeValue{
+				"method": pdata.NewAttributeValueString("GET"),
+			},
+		},
+		{
+			name:  "empty_group",
+			regex: `^(?P<method>[A-Z]*):(?P<path>\S+)$`,
+			body:  ":/",
+			expectedAttrs: map[string]pdata.AttributeValue{
+				"method": pdata.NewAttributeValueString(""),
+				"path":   pdata.NewAttributeValueString("/"),
+			},
+		},
+		{
+			name:  "update_existing",
+			regex: `^(?P<existing>\w+)$`,
+			body:  "new",
+			expectedAttrs: map[string]pdata.AttributeValue{
+				"existing": pdata.NewAttributeValueString("new"),
+			},
+		},
+		{
+			name:  "no_match",
+			regex: `^(?P<method>[A-Z]+)$`,
+			body:  "get",
+			expectedAttrs: map[string]pdata.AttributeValue{
+				"existing": pdata.NewAttributeValueInt(1),
+			},
+			errorString: "regex does not match body",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newRegexParser(field{}, &OperatorConfig{Regex: tc.regex})
+			require.NoError(t, err)
+
+			lr := newLogRecord(tc.body)
+			lr.Attributes().InsertInt("existing", 1)
+			err = p.process(lr)
+			if tc.errorString != "" {
+				assert.EqualError(t, err, tc.errorString)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, pdata.NewAttributeMap().InitFromMap(tc.expectedAttrs).Sort(), withoutKey(lr.Attributes(), "existing", tc.expectedAttrs).Sort())
+		})
+	}
+}
+
+// withoutKey returns the attributes, without the given key unless it is expected.
+func withoutKey(attrs pdata.AttributeMap, key string, expected map[string]pdata.AttributeValue) pdata.AttributeMap {
+	if _, ok := expected[key]; !ok {
+		attrs.Delete(key)
+	}
+	return attrs
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// severityNames maps the severity names used in the mapping configuration to
+// the severity numbers.
+var severityNames = map[string]pdata.SeverityNumber{
+	"trace":  pdata.SeverityNumberTRACE,
+	"trace2": pdata.SeverityNumberTRACE2,
+	"trace3": pdata.SeverityNumberTRACE3,
+	"trace4": pdata.SeverityNumberTRACE4,
+	"debug":  pdata.SeverityNumberDEBUG,
+	"debug2": pdata.SeverityNumberDEBUG2,
+	"debug3": pdata.SeverityNumberDEBUG3,
+	"debug4": pdata.SeverityNumberDEBUG4,
+	"info":   pdata.SeverityNumberINFO,
+	"info2":  pdata.SeverityNumberINFO2,
+	"info3":  pdata.SeverityNumberINFO3,
+	"info4":  pdata.SeverityNumberINFO4,
+	"warn":   pdata.SeverityNumberWARN,
+	"warn2":  pdata.SeverityNumberWARN2,
+	"warn3":  pdata.SeverityNumberWARN3,
+	"warn4":  pdata.SeverityNumberWARN4,
+	"error":  pdata.SeverityNumberERROR,
+	"error2": pdata.SeverityNumberERROR2,
+	"error3": pdata.SeverityNumberERROR3,
+	"error4": pdata.SeverityNumberERROR4,
+	"fatal":  pdata.SeverityNumberFATAL,
+	"fatal2": pdata.SeverityNumberFATAL2,
+	"fatal3": pdata.SeverityNumberFATAL3,
+	"fatal4": pdata.SeverityNumberFATAL4,
+}
+
+// defaultSeverityAliases are the values, other than the severity names, parsed by
+// default.
+var defaultSeverityAliases = map[string]pdata.SeverityNumber{
+	"dbg":         pdata.SeverityNumberDEBUG,
+	"information": pdata.SeverityNumberINFO,
+	"notice":      pdata.SeverityNumberINFO2,
+	"warning":     pdata.SeverityNumberWARN,
+	"err":         pdata.SeverityNumberERROR,
+	"crit":        pdata.SeverityNumberFATAL,
+	"critical":    pdata.SeverityNumberFATAL,
+	"alert":       pdata.SeverityNumberFATAL2,
+	"emerg":       pdata.SeverityNumberFATAL3,
+	"emergency":   pdata.SeverityNumberFATAL3,
+	"panic":       pdata.SeverityNumberFATAL3,
+}
+
+// severityParser sets the severity number of the log record from the field, and
+// its severity text to the value of the field.
+type severityParser struct {
+	from    field
+	mapping map[string]pdata.SeverityNumber
+}
+
+func newSeverityParser(from field, cfg *OperatorConfig) (*severityParser, error) {
+	p := &severityParser{
+		from:    from,
+		mapping: make(map[string]pdata.SeverityNumber, len(severityNames)+len(defaultSeverityAliases)),
+	}
+	for name, sn := range severityNames {
+		p.mapping[name] = sn
+	}
+	for alias, sn := range defaultSeverityAliases {
+		p.mapping[alias] = sn
+	}
+	for name, values := range cfg.Mapping {
+		sn, ok := severityNames[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown severity %q in mapping", name)
+		}
+		for _, value := range values {
+			p.mapping[strings.ToLower(value)] = sn
+		}
+	}
+	return p, nil
+}
+
+func (p *severityParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.stringValue(lr)
+	if err != nil {
+		return err
+	}
+	sn, ok := p.mapping[strings.ToLower(strings.TrimSpace(value))]
+	if !ok {
+		return fmt.Errorf("unknown severity %q", value)
+	}
+	lr.SetSeverityNumber(sn)
+	lr.SetSeverityText(value)
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSeverityParser(t *testing.T) {
+	mapping := map[string][]string{
+		"ERROR3": {"sev-e3"},
+		"info":   {"I", "200"},
+		"warn":   {"Warning"},
+	}
+	testcases := []struct {
+		value       string
+		expectedSev pdata.SeverityNumber
+		errorString string
+	}{
+		{value: "TRACE", expectedSev: pdata.SeverityNumberTRACE},
+		{value: "debug", expectedSev: pdata.SeverityNumberDEBUG},
+		{value: "Info", expectedSev: pdata.SeverityNumberINFO},
+		{value: "info4", expectedSev: pdata.SeverityNumberINFO4},
+		{value: "notice", expectedSev: pdata.SeverityNumberINFO2},
+		{value: "warning", expectedSev: pdata.SeverityNumberWARN},
+		{value: "ERR", expectedSev: pdata.SeverityNumberERROR},
+		{value: "critical", expectedSev: pdata.SeverityNumberFATAL},
+		{value: "emerg", expectedSev: pdata.SeverityNumberFATAL3},
+		{value: " fatal ", expectedSev: pdata.SeverityNumberFATAL},
+		{value: "i", expectedSev: pdata.SeverityNumberINFO},
+		{value: "200", expectedSev: pdata.SeverityNumberINFO},
+		{value: "SEV-E3", expectedSev: pdata.SeverityNumberERROR3},
+		{value: "verbose", errorString: `unknown severity "verbose"`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			p, err := newSeverityParser(field{attribute: "level"}, &OperatorConfig{Mapping: mapping})
+			require.NoError(t, err)
+
+			lr := newLogRecord("body")
+			lr.Attributes().InsertString("level", tc.value)
+			err = p.process(lr)
+			if tc.errorString != "" {
+				assert.EqualError(t, err, tc.errorString)
+				assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+				assert.Equal(t, "", lr.SeverityText())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSev, lr.SeverityNumber())
+			assert.Equal(t, tc.value, lr.SeverityText())
+		})
+	}
+}
+
+func TestSeverityParser_IntValue(t *testing.T) {
+	p, err := newSeverityParser(field{attribute: "status"}, &OperatorConfig{
+		Mapping: map[string][]string{"error": {"500"}},
+	})
+	require.NoError(t, err)
+
+	lr := newLogRecord("body")
+	lr.Attributes().InsertInt("status", 500)
+	require.NoError(t, p.process(lr))
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "500", lr.SeverityText())
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// strptimeDirectives maps the supported strptime directives to the equivalent
+// elements of the time package layouts.
+var strptimeDirectives = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'e': "_2",
+	'j': "002",
+	'b': "Jan",
+	'h': "Jan",
+	'B': "January",
+	'a': "Mon",
+	'A': "Monday",
+	'H': "15",
+	'I': "03",
+	'M': "04",
+	'S': "05",
+	'L': "000",
+	'f': "000000",
+	'N': "000000000",
+	'p': "PM",
+	'z': "-0700",
+	'Z': "MST",
+	'T': "15:04:05",
+	'D': "01/02/06",
+	'F': "2006-01-02",
+	'%': "%",
+}
+
+// strptimeToGoLayout converts a strptime layout to a time package layout.
+func strptimeToGoLayout(layout string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' {
+			sb.WriteByte(layout[i])
+			continue
+		}
+		if i+1 == len(layout) {
+			return "", fmt.Errorf("invalid strptime layout %q: trailing %%", layout)
+		}
+		i++
+		element, ok := strptimeDirectives[layout[i]]
+		if !ok {
+			return "", fmt.Errorf("invalid strptime layout %q: unsupported directive %%%c", layout, layout[i])
+		}
+		sb.WriteString(element)
+	}
+	return sb.String(), nil
+}
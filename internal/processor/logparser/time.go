@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// epochUnits maps the epoch layouts to the number of nanoseconds of their unit.
+var epochUnits = map[string]float64{
+	"s":  float64(time.Second),
+	"ms": float64(time.Millisecond),
+	"us": float64(time.Microsecond),
+	"ns": float64(time.Nanosecond),
+}
+
+// timeParser sets the timestamp of the log record from the field.
+type timeParser struct {
+	from     field
+	layout   string
+	epoch    bool
+	location *time.Location
+}
+
+func newTimeParser(from field, cfg *OperatorConfig) (*timeParser, error) {
+	if cfg.Layout == "" {
+		return nil, errors.New("missing layout")
+	}
+	p := &timeParser{from: from, layout: cfg.Layout, location: time.UTC}
+
+	switch cfg.LayoutType {
+	case "", GoTimeLayout:
+	case StrptimeLayout:
+		layout, err := strptimeToGoLayout(cfg.Layout)
+		if err != nil {
+			return nil, err
+		}
+		p.layout = layout
+	case EpochLayout:
+		if _, ok := epochUnits[cfg.Layout]; !ok {
+			return nil, fmt.Errorf("invalid epoch layout %q, must be \"s\", \"ms\", \"us\" or \"ns\"", cfg.Layout)
+		}
+		p.epoch = true
+	default:
+		return nil, fmt.Errorf("invalid layout_type %q", cfg.LayoutType)
+	}
+
+	if cfg.Location != "" {
+		location, err := time.LoadLocation(cfg.Location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid location: %v", err)
+		}
+		p.location = location
+	}
+	return p, nil
+}
+
+func (p *timeParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.stringValue(lr)
+	if err != nil {
+		return err
+	}
+	value = strings.TrimSpace(value)
+
+	if p.epoch {
+		ts, err := parseEpoch(value, p.layout)
+		if err != nil {
+			return err
+		}
+		lr.SetTimestamp(ts)
+		return nil
+	}
+
+	t, err := time.ParseInLocation(p.layout, value, p.location)
+	if err != nil {
+		return err
+	}
+	lr.SetTimestamp(pdata.TimestampUnixNano(t.UnixNano()))
+	return nil
+}
+
+func parseEpoch(value string, layout string) (pdata.TimestampUnixNano, error) {
+	unit := epochUnits[layout]
+	// Parse integers separately to not lose the precision of the nanoseconds.
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if i < 0 || float64(i) > math.MaxInt64/unit {
+			return 0, fmt.Errorf("epoch %q out of range", value)
+		}
+		return pdata.TimestampUnixNano(i * int64(unit)), nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %q as an epoch", value)
+	}
+	if f < 0 || f > math.MaxInt64/unit {
+		return 0, fmt.Errorf("epoch %q out of range", value)
+	}
+	return pdata.TimestampUnixNano(f * unit), nil
+}
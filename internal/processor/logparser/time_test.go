@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestTimeParser(t *testing.T) {
+	testcases := []struct {
+		name        string
+		cfg         OperatorConfig
+		value       string
+		expectedTs  pdata.TimestampUnixNano
+		errorString string
+	}{
+		{
+			name:       "gotime",
+			cfg:        OperatorConfig{Layout: "2006-01-02T15:04:05.000Z07:00"},
+			value:      "2020-11-05T12:15:30.123+02:00",
+			expectedTs: 1604571330123000000,
+		},
+		{
+			name:       "gotime_location",
+			cfg:        OperatorConfig{LayoutType: GoTimeLayout, Layout: "2006-01-02 15:04:05", Location: "Europe/Paris"},
+			value:      "2020-11-05 11:15:30",
+			expectedTs: 1604571330000000000,
+		},
+		{
+			name:       "strptime",
+			cfg:        OperatorConfig{LayoutType: StrptimeLayout, Layout: "%d/%b/%Y:%H:%M:%S %z"},
+			value:      "05/Nov/2020:10:15:30 +0000",
+			expectedTs: 1604571330000000000,
+		},
+		{
+			name:       "strptime_fraction",
+			cfg:        OperatorConfig{LayoutType: StrptimeLayout, Layout: "%F %T.%f"},
+			value:      "2020-11-05 10:15:30.000123",
+			expectedTs: 1604571330000123000,
+		},
+		{
+			name:       "strptime_12h",
+			cfg:        OperatorConfig{LayoutType: StrptimeLayout, Layout: "%a %B %e %I:%M:%S %p %y %%"},
+			value:      "Thu November  5 10:15:30 AM 20 %",
+			expectedTs: 1604571330000000000,
+		},
+		{
+			name:       "epoch_seconds",
+			cfg:        OperatorConfig{LayoutType: EpochLayout, Layout: "s"},
+			value:      "1604571330",
+			expectedTs: 1604571330000000000,
+		},
+		{
+			name:       "epoch_fractional_seconds",
+			cfg:        OperatorConfig{LayoutType: EpochLayout, Layout: "s"},
+			value:      "1604571330.5",
+			expectedTs: 1604571330500000000,
+		},
+		{
+			name:       "epoch_milliseconds",
+			cfg:        OperatorConfig{LayoutType: EpochLayout, Layout: "ms"},
+			value:      " 1604571330123 ",
+			expectedTs: 1604571330123000000,
+		},
+		{
+			name:       "epoch_microseconds",
+			cfg:        OperatorConfig{LayoutType: EpochLayout, Layout: "us"},
+			value:      "1604571330123456",
+			expectedTs: 1604571330123456000,
+		},
+		{
+			name:       "epoch_nanoseconds",
+			cfg:        OperatorConfig{LayoutType: EpochLayout, Layout: "ns"},
+			value:      "1604571330123456789",
+			expectedTs: 1604571330123456789,
+		},
+		{
+			name:        "epoch_invalid",
+			cfg:         OperatorConfig{LayoutType: EpochLayout, Layout: "s"},
+			value:       "yesterday",
+			errorString: `cannot parse "yesterday" as an epoch`,
+		},
+		{
+			name:        "epoch_negative",
+			cfg:         OperatorConfig{LayoutType: EpochLayout, Layout: "ms"},
+			value:       "-1",
+			errorString: `epoch "-1" out of range`,
+		},
+		{
+			name:        "epoch_overflow",
+			cfg:         OperatorConfig{LayoutType: EpochLayout, Layout: "s"},
+			value:       "10000000000000",
+			errorString: `epoch "10000000000000" out of range`,
+		},
+		{
+			name:        "layout_mismatch",
+			cfg:         OperatorConfig{Layout: "2006-01-02"},
+			value:       "05/11/2020",
+			errorString: `parsing time "05/11/2020" as "2006-01-02": cannot parse "05/11/2020" as "2006"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := newTimeParser(field{}, &tc.cfg)
+			require.NoError(t, err)
+
+			lr := newLogRecord(tc.value)
+			err = p.process(lr)
+			if tc.errorString != "" {
+				assert.EqualError(t, err, tc.errorString)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedTs, lr.Timestamp())
+		})
+	}
+}
+
+func TestStrptimeToGoLayout(t *testing.T) {
+	layout, err := strptimeToGoLayout("%Y-%m-%dT%H:%M:%S.%L%z [%j] %A %h %Z")
+	require.NoError(t, err)
+	assert.Equal(t, "2006-01-02T15:04:05.000-0700 [002] Monday Jan MST", layout)
+
+	_, err = strptimeToGoLayout("%Y-%")
+	assert.EqualError(t, err, `invalid strptime layout "%Y-%": trailing %`)
+}
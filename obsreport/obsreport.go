@@ -34,6 +34,10 @@ var (
 	gLevel = configtelemetry.LevelBasic
 
 	okStatus = trace.Status{Code: trace.StatusCodeOK}
+
+	// lastValueAggregation is shared by all the views, so that the views returned by
+	// different calls to AllViews are equal, as it happens with view.Sum().
+	lastValueAggregation = view.LastValue()
 )
 
 // setParentLink tries to retrieve a span from parentCtx and if one exists
@@ -99,6 +103,15 @@ func AllViews() (views []*view.View) {
 	}
 	views = append(views, genViews(measures, tagKeys, view.Sum())...)
 
+	// Receiver lag views.
+	measures = []*stats.Int64Measure{
+		mReceiverLag,
+	}
+	tagKeys = []tag.Key{
+		tagKeyReceiver, tagKeyTransport, tagKeySource,
+	}
+	views = append(views, genViews(measures, tagKeys, lastValueAggregation)...)
+
 	// Scraper views.
 	measures = []*stats.Int64Measure{
 		mScraperScrapedMetricPoints,
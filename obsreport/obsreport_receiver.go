@@ -49,11 +49,19 @@ const (
 	// Key used to identify log records refused (ie.: not ingested) by the
 	// Collector.
 	RefusedLogRecordsKey = "refused_log_records"
+
+	// Key used to identify the number of items available in the source of a
+	// receiver that were not received yet.
+	LagKey = "lag"
+	// Key used to identify the source, e.g. a topic partition, of the data
+	// of pull based receivers.
+	SourceKey = "source"
 )
 
 var (
 	tagKeyReceiver, _  = tag.NewKey(ReceiverKey)
 	tagKeyTransport, _ = tag.NewKey(TransportKey)
+	tagKeySource, _    = tag.NewKey(SourceKey)
 
 	receiverPrefix                  = ReceiverKey + nameSep
 	receiveTraceDataOperationSuffix = nameSep + "TraceDataReceived"
@@ -89,6 +97,10 @@ var (
 		receiverPrefix+RefusedLogRecordsKey,
 		"Number of log records that could not be pushed into the pipeline.",
 		stats.UnitDimensionless)
+	mReceiverLag = stats.Int64(
+		receiverPrefix+LagKey,
+		"Number of items available in the source that were not received yet, e.g. the consumer lag of a partition.",
+		stats.UnitDimensionless)
 )
 
 // StartReceiveOptions has the options related to starting a receive operation.
@@ -261,6 +273,24 @@ func ReceiverContext(
 	return ctx
 }
 
+// RecordReceiverLag records the number of items, e.g. messages, available in the given
+// source that were not received yet. It is meant for pull based receivers that know how
+// far behind they are, for instance the consumer lag of a partition for a queue receiver.
+// The receiverCtx must be created with ReceiverContext.
+func RecordReceiverLag(
+	receiverCtx context.Context,
+	source string,
+	lag int64,
+) {
+	if gLevel == configtelemetry.LevelNone {
+		return
+	}
+	_ = stats.RecordWithTags(
+		receiverCtx,
+		[]tag.Mutator{tag.Upsert(tagKeySource, source, tag.WithTTL(tag.TTLNoPropagation))},
+		mReceiverLag.M(lag))
+}
+
 // traceReceiveOp creates the span used to trace the operation. Returning
 // the updated context with the created span.
 func traceReceiveOp(
@@ -37,6 +37,7 @@ var (
 	receiverTag, _  = tag.NewKey("receiver")
 	scraperTag, _   = tag.NewKey("scraper")
 	transportTag, _ = tag.NewKey("transport")
+	sourceTag, _    = tag.NewKey("source")
 	exporterTag, _  = tag.NewKey("exporter")
 	processorTag, _ = tag.NewKey("processor")
 )
@@ -130,6 +131,13 @@ func CheckReceiverMetricsViews(t *testing.T, receiver, protocol string, accepted
 	CheckValueForView(t, receiverTags, droppedMetricPoints, "receiver/refused_metric_points")
 }
 
+// CheckReceiverLagViews checks that for the current exported value for the receiver lag view matches the given value.
+// When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
+func CheckReceiverLagViews(t *testing.T, receiver, protocol, source string, lag int64) {
+	receiverTags := append(tagsForReceiverView(receiver, protocol), tag.Tag{Key: sourceTag, Value: source})
+	CheckValueForView(t, receiverTags, lag, "receiver/lag")
+}
+
 // CheckScraperMetricsViews checks that for the current exported values for metrics scraper views match given values.
 // When this function is called it is required to also call SetupRecordedMetricsTest as first thing.
 func CheckScraperMetricsViews(t *testing.T, receiver, scraper string, scrapedMetricPoints, erroredMetricPoints int64) {
@@ -151,8 +159,13 @@ func CheckValueForView(t *testing.T, wantTags []tag.Tag, value int64, vName stri
 		// Make sure the tags slice is sorted by tag keys.
 		sortTags(row.Tags)
 		if reflect.DeepEqual(wantTags, row.Tags) {
-			sum := row.Data.(*view.SumData)
-			require.Equal(t, float64(value), sum.Value)
+			switch data := row.Data.(type) {
+			case *view.LastValueData:
+				require.Equal(t, float64(value), data.Value)
+			default:
+				sum := data.(*view.SumData)
+				require.Equal(t, float64(value), sum.Value)
+			}
 			return
 		}
 	}
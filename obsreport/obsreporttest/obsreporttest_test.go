@@ -75,6 +75,20 @@ func TestCheckReceiverLogsViews(t *testing.T) {
 	obsreporttest.CheckReceiverLogsViews(t, receiver, transport, 7, 0)
 }
 
+func TestCheckReceiverLagViews(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	receiverCtx := obsreport.ReceiverContext(context.Background(), receiver, transport)
+	obsreport.RecordReceiverLag(receiverCtx, "topic/0", 10)
+	obsreport.RecordReceiverLag(receiverCtx, "topic/0", 3)
+	obsreport.RecordReceiverLag(receiverCtx, "topic/1", 5)
+
+	obsreporttest.CheckReceiverLagViews(t, receiver, transport, "topic/0", 3)
+	obsreporttest.CheckReceiverLagViews(t, receiver, transport, "topic/1", 5)
+}
+
 func TestCheckExporterTracesViews(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	require.NoError(t, err)
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attributesprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filtermetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+type metricAttributesProcessor struct {
+	attrProc *processorhelper.AttrProc
+	include  filtermetric.PropertiesMatcher
+	exclude  filtermetric.PropertiesMatcher
+}
+
+// newMetricAttributesProcessor returns a processor that modifies the labels of the data
+// points of a metric. To construct the attributes processors, the use of the factory
+// methods are required in order to validate the inputs.
+func newMetricAttributesProcessor(attrProc *processorhelper.AttrProc, include, exclude filtermetric.PropertiesMatcher) *metricAttributesProcessor {
+	return &metricAttributesProcessor{
+		attrProc: attrProc,
+		include:  include,
+		exclude:  exclude,
+	}
+}
+
+// ProcessMetrics implements the MetricsProcessor
+func (a *metricAttributesProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		resource := rm.Resource()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			library := ilm.InstrumentationLibrary()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+
+				if a.skipMetric(metric, resource, library) {
+					continue
+				}
+
+				a.processMetric(metric)
+			}
+		}
+	}
+	return md, nil
+}
+
+// processMetric applies the actions to the labels of every data point of the metric.
+func (a *metricAttributesProcessor) processMetric(metric pdata.Metric) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if data := metric.IntGauge(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if data := metric.DoubleGauge(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	case pdata.MetricDataTypeIntSum:
+		if data := metric.IntSum(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if data := metric.DoubleSum(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if data := metric.IntHistogram(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if data := metric.DoubleHistogram(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		if data := metric.DoubleSummary(); !data.IsNil() {
+			for i := 0; i < data.DataPoints().Len(); i++ {
+				if dp := data.DataPoints().At(i); !dp.IsNil() {
+					a.processLabels(dp.LabelsMap())
+				}
+			}
+		}
+	}
+}
+
+// processLabels applies the actions to the labels of a data point. The labels are
+// processed as attributes, and values of other types than string set by the actions are
+// converted to their string representation.
+func (a *metricAttributesProcessor) processLabels(labels pdata.StringMap) {
+	attrs := pdata.NewAttributeMap()
+	attrs.InitEmptyWithCapacity(labels.Len())
+	labels.ForEach(func(k string, v string) {
+		attrs.InsertString(k, v)
+	})
+
+	a.attrProc.Process(attrs)
+
+	labels.InitEmptyWithCapacity(attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		labels.Insert(k, tracetranslator.AttributeValueToString(v, false))
+	})
+}
+
+// skipMetric determines if a metric should be processed.
+// True is returned when a metric should be skipped.
+// False is returned when a metric should not be skipped.
+// The logic determining if a metric should be processed is set
+// in the attribute configuration with the include and exclude settings.
+// Include properties are checked before exclude settings are checked.
+func (a *metricAttributesProcessor) skipMetric(metric pdata.Metric, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
+	if a.include != nil {
+		// A false returned in this case means the metric should not be processed.
+		if include := a.include.MatchMetricProperties(metric, resource, library); !include {
+			return true
+		}
+	}
+
+	if a.exclude != nil {
+		// A true returned in this case means the metric should not be processed.
+		if exclude := a.exclude.MatchMetricProperties(metric, resource, library); exclude {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,291 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package attributesprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+// Common structure for all the Tests
+type metricTestCase struct {
+	name           string
+	inputLabels    map[string]string
+	expectedLabels map[string]string
+}
+
+var allMetricDataTypes = []pdata.MetricDataType{
+	pdata.MetricDataTypeIntGauge,
+	pdata.MetricDataTypeDoubleGauge,
+	pdata.MetricDataTypeIntSum,
+	pdata.MetricDataTypeDoubleSum,
+	pdata.MetricDataTypeIntHistogram,
+	pdata.MetricDataTypeDoubleHistogram,
+	pdata.MetricDataTypeDoubleSummary,
+}
+
+// runIndividualMetricTestCase is the common logic of passing metric data of every type through a configured attributes processor.
+func runIndividualMetricTestCase(t *testing.T, tt metricTestCase, mp component.MetricsProcessor) {
+	for _, dataType := range allMetricDataTypes {
+		t.Run(tt.name+"/"+dataType.String(), func(t *testing.T) {
+			md := generateMetricData(tt.name, dataType, tt.inputLabels)
+			assert.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+			// The order of the labels is not deterministic, sort them before comparing.
+			dataPointLabels(md).Sort()
+			require.Equal(t, generateMetricData(tt.name, dataType, tt.expectedLabels), md)
+		})
+	}
+}
+
+func generateMetricData(metricName string, dataType pdata.MetricDataType, labels map[string]string) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	ilm.Metrics().Resize(1)
+	metric := ilm.Metrics().At(0)
+	metric.SetName(metricName)
+	metric.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeIntGauge:
+		metric.IntGauge().InitEmpty()
+		metric.IntGauge().DataPoints().Resize(1)
+	case pdata.MetricDataTypeDoubleGauge:
+		metric.DoubleGauge().InitEmpty()
+		metric.DoubleGauge().DataPoints().Resize(1)
+	case pdata.MetricDataTypeIntSum:
+		metric.IntSum().InitEmpty()
+		metric.IntSum().DataPoints().Resize(1)
+	case pdata.MetricDataTypeDoubleSum:
+		metric.DoubleSum().InitEmpty()
+		metric.DoubleSum().DataPoints().Resize(1)
+	case pdata.MetricDataTypeIntHistogram:
+		metric.IntHistogram().InitEmpty()
+		metric.IntHistogram().DataPoints().Resize(1)
+	case pdata.MetricDataTypeDoubleHistogram:
+		metric.DoubleHistogram().InitEmpty()
+		metric.DoubleHistogram().DataPoints().Resize(1)
+	case pdata.MetricDataTypeDoubleSummary:
+		metric.DoubleSummary().InitEmpty()
+		metric.DoubleSummary().DataPoints().Resize(1)
+	}
+	dataPointLabels(md).InitFromMap(labels).Sort()
+	return md
+}
+
+// dataPointLabels returns the labels of the only data point generated by generateMetricData.
+func dataPointLabels(md pdata.Metrics) pdata.StringMap {
+	metric := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		return metric.IntGauge().DataPoints().At(0).LabelsMap()
+	case pdata.MetricDataTypeDoubleGauge:
+		return metric.DoubleGauge().DataPoints().At(0).LabelsMap()
+	case pdata.MetricDataTypeIntSum:
+		return metric.IntSum().DataPoints().At(0).LabelsMap()
+	case pdata.MetricDataTypeDoubleSum:
+		return metric.DoubleSum().DataPoints().At(0).LabelsMap()
+	case pdata.MetricDataTypeIntHistogram:
+		return metric.IntHistogram().DataPoints().At(0).LabelsMap()
+	case pdata.MetricDataTypeDoubleHistogram:
+		return metric.DoubleHistogram().DataPoints().At(0).LabelsMap()
+	default:
+		return metric.DoubleSummary().DataPoints().At(0).LabelsMap()
+	}
+}
+
+func newTestMetricsProcessor(t *testing.T, oCfg *Config) component.MetricsProcessor {
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewMetricsNop())
+	require.Nil(t, err)
+	require.NotNil(t, mp)
+	return mp
+}
+
+func TestMetricProcessor_NilEmptyData(t *testing.T) {
+	type nilEmptyTestCase struct {
+		name   string
+		input  pdata.Metrics
+		output pdata.Metrics
+	}
+	testCases := []nilEmptyTestCase{
+		{
+			name:   "empty",
+			input:  testdata.GenerateMetricsEmpty(),
+			output: testdata.GenerateMetricsEmpty(),
+		},
+		{
+			name:   "one-empty-resource-metrics",
+			input:  testdata.GenerateMetricsOneEmptyResourceMetrics(),
+			output: testdata.GenerateMetricsOneEmptyResourceMetrics(),
+		},
+		{
+			name:   "one-empty-one-nil-resource-metrics",
+			input:  testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+			output: testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+		},
+		{
+			name:   "one-empty-one-nil-instrumentation-library",
+			input:  testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+			output: testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+		},
+		{
+			name:   "one-metric-one-nil",
+			input:  testdata.GenerateMetricsOneMetricOneNil(),
+			output: testdata.GenerateMetricsOneMetricOneNil(),
+		},
+		{
+			name:   "all-types-no-data-points",
+			input:  testdata.GenerateMetricsAllTypesNoDataPoints(),
+			output: testdata.GenerateMetricsAllTypesNoDataPoints(),
+		},
+		{
+			name:   "all-types-nil-data",
+			input:  testdata.GenerateMetricsAllTypesNilDataPoint(),
+			output: testdata.GenerateMetricsAllTypesNilDataPoint(),
+		},
+	}
+	oCfg := NewFactory().CreateDefaultConfig().(*Config)
+	oCfg.Settings.Actions = []processorhelper.ActionKeyValue{
+		{Key: "label1", Action: processorhelper.INSERT, Value: 123},
+		{Key: "label1", Action: processorhelper.DELETE},
+	}
+	mp := newTestMetricsProcessor(t, oCfg)
+	for i := range testCases {
+		tt := testCases[i]
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, mp.ConsumeMetrics(context.Background(), tt.input))
+			assert.EqualValues(t, tt.output, tt.input)
+		})
+	}
+}
+
+func TestAttributes_Metrics(t *testing.T) {
+	testCases := []metricTestCase{
+		{
+			name:           "insert",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{"label1": "123", "label2": "value"},
+		},
+		{
+			name:           "update_and_delete",
+			inputLabels:    map[string]string{"label2": "old", "label3": "deleted"},
+			expectedLabels: map[string]string{"label1": "123", "label2": "value"},
+		},
+		{
+			name:           "hash_and_extract",
+			inputLabels:    map[string]string{"user.email": "john@example.com", "http.path": "/api/v1/users"},
+			expectedLabels: map[string]string{"label1": "123", "label2": "value", "user.email": "5224cb6fdd5bbe463af1db8ee499e858fcb79f81", "http.path": "/api/v1/users", "version": "v1"},
+		},
+	}
+
+	oCfg := NewFactory().CreateDefaultConfig().(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "label1", Action: processorhelper.INSERT, Value: 123},
+		{Key: "label2", Action: processorhelper.UPSERT, Value: "value"},
+		{Key: "label3", Action: processorhelper.DELETE},
+		{Key: "user.email", Action: processorhelper.HASH},
+		{Key: "http.path", Action: processorhelper.EXTRACT, RegexPattern: "^/api/(?P<version>v[0-9]+)/"},
+	}
+	mp := newTestMetricsProcessor(t, oCfg)
+	for _, tt := range testCases {
+		runIndividualMetricTestCase(t, tt, mp)
+	}
+}
+
+func TestAttributes_FilterMetricsByName(t *testing.T) {
+	testCases := []metricTestCase{
+		{
+			name:           "http.requests",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{"label1": "value"},
+		},
+		{
+			name:           "http.requests.excluded",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{},
+		},
+		{
+			name:           "db.queries",
+			inputLabels:    map[string]string{},
+			expectedLabels: map[string]string{},
+		},
+	}
+
+	oCfg := NewFactory().CreateDefaultConfig().(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "label1", Action: processorhelper.INSERT, Value: "value"},
+	}
+	oCfg.Include = &filterconfig.MatchProperties{
+		Config:      *createConfig(filterset.Regexp),
+		MetricNames: []string{"^http\\..*"},
+	}
+	oCfg.Exclude = &filterconfig.MatchProperties{
+		Config:      *createConfig(filterset.Strict),
+		MetricNames: []string{"http.requests.excluded"},
+	}
+	mp := newTestMetricsProcessor(t, oCfg)
+	for _, tt := range testCases {
+		runIndividualMetricTestCase(t, tt, mp)
+	}
+}
+
+func TestAttributes_FilterMetricsByResource(t *testing.T) {
+	oCfg := NewFactory().CreateDefaultConfig().(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "label1", Action: processorhelper.INSERT, Value: "value"},
+	}
+	oCfg.Include = &filterconfig.MatchProperties{
+		Config:    *createConfig(filterset.Strict),
+		Resources: []filterconfig.Attribute{{Key: "service.name", Value: "svc"}},
+	}
+	mp := newTestMetricsProcessor(t, oCfg)
+
+	md := generateMetricData("metric", pdata.MetricDataTypeIntGauge, map[string]string{})
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+	assert.Equal(t, generateMetricData("metric", pdata.MetricDataTypeIntGauge, map[string]string{}), md)
+
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("service.name", "svc")
+	assert.NoError(t, mp.ConsumeMetrics(context.Background(), md))
+	v, ok := dataPointLabels(md).Get("label1")
+	assert.True(t, ok)
+	assert.Equal(t, "value", v)
+}
+
+func TestAttributes_FilterMetricsInvalidConfig(t *testing.T) {
+	oCfg := NewFactory().CreateDefaultConfig().(*Config)
+	oCfg.Actions = []processorhelper.ActionKeyValue{
+		{Key: "label1", Action: processorhelper.INSERT, Value: "value"},
+	}
+	oCfg.Include = &filterconfig.MatchProperties{
+		Config:    *createConfig(filterset.Strict),
+		SpanNames: []string{"span"},
+	}
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, oCfg, consumertest.NewMetricsNop())
+	assert.Nil(t, mp)
+	assert.EqualError(t, err, "none of services, span_names, log_names or log_bodies should be specified for metrics")
+}
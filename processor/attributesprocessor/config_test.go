@@ -234,4 +234,24 @@ func TestLoadingConfig(t *testing.T) {
 		},
 	})
 
+	p11 := cfg.Processors["attributes/metrics"]
+	assert.Equal(t, p11, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "attributes/metrics",
+			TypeVal: typeStr,
+		},
+		MatchConfig: filterconfig.MatchConfig{
+			Include: &filterconfig.MatchProperties{
+				Config:      *createConfig(filterset.Regexp),
+				MetricNames: []string{"http\\..*"},
+			},
+		},
+		Settings: processorhelper.Settings{
+			Actions: []processorhelper.ActionKeyValue{
+				{Key: "user_id", Action: processorhelper.HASH},
+				{Key: "env", Action: processorhelper.INSERT, Value: "production"},
+			},
+		},
+	})
+
 }
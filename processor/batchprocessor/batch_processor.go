@@ -17,6 +17,8 @@ package batchprocessor
 import (
 	"context"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opencensus.io/stats"
@@ -24,6 +26,7 @@ import (
 	"go.uber.org/zap"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config/configtelemetry"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/pdata"
@@ -38,6 +41,10 @@ import (
 // Batches are sent out with any of the following conditions:
 // - batch size reaches cfg.SendBatchSize
 // - cfg.Timeout is elapsed since the timestamp when the previous batch was sent out.
+//
+// When cfg.MetadataKeys are configured, a separate batch, with its own size and timeout
+// triggers, is kept for each distinct combination of metadata values, until it has been
+// idle for cfg.MetadataKeysIdleTimeout.
 type batchProcessor struct {
 	name           string
 	logger         *zap.Logger
@@ -46,14 +53,50 @@ type batchProcessor struct {
 	sendBatchSize    uint32
 	timeout          time.Duration
 	sendBatchMaxSize uint32
+	// sendBatchMaxBytes is the maximum size of a batch serialized as an OTLP request.
+	sendBatchMaxBytes uint32
+
+	metadataKeys             []string
+	metadataSource           MetadataSource
+	metadataCardinalityLimit uint32
+	metadataKeysIdleTimeout  time.Duration
+
+	newBatch func() batch
+
+	// defaultShard holds the single batch used when there are no metadata keys.
+	defaultShard *shard
+
+	shardsLock sync.Mutex
+	shards     map[string]*shard
+
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// shard is a batch of data sharing the same metadata values, with the goroutine
+// that sends it.
+type shard struct {
+	processor *batchProcessor
+	// key is the key of the shard in the shards of the processor.
+	key string
+
+	// exportCtx is the context passed to the next consumer.
+	exportCtx context.Context
+	statsTags []tag.Mutator
 
 	timer   *time.Timer
-	done    chan struct{}
 	newItem chan interface{}
 	batch   batch
+	// batchBytes is the size of the batch serialized as an OTLP request, only tracked
+	// when there is a limit on it.
+	batchBytes int
 
-	ctx    context.Context
-	cancel context.CancelFunc
+	// pending is the number of items about to be sent to newItem, the shard is not removed
+	// while it is positive. It is incremented with the shardsLock of the processor held.
+	pending int32
+	// lastItem is when the last item was processed.
+	lastItem time.Time
 }
 
 type batch interface {
@@ -77,21 +120,45 @@ var _ consumer.TracesConsumer = (*batchProcessor)(nil)
 var _ consumer.MetricsConsumer = (*batchProcessor)(nil)
 var _ consumer.LogsConsumer = (*batchProcessor)(nil)
 
-func newBatchProcessor(params component.ProcessorCreateParams, cfg *Config, batch batch, telemetryLevel configtelemetry.Level) *batchProcessor {
+func newBatchProcessor(params component.ProcessorCreateParams, cfg *Config, newBatch func() batch, telemetryLevel configtelemetry.Level) *batchProcessor {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &batchProcessor{
+	bp := &batchProcessor{
 		name:           cfg.Name(),
 		logger:         params.Logger,
 		telemetryLevel: telemetryLevel,
 
-		sendBatchSize:    cfg.SendBatchSize,
-		sendBatchMaxSize: cfg.SendBatchMaxSize,
-		timeout:          cfg.Timeout,
-		done:             make(chan struct{}, 1),
-		newItem:          make(chan interface{}, runtime.NumCPU()),
-		batch:            batch,
-		ctx:              ctx,
-		cancel:           cancel,
+		sendBatchSize:     cfg.SendBatchSize,
+		sendBatchMaxSize:  cfg.SendBatchMaxSize,
+		sendBatchMaxBytes: cfg.SendBatchMaxBytes,
+		timeout:           cfg.Timeout,
+
+		metadataKeys:             cfg.MetadataKeys,
+		metadataSource:           cfg.MetadataSource,
+		metadataCardinalityLimit: cfg.MetadataCardinalityLimit,
+		metadataKeysIdleTimeout:  cfg.MetadataKeysIdleTimeout,
+
+		newBatch: newBatch,
+		shards:   make(map[string]*shard),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if len(bp.metadataKeys) == 0 {
+		bp.defaultShard = bp.newShard("", context.Background())
+	}
+	return bp
+}
+
+// newShard creates a shard. The telemetry of the shards is only tagged with the name of the
+// processor, the metadata values, such as credentials or tenant IDs, are not exposed.
+func (bp *batchProcessor) newShard(key string, exportCtx context.Context) *shard {
+	return &shard{
+		processor: bp,
+		key:       key,
+		exportCtx: exportCtx,
+		statsTags: []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)},
+		newItem:   make(chan interface{}, runtime.NumCPU()),
+		batch:     bp.newBatch(),
+		lastItem:  time.Now(),
 	}
 }
 
@@ -101,126 +168,311 @@ func (bp *batchProcessor) GetCapabilities() component.ProcessorCapabilities {
 
 // Start is invoked during service startup.
 func (bp *batchProcessor) Start(context.Context, component.Host) error {
-	go bp.startProcessingCycle()
+	if bp.defaultShard != nil {
+		bp.startShard(bp.defaultShard)
+	}
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
 func (bp *batchProcessor) Shutdown(context.Context) error {
+	bp.shardsLock.Lock()
 	bp.cancel()
-	<-bp.done
+	bp.shardsLock.Unlock()
+	bp.wg.Wait()
 	return nil
 }
 
-func (bp *batchProcessor) startProcessingCycle() {
-	bp.timer = time.NewTimer(bp.timeout)
+func (bp *batchProcessor) startShard(s *shard) {
+	bp.wg.Add(1)
+	go func() {
+		defer bp.wg.Done()
+		s.startProcessingCycle()
+	}()
+}
+
+// shard returns the shard for the metadata values, creating and starting it if needed.
+// The shard is not removed until the item is sent to it with send.
+func (bp *batchProcessor) shard(values []string, exportCtx func() context.Context) (*shard, error) {
+	key := metadataShardKey(values)
+
+	bp.shardsLock.Lock()
+	defer bp.shardsLock.Unlock()
+	if s, ok := bp.shards[key]; ok {
+		atomic.AddInt32(&s.pending, 1)
+		return s, nil
+	}
+	if bp.ctx.Err() != nil {
+		return nil, errProcessorShutdown
+	}
+	if uint32(len(bp.shards)) >= bp.metadataCardinalityLimit {
+		_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}, statMetadataCardinalityLimitExceeded.M(1))
+		return nil, errTooManyBatchers
+	}
+
+	s := bp.newShard(key, exportCtx())
+	s.pending = 1
+	bp.shards[key] = s
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}, statMetadataCardinality.M(int64(len(bp.shards))))
+	bp.startShard(s)
+	return s, nil
+}
+
+// removeIdleShard removes the shard if it has no data and none is about to be sent to it.
+// It returns whether the shard was removed, its goroutine must then stop.
+func (bp *batchProcessor) removeIdleShard(s *shard) bool {
+	bp.shardsLock.Lock()
+	defer bp.shardsLock.Unlock()
+	if atomic.LoadInt32(&s.pending) > 0 || len(s.newItem) > 0 || s.batch.itemCount() > 0 {
+		return false
+	}
+	delete(bp.shards, s.key)
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}, statMetadataCardinality.M(int64(len(bp.shards))))
+	return true
+}
+
+// send sends the item to the shard returned by shard.
+func (s *shard) send(item interface{}) {
+	s.newItem <- item
+	atomic.AddInt32(&s.pending, -1)
+}
+
+// sendLater sends the remaining of a split item to the shard, without blocking its goroutine.
+func (s *shard) sendLater(item interface{}) {
+	atomic.AddInt32(&s.pending, 1)
+	go s.send(item)
+}
+
+// idle returns whether the shard has been idle long enough to be removed. The shard used
+// when there are no metadata keys is never removed.
+func (s *shard) idle() bool {
+	bp := s.processor
+	return s != bp.defaultShard && bp.metadataKeysIdleTimeout > 0 && s.batch.itemCount() == 0 &&
+		time.Since(s.lastItem) >= bp.metadataKeysIdleTimeout
+}
+
+func (s *shard) startProcessingCycle() {
+	bp := s.processor
+	s.timer = time.NewTimer(bp.timeout)
 	for {
 		select {
 		case <-bp.ctx.Done():
 		DONE:
 			for {
 				select {
-				case item := <-bp.newItem:
-					bp.processItem(item)
+				case item := <-s.newItem:
+					s.processItem(item)
 				default:
 					break DONE
 				}
 			}
 			// This is the close of the channel
-			if bp.batch.itemCount() > 0 {
+			if s.batch.itemCount() > 0 {
 				// TODO: Set a timeout on sendTraces or
 				// make it cancellable using the context that Shutdown gets as a parameter
-				bp.sendItems(statTimeoutTriggerSend)
+				s.sendItems(statTimeoutTriggerSend)
 			}
-			close(bp.done)
 			return
-		case item := <-bp.newItem:
+		case item := <-s.newItem:
 			if item == nil {
 				continue
 			}
-			bp.processItem(item)
-		case <-bp.timer.C:
-			if bp.batch.itemCount() > 0 {
-				bp.sendItems(statTimeoutTriggerSend)
+			s.processItem(item)
+		case <-s.timer.C:
+			if s.batch.itemCount() > 0 {
+				s.sendItems(statTimeoutTriggerSend)
+			}
+			if s.idle() && bp.removeIdleShard(s) {
+				return
 			}
-			bp.resetTimer()
+			s.resetTimer()
 		}
 	}
 }
 
-func (bp *batchProcessor) processItem(item interface{}) {
+func (s *shard) processItem(item interface{}) {
+	bp := s.processor
+	s.lastItem = time.Now()
 	if bp.sendBatchMaxSize > 0 {
-		if td, ok := item.(pdata.Traces); ok {
-			itemCount := bp.batch.itemCount()
-			if itemCount+uint32(td.SpanCount()) > bp.sendBatchMaxSize {
-				tdRemainSize := splitTrace(int(bp.sendBatchSize-itemCount), td)
-				item = tdRemainSize
-				go func() {
-					bp.newItem <- td
-				}()
-			}
+		itemCount := s.batch.itemCount()
+		if itemCount+uint32(itemCountOf(item)) > bp.sendBatchMaxSize {
+			remaining := item
+			item = splitItem(int(bp.sendBatchSize-itemCount), remaining)
+			s.sendLater(remaining)
 		}
 	}
 
-	bp.batch.add(item)
-	if bp.batch.itemCount() >= bp.sendBatchSize {
-		bp.timer.Stop()
-		bp.sendItems(statBatchSizeTriggerSend)
-		bp.resetTimer()
+	if bp.sendBatchMaxBytes > 0 {
+		itemBytes := itemSize(item)
+		if s.batch.itemCount() > 0 && s.batchBytes+itemBytes > int(bp.sendBatchMaxBytes) {
+			// The item does not fit in the current batch, send the batch first.
+			s.timer.Stop()
+			s.sendItems(statBatchSizeTriggerSend)
+			s.resetTimer()
+		}
+		if itemBytes > int(bp.sendBatchMaxBytes) {
+			remaining := item
+			item = splitItemBytes(int(bp.sendBatchMaxBytes), remaining)
+			itemBytes = itemSize(item)
+			s.sendLater(remaining)
+		}
+		s.batchBytes += itemBytes
+	}
+
+	s.batch.add(item)
+	if s.batch.itemCount() >= bp.sendBatchSize {
+		s.timer.Stop()
+		s.sendItems(statBatchSizeTriggerSend)
+		s.resetTimer()
+	}
+}
+
+// itemCountOf returns the number of spans, metrics or log records of the item.
+func itemCountOf(item interface{}) int {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return data.SpanCount()
+	case pdata.Metrics:
+		return data.MetricCount()
+	case pdata.Logs:
+		return data.LogRecordCount()
+	}
+	return 0
+}
+
+// itemSize returns the size of the item serialized as an OTLP request.
+func itemSize(item interface{}) int {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return tracesSize(data)
+	case pdata.Metrics:
+		return metricsSize(data)
+	case pdata.Logs:
+		return logsSize(data)
+	}
+	return 0
+}
+
+// splitItem removes up to size spans, metrics or log records from the item and returns them.
+func splitItem(size int, item interface{}) interface{} {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return splitTrace(size, data)
+	case pdata.Metrics:
+		return splitMetrics(size, data)
+	case pdata.Logs:
+		return splitLogs(size, data)
+	}
+	return item
+}
+
+// splitItemBytes removes spans, metrics or log records from the item and returns them,
+// within maxBytes once serialized as an OTLP request.
+func splitItemBytes(maxBytes int, item interface{}) interface{} {
+	switch data := item.(type) {
+	case pdata.Traces:
+		return splitTraceBytes(maxBytes, data)
+	case pdata.Metrics:
+		return splitMetricsBytes(maxBytes, data)
+	case pdata.Logs:
+		return splitLogsBytes(maxBytes, data)
 	}
+	return item
 }
 
-func (bp *batchProcessor) resetTimer() {
-	bp.timer.Reset(bp.timeout)
+func (s *shard) resetTimer() {
+	s.timer.Reset(s.processor.timeout)
 }
 
-func (bp *batchProcessor) sendItems(measure *stats.Int64Measure) {
+func (s *shard) sendItems(measure *stats.Int64Measure) {
+	bp := s.processor
 	// Add that it came form the trace pipeline?
-	statsTags := []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, bp.name)}
-	_ = stats.RecordWithTags(context.Background(), statsTags, measure.M(1), statBatchSendSize.M(int64(bp.batch.itemCount())))
+	_ = stats.RecordWithTags(context.Background(), s.statsTags, measure.M(1), statBatchSendSize.M(int64(s.batch.itemCount())))
 
 	if bp.telemetryLevel == configtelemetry.LevelDetailed {
-		_ = stats.RecordWithTags(context.Background(), statsTags, statBatchSendSizeBytes.M(int64(bp.batch.size())))
+		_ = stats.RecordWithTags(context.Background(), s.statsTags, statBatchSendSizeBytes.M(int64(s.batch.size())))
 	}
 
-	if err := bp.batch.export(context.Background()); err != nil {
+	if err := s.batch.export(s.exportCtx); err != nil {
 		bp.logger.Warn("Sender failed", zap.Error(err))
 	}
-	bp.batch.reset()
+	s.batch.reset()
+	s.batchBytes = 0
 }
 
 // ConsumeTraces implements TracesProcessor
-func (bp *batchProcessor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
-	bp.newItem <- td
-	return nil
+func (bp *batchProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if bp.defaultShard != nil {
+		bp.defaultShard.newItem <- td
+		return nil
+	}
+	if bp.metadataSource == ResourceMetadataSource {
+		return bp.consumeByResource(splitTracesByResource(td, bp.resourceValues))
+	}
+	return bp.consumeByContext(ctx, td)
 }
 
 // ConsumeTraces implements MetricsProcessor
-func (bp *batchProcessor) ConsumeMetrics(_ context.Context, md pdata.Metrics) error {
+func (bp *batchProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
 	// First thing is convert into a different internal format
-	bp.newItem <- md
-	return nil
+	if bp.defaultShard != nil {
+		bp.defaultShard.newItem <- md
+		return nil
+	}
+	if bp.metadataSource == ResourceMetadataSource {
+		return bp.consumeByResource(splitMetricsByResource(md, bp.resourceValues))
+	}
+	return bp.consumeByContext(ctx, md)
 }
 
 // ConsumeLogs implements LogsProcessor
-func (bp *batchProcessor) ConsumeLogs(_ context.Context, ld pdata.Logs) error {
-	bp.newItem <- ld
+func (bp *batchProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if bp.defaultShard != nil {
+		bp.defaultShard.newItem <- ld
+		return nil
+	}
+	if bp.metadataSource == ResourceMetadataSource {
+		return bp.consumeByResource(splitLogsByResource(ld, bp.resourceValues))
+	}
+	return bp.consumeByContext(ctx, ld)
+}
+
+func (bp *batchProcessor) consumeByContext(ctx context.Context, item interface{}) error {
+	values := bp.contextValues(ctx)
+	s, err := bp.shard(values, func() context.Context { return bp.exportContext(values) })
+	if err != nil {
+		return err
+	}
+	s.send(item)
 	return nil
 }
 
+func (bp *batchProcessor) consumeByResource(groups []resourceGroup) error {
+	var errs []error
+	for _, group := range groups {
+		s, err := bp.shard(group.values, context.Background)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		s.send(group.item)
+	}
+	return componenterror.CombineErrors(errs)
+}
+
 // newBatchTracesProcessor creates a new batch processor that batches traces by size or with timeout
 func newBatchTracesProcessor(params component.ProcessorCreateParams, trace consumer.TracesConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchTraces(trace), telemetryLevel)
+	return newBatchProcessor(params, cfg, func() batch { return newBatchTraces(trace) }, telemetryLevel)
 }
 
 // newBatchMetricsProcessor creates a new batch processor that batches metrics by size or with timeout
 func newBatchMetricsProcessor(params component.ProcessorCreateParams, metrics consumer.MetricsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchMetrics(metrics), telemetryLevel)
+	return newBatchProcessor(params, cfg, func() batch { return newBatchMetrics(metrics) }, telemetryLevel)
 }
 
 // newBatchLogsProcessor creates a new batch processor that batches logs by size or with timeout
 func newBatchLogsProcessor(params component.ProcessorCreateParams, logs consumer.LogsConsumer, cfg *Config, telemetryLevel configtelemetry.Level) *batchProcessor {
-	return newBatchProcessor(params, cfg, newBatchLogs(logs), telemetryLevel)
+	return newBatchProcessor(params, cfg, func() batch { return newBatchLogs(logs) }, telemetryLevel)
 }
 
 type batchTraces struct {
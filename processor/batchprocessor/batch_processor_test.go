@@ -17,6 +17,8 @@ package batchprocessor
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -24,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -715,3 +718,193 @@ func logsReceivedByName(lds []pdata.Logs) map[string]pdata.LogRecord {
 	}
 	return logsReceivedByName
 }
+
+// tenantTracesSink records the spans received for each tenant, read from the metadata of
+// the context or, if missing, from the resource attributes.
+type tenantTracesSink struct {
+	mu      sync.Mutex
+	batches []map[string]int
+}
+
+func (s *tenantTracesSink) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	spansByTenant := make(map[string]int)
+	md, _ := metadata.FromIncomingContext(ctx)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		tenant := strings.Join(md.Get("tenant"), ",")
+		if v, ok := rss.At(i).Resource().Attributes().Get("tenant"); ok {
+			tenant = v.StringVal()
+		}
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spansByTenant[tenant] += ilss.At(j).Spans().Len()
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, spansByTenant)
+	return nil
+}
+
+func (s *tenantTracesSink) allBatches() []map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func newTenantContext(tenant string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("tenant", tenant))
+}
+
+func TestBatchProcessor_MetadataFromContext(t *testing.T) {
+	sink := &tenantTracesSink{}
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 20
+	cfg.Timeout = time.Hour
+	cfg.MetadataKeys = []string{"tenant"}
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, batcher.ConsumeTraces(newTenantContext("acme"), testdata.GenerateTraceDataManySpansSameResource(10)))
+		assert.NoError(t, batcher.ConsumeTraces(newTenantContext("globex"), testdata.GenerateTraceDataManySpansSameResource(5)))
+	}
+
+	// The acme batch reached the batch size while the globex one is still pending.
+	require.Eventually(t, func() bool { return len(sink.allBatches()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]int{"acme": 20}, sink.allBatches()[0])
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	require.Len(t, sink.allBatches(), 2)
+	assert.Equal(t, map[string]int{"globex": 10}, sink.allBatches()[1])
+}
+
+func TestBatchProcessor_MetadataFromResource(t *testing.T) {
+	sink := &tenantTracesSink{}
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"tenant"}
+	cfg.MetadataSource = ResourceMetadataSource
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	td := pdata.NewTraces()
+	for _, tenant := range []string{"acme", "globex", "acme", ""} {
+		rs := testdata.GenerateTraceDataManySpansSameResource(3).ResourceSpans().At(0)
+		if tenant != "" {
+			rs.Resource().Attributes().InsertString("tenant", tenant)
+		}
+		td.ResourceSpans().Append(rs)
+	}
+	assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	assert.ElementsMatch(t, []map[string]int{
+		{"acme": 6},
+		{"globex": 3},
+		{"": 3},
+	}, sink.allBatches())
+}
+
+func TestBatchProcessor_MetadataCardinalityLimit(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"tenant"}
+	cfg.MetadataCardinalityLimit = 1
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchMetricsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeMetrics(newTenantContext("acme"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+	assert.Equal(t, errTooManyBatchers, batcher.ConsumeMetrics(newTenantContext("globex"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+	assert.NoError(t, batcher.ConsumeMetrics(newTenantContext("acme"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 4, sink.MetricsCount())
+	assert.Equal(t, errProcessorShutdown, batcher.ConsumeMetrics(newTenantContext("globex"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+}
+
+func TestBatchProcessor_MetadataKeysIdleTimeout(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Timeout = 10 * time.Millisecond
+	cfg.MetadataKeys = []string{"tenant"}
+	cfg.MetadataCardinalityLimit = 1
+	cfg.MetadataKeysIdleTimeout = 50 * time.Millisecond
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchMetricsProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, batcher.ConsumeMetrics(newTenantContext("acme"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+	assert.Equal(t, errTooManyBatchers, batcher.ConsumeMetrics(newTenantContext("globex"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+
+	// The batch of the first tenant is removed once idle, making room for the second one.
+	assert.Eventually(t, func() bool {
+		return batcher.ConsumeMetrics(newTenantContext("globex"), testdata.GenerateMetricsManyMetricsSameResource(2)) == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, errTooManyBatchers, batcher.ConsumeMetrics(newTenantContext("acme"), testdata.GenerateMetricsManyMetricsSameResource(2)))
+
+	require.NoError(t, batcher.Shutdown(context.Background()))
+	assert.Equal(t, 4, sink.MetricsCount())
+}
+
+func TestBatchProcessorSentByBytes(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	cfg := createDefaultConfig().(*Config)
+	maxBytes := tracesSize(testdata.GenerateTraceDataManySpansSameResource(25))
+	cfg.SendBatchMaxBytes = uint32(maxBytes)
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchTracesProcessor(creationParams, sink, cfg, configtelemetry.LevelDetailed)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 10
+	spansPerRequest := 40
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		td := testdata.GenerateTraceDataManySpansSameResource(spansPerRequest)
+		spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+		for spanIndex := 0; spanIndex < spansPerRequest; spanIndex++ {
+			spans.At(spanIndex).SetName(getTestSpanName(requestNum, spanIndex))
+		}
+		assert.NoError(t, batcher.ConsumeTraces(context.Background(), td))
+	}
+
+	// wait for all spans to be reported
+	for sink.SpansCount() < requestCount*spansPerRequest {
+		<-time.After(cfg.Timeout)
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*spansPerRequest, sink.SpansCount())
+	for _, td := range sink.AllTraces() {
+		assert.LessOrEqual(t, tracesSize(td), maxBytes)
+	}
+	assert.Len(t, spansReceivedByName(sink.AllTraces()), requestCount*spansPerRequest)
+}
+
+func TestBatchMetricProcessor_EnforceBatchSize(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.SendBatchSize = 128
+	cfg.SendBatchMaxSize = 128
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	batcher := newBatchMetricsProcessor(creationParams, sink, cfg, configtelemetry.LevelBasic)
+	require.NoError(t, batcher.Start(context.Background(), componenttest.NewNopHost()))
+
+	requestCount := 10
+	metricsPerRequest := 100
+	for requestNum := 0; requestNum < requestCount; requestNum++ {
+		assert.NoError(t, batcher.ConsumeMetrics(context.Background(), testdata.GenerateMetricsManyMetricsSameResource(metricsPerRequest)))
+	}
+
+	// wait for all metrics to be reported
+	for sink.MetricsCount() < requestCount*metricsPerRequest {
+		<-time.After(cfg.Timeout)
+	}
+	require.NoError(t, batcher.Shutdown(context.Background()))
+
+	require.Equal(t, requestCount*metricsPerRequest, sink.MetricsCount())
+	for _, md := range sink.AllMetrics() {
+		assert.LessOrEqual(t, md.MetricCount(), 128)
+	}
+}
@@ -20,6 +20,17 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 )
 
+// MetadataSource defines where the values of the metadata keys are read from.
+type MetadataSource string
+
+const (
+	// ContextMetadataSource reads the values from the metadata of the request, such as the
+	// gRPC metadata sent with OTLP, and from the client of the request.
+	ContextMetadataSource MetadataSource = "context"
+	// ResourceMetadataSource reads the values from the resource attributes.
+	ResourceMetadataSource MetadataSource = "resource"
+)
+
 // Config defines configuration for batch processor.
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
@@ -33,4 +44,26 @@ type Config struct {
 	// SendBatchMaxSize is the maximum size of a batch. Larger batches are split into smaller units.
 	// Default value is 0, that means no maximum size.
 	SendBatchMaxSize uint32 `mapstructure:"send_batch_max_size,omitempty"`
+
+	// SendBatchMaxBytes is the maximum size in bytes of a batch, estimated as the size of the
+	// batch serialized as an OTLP request. Larger batches are split into smaller units.
+	// Default value is 0, that means no maximum size.
+	SendBatchMaxBytes uint32 `mapstructure:"send_batch_max_bytes,omitempty"`
+
+	// MetadataKeys are the names of the metadata whose values identify a batch. Data with
+	// different values is kept in separate batches, so that it is never mixed in the same
+	// outgoing request. Default value is empty, that means a single batch.
+	MetadataKeys []string `mapstructure:"metadata_keys,omitempty"`
+
+	// MetadataSource is where the values of the MetadataKeys are read from.
+	MetadataSource MetadataSource `mapstructure:"metadata_source,omitempty"`
+
+	// MetadataCardinalityLimit is the maximum number of batches with distinct metadata values.
+	// Data that would need a new batch beyond the limit is refused.
+	MetadataCardinalityLimit uint32 `mapstructure:"metadata_cardinality_limit,omitempty"`
+
+	// MetadataKeysIdleTimeout is the time after which a batch with distinct metadata values
+	// that received no data is removed, so that it no longer counts towards the
+	// MetadataCardinalityLimit. The batches are never removed if it is 0.
+	MetadataKeysIdleTimeout time.Duration `mapstructure:"metadata_keys_idle_timeout,omitempty"`
 }
@@ -46,6 +46,7 @@ func TestLoadConfig(t *testing.T) {
 	timeout := time.Second * 10
 	sendBatchSize := uint32(10000)
 	sendBatchMaxSize := uint32(11000)
+	sendBatchMaxBytes := uint32(5000000)
 
 	assert.Equal(t, p1,
 		&Config{
@@ -53,8 +54,27 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "batch",
 				NameVal: "batch/2",
 			},
-			SendBatchSize:    sendBatchSize,
-			SendBatchMaxSize: sendBatchMaxSize,
-			Timeout:          timeout,
+			SendBatchSize:            sendBatchSize,
+			SendBatchMaxSize:         sendBatchMaxSize,
+			SendBatchMaxBytes:        sendBatchMaxBytes,
+			Timeout:                  timeout,
+			MetadataSource:           ContextMetadataSource,
+			MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
+			MetadataKeysIdleTimeout:  defaultMetadataKeysIdleTimeout,
+		})
+
+	p2 := cfg.Processors["batch/tenant"]
+	assert.Equal(t, p2,
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: "batch",
+				NameVal: "batch/tenant",
+			},
+			SendBatchSize:            defaultSendBatchSize,
+			Timeout:                  defaultTimeout,
+			MetadataKeys:             []string{"tenant", "region"},
+			MetadataSource:           ResourceMetadataSource,
+			MetadataCardinalityLimit: 10,
+			MetadataKeysIdleTimeout:  time.Minute,
 		})
 }
@@ -16,6 +16,8 @@ package batchprocessor
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -29,8 +31,10 @@ const (
 	// The value of "type" key in configuration.
 	typeStr = "batch"
 
-	defaultSendBatchSize = uint32(8192)
-	defaultTimeout       = 200 * time.Millisecond
+	defaultSendBatchSize            = uint32(8192)
+	defaultTimeout                  = 200 * time.Millisecond
+	defaultMetadataCardinalityLimit = uint32(1000)
+	defaultMetadataKeysIdleTimeout  = 5 * time.Minute
 )
 
 // NewFactory returns a new factory for the Batch processor.
@@ -49,8 +53,11 @@ func createDefaultConfig() configmodels.Processor {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		SendBatchSize: defaultSendBatchSize,
-		Timeout:       defaultTimeout,
+		SendBatchSize:            defaultSendBatchSize,
+		Timeout:                  defaultTimeout,
+		MetadataSource:           ContextMetadataSource,
+		MetadataCardinalityLimit: defaultMetadataCardinalityLimit,
+		MetadataKeysIdleTimeout:  defaultMetadataKeysIdleTimeout,
 	}
 }
 
@@ -61,6 +68,9 @@ func createTraceProcessor(
 	nextConsumer consumer.TracesConsumer,
 ) (component.TracesProcessor, error) {
 	oCfg := cfg.(*Config)
+	if err := validateConfig(oCfg); err != nil {
+		return nil, err
+	}
 	// error can be ignored, level is parsed at the service startup
 	level, _ := telemetry.GetLevel()
 	return newBatchTracesProcessor(params, nextConsumer, oCfg, level), nil
@@ -73,6 +83,9 @@ func createMetricsProcessor(
 	nextConsumer consumer.MetricsConsumer,
 ) (component.MetricsProcessor, error) {
 	oCfg := cfg.(*Config)
+	if err := validateConfig(oCfg); err != nil {
+		return nil, err
+	}
 	level, _ := telemetry.GetLevel()
 	return newBatchMetricsProcessor(params, nextConsumer, oCfg, level), nil
 }
@@ -84,6 +97,25 @@ func createLogsProcessor(
 	nextConsumer consumer.LogsConsumer,
 ) (component.LogsProcessor, error) {
 	oCfg := cfg.(*Config)
+	if err := validateConfig(oCfg); err != nil {
+		return nil, err
+	}
 	level, _ := telemetry.GetLevel()
 	return newBatchLogsProcessor(params, nextConsumer, oCfg, level), nil
 }
+
+func validateConfig(cfg *Config) error {
+	if len(cfg.MetadataKeys) == 0 {
+		return nil
+	}
+	if cfg.MetadataSource != ContextMetadataSource && cfg.MetadataSource != ResourceMetadataSource {
+		return fmt.Errorf("invalid metadata_source %q, must be %q or %q", cfg.MetadataSource, ContextMetadataSource, ResourceMetadataSource)
+	}
+	if cfg.MetadataCardinalityLimit == 0 {
+		return errors.New("metadata_cardinality_limit must be positive when metadata_keys are set")
+	}
+	if cfg.MetadataKeysIdleTimeout < 0 {
+		return errors.New("metadata_keys_idle_timeout must not be negative")
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ package batchprocessor
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
@@ -50,3 +51,26 @@ func TestCreateProcessor(t *testing.T) {
 	assert.NotNil(t, lp)
 	assert.NoError(t, err, "cannot create logs processor")
 }
+
+func TestCreateProcessorInvalidMetadataConfig(t *testing.T) {
+	factory := NewFactory()
+	creationParams := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"tenant"}
+	cfg.MetadataSource = "span"
+	_, err := factory.CreateTracesProcessor(context.Background(), creationParams, cfg, nil)
+	assert.EqualError(t, err, `invalid metadata_source "span", must be "context" or "resource"`)
+
+	cfg = factory.CreateDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"tenant"}
+	cfg.MetadataCardinalityLimit = 0
+	_, err = factory.CreateMetricsProcessor(context.Background(), creationParams, cfg, nil)
+	assert.Error(t, err)
+
+	cfg = factory.CreateDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"tenant"}
+	cfg.MetadataKeysIdleTimeout = -time.Second
+	_, err = factory.CreateLogsProcessor(context.Background(), creationParams, cfg, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// clientIPMetadataKey is the metadata key whose value is the IP of the client of the
+// request, as reported by client.FromContext.
+const clientIPMetadataKey = "client.ip"
+
+var (
+	errTooManyBatchers   = errors.New("too many batches with distinct metadata values, metadata_cardinality_limit reached")
+	errProcessorShutdown = errors.New("batch processor is shut down")
+)
+
+// contextValues returns the values of the metadata keys in the request context. Keys
+// with multiple values are joined with commas and missing keys are empty.
+func (bp *batchProcessor) contextValues(ctx context.Context) []string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := make([]string, len(bp.metadataKeys))
+	for i, key := range bp.metadataKeys {
+		if key == clientIPMetadataKey {
+			if c, ok := client.FromContext(ctx); ok {
+				values[i] = c.IP
+			}
+			continue
+		}
+		values[i] = strings.Join(md.Get(key), ",")
+	}
+	return values
+}
+
+// exportContext returns the context passed to the next consumer for data batched by
+// the context metadata values, so that the metadata is still available downstream.
+func (bp *batchProcessor) exportContext(values []string) context.Context {
+	ctx := context.Background()
+	md := metadata.MD{}
+	for i, key := range bp.metadataKeys {
+		if values[i] == "" {
+			continue
+		}
+		if key == clientIPMetadataKey {
+			ctx = client.NewContext(ctx, &client.Client{IP: values[i]})
+			continue
+		}
+		md.Set(key, values[i])
+	}
+	if md.Len() > 0 {
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	return ctx
+}
+
+// resourceValues returns the values of the metadata keys in the resource attributes.
+func (bp *batchProcessor) resourceValues(resource pdata.Resource) []string {
+	attrs := resource.Attributes()
+	values := make([]string, len(bp.metadataKeys))
+	for i, key := range bp.metadataKeys {
+		if v, ok := attrs.Get(key); ok {
+			values[i] = tracetranslator.AttributeValueToString(v, false)
+		}
+	}
+	return values
+}
+
+// metadataShardKey returns a key that uniquely identifies the metadata values.
+func metadataShardKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
+
+// resourceGroup is the part of the incoming data whose resources have the same
+// metadata values.
+type resourceGroup struct {
+	values []string
+	item   interface{}
+}
+
+func splitTracesByResource(td pdata.Traces, valuesOf func(pdata.Resource) []string) []resourceGroup {
+	var groups []resourceGroup
+	byKey := make(map[string]pdata.Traces)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		values := valuesOf(rs.Resource())
+		key := metadataShardKey(values)
+		dest, ok := byKey[key]
+		if !ok {
+			dest = pdata.NewTraces()
+			byKey[key] = dest
+			groups = append(groups, resourceGroup{values: values, item: dest})
+		}
+		dest.ResourceSpans().Append(rs)
+	}
+	return groups
+}
+
+func splitMetricsByResource(md pdata.Metrics, valuesOf func(pdata.Resource) []string) []resourceGroup {
+	var groups []resourceGroup
+	byKey := make(map[string]pdata.Metrics)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		values := valuesOf(rm.Resource())
+		key := metadataShardKey(values)
+		dest, ok := byKey[key]
+		if !ok {
+			dest = pdata.NewMetrics()
+			byKey[key] = dest
+			groups = append(groups, resourceGroup{values: values, item: dest})
+		}
+		dest.ResourceMetrics().Append(rm)
+	}
+	return groups
+}
+
+func splitLogsByResource(ld pdata.Logs, valuesOf func(pdata.Resource) []string) []resourceGroup {
+	var groups []resourceGroup
+	byKey := make(map[string]pdata.Logs)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		values := valuesOf(rl.Resource())
+		key := metadataShardKey(values)
+		dest, ok := byKey[key]
+		if !ok {
+			dest = pdata.NewLogs()
+			byKey[key] = dest
+			groups = append(groups, resourceGroup{values: values, item: dest})
+		}
+		dest.ResourceLogs().Append(rl)
+	}
+	return groups
+}
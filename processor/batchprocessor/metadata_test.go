@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestContextValuesAndExportContext(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetadataKeys = []string{"X-Tenant", clientIPMetadataKey, "region"}
+	bp := newBatchTracesProcessor(component.ProcessorCreateParams{Logger: zap.NewNop()}, consumertest.NewTracesNop(), cfg, configtelemetry.LevelNone)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme", "x-tenant", "globex"))
+	ctx = client.NewContext(ctx, &client.Client{IP: "10.0.0.1"})
+	values := bp.contextValues(ctx)
+	assert.Equal(t, []string{"acme,globex", "10.0.0.1", ""}, values)
+
+	exportCtx := bp.exportContext(values)
+	md, ok := metadata.FromIncomingContext(exportCtx)
+	assert.True(t, ok)
+	assert.Equal(t, metadata.Pairs("x-tenant", "acme,globex"), md)
+	c, ok := client.FromContext(exportCtx)
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1", c.IP)
+
+	assert.Equal(t, []string{"", "", ""}, bp.contextValues(context.Background()))
+	assert.Equal(t, context.Background(), bp.exportContext([]string{"", "", ""}))
+}
+
+func TestMetadataShardKey(t *testing.T) {
+	assert.NotEqual(t, metadataShardKey([]string{"a,b", ""}), metadataShardKey([]string{"a", "b"}))
+	assert.Equal(t, metadataShardKey([]string{"a", "b"}), metadataShardKey([]string{"a", "b"}))
+}
@@ -29,6 +29,9 @@ var (
 	statTimeoutTriggerSend   = stats.Int64("timeout_trigger_send", "Number of times the batch was sent due to a timeout trigger", stats.UnitDimensionless)
 	statBatchSendSize        = stats.Int64("batch_send_size", "Number of units in the batch", stats.UnitDimensionless)
 	statBatchSendSizeBytes   = stats.Int64("batch_send_size_bytes", "Number of bytes in batch that was sent", stats.UnitBytes)
+
+	statMetadataCardinality              = stats.Int64("metadata_cardinality", "Number of batches with distinct metadata values", stats.UnitDimensionless)
+	statMetadataCardinalityLimitExceeded = stats.Int64("metadata_cardinality_limit_exceeded", "Number of times data was refused because the metadata cardinality limit was reached", stats.UnitDimensionless)
 )
 
 // MetricViews returns the metrics views related to batching
@@ -73,11 +76,29 @@ func MetricViews(level configtelemetry.Level) []*view.View {
 			1000_000, 2000_000, 3000_000, 4000_000, 5000_000, 6000_000, 7000_000, 8000_000, 9000_000),
 	}
 
+	metadataCardinalityView := &view.View{
+		Name:        statMetadataCardinality.Name(),
+		Measure:     statMetadataCardinality,
+		Description: statMetadataCardinality.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	countMetadataCardinalityLimitExceededView := &view.View{
+		Name:        statMetadataCardinalityLimitExceeded.Name(),
+		Measure:     statMetadataCardinalityLimitExceeded,
+		Description: statMetadataCardinalityLimitExceeded.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
 	legacyViews := []*view.View{
 		countBatchSizeTriggerSendView,
 		countTimeoutTriggerSendView,
 		distributionBatchSendSizeView,
 		distributionBatchSendSizeBytesView,
+		metadataCardinalityView,
+		countMetadataCardinalityLimitExceededView,
 	}
 
 	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
@@ -28,7 +28,7 @@ func TestBatchProcessorMetrics(t *testing.T) {
 		level     configtelemetry.Level
 	}{
 		{
-			viewNames: []string{"batch_size_trigger_send", "timeout_trigger_send", "batch_send_size", "batch_send_size_bytes"},
+			viewNames: []string{"batch_size_trigger_send", "timeout_trigger_send", "batch_send_size", "batch_send_size_bytes", "metadata_cardinality", "metadata_cardinality_limit_exceeded"},
 			level:     configtelemetry.LevelDetailed,
 		},
 		{
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"math/bits"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+)
+
+// maxContainerOverhead is the largest size of the tag and the length of a nested message
+// whose size is not known yet, such as the resource spans of a batch being split.
+const maxContainerOverhead = 1 + 5
+
+// fieldSize returns the serialized size of a nested message of the given size, including
+// its tag and length.
+func fieldSize(size int) int {
+	return 1 + (bits.Len64(uint64(size)|1)+6)/7 + size
+}
+
+// tracesSize returns the size in bytes of the traces serialized as an OTLP request.
+func tracesSize(td pdata.Traces) int {
+	size := 0
+	for _, rs := range pdata.TracesToOtlp(td) {
+		if rs != nil {
+			size += fieldSize(rs.Size())
+		}
+	}
+	return size
+}
+
+// metricsSize returns the size in bytes of the metrics serialized as an OTLP request.
+func metricsSize(md pdata.Metrics) int {
+	size := 0
+	for _, rm := range pdata.MetricsToOtlp(md) {
+		if rm != nil {
+			size += fieldSize(rm.Size())
+		}
+	}
+	return size
+}
+
+// logsSize returns the size in bytes of the logs serialized as an OTLP request.
+func logsSize(ld pdata.Logs) int {
+	size := 0
+	for _, rl := range internal.LogsToOtlp(ld.InternalRep()) {
+		if rl != nil {
+			size += fieldSize(rl.Size())
+		}
+	}
+	return size
+}
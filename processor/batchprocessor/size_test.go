@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func TestSizeMatchesOtlpProtoBytes(t *testing.T) {
+	td := testdata.GenerateTraceDataTwoSpansSameResourceOneDifferent()
+	tdBytes, err := td.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.Equal(t, len(tdBytes), tracesSize(td))
+
+	md := testdata.GenerateMetricsAllTypesNoDataPoints()
+	mdBytes, err := md.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.Equal(t, len(mdBytes), metricsSize(md))
+
+	ld := testdata.GenerateLogDataManyLogsSameResource(10)
+	ldBytes, err := ld.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	assert.Equal(t, len(ldBytes), logsSize(ld))
+}
+
+func TestFieldSize(t *testing.T) {
+	assert.Equal(t, 2, fieldSize(0))
+	assert.Equal(t, 2+127, fieldSize(127))
+	assert.Equal(t, 3+128, fieldSize(128))
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+)
+
+// splitLogs removes log records from the input data and returns a new data of the specified size.
+func splitLogs(size int, toSplit pdata.Logs) pdata.Logs {
+	if toSplit.LogRecordCount() <= size {
+		return toSplit
+	}
+	return moveLogs(toSplit, func(count, _ int) bool { return count <= size })
+}
+
+// splitLogsBytes removes log records from the input data and returns a new data whose size,
+// serialized as an OTLP request, is at most maxBytes. At least one log record is always
+// returned, even if it is larger than maxBytes.
+func splitLogsBytes(maxBytes int, toSplit pdata.Logs) pdata.Logs {
+	if logsSize(toSplit) <= maxBytes {
+		return toSplit
+	}
+	return moveLogs(toSplit, func(_, bytes int) bool { return bytes <= maxBytes })
+}
+
+// moveLogs moves log records, starting from the last one, from the input data to a new data
+// for as long as fits accepts the number of log records and the estimated OTLP size in bytes of
+// the new data. At least one log record is always moved.
+func moveLogs(toSplit pdata.Logs, fits func(count, bytes int) bool) pdata.Logs {
+	result := pdata.NewLogs()
+	movedRecords, movedBytes := 0, 0
+	orig := internal.LogsToOtlp(toSplit.InternalRep())
+	rls := toSplit.ResourceLogs()
+	for i := rls.Len() - 1; i >= 0; i-- {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			rls.Resize(i)
+			continue
+		}
+		destRl := pdata.NewResourceLogs()
+		rlBytes := maxContainerOverhead + fieldSize(orig[i].Resource.Size())
+		ills := rl.InstrumentationLibraryLogs()
+		for j := ills.Len() - 1; j >= 0; j-- {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				ills.Resize(j)
+				continue
+			}
+			destIll := pdata.NewInstrumentationLibraryLogs()
+			illBytes := maxContainerOverhead + fieldSize(orig[i].InstrumentationLibraryLogs[j].InstrumentationLibrary.Size())
+			logs := ill.Logs()
+			for k := logs.Len() - 1; k >= 0; k-- {
+				recordBytes := fieldSize(orig[i].InstrumentationLibraryLogs[j].Logs[k].Size())
+				if destIll.IsNil() {
+					recordBytes += illBytes
+				}
+				if destRl.IsNil() {
+					recordBytes += rlBytes
+				}
+				if movedRecords > 0 && !fits(movedRecords+1, movedBytes+recordBytes) {
+					return result
+				}
+				if destRl.IsNil() {
+					destRl.InitEmpty()
+					rl.Resource().CopyTo(destRl.Resource())
+					result.ResourceLogs().Append(destRl)
+				}
+				if destIll.IsNil() {
+					destIll.InitEmpty()
+					ill.InstrumentationLibrary().CopyTo(destIll.InstrumentationLibrary())
+					destRl.InstrumentationLibraryLogs().Append(destIll)
+				}
+				destIll.Logs().Append(logs.At(k))
+				logs.Resize(k)
+				movedRecords++
+				movedBytes += recordBytes
+			}
+			ills.Resize(j)
+		}
+		rls.Resize(i)
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func newTestLogs(resources, logsPerResource int) pdata.Logs {
+	ld := pdata.NewLogs()
+	for i := 0; i < resources; i++ {
+		rl := testdata.GenerateLogDataManyLogsSameResource(logsPerResource).ResourceLogs().At(0)
+		logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+		for j := 0; j < logs.Len(); j++ {
+			logs.At(j).SetName(getTestLogName(i, j))
+		}
+		ld.ResourceLogs().Append(rl)
+	}
+	return ld
+}
+
+func TestSplitLogs_noop(t *testing.T) {
+	ld := newTestLogs(1, 20)
+	split := splitLogs(40, ld)
+	assert.Equal(t, ld, split)
+}
+
+func TestSplitLogs(t *testing.T) {
+	ld := newTestLogs(1, 20)
+	resource := ld.ResourceLogs().At(0).Resource()
+
+	split := splitLogs(5, ld)
+	assert.Equal(t, 5, split.LogRecordCount())
+	assert.Equal(t, 15, ld.LogRecordCount())
+	require.Equal(t, 1, split.ResourceLogs().Len())
+	assert.Equal(t, resource, split.ResourceLogs().At(0).Resource())
+	logs := split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, "test-log-int-0-19", logs.At(0).Name())
+	assert.Equal(t, "test-log-int-0-15", logs.At(4).Name())
+}
+
+func TestSplitLogsMultipleResourceLogs(t *testing.T) {
+	ld := newTestLogs(2, 20)
+
+	split := splitLogs(25, ld)
+	assert.Equal(t, 25, split.LogRecordCount())
+	assert.Equal(t, 15, ld.LogRecordCount())
+	assert.Equal(t, 1, ld.ResourceLogs().Len())
+	require.Equal(t, 2, split.ResourceLogs().Len())
+	assert.Equal(t, "test-log-int-1-19", split.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+	assert.Equal(t, "test-log-int-0-19", split.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+	assert.Equal(t, "test-log-int-0-15", split.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().At(4).Name())
+}
+
+func TestSplitLogsBytes(t *testing.T) {
+	ld := newTestLogs(2, 20)
+	total := ld.LogRecordCount()
+	maxBytes := logsSize(ld) / 3
+
+	var parts []pdata.Logs
+	for ld.LogRecordCount() > 0 {
+		split := splitLogsBytes(maxBytes, ld)
+		assert.LessOrEqual(t, logsSize(split), maxBytes)
+		parts = append(parts, split)
+		if split == ld {
+			break
+		}
+	}
+	assert.GreaterOrEqual(t, len(parts), 3)
+	count := 0
+	for _, part := range parts {
+		count += part.LogRecordCount()
+	}
+	assert.Equal(t, total, count)
+}
+
+func TestSplitLogsBytes_largerThanLimit(t *testing.T) {
+	ld := newTestLogs(1, 2)
+	split := splitLogsBytes(1, ld)
+	assert.Equal(t, 1, split.LogRecordCount())
+	assert.Equal(t, 1, ld.LogRecordCount())
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// splitMetrics removes metrics from the input data and returns a new data of the specified size.
+func splitMetrics(size int, toSplit pdata.Metrics) pdata.Metrics {
+	if toSplit.MetricCount() <= size {
+		return toSplit
+	}
+	return moveMetrics(toSplit, func(count, _ int) bool { return count <= size })
+}
+
+// splitMetricsBytes removes metrics from the input data and returns a new data whose size,
+// serialized as an OTLP request, is at most maxBytes. At least one metric is always
+// returned, even if it is larger than maxBytes.
+func splitMetricsBytes(maxBytes int, toSplit pdata.Metrics) pdata.Metrics {
+	if metricsSize(toSplit) <= maxBytes {
+		return toSplit
+	}
+	return moveMetrics(toSplit, func(_, bytes int) bool { return bytes <= maxBytes })
+}
+
+// moveMetrics moves metrics, starting from the last one, from the input data to a new data
+// for as long as fits accepts the number of metrics and the estimated OTLP size in bytes of
+// the new data. At least one metric is always moved.
+func moveMetrics(toSplit pdata.Metrics, fits func(count, bytes int) bool) pdata.Metrics {
+	result := pdata.NewMetrics()
+	movedMetrics, movedBytes := 0, 0
+	orig := pdata.MetricsToOtlp(toSplit)
+	rms := toSplit.ResourceMetrics()
+	for i := rms.Len() - 1; i >= 0; i-- {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			rms.Resize(i)
+			continue
+		}
+		destRm := pdata.NewResourceMetrics()
+		rmBytes := maxContainerOverhead + fieldSize(orig[i].Resource.Size())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := ilms.Len() - 1; j >= 0; j-- {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				ilms.Resize(j)
+				continue
+			}
+			destIlm := pdata.NewInstrumentationLibraryMetrics()
+			ilmBytes := maxContainerOverhead + fieldSize(orig[i].InstrumentationLibraryMetrics[j].InstrumentationLibrary.Size())
+			metrics := ilm.Metrics()
+			for k := metrics.Len() - 1; k >= 0; k-- {
+				metricBytes := fieldSize(orig[i].InstrumentationLibraryMetrics[j].Metrics[k].Size())
+				if destIlm.IsNil() {
+					metricBytes += ilmBytes
+				}
+				if destRm.IsNil() {
+					metricBytes += rmBytes
+				}
+				if movedMetrics > 0 && !fits(movedMetrics+1, movedBytes+metricBytes) {
+					return result
+				}
+				if destRm.IsNil() {
+					destRm.InitEmpty()
+					rm.Resource().CopyTo(destRm.Resource())
+					result.ResourceMetrics().Append(destRm)
+				}
+				if destIlm.IsNil() {
+					destIlm.InitEmpty()
+					ilm.InstrumentationLibrary().CopyTo(destIlm.InstrumentationLibrary())
+					destRm.InstrumentationLibraryMetrics().Append(destIlm)
+				}
+				destIlm.Metrics().Append(metrics.At(k))
+				metrics.Resize(k)
+				movedMetrics++
+				movedBytes += metricBytes
+			}
+			ilms.Resize(j)
+		}
+		rms.Resize(i)
+	}
+	return result
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batchprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func newTestMetrics(resources, metricsPerResource int) pdata.Metrics {
+	md := pdata.NewMetrics()
+	for i := 0; i < resources; i++ {
+		rm := testdata.GenerateMetricsManyMetricsSameResource(metricsPerResource).ResourceMetrics().At(0)
+		metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metrics.At(j).SetName(getTestMetricName(i, j))
+		}
+		md.ResourceMetrics().Append(rm)
+	}
+	return md
+}
+
+func TestSplitMetrics_noop(t *testing.T) {
+	md := newTestMetrics(1, 20)
+	split := splitMetrics(40, md)
+	assert.Equal(t, md, split)
+}
+
+func TestSplitMetrics(t *testing.T) {
+	md := newTestMetrics(1, 20)
+	resource := md.ResourceMetrics().At(0).Resource()
+
+	split := splitMetrics(5, md)
+	assert.Equal(t, 5, split.MetricCount())
+	assert.Equal(t, 15, md.MetricCount())
+	require.Equal(t, 1, split.ResourceMetrics().Len())
+	assert.Equal(t, resource, split.ResourceMetrics().At(0).Resource())
+	metrics := split.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	assert.Equal(t, "test-metric-int-0-19", metrics.At(0).Name())
+	assert.Equal(t, "test-metric-int-0-15", metrics.At(4).Name())
+}
+
+func TestSplitMetricsMultipleResourceMetrics(t *testing.T) {
+	md := newTestMetrics(2, 20)
+
+	split := splitMetrics(25, md)
+	assert.Equal(t, 25, split.MetricCount())
+	assert.Equal(t, 15, md.MetricCount())
+	assert.Equal(t, 1, md.ResourceMetrics().Len())
+	require.Equal(t, 2, split.ResourceMetrics().Len())
+	assert.Equal(t, "test-metric-int-1-19", split.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+	assert.Equal(t, "test-metric-int-0-19", split.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(0).Metrics().At(0).Name())
+	assert.Equal(t, "test-metric-int-0-15", split.ResourceMetrics().At(1).InstrumentationLibraryMetrics().At(0).Metrics().At(4).Name())
+}
+
+func TestSplitMetricsBytes(t *testing.T) {
+	md := newTestMetrics(2, 20)
+	total := md.MetricCount()
+	maxBytes := metricsSize(md) / 3
+
+	var parts []pdata.Metrics
+	for md.MetricCount() > 0 {
+		split := splitMetricsBytes(maxBytes, md)
+		assert.LessOrEqual(t, metricsSize(split), maxBytes)
+		parts = append(parts, split)
+		if split == md {
+			break
+		}
+	}
+	assert.GreaterOrEqual(t, len(parts), 3)
+	count := 0
+	for _, part := range parts {
+		count += part.MetricCount()
+	}
+	assert.Equal(t, total, count)
+}
+
+func TestSplitMetricsBytes_largerThanLimit(t *testing.T) {
+	md := newTestMetrics(1, 2)
+	split := splitMetricsBytes(1, md)
+	assert.Equal(t, 1, split.MetricCount())
+	assert.Equal(t, 1, md.MetricCount())
+}
@@ -65,3 +65,72 @@ func splitTrace(size int, toSplit pdata.Traces) pdata.Traces {
 	}
 	return result
 }
+
+// splitTraceBytes removes spans from the input trace and returns a new trace whose size,
+// serialized as an OTLP request, is at most maxBytes. At least one span is always
+// returned, even if it is larger than maxBytes.
+func splitTraceBytes(maxBytes int, toSplit pdata.Traces) pdata.Traces {
+	if tracesSize(toSplit) <= maxBytes {
+		return toSplit
+	}
+	return moveSpans(toSplit, func(_, bytes int) bool { return bytes <= maxBytes })
+}
+
+// moveSpans moves spans, starting from the last one, from the input trace to a new trace
+// for as long as fits accepts the number of spans and the estimated OTLP size in bytes of
+// the new trace. At least one span is always moved.
+func moveSpans(toSplit pdata.Traces, fits func(count, bytes int) bool) pdata.Traces {
+	result := pdata.NewTraces()
+	movedSpans, movedBytes := 0, 0
+	orig := pdata.TracesToOtlp(toSplit)
+	rss := toSplit.ResourceSpans()
+	for i := rss.Len() - 1; i >= 0; i-- {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			rss.Resize(i)
+			continue
+		}
+		destRs := pdata.NewResourceSpans()
+		rsBytes := maxContainerOverhead + fieldSize(orig[i].Resource.Size())
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := ilss.Len() - 1; j >= 0; j-- {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				ilss.Resize(j)
+				continue
+			}
+			destIls := pdata.NewInstrumentationLibrarySpans()
+			ilsBytes := maxContainerOverhead + fieldSize(orig[i].InstrumentationLibrarySpans[j].InstrumentationLibrary.Size())
+			spans := ils.Spans()
+			for k := spans.Len() - 1; k >= 0; k-- {
+				spanBytes := fieldSize(orig[i].InstrumentationLibrarySpans[j].Spans[k].Size())
+				if destIls.IsNil() {
+					spanBytes += ilsBytes
+				}
+				if destRs.IsNil() {
+					spanBytes += rsBytes
+				}
+				if movedSpans > 0 && !fits(movedSpans+1, movedBytes+spanBytes) {
+					return result
+				}
+				if destRs.IsNil() {
+					destRs.InitEmpty()
+					rs.Resource().CopyTo(destRs.Resource())
+					result.ResourceSpans().Append(destRs)
+				}
+				if destIls.IsNil() {
+					destIls.InitEmpty()
+					ils.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+					destRs.InstrumentationLibrarySpans().Append(destIls)
+				}
+				destIls.Spans().Append(spans.At(k))
+				spans.Resize(k)
+				movedSpans++
+				movedBytes += spanBytes
+			}
+			ilss.Resize(j)
+		}
+		rss.Resize(i)
+	}
+	return result
+}
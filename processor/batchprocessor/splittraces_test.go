@@ -111,3 +111,32 @@ func TestSplitTracesMultipleResourceSpans_split_size_greater_than_span_size(t *t
 	assert.Equal(t, "test-span-0-19", split.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
 	assert.Equal(t, "test-span-0-15", split.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(4).Name())
 }
+
+func TestSplitTracesBytes(t *testing.T) {
+	td := testdata.GenerateTraceDataManySpansSameResource(20)
+	td.ResourceSpans().Resize(2)
+	testdata.GenerateTraceDataManySpansSameResource(20).ResourceSpans().At(0).CopyTo(td.ResourceSpans().At(1))
+	maxBytes := tracesSize(td) / 3
+
+	var parts []pdata.Traces
+	for td.SpanCount() > 0 {
+		split := splitTraceBytes(maxBytes, td)
+		assert.LessOrEqual(t, tracesSize(split), maxBytes)
+		parts = append(parts, split)
+		if split == td {
+			break
+		}
+	}
+	assert.GreaterOrEqual(t, len(parts), 3)
+	count := 0
+	for _, part := range parts {
+		count += part.SpanCount()
+	}
+	assert.Equal(t, 40, count)
+}
+
+func TestSplitTracesBytes_noop(t *testing.T) {
+	td := testdata.GenerateTraceDataManySpansSameResource(20)
+	split := splitTraceBytes(tracesSize(td), td)
+	assert.Equal(t, td, split)
+}
@@ -16,6 +16,7 @@ package filterprocessor
 
 import (
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
 	"go.opentelemetry.io/collector/internal/processor/filtermetric"
 )
 
@@ -23,6 +24,16 @@ import (
 type Config struct {
 	configmodels.ProcessorSettings `mapstructure:",squash"`
 	Metrics                        MetricFilters `mapstructure:"metrics"`
+
+	// Spans match properties describe spans that should be included in or excluded from
+	// the Collector Service pipeline.
+	// If both Include and Exclude are specified, Include filtering occurs first.
+	Spans filterconfig.MatchConfig `mapstructure:"spans"`
+
+	// Logs match properties describe log records that should be included in or excluded from
+	// the Collector Service pipeline.
+	// If both Include and Exclude are specified, Include filtering occurs first.
+	Logs filterconfig.MatchConfig `mapstructure:"logs"`
 }
 
 // MetricFilter filters by Metric properties.
@@ -24,7 +24,9 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
 	"go.opentelemetry.io/collector/internal/processor/filtermetric"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
 	fsregexp "go.opentelemetry.io/collector/internal/processor/filterset/regexp"
 )
 
@@ -311,3 +313,125 @@ func TestLoadingConfigExpr(t *testing.T) {
 		})
 	}
 }
+
+// TestLoadingConfigSpans tests loading testdata/config_spans.yaml
+func TestLoadingConfigSpans(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Processors[configmodels.Type(typeStr)] = factory
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_spans.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+
+	tests := []struct {
+		filterName string
+		expCfg     configmodels.Processor
+	}{
+		{
+			filterName: "filter/spans",
+			expCfg: &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					NameVal: "filter/spans",
+					TypeVal: typeStr,
+				},
+				Spans: filterconfig.MatchConfig{
+					Include: &filterconfig.MatchProperties{
+						Config:   filterset.Config{MatchType: filterset.Strict},
+						Services: []string{"checkout", "payment"},
+					},
+					Exclude: &filterconfig.MatchProperties{
+						Config:    filterset.Config{MatchType: filterset.Regexp},
+						SpanNames: []string{"health.*"},
+						Attributes: []filterconfig.Attribute{
+							{Key: "http.method", Value: "GET"},
+						},
+					},
+				},
+			},
+		},
+		{
+			filterName: "filter/spans_expr",
+			expCfg: &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					NameVal: "filter/spans_expr",
+					TypeVal: typeStr,
+				},
+				Spans: filterconfig.MatchConfig{
+					Exclude: &filterconfig.MatchProperties{
+						Config: filterset.Config{MatchType: filterconfig.Expr},
+						Expressions: []string{
+							`SpanName == "ping" && ResourceAttribute("host.name") == "localhost"`,
+							`Attribute("http.status_code") == "200"`,
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.filterName, func(t *testing.T) {
+			cfg := config.Processors[test.filterName]
+			assert.Equal(t, test.expCfg, cfg)
+		})
+	}
+}
+
+// TestLoadingConfigLogs tests loading testdata/config_logs.yaml
+func TestLoadingConfigLogs(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	factory := NewFactory()
+	factories.Processors[configmodels.Type(typeStr)] = factory
+	config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config_logs.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, config)
+
+	tests := []struct {
+		filterName string
+		expCfg     configmodels.Processor
+	}{
+		{
+			filterName: "filter/logs",
+			expCfg: &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					NameVal: "filter/logs",
+					TypeVal: typeStr,
+				},
+				Logs: filterconfig.MatchConfig{
+					Include: &filterconfig.MatchProperties{
+						Config: filterset.Config{MatchType: filterset.Strict},
+						Resources: []filterconfig.Attribute{
+							{Key: "service.name", Value: "checkout"},
+						},
+					},
+					Exclude: &filterconfig.MatchProperties{
+						Config:    filterset.Config{MatchType: filterset.Regexp},
+						LogBodies: []string{"^DEBUG.*"},
+					},
+				},
+			},
+		},
+		{
+			filterName: "filter/logs_expr",
+			expCfg: &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					NameVal: "filter/logs_expr",
+					TypeVal: typeStr,
+				},
+				Logs: filterconfig.MatchConfig{
+					Exclude: &filterconfig.MatchProperties{
+						Config:      filterset.Config{MatchType: filterconfig.Expr},
+						Expressions: []string{`SeverityText == "DEBUG" || Body matches "^health"`},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.filterName, func(t *testing.T) {
+			cfg := config.Processors[test.filterName]
+			assert.Equal(t, test.expCfg, cfg)
+		})
+	}
+}
@@ -61,6 +61,12 @@ func TestCreateProcessors(t *testing.T) {
 		}, {
 			configName: "config_strict.yaml",
 			succeed:    true,
+		}, {
+			configName: "config_spans.yaml",
+			succeed:    true,
+		}, {
+			configName: "config_logs.yaml",
+			succeed:    true,
 		}, {
 			configName: "config_invalid.yaml",
 			succeed:    false,
@@ -81,13 +87,16 @@ func TestCreateProcessors(t *testing.T) {
 				factory := NewFactory()
 
 				tp, tErr := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
-				// Not implemented error
-				assert.NotNil(t, tErr)
-				assert.Nil(t, tp)
+				assert.Equal(t, test.succeed, tp != nil)
+				assert.Equal(t, test.succeed, tErr == nil)
 
 				mp, mErr := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
 				assert.Equal(t, test.succeed, mp != nil)
 				assert.Equal(t, test.succeed, mErr == nil)
+
+				lp, lErr := factory.CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewLogsNop())
+				assert.Equal(t, test.succeed, lp != nil)
+				assert.Equal(t, test.succeed, lErr == nil)
 			})
 		}
 	}
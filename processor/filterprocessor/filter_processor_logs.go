@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterlog"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type filterLogProcessor struct {
+	cfg     *Config
+	include filterlog.Matcher
+	exclude filterlog.Matcher
+	logger  *zap.Logger
+}
+
+func newFilterLogProcessor(logger *zap.Logger, cfg *Config) (*filterLogProcessor, error) {
+	inc, err := filterlog.NewMatcher(cfg.Logs.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exc, err := filterlog.NewMatcher(cfg.Logs.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(
+		"Log filter configured",
+		zap.Bool("include", inc != nil),
+		zap.Bool("exclude", exc != nil),
+	)
+
+	return &filterLogProcessor{
+		cfg:     cfg,
+		include: inc,
+		exclude: exc,
+		logger:  logger,
+	}, nil
+}
+
+// ProcessLogs drops the log records that are not included or that are excluded, along with
+// the resources and instrumentation libraries left without log records.
+func (flp *filterLogProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	if flp.include == nil && flp.exclude == nil {
+		return ld, nil
+	}
+
+	out := pdata.NewLogs()
+	rlsOut := out.ResourceLogs()
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		resource := rl.Resource()
+		rlOut := pdata.NewResourceLogs()
+		rlOut.InitEmpty()
+		illsOut := rlOut.InstrumentationLibraryLogs()
+
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			library := ill.InstrumentationLibrary()
+			illOut := pdata.NewInstrumentationLibraryLogs()
+			illOut.InitEmpty()
+			logsOut := illOut.Logs()
+
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				if !flp.shouldKeepLogRecord(lr, resource, library) {
+					continue
+				}
+				logsOut.Append(lr)
+			}
+
+			if logsOut.Len() > 0 {
+				library.CopyTo(illOut.InstrumentationLibrary())
+				illsOut.Append(illOut)
+			}
+		}
+
+		if illsOut.Len() > 0 {
+			resource.CopyTo(rlOut.Resource())
+			rlsOut.Append(rlOut)
+		}
+	}
+
+	if rlsOut.Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return out, nil
+}
+
+func (flp *filterLogProcessor) shouldKeepLogRecord(lr pdata.LogRecord, resource pdata.Resource, library pdata.InstrumentationLibrary) bool {
+	if flp.include != nil && !flp.include.MatchLogRecord(lr, resource, library) {
+		return false
+	}
+
+	if flp.exclude != nil && flp.exclude.MatchLogRecord(lr, resource, library) {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+type logBodyTest struct {
+	name    string
+	inc     *filterconfig.MatchProperties
+	exc     *filterconfig.MatchProperties
+	outLogs [][]string // output log bodies per resource
+}
+
+// inLogs are the log bodies per resource in the input logs, the resources have
+// a "host" attribute with the values "host0", "host1"...
+var inLogs = [][]string{
+	{"DEBUG starting", "INFO started", "ERROR failed"},
+	{"DEBUG stopping", "INFO stopped"},
+}
+
+var standardLogTests = []logBodyTest{
+	{
+		name: "includeBody",
+		inc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Regexp},
+			LogBodies: []string{"^INFO"},
+		},
+		outLogs: [][]string{
+			{"INFO started"},
+			{"INFO stopped"},
+		},
+	},
+	{
+		name: "excludeBody",
+		exc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Strict},
+			LogBodies: []string{"DEBUG starting", "DEBUG stopping"},
+		},
+		outLogs: [][]string{
+			{"INFO started", "ERROR failed"},
+			{"INFO stopped"},
+		},
+	},
+	{
+		name: "includeResourceExcludeAttribute",
+		inc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Strict},
+			Resources: []filterconfig.Attribute{{Key: "host", Value: "host1"}},
+		},
+		exc: &filterconfig.MatchProperties{
+			Config:     filterset.Config{MatchType: filterset.Strict},
+			Attributes: []filterconfig.Attribute{{Key: "index", Value: 0}},
+		},
+		outLogs: [][]string{
+			{"INFO stopped"},
+		},
+	},
+	{
+		name: "excludeExpr",
+		exc: &filterconfig.MatchProperties{
+			Config:      filterset.Config{MatchType: filterconfig.Expr},
+			Expressions: []string{`Body matches "^DEBUG" || ResourceAttribute("host") == "host0" && Attribute("index") == "1"`},
+		},
+		outLogs: [][]string{
+			{"ERROR failed"},
+			{"INFO stopped"},
+		},
+	},
+	{
+		name: "excludeAll",
+		exc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Regexp},
+			LogBodies: []string{".*"},
+		},
+	},
+	{
+		name:    "emptyFilters",
+		outLogs: inLogs,
+	},
+}
+
+func TestFilterLogProcessor(t *testing.T) {
+	for _, test := range standardLogTests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.LogsSink)
+			cfg := &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					TypeVal: typeStr,
+					NameVal: typeStr,
+				},
+				Logs: filterconfig.MatchConfig{
+					Include: test.inc,
+					Exclude: test.exc,
+				},
+			}
+			factory := NewFactory()
+			lp, err := factory.CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+			require.NotNil(t, lp)
+
+			assert.NoError(t, lp.ConsumeLogs(context.Background(), generateLogs(inLogs)))
+
+			if len(test.outLogs) == 0 {
+				assert.Empty(t, next.AllLogs())
+				return
+			}
+			require.Len(t, next.AllLogs(), 1)
+			assert.Equal(t, test.outLogs, logBodies(next.AllLogs()[0]))
+		})
+	}
+}
+
+func generateLogs(bodies [][]string) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(len(bodies))
+	for i, resourceBodies := range bodies {
+		rl := ld.ResourceLogs().At(i)
+		rl.Resource().Attributes().InsertString("host", fmt.Sprintf("host%d", i))
+		rl.InstrumentationLibraryLogs().Resize(1)
+		logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+		logs.Resize(len(resourceBodies))
+		for j, body := range resourceBodies {
+			logs.At(j).Body().SetStringVal(body)
+			logs.At(j).Attributes().InsertInt("index", int64(j))
+		}
+	}
+	return ld
+}
+
+func logBodies(ld pdata.Logs) [][]string {
+	var bodies [][]string
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		var resourceBodies []string
+		ills := rls.At(i).InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			logs := ills.At(j).Logs()
+			for k := 0; k < logs.Len(); k++ {
+				resourceBodies = append(resourceBodies, logs.At(k).Body().StringVal())
+			}
+		}
+		bodies = append(bodies, resourceBodies)
+	}
+	return bodies
+}
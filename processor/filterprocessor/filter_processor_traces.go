@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterspan"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type filterSpanProcessor struct {
+	cfg     *Config
+	include filterspan.Matcher
+	exclude filterspan.Matcher
+	logger  *zap.Logger
+}
+
+func newFilterSpanProcessor(logger *zap.Logger, cfg *Config) (*filterSpanProcessor, error) {
+	inc, err := filterspan.NewMatcher(cfg.Spans.Include)
+	if err != nil {
+		return nil, err
+	}
+
+	exc, err := filterspan.NewMatcher(cfg.Spans.Exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info(
+		"Span filter configured",
+		zap.Bool("include", inc != nil),
+		zap.Bool("exclude", exc != nil),
+	)
+
+	return &filterSpanProcessor{
+		cfg:     cfg,
+		include: inc,
+		exclude: exc,
+		logger:  logger,
+	}, nil
+}
+
+// ProcessTraces drops the spans that are not included or that are excluded, along with
+// the resources and instrumentation libraries left without spans.
+func (fsp *filterSpanProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	if fsp.include == nil && fsp.exclude == nil {
+		return td, nil
+	}
+
+	out := pdata.NewTraces()
+	rssOut := out.ResourceSpans()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		resource := rs.Resource()
+		rsOut := pdata.NewResourceSpans()
+		rsOut.InitEmpty()
+		ilssOut := rsOut.InstrumentationLibrarySpans()
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			library := ils.InstrumentationLibrary()
+			ilsOut := pdata.NewInstrumentationLibrarySpans()
+			ilsOut.InitEmpty()
+			spansOut := ilsOut.Spans()
+
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				if filterspan.SkipSpan(fsp.include, fsp.exclude, span, resource, library) {
+					continue
+				}
+				spansOut.Append(span)
+			}
+
+			if spansOut.Len() > 0 {
+				library.CopyTo(ilsOut.InstrumentationLibrary())
+				ilssOut.Append(ilsOut)
+			}
+		}
+
+		if ilssOut.Len() > 0 {
+			resource.CopyTo(rsOut.Resource())
+			rssOut.Append(rsOut)
+		}
+	}
+
+	if rssOut.Len() == 0 {
+		return td, processorhelper.ErrSkipProcessingData
+	}
+	return out, nil
+}
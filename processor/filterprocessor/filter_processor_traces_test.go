@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filterprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterconfig"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+type spanNameTest struct {
+	name     string
+	inc      *filterconfig.MatchProperties
+	exc      *filterconfig.MatchProperties
+	outSpans map[string][]string // output span names per service name
+}
+
+// inSpans are the span names per service name in the input traces.
+var inSpans = map[string][]string{
+	"svcA": {"spanA1", "health", "spanA2"},
+	"svcB": {"spanB1", "health"},
+}
+
+var standardSpanTests = []spanNameTest{
+	{
+		name: "includeService",
+		inc: &filterconfig.MatchProperties{
+			Config:   filterset.Config{MatchType: filterset.Strict},
+			Services: []string{"svcA"},
+		},
+		outSpans: map[string][]string{
+			"svcA": {"spanA1", "health", "spanA2"},
+		},
+	},
+	{
+		name: "excludeSpanName",
+		exc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Regexp},
+			SpanNames: []string{"^health$"},
+		},
+		outSpans: map[string][]string{
+			"svcA": {"spanA1", "spanA2"},
+			"svcB": {"spanB1"},
+		},
+	},
+	{
+		name: "includeServiceExcludeAttribute",
+		inc: &filterconfig.MatchProperties{
+			Config:   filterset.Config{MatchType: filterset.Regexp},
+			Services: []string{"svc.*"},
+		},
+		exc: &filterconfig.MatchProperties{
+			Config:     filterset.Config{MatchType: filterset.Strict},
+			Attributes: []filterconfig.Attribute{{Key: "index", Value: 0}},
+		},
+		outSpans: map[string][]string{
+			"svcA": {"health", "spanA2"},
+			"svcB": {"health"},
+		},
+	},
+	{
+		name: "excludeResourceAttribute",
+		exc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Strict},
+			Resources: []filterconfig.Attribute{{Key: conventions.AttributeServiceName, Value: "svcB"}},
+		},
+		outSpans: map[string][]string{
+			"svcA": {"spanA1", "health", "spanA2"},
+		},
+	},
+	{
+		name: "excludeExpr",
+		exc: &filterconfig.MatchProperties{
+			Config:      filterset.Config{MatchType: filterconfig.Expr},
+			Expressions: []string{`ServiceName == "svcA" && SpanName != "spanA1"`},
+		},
+		outSpans: map[string][]string{
+			"svcA": {"spanA1"},
+			"svcB": {"spanB1", "health"},
+		},
+	},
+	{
+		name: "excludeAll",
+		exc: &filterconfig.MatchProperties{
+			Config:    filterset.Config{MatchType: filterset.Regexp},
+			SpanNames: []string{".*"},
+		},
+	},
+	{
+		name: "emptyFilters",
+		outSpans: map[string][]string{
+			"svcA": {"spanA1", "health", "spanA2"},
+			"svcB": {"spanB1", "health"},
+		},
+	},
+}
+
+func TestFilterTraceProcessor(t *testing.T) {
+	for _, test := range standardSpanTests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.TracesSink)
+			cfg := &Config{
+				ProcessorSettings: configmodels.ProcessorSettings{
+					TypeVal: typeStr,
+					NameVal: typeStr,
+				},
+				Spans: filterconfig.MatchConfig{
+					Include: test.inc,
+					Exclude: test.exc,
+				},
+			}
+			factory := NewFactory()
+			tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+			require.NotNil(t, tp)
+
+			td := generateTraces(inSpans)
+			assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+
+			if len(test.outSpans) == 0 {
+				assert.Empty(t, next.AllTraces())
+				return
+			}
+			require.Len(t, next.AllTraces(), 1)
+			assert.Equal(t, test.outSpans, spanNamesByService(next.AllTraces()[0]))
+		})
+	}
+}
+
+func generateTraces(spansByService map[string][]string) pdata.Traces {
+	td := pdata.NewTraces()
+	for _, service := range []string{"svcA", "svcB"} {
+		names, ok := spansByService[service]
+		if !ok {
+			continue
+		}
+		rs := pdata.NewResourceSpans()
+		rs.InitEmpty()
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(len(names))
+		for i, name := range names {
+			spans.At(i).SetName(name)
+			spans.At(i).Attributes().InsertInt("index", int64(i))
+		}
+		td.ResourceSpans().Append(rs)
+	}
+	return td
+}
+
+func spanNamesByService(td pdata.Traces) map[string][]string {
+	names := map[string][]string{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		service, _ := rss.At(i).Resource().Attributes().Get(conventions.AttributeServiceName)
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				names[service.StringVal()] = append(names[service.StringVal()], spans.At(k).Name())
+			}
+		}
+	}
+	return names
+}
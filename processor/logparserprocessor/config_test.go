@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors["logparser"])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "logparser/access",
+			TypeVal: typeStr,
+		},
+		Operators: []logparser.OperatorConfig{
+			{
+				Type:  logparser.RegexParser,
+				Regex: `^(?P<time>\S+) (?P<level>\w+) (?P<message>.*)$`,
+			},
+			{
+				Type:       logparser.TimeParser,
+				ParseFrom:  "attributes.time",
+				LayoutType: logparser.StrptimeLayout,
+				Layout:     "%Y-%m-%dT%H:%M:%S%z",
+				Location:   "Europe/Paris",
+			},
+			{
+				Type:      logparser.SeverityParser,
+				ParseFrom: "attributes.level",
+				Mapping: map[string][]string{
+					"warn":   {"w", "wrn"},
+					"fatal2": {"alarm"},
+				},
+			},
+			{
+				Type:          logparser.KeyValueParser,
+				ParseFrom:     "attributes.message",
+				Delimiter:     ":",
+				PairDelimiter: ",",
+				OnError:       logparser.OnErrorDrop,
+			},
+		},
+	}, cfg.Processors["logparser/access"])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "logparser/json",
+			TypeVal: typeStr,
+		},
+		Operators: []logparser.OperatorConfig{
+			{
+				Type: logparser.JSONParser,
+			},
+			{
+				Type:       logparser.TimeParser,
+				ParseFrom:  "attributes.ts",
+				LayoutType: logparser.EpochLayout,
+				Layout:     "ms",
+			},
+		},
+	}, cfg.Processors["logparser/json"])
+}
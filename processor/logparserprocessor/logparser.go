@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+)
+
+type logParser struct {
+	logger   *zap.Logger
+	pipeline *logparser.Pipeline
+}
+
+func newLogParser(logger *zap.Logger, cfg *Config) (*logParser, error) {
+	pipeline, err := logparser.NewPipeline(cfg.Operators)
+	if err != nil {
+		return nil, fmt.Errorf("error creating %q processor: %v", cfg.Name(), err)
+	}
+	return &logParser{logger: logger, pipeline: pipeline}, nil
+}
+
+// ProcessLogs implements the LProcessor interface.
+func (lp *logParser) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			lp.processLogs(ill.Logs())
+		}
+	}
+	return ld, nil
+}
+
+// processLogs applies the operators to the log records and removes the dropped ones.
+func (lp *logParser) processLogs(logs pdata.LogSlice) {
+	kept := pdata.NewLogSlice()
+	for k := 0; k < logs.Len(); k++ {
+		lr := logs.At(k)
+		if lr.IsNil() {
+			kept.Append(lr)
+			continue
+		}
+		keep, errs := lp.pipeline.Process(lr)
+		for _, err := range errs {
+			lp.logger.Debug("Failed to parse log record", zap.Error(err))
+		}
+		if keep {
+			kept.Append(lr)
+		}
+	}
+	if kept.Len() == logs.Len() {
+		return
+	}
+	logs.Resize(0)
+	kept.MoveAndAppendTo(logs)
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logparserprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+)
+
+func newTestLogs(bodies ...string) pdata.Logs {
+	ld := testdata.GenerateLogDataOneEmptyLogs()
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(bodies))
+	for i, body := range bodies {
+		logs.At(i).Body().SetStringVal(body)
+	}
+	return ld
+}
+
+func TestLogParser(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Operators = []logparser.OperatorConfig{
+		{
+			Type:    logparser.JSONParser,
+			OnError: logparser.OnErrorDrop,
+		},
+		{
+			Type:      logparser.SeverityParser,
+			ParseFrom: "attributes.level",
+		},
+		{
+			Type:       logparser.TimeParser,
+			ParseFrom:  "attributes.ts",
+			LayoutType: logparser.EpochLayout,
+			Layout:     "ms",
+		},
+	}
+	sink := new(consumertest.LogsSink)
+	lp, err := factory.CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := newTestLogs(
+		`{"level":"error","ts":1604571330123,"msg":"connection refused"}`,
+		"not json",
+		`{"level":"verbose","msg":"no timestamp"}`,
+	)
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	require.NoError(t, lp.Shutdown(context.Background()))
+
+	require.Len(t, sink.AllLogs(), 1)
+	logs := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, logs.Len())
+
+	lr := logs.At(0)
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, "error", lr.SeverityText())
+	assert.Equal(t, pdata.TimestampUnixNano(1604571330123000000), lr.Timestamp())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"level": pdata.NewAttributeValueString("error"),
+		"msg":   pdata.NewAttributeValueString("connection refused"),
+		"ts":    pdata.NewAttributeValueInt(1604571330123),
+	}).Sort(), lr.Attributes().Sort())
+
+	lr = logs.At(1)
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+	assert.Equal(t, "", lr.SeverityText())
+	assert.Equal(t, pdata.TimestampUnixNano(0), lr.Timestamp())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"level": pdata.NewAttributeValueString("verbose"),
+		"msg":   pdata.NewAttributeValueString("no timestamp"),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestLogParser_NilEmptyData(t *testing.T) {
+	lp, err := newLogParser(zap.NewNop(), &Config{
+		Operators: []logparser.OperatorConfig{{Type: logparser.JSONParser, OnError: logparser.OnErrorDrop}},
+	})
+	require.NoError(t, err)
+
+	for _, ld := range []pdata.Logs{
+		testdata.GenerateLogDataEmpty(),
+		testdata.GenerateLogDataOneEmptyOneNilResourceLogs(),
+		testdata.GenerateLogDataOneEmptyOneNilInstrumentationLibrary(),
+		testdata.GenerateLogDataOneEmptyOneNilLogRecord(),
+	} {
+		expected := pdata.LogsFromInternalRep(ld.InternalRep())
+		_, err := lp.ProcessLogs(context.Background(), ld)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, ld)
+	}
+}
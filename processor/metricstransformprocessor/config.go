@@ -0,0 +1,157 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// Config defines configuration for the metrics transform processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Transforms contains the list of transformations to apply to the metrics, in the
+	// order they are specified.
+	Transforms []Transform `mapstructure:"transforms"`
+}
+
+// Transform defines the transformation applied to the metrics matching MetricName.
+type Transform struct {
+	// MetricName is the name, or the regexp pattern with match_type regexp, of the metrics
+	// to transform.
+	MetricName string `mapstructure:"include"`
+
+	// MatchType determines how MetricName is matched against the metric names. Possible
+	// values are "strict" (default) and "regexp".
+	MatchType filterset.MatchType `mapstructure:"match_type"`
+
+	// Action specifies if the matching metrics are updated in place, inserted as new
+	// metrics or combined into a single metric.
+	Action ConfigAction `mapstructure:"action"`
+
+	// NewName is the name of the transformed metric. It is required with the insert and
+	// combine actions and optional with the update action.
+	NewName string `mapstructure:"new_name"`
+
+	// AggregationType specifies how the data points with the same labels are aggregated
+	// once the metrics are combined. It is only used with the combine action and
+	// defaults to sum.
+	AggregationType AggregationType `mapstructure:"aggregation_type"`
+
+	// Operations contains the list of operations applied to the transformed metric, in the
+	// order they are specified.
+	Operations []Operation `mapstructure:"operations"`
+}
+
+// Operation defines an operation on the data points of a metric.
+type Operation struct {
+	// Action specifies the operation to perform.
+	Action OperationAction `mapstructure:"action"`
+
+	// Label is the label key the operation applies to. It is used by the update_label,
+	// delete_label_value and aggregate_label_values actions.
+	Label string `mapstructure:"label"`
+
+	// NewLabel is the label key to add with add_label, or the new key of the label with
+	// update_label.
+	NewLabel string `mapstructure:"new_label"`
+
+	// LabelSet is the list of label keys kept by aggregate_labels. All the other labels
+	// are aggregated away.
+	LabelSet []string `mapstructure:"label_set"`
+
+	// AggregationType specifies how the data points are aggregated by aggregate_labels and
+	// aggregate_label_values.
+	AggregationType AggregationType `mapstructure:"aggregation_type"`
+
+	// AggregatedValues is the list of label values aggregated by aggregate_label_values.
+	AggregatedValues []string `mapstructure:"aggregated_values"`
+
+	// NewValue is the value of the label added by add_label, or the value replacing the
+	// aggregated values with aggregate_label_values.
+	NewValue string `mapstructure:"new_value"`
+
+	// LabelValue is the value of the label of the data points removed by
+	// delete_label_value.
+	LabelValue string `mapstructure:"label_value"`
+
+	// ValueActions is the list of label values renamed by update_label.
+	ValueActions []ValueAction `mapstructure:"value_actions"`
+}
+
+// ValueAction renames a label value.
+type ValueAction struct {
+	// Value is the current value of the label.
+	Value string `mapstructure:"value"`
+
+	// NewValue is the value replacing Value.
+	NewValue string `mapstructure:"new_value"`
+}
+
+// ConfigAction is the action of a transform.
+type ConfigAction string
+
+const (
+	// Update updates the matching metrics in place.
+	Update ConfigAction = "update"
+
+	// Insert inserts a transformed copy of each matching metric.
+	Insert ConfigAction = "insert"
+
+	// Combine combines the matching metrics into a single new metric. The named
+	// submatches of the regexp become labels of the data points of the new metric.
+	Combine ConfigAction = "combine"
+)
+
+// OperationAction is the action of an operation.
+type OperationAction string
+
+const (
+	// AddLabel adds a label with a fixed value to all the data points.
+	AddLabel OperationAction = "add_label"
+
+	// UpdateLabel renames a label key and/or some of its values.
+	UpdateLabel OperationAction = "update_label"
+
+	// DeleteLabelValue removes the data points that have a given label value.
+	DeleteLabelValue OperationAction = "delete_label_value"
+
+	// AggregateLabels keeps only the labels of a label set and aggregates the data points
+	// that end up with the same labels.
+	AggregateLabels OperationAction = "aggregate_labels"
+
+	// AggregateLabelValues replaces some values of a label with a new value and
+	// aggregates the data points that end up with the same labels.
+	AggregateLabelValues OperationAction = "aggregate_label_values"
+)
+
+// AggregationType is the function used to aggregate the values of data points.
+// Histograms and summaries are always merged by adding their counts, sums and buckets.
+type AggregationType string
+
+const (
+	// Sum sums the values of the data points.
+	Sum AggregationType = "sum"
+
+	// Mean averages the values of the data points.
+	Mean AggregationType = "mean"
+
+	// Max keeps the maximum value of the data points.
+	Max AggregationType = "max"
+
+	// Min keeps the minimum value of the data points.
+	Min AggregationType = "min"
+)
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestLoadingConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Processors["metricstransform"])
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "metricstransform/example",
+			TypeVal: typeStr,
+		},
+		Transforms: []Transform{
+			{
+				MetricName: "old_name",
+				Action:     Update,
+				NewName:    "new_name",
+				Operations: []Operation{
+					{
+						Action:       UpdateLabel,
+						Label:        "old_label",
+						NewLabel:     "new_label",
+						ValueActions: []ValueAction{{Value: "old_value", NewValue: "new_value"}},
+					},
+				},
+			},
+			{
+				MetricName: "system.cpu.usage",
+				Action:     Insert,
+				NewName:    "system.cpu.usage_by_state",
+				Operations: []Operation{
+					{
+						Action:          AggregateLabels,
+						LabelSet:        []string{"state"},
+						AggregationType: Sum,
+					},
+					{
+						Action:   AddLabel,
+						NewLabel: "version",
+						NewValue: "opentelemetry",
+					},
+				},
+			},
+			{
+				MetricName:      `^system\.memory\.(?P<state>.*)$`,
+				MatchType:       filterset.Regexp,
+				Action:          Combine,
+				NewName:         "system.memory.usage",
+				AggregationType: Sum,
+				Operations: []Operation{
+					{
+						Action:           AggregateLabelValues,
+						Label:            "state",
+						AggregatedValues: []string{"slab_reclaimable", "slab_unreclaimable"},
+						NewValue:         "slab",
+						AggregationType:  Sum,
+					},
+					{
+						Action:     DeleteLabelValue,
+						Label:      "state",
+						LabelValue: "idle",
+					},
+				},
+			},
+		},
+	}, cfg.Processors["metricstransform/example"])
+}
@@ -0,0 +1,409 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// dataPoints abstracts the data points of a metric so that the label operations and the
+// aggregations are implemented once for all the metric data types.
+type dataPoints interface {
+	len() int
+	isNil(i int) bool
+	labels(i int) pdata.StringMap
+	// merge aggregates the data points at the given indexes into the first of them.
+	merge(indexes []int, aggType AggregationType)
+	// keep removes all the data points but the ones at the given indexes.
+	keep(indexes []int)
+	// moveAndAppendFrom moves the data points of other, that must be of the same type,
+	// at the end of the data points.
+	moveAndAppendFrom(other dataPoints)
+}
+
+// metricDataPoints returns the data points of the metric, or nil if the metric has no data.
+func metricDataPoints(metric pdata.Metric) dataPoints {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if data := metric.IntGauge(); !data.IsNil() {
+			return intPoints{data.DataPoints()}
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if data := metric.DoubleGauge(); !data.IsNil() {
+			return doublePoints{data.DataPoints()}
+		}
+	case pdata.MetricDataTypeIntSum:
+		if data := metric.IntSum(); !data.IsNil() {
+			return intPoints{data.DataPoints()}
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if data := metric.DoubleSum(); !data.IsNil() {
+			return doublePoints{data.DataPoints()}
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if data := metric.IntHistogram(); !data.IsNil() {
+			return intHistogramPoints{data.DataPoints()}
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if data := metric.DoubleHistogram(); !data.IsNil() {
+			return doubleHistogramPoints{data.DataPoints()}
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		if data := metric.DoubleSummary(); !data.IsNil() {
+			return summaryPoints{data.DataPoints()}
+		}
+	}
+	return nil
+}
+
+// aggregate merges the data points that have the same labels.
+func aggregate(points dataPoints, aggType AggregationType) {
+	var groups [][]int
+	groupIndexes := make(map[string]int)
+	for i := 0; i < points.len(); i++ {
+		if points.isNil(i) {
+			groups = append(groups, []int{i})
+			continue
+		}
+		key := labelsKey(points.labels(i))
+		if g, ok := groupIndexes[key]; ok {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		groupIndexes[key] = len(groups)
+		groups = append(groups, []int{i})
+	}
+
+	if len(groups) == points.len() {
+		return
+	}
+
+	kept := make([]int, 0, len(groups))
+	for _, group := range groups {
+		if len(group) > 1 {
+			points.merge(group, aggType)
+		}
+		kept = append(kept, group[0])
+	}
+	points.keep(kept)
+}
+
+// labelsKey returns a string uniquely identifying a set of labels.
+func labelsKey(labels pdata.StringMap) string {
+	pairs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		pairs = append(pairs, k+"="+v)
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
+
+// mergeTimestamps returns the earliest start time and the latest timestamp of two data points.
+func mergeTimestamps(start, timestamp, otherStart, otherTimestamp pdata.TimestampUnixNano) (pdata.TimestampUnixNano, pdata.TimestampUnixNano) {
+	if otherStart != 0 && (start == 0 || otherStart < start) {
+		start = otherStart
+	}
+	if otherTimestamp > timestamp {
+		timestamp = otherTimestamp
+	}
+	return start, timestamp
+}
+
+func aggregateInts(values []int64, aggType AggregationType) int64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		switch aggType {
+		case Max:
+			if v > result {
+				result = v
+			}
+		case Min:
+			if v < result {
+				result = v
+			}
+		default:
+			result += v
+		}
+	}
+	if aggType == Mean {
+		result /= int64(len(values))
+	}
+	return result
+}
+
+func aggregateDoubles(values []float64, aggType AggregationType) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		switch aggType {
+		case Max:
+			if v > result {
+				result = v
+			}
+		case Min:
+			if v < result {
+				result = v
+			}
+		default:
+			result += v
+		}
+	}
+	if aggType == Mean {
+		result /= float64(len(values))
+	}
+	return result
+}
+
+// commonBounds returns the bucket bounds shared by all the histograms, in order.
+func commonBounds(bounds [][]float64) []float64 {
+	common := bounds[0]
+	for _, other := range bounds[1:] {
+		shared := make(map[float64]bool, len(other))
+		for _, b := range other {
+			shared[b] = true
+		}
+		var kept []float64
+		for _, b := range common {
+			if shared[b] {
+				kept = append(kept, b)
+			}
+		}
+		common = kept
+	}
+	return common
+}
+
+// rebucket returns the bucket counts of a histogram with the given bounds, moved to the
+// buckets of the common bounds, which are a subset of them. Each bucket is added to the
+// bucket of the first common bound at or above its upper bound, so the counts stay exact.
+func rebucket(counts []uint64, bounds, common []float64) []uint64 {
+	if len(counts) == 0 {
+		return nil
+	}
+	rebucketed := make([]uint64, len(common)+1)
+	j := 0
+	for i, c := range counts {
+		if i < len(bounds) {
+			for j < len(common) && bounds[i] > common[j] {
+				j++
+			}
+		} else {
+			j = len(common)
+		}
+		rebucketed[j] += c
+	}
+	return rebucketed
+}
+
+// mergeBucketCounts adds the bucket counts of other to counts.
+func mergeBucketCounts(counts, other []uint64) []uint64 {
+	merged := make([]uint64, len(counts))
+	copy(merged, counts)
+	for i, c := range other {
+		if i < len(merged) {
+			merged[i] += c
+		} else {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
+type intPoints struct {
+	pdata.IntDataPointSlice
+}
+
+func (p intPoints) len() int                     { return p.Len() }
+func (p intPoints) isNil(i int) bool             { return p.At(i).IsNil() }
+func (p intPoints) labels(i int) pdata.StringMap { return p.At(i).LabelsMap() }
+func (p intPoints) moveAndAppendFrom(o dataPoints) {
+	o.(intPoints).MoveAndAppendTo(p.IntDataPointSlice)
+}
+
+func (p intPoints) merge(indexes []int, aggType AggregationType) {
+	dp := p.At(indexes[0])
+	values := make([]int64, len(indexes))
+	for j, i := range indexes {
+		other := p.At(i)
+		values[j] = other.Value()
+		start, timestamp := mergeTimestamps(dp.StartTime(), dp.Timestamp(), other.StartTime(), other.Timestamp())
+		dp.SetStartTime(start)
+		dp.SetTimestamp(timestamp)
+	}
+	dp.SetValue(aggregateInts(values, aggType))
+}
+
+func (p intPoints) keep(indexes []int) {
+	kept := pdata.NewIntDataPointSlice()
+	for _, i := range indexes {
+		kept.Append(p.At(i))
+	}
+	p.Resize(0)
+	kept.MoveAndAppendTo(p.IntDataPointSlice)
+}
+
+type doublePoints struct {
+	pdata.DoubleDataPointSlice
+}
+
+func (p doublePoints) len() int                     { return p.Len() }
+func (p doublePoints) isNil(i int) bool             { return p.At(i).IsNil() }
+func (p doublePoints) labels(i int) pdata.StringMap { return p.At(i).LabelsMap() }
+func (p doublePoints) moveAndAppendFrom(o dataPoints) {
+	o.(doublePoints).MoveAndAppendTo(p.DoubleDataPointSlice)
+}
+
+func (p doublePoints) merge(indexes []int, aggType AggregationType) {
+	dp := p.At(indexes[0])
+	values := make([]float64, len(indexes))
+	for j, i := range indexes {
+		other := p.At(i)
+		values[j] = other.Value()
+		start, timestamp := mergeTimestamps(dp.StartTime(), dp.Timestamp(), other.StartTime(), other.Timestamp())
+		dp.SetStartTime(start)
+		dp.SetTimestamp(timestamp)
+	}
+	dp.SetValue(aggregateDoubles(values, aggType))
+}
+
+func (p doublePoints) keep(indexes []int) {
+	kept := pdata.NewDoubleDataPointSlice()
+	for _, i := range indexes {
+		kept.Append(p.At(i))
+	}
+	p.Resize(0)
+	kept.MoveAndAppendTo(p.DoubleDataPointSlice)
+}
+
+type intHistogramPoints struct {
+	pdata.IntHistogramDataPointSlice
+}
+
+func (p intHistogramPoints) len() int                     { return p.Len() }
+func (p intHistogramPoints) isNil(i int) bool             { return p.At(i).IsNil() }
+func (p intHistogramPoints) labels(i int) pdata.StringMap { return p.At(i).LabelsMap() }
+func (p intHistogramPoints) moveAndAppendFrom(o dataPoints) {
+	o.(intHistogramPoints).MoveAndAppendTo(p.IntHistogramDataPointSlice)
+}
+
+// merge adds the counts, sums and bucket counts of the histograms. The histograms with
+// different bucket bounds are first moved to the buckets of the bounds they share.
+func (p intHistogramPoints) merge(indexes []int, _ AggregationType) {
+	dp := p.At(indexes[0])
+	bounds := make([][]float64, len(indexes))
+	for j, i := range indexes {
+		bounds[j] = p.At(i).ExplicitBounds()
+	}
+	common := commonBounds(bounds)
+	dp.SetBucketCounts(rebucket(dp.BucketCounts(), bounds[0], common))
+	dp.SetExplicitBounds(common)
+	for j, i := range indexes[1:] {
+		other := p.At(i)
+		dp.SetCount(dp.Count() + other.Count())
+		dp.SetSum(dp.Sum() + other.Sum())
+		dp.SetBucketCounts(mergeBucketCounts(dp.BucketCounts(), rebucket(other.BucketCounts(), bounds[j+1], common)))
+		start, timestamp := mergeTimestamps(dp.StartTime(), dp.Timestamp(), other.StartTime(), other.Timestamp())
+		dp.SetStartTime(start)
+		dp.SetTimestamp(timestamp)
+	}
+}
+
+func (p intHistogramPoints) keep(indexes []int) {
+	kept := pdata.NewIntHistogramDataPointSlice()
+	for _, i := range indexes {
+		kept.Append(p.At(i))
+	}
+	p.Resize(0)
+	kept.MoveAndAppendTo(p.IntHistogramDataPointSlice)
+}
+
+type doubleHistogramPoints struct {
+	pdata.DoubleHistogramDataPointSlice
+}
+
+func (p doubleHistogramPoints) len() int                     { return p.Len() }
+func (p doubleHistogramPoints) isNil(i int) bool             { return p.At(i).IsNil() }
+func (p doubleHistogramPoints) labels(i int) pdata.StringMap { return p.At(i).LabelsMap() }
+func (p doubleHistogramPoints) moveAndAppendFrom(o dataPoints) {
+	o.(doubleHistogramPoints).MoveAndAppendTo(p.DoubleHistogramDataPointSlice)
+}
+
+// merge adds the counts, sums and bucket counts of the histograms. The histograms with
+// different bucket bounds are first moved to the buckets of the bounds they share.
+func (p doubleHistogramPoints) merge(indexes []int, _ AggregationType) {
+	dp := p.At(indexes[0])
+	bounds := make([][]float64, len(indexes))
+	for j, i := range indexes {
+		bounds[j] = p.At(i).ExplicitBounds()
+	}
+	common := commonBounds(bounds)
+	dp.SetBucketCounts(rebucket(dp.BucketCounts(), bounds[0], common))
+	dp.SetExplicitBounds(common)
+	for j, i := range indexes[1:] {
+		other := p.At(i)
+		dp.SetCount(dp.Count() + other.Count())
+		dp.SetSum(dp.Sum() + other.Sum())
+		dp.SetBucketCounts(mergeBucketCounts(dp.BucketCounts(), rebucket(other.BucketCounts(), bounds[j+1], common)))
+		start, timestamp := mergeTimestamps(dp.StartTime(), dp.Timestamp(), other.StartTime(), other.Timestamp())
+		dp.SetStartTime(start)
+		dp.SetTimestamp(timestamp)
+	}
+}
+
+func (p doubleHistogramPoints) keep(indexes []int) {
+	kept := pdata.NewDoubleHistogramDataPointSlice()
+	for _, i := range indexes {
+		kept.Append(p.At(i))
+	}
+	p.Resize(0)
+	kept.MoveAndAppendTo(p.DoubleHistogramDataPointSlice)
+}
+
+type summaryPoints struct {
+	pdata.DoubleSummaryDataPointSlice
+}
+
+func (p summaryPoints) len() int                     { return p.Len() }
+func (p summaryPoints) isNil(i int) bool             { return p.At(i).IsNil() }
+func (p summaryPoints) labels(i int) pdata.StringMap { return p.At(i).LabelsMap() }
+func (p summaryPoints) moveAndAppendFrom(o dataPoints) {
+	o.(summaryPoints).MoveAndAppendTo(p.DoubleSummaryDataPointSlice)
+}
+
+// merge adds the counts and sums of the summaries. The quantiles cannot be merged and
+// are dropped.
+func (p summaryPoints) merge(indexes []int, _ AggregationType) {
+	dp := p.At(indexes[0])
+	for _, i := range indexes[1:] {
+		other := p.At(i)
+		dp.SetCount(dp.Count() + other.Count())
+		dp.SetSum(dp.Sum() + other.Sum())
+		start, timestamp := mergeTimestamps(dp.StartTime(), dp.Timestamp(), other.StartTime(), other.Timestamp())
+		dp.SetStartTime(start)
+		dp.SetTimestamp(timestamp)
+	}
+	dp.QuantileValues().Resize(0)
+}
+
+func (p summaryPoints) keep(indexes []int) {
+	kept := pdata.NewDoubleSummaryDataPointSlice()
+	for _, i := range indexes {
+		kept.Append(p.At(i))
+	}
+	p.Resize(0)
+	kept.MoveAndAppendTo(p.DoubleSummaryDataPointSlice)
+}
@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "metricstransform"
+)
+
+var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: true}
+
+// NewFactory returns a new factory for the Metrics Transform processor.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithMetrics(createMetricsProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+	}
+}
+
+func createMetricsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsProcessor, error) {
+	oCfg := cfg.(*Config)
+	if err := validateConfiguration(oCfg); err != nil {
+		return nil, fmt.Errorf("error creating %q processor: %w", cfg.Name(), err)
+	}
+	mtp, err := newMetricsTransformProcessor(params.Logger, oCfg)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewMetricsProcessor(
+		cfg,
+		nextConsumer,
+		mtp,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+// validateConfiguration checks that the transforms and their operations have all their
+// required fields set to valid values.
+func validateConfiguration(config *Config) error {
+	for i, transform := range config.Transforms {
+		if transform.MetricName == "" {
+			return fmt.Errorf("transform %d: missing required field \"include\"", i)
+		}
+
+		switch transform.MatchType {
+		case "", filterset.Strict, filterset.Regexp:
+		default:
+			return fmt.Errorf("transform %d: unsupported \"match_type\" %q, must be one of %q or %q", i, transform.MatchType, filterset.Strict, filterset.Regexp)
+		}
+
+		switch transform.Action {
+		case Update:
+		case Insert:
+			if transform.NewName == "" {
+				return fmt.Errorf("transform %d: missing required field \"new_name\" with action %q", i, Insert)
+			}
+		case Combine:
+			if transform.NewName == "" {
+				return fmt.Errorf("transform %d: missing required field \"new_name\" with action %q", i, Combine)
+			}
+			if transform.MatchType != filterset.Regexp {
+				return fmt.Errorf("transform %d: action %q requires \"match_type\" %q", i, Combine, filterset.Regexp)
+			}
+		default:
+			return fmt.Errorf("transform %d: unsupported \"action\" %q, must be one of %q, %q or %q", i, transform.Action, Update, Insert, Combine)
+		}
+
+		if transform.AggregationType != "" {
+			if err := validateAggregationType(transform.AggregationType); err != nil {
+				return fmt.Errorf("transform %d: %v", i, err)
+			}
+		}
+
+		for j, op := range transform.Operations {
+			if err := validateOperation(op); err != nil {
+				return fmt.Errorf("transform %d, operation %d: %v", i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateOperation(op Operation) error {
+	switch op.Action {
+	case AddLabel:
+		if op.NewLabel == "" || op.NewValue == "" {
+			return fmt.Errorf("action %q requires the fields \"new_label\" and \"new_value\"", op.Action)
+		}
+	case UpdateLabel:
+		if op.Label == "" {
+			return fmt.Errorf("action %q requires the field \"label\"", op.Action)
+		}
+	case DeleteLabelValue:
+		if op.Label == "" || op.LabelValue == "" {
+			return fmt.Errorf("action %q requires the fields \"label\" and \"label_value\"", op.Action)
+		}
+	case AggregateLabels:
+		return validateAggregationType(op.AggregationType)
+	case AggregateLabelValues:
+		if op.Label == "" || op.NewValue == "" || len(op.AggregatedValues) == 0 {
+			return fmt.Errorf("action %q requires the fields \"label\", \"new_value\" and \"aggregated_values\"", op.Action)
+		}
+		return validateAggregationType(op.AggregationType)
+	default:
+		return fmt.Errorf("unsupported \"action\" %q, must be one of %q, %q, %q, %q or %q",
+			op.Action, AddLabel, UpdateLabel, DeleteLabelValue, AggregateLabels, AggregateLabelValues)
+	}
+	return nil
+}
+
+func validateAggregationType(aggType AggregationType) error {
+	switch aggType {
+	case Sum, Mean, Max, Min:
+		return nil
+	}
+	return fmt.Errorf("unsupported \"aggregation_type\" %q, must be one of %q, %q, %q or %q", aggType, Sum, Mean, Max, Min)
+}
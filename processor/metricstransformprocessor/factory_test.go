@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func TestType(t *testing.T) {
+	factory := NewFactory()
+	assert.Equal(t, configmodels.Type("metricstransform"), factory.Type())
+}
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: typeStr,
+			TypeVal: typeStr,
+		},
+	}, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessors(t *testing.T) {
+	tests := []struct {
+		configName string
+		succeed    bool
+	}{
+		{
+			configName: "config.yaml",
+			succeed:    true,
+		},
+		{
+			configName: "config_invalid.yaml",
+			succeed:    false,
+		},
+	}
+
+	for _, test := range tests {
+		factories, err := componenttest.ExampleComponents()
+		require.NoError(t, err)
+
+		factory := NewFactory()
+		factories.Processors[typeStr] = factory
+		config, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", test.configName), factories)
+		require.NoError(t, err)
+
+		for name, cfg := range config.Processors {
+			t.Run(test.configName+"/"+name, func(t *testing.T) {
+				tp, tErr := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+				assert.Nil(t, tp)
+				assert.Error(t, tErr)
+
+				mp, mErr := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
+				if test.succeed {
+					assert.NotNil(t, mp)
+					assert.NoError(t, mErr)
+				} else {
+					assert.Nil(t, mp)
+					assert.Error(t, mErr)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateConfiguration(t *testing.T) {
+	tests := []struct {
+		name      string
+		transform Transform
+		err       string
+	}{
+		{
+			name:      "missing_include",
+			transform: Transform{Action: Update},
+			err:       `transform 0: missing required field "include"`,
+		},
+		{
+			name:      "invalid_match_type",
+			transform: Transform{MetricName: "name", MatchType: "invalid", Action: Update},
+			err:       `transform 0: unsupported "match_type" "invalid", must be one of "strict" or "regexp"`,
+		},
+		{
+			name:      "invalid_action",
+			transform: Transform{MetricName: "name", Action: "invalid"},
+			err:       `transform 0: unsupported "action" "invalid", must be one of "update", "insert" or "combine"`,
+		},
+		{
+			name:      "insert_without_new_name",
+			transform: Transform{MetricName: "name", Action: Insert},
+			err:       `transform 0: missing required field "new_name" with action "insert"`,
+		},
+		{
+			name:      "combine_strict",
+			transform: Transform{MetricName: "name", Action: Combine, NewName: "new_name"},
+			err:       `transform 0: action "combine" requires "match_type" "regexp"`,
+		},
+		{
+			name:      "invalid_aggregation_type",
+			transform: Transform{MetricName: "name", MatchType: filterset.Regexp, Action: Combine, NewName: "new_name", AggregationType: "invalid"},
+			err:       `transform 0: unsupported "aggregation_type" "invalid", must be one of "sum", "mean", "max" or "min"`,
+		},
+		{
+			name: "invalid_operation_action",
+			transform: Transform{MetricName: "name", Action: Update, Operations: []Operation{
+				{Action: "invalid"},
+			}},
+			err: `transform 0, operation 0: unsupported "action" "invalid", must be one of "add_label", "update_label", "delete_label_value", "aggregate_labels" or "aggregate_label_values"`,
+		},
+		{
+			name: "add_label_without_value",
+			transform: Transform{MetricName: "name", Action: Update, Operations: []Operation{
+				{Action: AddLabel, NewLabel: "label"},
+			}},
+			err: `transform 0, operation 0: action "add_label" requires the fields "new_label" and "new_value"`,
+		},
+		{
+			name: "aggregate_labels_without_aggregation_type",
+			transform: Transform{MetricName: "name", Action: Update, Operations: []Operation{
+				{Action: AggregateLabels, LabelSet: []string{"label"}},
+			}},
+			err: `transform 0, operation 0: unsupported "aggregation_type" "", must be one of "sum", "mean", "max" or "min"`,
+		},
+		{
+			name: "aggregate_label_values_without_values",
+			transform: Transform{MetricName: "name", Action: Update, Operations: []Operation{
+				{Action: AggregateLabelValues, Label: "label", NewValue: "value", AggregationType: Sum},
+			}},
+			err: `transform 0, operation 0: action "aggregate_label_values" requires the fields "label", "new_value" and "aggregated_values"`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateConfiguration(&Config{Transforms: []Transform{test.transform}})
+			require.Error(t, err)
+			assert.Equal(t, test.err, err.Error())
+		})
+	}
+}
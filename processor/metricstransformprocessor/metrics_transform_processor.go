@@ -0,0 +1,237 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+type metricsTransformProcessor struct {
+	transforms []internalTransform
+	logger     *zap.Logger
+}
+
+// internalTransform is a Transform with its matcher and operations ready to be applied.
+type internalTransform struct {
+	matcher filterset.FilterSet
+	// submatcher extracts the named submatches of the metric names with the combine action.
+	submatcher      *regexp.Regexp
+	action          ConfigAction
+	newName         string
+	aggregationType AggregationType
+	operations      []internalOperation
+}
+
+// internalOperation is an Operation with its lists turned into lookup maps.
+type internalOperation struct {
+	configOperation     Operation
+	valueActionsMapping map[string]string
+	labelSetMap         map[string]bool
+	aggregatedValuesSet map[string]bool
+}
+
+func newMetricsTransformProcessor(logger *zap.Logger, cfg *Config) (*metricsTransformProcessor, error) {
+	transforms := make([]internalTransform, 0, len(cfg.Transforms))
+	for _, t := range cfg.Transforms {
+		matchType := t.MatchType
+		if matchType == "" {
+			matchType = filterset.Strict
+		}
+		matcher, err := filterset.CreateFilterSet([]string{t.MetricName}, &filterset.Config{MatchType: matchType})
+		if err != nil {
+			return nil, fmt.Errorf("error creating metric name filter for %q: %v", t.MetricName, err)
+		}
+
+		it := internalTransform{
+			matcher:         matcher,
+			action:          t.Action,
+			newName:         t.NewName,
+			aggregationType: t.AggregationType,
+			operations:      make([]internalOperation, 0, len(t.Operations)),
+		}
+		if t.Action == Combine {
+			if it.submatcher, err = regexp.Compile(t.MetricName); err != nil {
+				return nil, fmt.Errorf("error creating metric name filter for %q: %v", t.MetricName, err)
+			}
+			if it.aggregationType == "" {
+				it.aggregationType = Sum
+			}
+		}
+
+		for _, op := range t.Operations {
+			iop := internalOperation{
+				configOperation:     op,
+				valueActionsMapping: make(map[string]string, len(op.ValueActions)),
+				labelSetMap:         make(map[string]bool, len(op.LabelSet)),
+				aggregatedValuesSet: make(map[string]bool, len(op.AggregatedValues)),
+			}
+			for _, va := range op.ValueActions {
+				iop.valueActionsMapping[va.Value] = va.NewValue
+			}
+			for _, label := range op.LabelSet {
+				iop.labelSetMap[label] = true
+			}
+			for _, value := range op.AggregatedValues {
+				iop.aggregatedValuesSet[value] = true
+			}
+			it.operations = append(it.operations, iop)
+		}
+		transforms = append(transforms, it)
+	}
+
+	return &metricsTransformProcessor{
+		transforms: transforms,
+		logger:     logger,
+	}, nil
+}
+
+// ProcessMetrics implements the MProcessor interface.
+func (mtp *metricsTransformProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for _, transform := range mtp.transforms {
+				switch transform.action {
+				case Update:
+					mtp.update(metrics, transform)
+				case Insert:
+					mtp.insert(metrics, transform)
+				case Combine:
+					mtp.combine(metrics, transform)
+				}
+			}
+		}
+	}
+	return md, nil
+}
+
+// update transforms the matching metrics in place.
+func (mtp *metricsTransformProcessor) update(metrics pdata.MetricSlice, transform internalTransform) {
+	for k := 0; k < metrics.Len(); k++ {
+		metric := metrics.At(k)
+		if metric.IsNil() || !transform.matcher.Matches(metric.Name()) {
+			continue
+		}
+		if transform.newName != "" {
+			metric.SetName(transform.newName)
+		}
+		applyOperations(metric, transform.operations)
+	}
+}
+
+// insert appends a transformed copy of each matching metric.
+func (mtp *metricsTransformProcessor) insert(metrics pdata.MetricSlice, transform internalTransform) {
+	inserted := pdata.NewMetricSlice()
+	for k := 0; k < metrics.Len(); k++ {
+		metric := metrics.At(k)
+		if metric.IsNil() || !transform.matcher.Matches(metric.Name()) {
+			continue
+		}
+		newMetric := pdata.NewMetric()
+		newMetric.InitEmpty()
+		metric.CopyTo(newMetric)
+		newMetric.SetName(transform.newName)
+		applyOperations(newMetric, transform.operations)
+		inserted.Append(newMetric)
+	}
+	inserted.MoveAndAppendTo(metrics)
+}
+
+// combine replaces the matching metrics with a single metric holding all their data points.
+// The named submatches of the metric names are added as labels to the data points, then the
+// data points with the same labels are aggregated.
+func (mtp *metricsTransformProcessor) combine(metrics pdata.MetricSlice, transform internalTransform) {
+	var matched []int
+	for k := 0; k < metrics.Len(); k++ {
+		metric := metrics.At(k)
+		if metric.IsNil() || metricDataPoints(metric) == nil || !transform.matcher.Matches(metric.Name()) {
+			continue
+		}
+		matched = append(matched, k)
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	first := metrics.At(matched[0])
+	for _, k := range matched[1:] {
+		if metrics.At(k).DataType() != first.DataType() {
+			mtp.logger.Warn("Metrics of different data types cannot be combined",
+				zap.String("new_name", transform.newName),
+				zap.String("data_type", first.DataType().String()),
+				zap.String("other_data_type", metrics.At(k).DataType().String()))
+			return
+		}
+	}
+
+	combined := pdata.NewMetric()
+	combined.InitEmpty()
+	first.CopyTo(combined)
+	combined.SetName(transform.newName)
+	combinedPoints := metricDataPoints(combined)
+	combinedPoints.keep(nil)
+
+	subexpNames := transform.submatcher.SubexpNames()
+	for _, k := range matched {
+		metric := metrics.At(k)
+		points := metricDataPoints(metric)
+		if submatches := transform.submatcher.FindStringSubmatch(metric.Name()); submatches != nil {
+			for i := 0; i < points.len(); i++ {
+				if points.isNil(i) {
+					continue
+				}
+				labels := points.labels(i)
+				for s, name := range subexpNames {
+					if name != "" {
+						labels.Upsert(name, submatches[s])
+					}
+				}
+			}
+		}
+		combinedPoints.moveAndAppendFrom(points)
+	}
+	aggregate(combinedPoints, transform.aggregationType)
+	applyOperations(combined, transform.operations)
+
+	kept := pdata.NewMetricSlice()
+	m := 0
+	for k := 0; k < metrics.Len(); k++ {
+		if m < len(matched) && matched[m] == k {
+			m++
+			continue
+		}
+		kept.Append(metrics.At(k))
+	}
+	kept.Append(combined)
+	metrics.Resize(0)
+	kept.MoveAndAppendTo(metrics)
+}
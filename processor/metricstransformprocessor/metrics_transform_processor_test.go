@@ -0,0 +1,466 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// testMetric helps building the metrics of the tests.
+type testMetric struct {
+	pdata.Metric
+}
+
+func newTestMetric(name string, dataType pdata.MetricDataType) testMetric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeIntGauge:
+		metric.IntGauge().InitEmpty()
+	case pdata.MetricDataTypeDoubleGauge:
+		metric.DoubleGauge().InitEmpty()
+	case pdata.MetricDataTypeIntSum:
+		metric.IntSum().InitEmpty()
+	case pdata.MetricDataTypeDoubleSum:
+		metric.DoubleSum().InitEmpty()
+	case pdata.MetricDataTypeIntHistogram:
+		metric.IntHistogram().InitEmpty()
+	case pdata.MetricDataTypeDoubleHistogram:
+		metric.DoubleHistogram().InitEmpty()
+	case pdata.MetricDataTypeDoubleSummary:
+		metric.DoubleSummary().InitEmpty()
+	}
+	return testMetric{metric}
+}
+
+func (m testMetric) intPoint(labels map[string]string, value int64, timestamp pdata.TimestampUnixNano) testMetric {
+	dp := pdata.NewIntDataPoint()
+	dp.InitEmpty()
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetValue(value)
+	dp.SetTimestamp(timestamp)
+	if m.DataType() == pdata.MetricDataTypeIntGauge {
+		m.IntGauge().DataPoints().Append(dp)
+	} else {
+		m.IntSum().DataPoints().Append(dp)
+	}
+	return m
+}
+
+func (m testMetric) doublePoint(labels map[string]string, value float64, timestamp pdata.TimestampUnixNano) testMetric {
+	dp := pdata.NewDoubleDataPoint()
+	dp.InitEmpty()
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetValue(value)
+	dp.SetTimestamp(timestamp)
+	if m.DataType() == pdata.MetricDataTypeDoubleGauge {
+		m.DoubleGauge().DataPoints().Append(dp)
+	} else {
+		m.DoubleSum().DataPoints().Append(dp)
+	}
+	return m
+}
+
+func (m testMetric) histogramPoint(labels map[string]string, count uint64, sum float64, bounds []float64, buckets []uint64) testMetric {
+	dp := pdata.NewDoubleHistogramDataPoint()
+	dp.InitEmpty()
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(buckets)
+	m.DoubleHistogram().DataPoints().Append(dp)
+	return m
+}
+
+func (m testMetric) intHistogramPoint(labels map[string]string, count uint64, sum int64, bounds []float64, buckets []uint64) testMetric {
+	dp := pdata.NewIntHistogramDataPoint()
+	dp.InitEmpty()
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(buckets)
+	m.IntHistogram().DataPoints().Append(dp)
+	return m
+}
+
+func (m testMetric) summaryPoint(labels map[string]string, count uint64, sum float64, quantile, value float64) testMetric {
+	dp := pdata.NewDoubleSummaryDataPoint()
+	dp.InitEmpty()
+	dp.LabelsMap().InitFromMap(labels)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	q := pdata.NewValueAtQuantile()
+	q.InitEmpty()
+	q.SetQuantile(quantile)
+	q.SetValue(value)
+	dp.QuantileValues().Append(q)
+	m.DoubleSummary().DataPoints().Append(dp)
+	return m
+}
+
+func newTestMetrics(metrics ...testMetric) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ilm := rm.InstrumentationLibraryMetrics().At(0)
+	for _, metric := range metrics {
+		ilm.Metrics().Append(metric.Metric)
+	}
+	return md
+}
+
+// normalizeLabels sorts the labels of all the data points, the order of the labels changes
+// when they are renamed or deleted, and makes the empty labels comparable.
+func normalizeLabels(md pdata.Metrics) {
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		points := metricDataPoints(metrics.At(i))
+		for j := 0; j < points.len(); j++ {
+			if labels := points.labels(j); labels.Len() == 0 {
+				labels.InitEmptyWithCapacity(0)
+			} else {
+				labels.Sort()
+			}
+		}
+	}
+}
+
+func TestMetricsTransformProcessor(t *testing.T) {
+	tests := []struct {
+		name       string
+		transforms []Transform
+		in         []testMetric
+		out        []testMetric
+	}{
+		{
+			name: "update_name",
+			transforms: []Transform{
+				{MetricName: "old_name", Action: Update, NewName: "new_name"},
+			},
+			in: []testMetric{
+				newTestMetric("old_name", pdata.MetricDataTypeIntGauge).intPoint(nil, 1, 1),
+				newTestMetric("other_name", pdata.MetricDataTypeIntGauge).intPoint(nil, 2, 1),
+			},
+			out: []testMetric{
+				newTestMetric("new_name", pdata.MetricDataTypeIntGauge).intPoint(nil, 1, 1),
+				newTestMetric("other_name", pdata.MetricDataTypeIntGauge).intPoint(nil, 2, 1),
+			},
+		},
+		{
+			name: "update_label",
+			transforms: []Transform{
+				{MetricName: "metric.*", MatchType: filterset.Regexp, Action: Update, Operations: []Operation{
+					{Action: UpdateLabel, Label: "old", NewLabel: "new", ValueActions: []ValueAction{{Value: "a", NewValue: "b"}}},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleSum).
+					doublePoint(map[string]string{"old": "a", "other": "x"}, 1, 1).
+					doublePoint(map[string]string{"old": "c"}, 2, 1).
+					doublePoint(map[string]string{"other": "y"}, 3, 1),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleSum).
+					doublePoint(map[string]string{"new": "b", "other": "x"}, 1, 1).
+					doublePoint(map[string]string{"new": "c"}, 2, 1).
+					doublePoint(map[string]string{"other": "y"}, 3, 1),
+			},
+		},
+		{
+			name: "add_label",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Update, Operations: []Operation{
+					{Action: AddLabel, NewLabel: "version", NewValue: "v1"},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleHistogram).
+					histogramPoint(nil, 1, 1, []float64{1}, []uint64{1, 0}).
+					histogramPoint(map[string]string{"version": "v0"}, 1, 2, []float64{1}, []uint64{0, 1}),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleHistogram).
+					histogramPoint(map[string]string{"version": "v1"}, 1, 1, []float64{1}, []uint64{1, 0}).
+					histogramPoint(map[string]string{"version": "v0"}, 1, 2, []float64{1}, []uint64{0, 1}),
+			},
+		},
+		{
+			name: "delete_label_value",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Update, Operations: []Operation{
+					{Action: DeleteLabelValue, Label: "state", LabelValue: "idle"},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeIntSum).
+					intPoint(map[string]string{"state": "idle"}, 1, 1).
+					intPoint(map[string]string{"state": "busy"}, 2, 1),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeIntSum).
+					intPoint(map[string]string{"state": "busy"}, 2, 1),
+			},
+		},
+		{
+			name: "aggregate_labels_sum",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Update, Operations: []Operation{
+					{Action: AggregateLabels, LabelSet: []string{"a"}, AggregationType: Sum},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeIntSum).
+					intPoint(map[string]string{"a": "1", "b": "1"}, 1, 1).
+					intPoint(map[string]string{"a": "2", "b": "1"}, 4, 1).
+					intPoint(map[string]string{"a": "1", "b": "2"}, 2, 2),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeIntSum).
+					intPoint(map[string]string{"a": "1"}, 3, 2).
+					intPoint(map[string]string{"a": "2"}, 4, 1),
+			},
+		},
+		{
+			name: "aggregate_labels_mean_max_min",
+			transforms: []Transform{
+				{MetricName: "mean", Action: Update, Operations: []Operation{
+					{Action: AggregateLabels, AggregationType: Mean},
+				}},
+				{MetricName: "max", Action: Update, Operations: []Operation{
+					{Action: AggregateLabels, AggregationType: Max},
+				}},
+				{MetricName: "min", Action: Update, Operations: []Operation{
+					{Action: AggregateLabels, AggregationType: Min},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("mean", pdata.MetricDataTypeDoubleGauge).
+					doublePoint(map[string]string{"a": "1"}, 1, 1).
+					doublePoint(map[string]string{"a": "2"}, 2, 1),
+				newTestMetric("max", pdata.MetricDataTypeDoubleGauge).
+					doublePoint(map[string]string{"a": "1"}, 1, 1).
+					doublePoint(map[string]string{"a": "2"}, 2, 1),
+				newTestMetric("min", pdata.MetricDataTypeIntGauge).
+					intPoint(map[string]string{"a": "1"}, 1, 1).
+					intPoint(map[string]string{"a": "2"}, 2, 1),
+			},
+			out: []testMetric{
+				newTestMetric("mean", pdata.MetricDataTypeDoubleGauge).doublePoint(map[string]string{}, 1.5, 1),
+				newTestMetric("max", pdata.MetricDataTypeDoubleGauge).doublePoint(map[string]string{}, 2, 1),
+				newTestMetric("min", pdata.MetricDataTypeIntGauge).intPoint(map[string]string{}, 1, 1),
+			},
+		},
+		{
+			name: "aggregate_labels_histograms",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Update, Operations: []Operation{
+					{Action: AggregateLabels, AggregationType: Sum},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeIntHistogram).
+					intHistogramPoint(map[string]string{"a": "1"}, 2, 3, []float64{1, 2}, []uint64{1, 1, 0}).
+					intHistogramPoint(map[string]string{"a": "2"}, 3, 5, []float64{1, 2}, []uint64{0, 2, 1}).
+					intHistogramPoint(map[string]string{"a": "3"}, 1, 1, []float64{5}, []uint64{1, 0}),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeIntHistogram).
+					intHistogramPoint(map[string]string{}, 6, 9, nil, []uint64{6}),
+			},
+		},
+		{
+			name: "aggregate_labels_histograms_different_bounds",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Update, Operations: []Operation{
+					{Action: AggregateLabels, LabelSet: []string{"b"}, AggregationType: Sum},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleHistogram).
+					histogramPoint(map[string]string{"a": "1", "b": "x"}, 10, 30, []float64{1, 2, 5}, []uint64{1, 2, 3, 4}).
+					histogramPoint(map[string]string{"a": "2", "b": "x"}, 6, 40, []float64{2, 10}, []uint64{1, 2, 3}).
+					histogramPoint(map[string]string{"a": "3", "b": "x"}, 4, 10, []float64{1, 2, 5}, []uint64{1, 1, 1, 1}),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleHistogram).
+					histogramPoint(map[string]string{"b": "x"}, 20, 80, []float64{2}, []uint64{6, 14}),
+			},
+		},
+		{
+			name: "aggregate_label_values_summary",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Update, Operations: []Operation{
+					{Action: AggregateLabelValues, Label: "state", AggregatedValues: []string{"a", "b"}, NewValue: "ab", AggregationType: Sum},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleSummary).
+					summaryPoint(map[string]string{"state": "a"}, 1, 2, 0.5, 2).
+					summaryPoint(map[string]string{"state": "b"}, 2, 6, 0.5, 3).
+					summaryPoint(map[string]string{"state": "c"}, 1, 1, 0.5, 1),
+			},
+			out: []testMetric{
+				func() testMetric {
+					m := newTestMetric("metric", pdata.MetricDataTypeDoubleSummary).
+						summaryPoint(map[string]string{"state": "ab"}, 3, 8, 0.5, 2).
+						summaryPoint(map[string]string{"state": "c"}, 1, 1, 0.5, 1)
+					m.DoubleSummary().DataPoints().At(0).QuantileValues().Resize(0)
+					return m
+				}(),
+			},
+		},
+		{
+			name: "insert",
+			transforms: []Transform{
+				{MetricName: "metric", Action: Insert, NewName: "metric_by_a", Operations: []Operation{
+					{Action: AggregateLabels, LabelSet: []string{"a"}, AggregationType: Sum},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleSum).
+					doublePoint(map[string]string{"a": "1", "b": "1"}, 1, 1).
+					doublePoint(map[string]string{"a": "1", "b": "2"}, 2, 1),
+			},
+			out: []testMetric{
+				newTestMetric("metric", pdata.MetricDataTypeDoubleSum).
+					doublePoint(map[string]string{"a": "1", "b": "1"}, 1, 1).
+					doublePoint(map[string]string{"a": "1", "b": "2"}, 2, 1),
+				newTestMetric("metric_by_a", pdata.MetricDataTypeDoubleSum).
+					doublePoint(map[string]string{"a": "1"}, 3, 1),
+			},
+		},
+		{
+			name: "combine",
+			transforms: []Transform{
+				{MetricName: `^system\.memory\.(?P<state>.*)$`, MatchType: filterset.Regexp, Action: Combine, NewName: "system.memory.usage", Operations: []Operation{
+					{Action: AddLabel, NewLabel: "unit", NewValue: "bytes"},
+				}},
+			},
+			in: []testMetric{
+				newTestMetric("system.memory.used", pdata.MetricDataTypeIntGauge).
+					intPoint(map[string]string{"host": "a"}, 1, 1).
+					intPoint(map[string]string{"host": "b"}, 2, 1),
+				newTestMetric("system.cpu.usage", pdata.MetricDataTypeIntGauge).intPoint(nil, 5, 1),
+				newTestMetric("system.memory.free", pdata.MetricDataTypeIntGauge).
+					intPoint(map[string]string{"host": "a"}, 3, 1),
+			},
+			out: []testMetric{
+				newTestMetric("system.cpu.usage", pdata.MetricDataTypeIntGauge).intPoint(nil, 5, 1),
+				newTestMetric("system.memory.usage", pdata.MetricDataTypeIntGauge).
+					intPoint(map[string]string{"host": "a", "state": "used", "unit": "bytes"}, 1, 1).
+					intPoint(map[string]string{"host": "b", "state": "used", "unit": "bytes"}, 2, 1).
+					intPoint(map[string]string{"host": "a", "state": "free", "unit": "bytes"}, 3, 1),
+			},
+		},
+		{
+			name: "combine_different_data_types",
+			transforms: []Transform{
+				{MetricName: `^system\.memory\.(?P<state>.*)$`, MatchType: filterset.Regexp, Action: Combine, NewName: "system.memory.usage"},
+			},
+			in: []testMetric{
+				newTestMetric("system.memory.used", pdata.MetricDataTypeIntGauge).intPoint(nil, 1, 1),
+				newTestMetric("system.memory.free", pdata.MetricDataTypeDoubleGauge).doublePoint(nil, 3, 1),
+			},
+			out: []testMetric{
+				newTestMetric("system.memory.used", pdata.MetricDataTypeIntGauge).intPoint(nil, 1, 1),
+				newTestMetric("system.memory.free", pdata.MetricDataTypeDoubleGauge).doublePoint(nil, 3, 1),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := new(consumertest.MetricsSink)
+			cfg := &Config{Transforms: test.transforms}
+			require.NoError(t, validateConfiguration(cfg))
+			mp, err := createMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+			require.NoError(t, err)
+
+			require.NoError(t, mp.ConsumeMetrics(context.Background(), newTestMetrics(test.in...)))
+			require.Len(t, next.AllMetrics(), 1)
+			got := next.AllMetrics()[0]
+			normalizeLabels(got)
+			expected := newTestMetrics(test.out...)
+			normalizeLabels(expected)
+			assert.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestMetricsTransformProcessor_NilEmptyData(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  pdata.Metrics
+		output pdata.Metrics
+	}{
+		{
+			name:   "empty",
+			input:  testdata.GenerateMetricsEmpty(),
+			output: testdata.GenerateMetricsEmpty(),
+		},
+		{
+			name:   "one-empty-one-nil-resource-metrics",
+			input:  testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+			output: testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+		},
+		{
+			name:   "one-empty-one-nil-instrumentation-library",
+			input:  testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+			output: testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+		},
+		{
+			name:   "one-metric-one-nil",
+			input:  testdata.GenerateMetricsOneMetricOneNil(),
+			output: testdata.GenerateMetricsOneMetricOneNil(),
+		},
+		{
+			name:   "all-types-nil-data",
+			input:  testdata.GenerateMetricsAllTypesNilDataPoint(),
+			output: testdata.GenerateMetricsAllTypesNilDataPoint(),
+		},
+	}
+	cfg := &Config{Transforms: []Transform{
+		{MetricName: ".*", MatchType: filterset.Regexp, Action: Update, Operations: []Operation{
+			{Action: DeleteLabelValue, Label: "missing", LabelValue: "value"},
+			{Action: AggregateLabelValues, Label: "missing", AggregatedValues: []string{"value"}, NewValue: "new_value", AggregationType: Sum},
+		}},
+		{MetricName: "^missing$", MatchType: filterset.Regexp, Action: Combine, NewName: "combined"},
+	}}
+	mtp, err := newMetricsTransformProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := mtp.ProcessMetrics(context.Background(), test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.output, got)
+		})
+	}
+}
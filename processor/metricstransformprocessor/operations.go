@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricstransformprocessor
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// applyOperations applies the operations, in order, to the data points of the metric.
+func applyOperations(metric pdata.Metric, operations []internalOperation) {
+	points := metricDataPoints(metric)
+	if points == nil {
+		return
+	}
+	for _, op := range operations {
+		switch op.configOperation.Action {
+		case AddLabel:
+			addLabel(points, op)
+		case UpdateLabel:
+			updateLabel(points, op)
+		case DeleteLabelValue:
+			deleteLabelValue(points, op)
+		case AggregateLabels:
+			aggregateLabels(points, op)
+		case AggregateLabelValues:
+			aggregateLabelValues(points, op)
+		}
+	}
+}
+
+// addLabel adds the new label to the data points that don't have it yet.
+func addLabel(points dataPoints, op internalOperation) {
+	for i := 0; i < points.len(); i++ {
+		if points.isNil(i) {
+			continue
+		}
+		points.labels(i).Insert(op.configOperation.NewLabel, op.configOperation.NewValue)
+	}
+}
+
+// updateLabel renames the values of the label listed in the value actions, then renames
+// the label itself if a new label is set.
+func updateLabel(points dataPoints, op internalOperation) {
+	label := op.configOperation.Label
+	newLabel := op.configOperation.NewLabel
+	for i := 0; i < points.len(); i++ {
+		if points.isNil(i) {
+			continue
+		}
+		labels := points.labels(i)
+		value, ok := labels.Get(label)
+		if !ok {
+			continue
+		}
+		if newValue, ok := op.valueActionsMapping[value]; ok {
+			value = newValue
+		}
+		if newLabel != "" && newLabel != label {
+			labels.Delete(label)
+			labels.Upsert(newLabel, value)
+			continue
+		}
+		labels.Update(label, value)
+	}
+}
+
+// deleteLabelValue removes the data points that have the given label value.
+func deleteLabelValue(points dataPoints, op internalOperation) {
+	var kept []int
+	for i := 0; i < points.len(); i++ {
+		if !points.isNil(i) {
+			if value, ok := points.labels(i).Get(op.configOperation.Label); ok && value == op.configOperation.LabelValue {
+				continue
+			}
+		}
+		kept = append(kept, i)
+	}
+	if len(kept) != points.len() {
+		points.keep(kept)
+	}
+}
+
+// aggregateLabels removes the labels that are not in the label set and aggregates the data
+// points that end up with the same labels.
+func aggregateLabels(points dataPoints, op internalOperation) {
+	for i := 0; i < points.len(); i++ {
+		if points.isNil(i) {
+			continue
+		}
+		labels := points.labels(i)
+		var removed []string
+		labels.ForEach(func(k string, _ string) {
+			if !op.labelSetMap[k] {
+				removed = append(removed, k)
+			}
+		})
+		for _, k := range removed {
+			labels.Delete(k)
+		}
+	}
+	aggregate(points, op.configOperation.AggregationType)
+}
+
+// aggregateLabelValues replaces the aggregated values of the label with the new value and
+// aggregates the data points that end up with the same labels.
+func aggregateLabelValues(points dataPoints, op internalOperation) {
+	for i := 0; i < points.len(); i++ {
+		if points.isNil(i) {
+			continue
+		}
+		labels := points.labels(i)
+		if value, ok := labels.Get(op.configOperation.Label); ok && op.aggregatedValuesSet[value] {
+			labels.Update(op.configOperation.Label, op.configOperation.NewValue)
+		}
+	}
+	aggregate(points, op.configOperation.AggregationType)
+}
@@ -104,6 +104,12 @@ func TestNewTraceExporter_ProcessTraceError(t *testing.T) {
 	assert.Equal(t, want, me.ConsumeTraces(context.Background(), testdata.GenerateTraceDataEmpty()))
 }
 
+func TestNewTraceExporter_ProcessTracesErrSkipProcessingData(t *testing.T) {
+	me, err := NewTraceProcessor(testCfg, consumertest.NewTracesNop(), newTestTProcessor(ErrSkipProcessingData))
+	require.NoError(t, err)
+	assert.Equal(t, nil, me.ConsumeTraces(context.Background(), testdata.GenerateTraceDataEmpty()))
+}
+
 func TestNewMetricsExporter(t *testing.T) {
 	me, err := NewMetricsProcessor(testCfg, consumertest.NewMetricsNop(), newTestMProcessor(nil))
 	require.NoError(t, err)
@@ -158,6 +164,12 @@ func TestNewLogsExporter_ProcessLogError(t *testing.T) {
 	assert.Equal(t, want, me.ConsumeLogs(context.Background(), testdata.GenerateLogDataEmpty()))
 }
 
+func TestNewLogsExporter_ProcessLogsErrSkipProcessingData(t *testing.T) {
+	me, err := NewLogsProcessor(testCfg, consumertest.NewLogsNop(), newTestLProcessor(ErrSkipProcessingData))
+	require.NoError(t, err)
+	assert.Equal(t, nil, me.ConsumeLogs(context.Background(), testdata.GenerateLogDataEmpty()))
+}
+
 type testTProcessor struct {
 	retError error
 }
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the redaction processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// AllowAllKeys disables the deletion of the attributes whose key is not in AllowedKeys.
+	// The values of all the attributes are still masked.
+	AllowAllKeys bool `mapstructure:"allow_all_keys"`
+
+	// AllowedKeys is the list of the attribute keys that are kept. All the other attributes,
+	// including the resource attributes and the keys of the nested maps, are deleted, unless
+	// AllowAllKeys is set.
+	AllowedKeys []string `mapstructure:"allowed_keys"`
+
+	// IgnoredKeys is the list of the attribute keys that are neither deleted nor masked.
+	IgnoredKeys []string `mapstructure:"ignored_keys"`
+
+	// BlockedValues is the list of regular expressions matching the sensitive parts of
+	// the string values, that are masked.
+	BlockedValues []string `mapstructure:"blocked_values"`
+
+	// Mask is the string replacing the sensitive parts of the values.
+	Mask string `mapstructure:"mask"`
+}
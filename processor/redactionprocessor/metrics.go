@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+var (
+	statDeletedAttributes = stats.Int64("deleted_attributes", "Number of attributes deleted because their key is not allowed", stats.UnitDimensionless)
+	statMaskedValues      = stats.Int64("masked_values", "Number of attribute values and log bodies masked because they contain a blocked value", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to redaction.
+func MetricViews(level configtelemetry.Level) []*view.View {
+	if level == configtelemetry.LevelNone {
+		return nil
+	}
+
+	processorTagKeys := []tag.Key{processor.TagProcessorNameKey}
+
+	countDeletedAttributesView := &view.View{
+		Name:        statDeletedAttributes.Name(),
+		Measure:     statDeletedAttributes,
+		Description: statDeletedAttributes.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countMaskedValuesView := &view.View{
+		Name:        statMaskedValues.Name(),
+		Measure:     statMaskedValues,
+		Description: statMaskedValues.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	legacyViews := []*view.View{
+		countDeletedAttributesView,
+		countMaskedValuesView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
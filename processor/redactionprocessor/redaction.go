@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor"
+)
+
+type redaction struct {
+	allowAllKeys  bool
+	allowedKeys   map[string]bool
+	ignoredKeys   map[string]bool
+	blockedValues []*regexp.Regexp
+	mask          string
+	statsTags     []tag.Mutator
+}
+
+// redactionCounts counts the redactions of a batch of data.
+type redactionCounts struct {
+	deleted int64
+	masked  int64
+}
+
+func newRedaction(cfg *Config) (*redaction, error) {
+	rp := &redaction{
+		allowAllKeys: cfg.AllowAllKeys,
+		allowedKeys:  make(map[string]bool, len(cfg.AllowedKeys)),
+		ignoredKeys:  make(map[string]bool, len(cfg.IgnoredKeys)),
+		mask:         cfg.Mask,
+		statsTags:    []tag.Mutator{tag.Insert(processor.TagProcessorNameKey, cfg.Name())},
+	}
+	for _, key := range cfg.AllowedKeys {
+		rp.allowedKeys[key] = true
+	}
+	for _, key := range cfg.IgnoredKeys {
+		rp.ignoredKeys[key] = true
+	}
+	for _, pattern := range cfg.BlockedValues {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("error compiling blocked value %q: %v", pattern, err)
+		}
+		rp.blockedValues = append(rp.blockedValues, re)
+	}
+	return rp, nil
+}
+
+// ProcessTraces implements the TProcessor interface.
+func (rp *redaction) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	var counts redactionCounts
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		rp.processAttributes(rs.Resource().Attributes(), &counts)
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				rp.processAttributes(span.Attributes(), &counts)
+				events := span.Events()
+				for e := 0; e < events.Len(); e++ {
+					if event := events.At(e); !event.IsNil() {
+						rp.processAttributes(event.Attributes(), &counts)
+					}
+				}
+			}
+		}
+	}
+	rp.recordCounts(counts)
+	return td, nil
+}
+
+// ProcessLogs implements the LProcessor interface.
+func (rp *redaction) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	var counts redactionCounts
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		rp.processAttributes(rl.Resource().Attributes(), &counts)
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				rp.processAttributes(lr.Attributes(), &counts)
+				if body := lr.Body(); !body.IsNil() {
+					rp.processValue(body, &counts)
+				}
+			}
+		}
+	}
+	rp.recordCounts(counts)
+	return ld, nil
+}
+
+// processAttributes deletes the attributes whose key is not allowed and masks the values of
+// the remaining ones. The keys of the nested maps are processed the same way.
+func (rp *redaction) processAttributes(attributes pdata.AttributeMap, counts *redactionCounts) {
+	var deleted []string
+	attributes.ForEach(func(k string, v pdata.AttributeValue) {
+		if rp.ignoredKeys[k] {
+			return
+		}
+		if !rp.allowAllKeys && !rp.allowedKeys[k] {
+			deleted = append(deleted, k)
+			return
+		}
+		rp.processValue(v, counts)
+	})
+	for _, k := range deleted {
+		attributes.Delete(k)
+	}
+	counts.deleted += int64(len(deleted))
+}
+
+// processValue masks the blocked parts of a string value, and processes the values of
+// the maps and arrays recursively.
+func (rp *redaction) processValue(v pdata.AttributeValue, counts *redactionCounts) {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		rp.maskString(v, counts)
+	case pdata.AttributeValueMAP:
+		rp.processAttributes(v.MapVal(), counts)
+	case pdata.AttributeValueARRAY:
+		values := v.ArrayVal()
+		for i := 0; i < values.Len(); i++ {
+			rp.processValue(values.At(i), counts)
+		}
+	}
+}
+
+// maskString masks the blocked parts of a string value.
+func (rp *redaction) maskString(v pdata.AttributeValue, counts *redactionCounts) {
+	value := v.StringVal()
+	masked := value
+	for _, re := range rp.blockedValues {
+		masked = re.ReplaceAllLiteralString(masked, rp.mask)
+	}
+	if masked != value {
+		v.SetStringVal(masked)
+		counts.masked++
+	}
+}
+
+func (rp *redaction) recordCounts(counts redactionCounts) {
+	if counts.deleted == 0 && counts.masked == 0 {
+		return
+	}
+	_ = stats.RecordWithTags(context.Background(), rp.statsTags, statDeletedAttributes.M(counts.deleted), statMaskedValues.M(counts.masked))
+}
@@ -0,0 +1,220 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redactionprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func newTestRedaction(t *testing.T, modify func(cfg *Config)) *redaction {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AllowedKeys = []string{"http.url", "card", "description"}
+	cfg.IgnoredKeys = []string{"ignored"}
+	cfg.BlockedValues = []string{
+		"4[0-9]{12}(?:[0-9]{3})?",
+		"[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\\.[a-zA-Z]{2,}",
+	}
+	if modify != nil {
+		modify(cfg)
+	}
+	rp, err := newRedaction(cfg)
+	require.NoError(t, err)
+	return rp
+}
+
+func newTestTraces() (pdata.Traces, pdata.Span) {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString("description", "owner john@example.com")
+	rs.Resource().Attributes().InsertString("host.name", "host")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+	span.SetName("span")
+	span.Attributes().InsertString("http.url", "/users?email=john@example.com")
+	span.Attributes().InsertString("card", "4111111111111111")
+	span.Attributes().InsertInt("description", 4111111111111111)
+	span.Attributes().InsertString("password", "secret")
+	span.Attributes().InsertString("ignored", "4111111111111111")
+	span.Events().Resize(1)
+	span.Events().At(0).Attributes().InsertString("description", "paid with 4111111111111111")
+	span.Events().At(0).Attributes().InsertString("token", "secret")
+	return td, span
+}
+
+func assertAttributes(t *testing.T, expected map[string]pdata.AttributeValue, actual pdata.AttributeMap) {
+	am := pdata.NewAttributeMap().InitFromMap(expected).Sort()
+	assert.Equal(t, am, actual.Sort())
+}
+
+func TestRedaction_Traces(t *testing.T) {
+	rp := newTestRedaction(t, nil)
+	td, span := newTestTraces()
+
+	_, err := rp.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"description": pdata.NewAttributeValueString("owner ****"),
+	}, td.ResourceSpans().At(0).Resource().Attributes())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"http.url":    pdata.NewAttributeValueString("/users?email=****"),
+		"card":        pdata.NewAttributeValueString("****"),
+		"description": pdata.NewAttributeValueInt(4111111111111111),
+		"ignored":     pdata.NewAttributeValueString("4111111111111111"),
+	}, span.Attributes())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"description": pdata.NewAttributeValueString("paid with ****"),
+	}, span.Events().At(0).Attributes())
+}
+
+func TestRedaction_AllowAllKeys(t *testing.T) {
+	rp := newTestRedaction(t, func(cfg *Config) {
+		cfg.AllowAllKeys = true
+		cfg.Mask = "[REDACTED]"
+	})
+	td, span := newTestTraces()
+
+	_, err := rp.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"http.url":    pdata.NewAttributeValueString("/users?email=[REDACTED]"),
+		"card":        pdata.NewAttributeValueString("[REDACTED]"),
+		"description": pdata.NewAttributeValueInt(4111111111111111),
+		"password":    pdata.NewAttributeValueString("secret"),
+		"ignored":     pdata.NewAttributeValueString("4111111111111111"),
+	}, span.Attributes())
+}
+
+func TestRedaction_Logs(t *testing.T) {
+	rp := newTestRedaction(t, nil)
+
+	ld := testdata.GenerateLogDataOneLog()
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	lr.Body().SetStringVal("payment by john@example.com with card 4111111111111111")
+	lr.Attributes().InitFromMap(map[string]pdata.AttributeValue{
+		"card":  pdata.NewAttributeValueString("4111111111111111"),
+		"other": pdata.NewAttributeValueString("value"),
+	})
+
+	_, err := rp.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	assert.Equal(t, "payment by **** with card ****", lr.Body().StringVal())
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"card": pdata.NewAttributeValueString("****"),
+	}, lr.Attributes())
+	assert.Equal(t, 0, ld.ResourceLogs().At(0).Resource().Attributes().Len())
+}
+
+func TestRedaction_NestedValues(t *testing.T) {
+	rp := newTestRedaction(t, nil)
+	td, span := newTestTraces()
+	span.Attributes().InitFromMap(map[string]pdata.AttributeValue{"description": pdata.NewAttributeValueMap()})
+	nested, _ := span.Attributes().Get("description")
+	nested.MapVal().InsertString("card", "4111111111111111")
+	nested.MapVal().InsertString("password", "secret")
+	nested.MapVal().InsertString("ignored", "4111111111111111")
+	nested.MapVal().Insert("http.url", pdata.NewAttributeValueArray())
+	urls, _ := nested.MapVal().Get("http.url")
+	urls.ArrayVal().Append(pdata.NewAttributeValueString("/users?email=john@example.com"))
+
+	_, err := rp.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	expectedURLs := pdata.NewAttributeValueArray()
+	expectedURLs.ArrayVal().Append(pdata.NewAttributeValueString("/users?email=****"))
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"card":     pdata.NewAttributeValueString("****"),
+		"ignored":  pdata.NewAttributeValueString("4111111111111111"),
+		"http.url": expectedURLs,
+	}, nested.MapVal())
+}
+
+func TestRedaction_MapBody(t *testing.T) {
+	rp := newTestRedaction(t, nil)
+
+	ld := testdata.GenerateLogDataOneLog()
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	pdata.NewAttributeValueMap().CopyTo(lr.Body())
+	lr.Body().MapVal().InsertString("description", "payment by john@example.com")
+	lr.Body().MapVal().InsertString("password", "secret")
+
+	_, err := rp.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	assertAttributes(t, map[string]pdata.AttributeValue{
+		"description": pdata.NewAttributeValueString("payment by ****"),
+	}, lr.Body().MapVal())
+}
+
+func TestRedaction_NilEmptyData(t *testing.T) {
+	rp := newTestRedaction(t, nil)
+
+	for _, td := range []pdata.Traces{
+		testdata.GenerateTraceDataEmpty(),
+		testdata.GenerateTraceDataOneEmptyOneNilResourceSpans(),
+		testdata.GenerateTraceDataOneEmptyOneNilInstrumentationLibrary(),
+		testdata.GenerateTraceDataOneSpanOneNil(),
+	} {
+		_, err := rp.ProcessTraces(context.Background(), td)
+		assert.NoError(t, err)
+	}
+	for _, ld := range []pdata.Logs{
+		testdata.GenerateLogDataEmpty(),
+		testdata.GenerateLogDataOneEmptyOneNilResourceLogs(),
+		testdata.GenerateLogDataOneEmptyOneNilInstrumentationLibrary(),
+		testdata.GenerateLogDataOneLogOneNil(),
+	} {
+		_, err := rp.ProcessLogs(context.Background(), ld)
+		assert.NoError(t, err)
+	}
+}
+
+func TestRedaction_Metrics(t *testing.T) {
+	views := MetricViews(configtelemetry.LevelDetailed)
+	view.Register(views...)
+	defer view.Unregister(views...)
+
+	rp := newTestRedaction(t, nil)
+	td, _ := newTestTraces()
+	_, err := rp.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	viewData, err := view.RetrieveData("processor/redaction/" + statDeletedAttributes.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	// host.name, password and token.
+	assert.Equal(t, 3.0, viewData[0].Data.(*view.SumData).Value)
+
+	viewData, err = view.RetrieveData("processor/redaction/" + statMaskedValues.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	// The resource description, http.url, card and the event description.
+	assert.Equal(t, 4.0, viewData[0].Data.(*view.SumData).Value)
+}
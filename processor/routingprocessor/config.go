@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// AttributeSource defines where the attribute used for routing is read from.
+type AttributeSource string
+
+const (
+	// ContextAttributeSource reads the attribute from the metadata of the incoming request,
+	// such as the gRPC metadata. The whole batch is routed by the value of the request.
+	ContextAttributeSource AttributeSource = "context"
+	// ResourceAttributeSource reads the attribute from the resource, batches with resources
+	// mapped to different routes are split.
+	ResourceAttributeSource AttributeSource = "resource"
+)
+
+// Config defines configuration for the routing processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// AttributeSource defines where to look for the attribute in FromAttribute. The allowed
+	// values are "context" (the default) and "resource".
+	AttributeSource AttributeSource `mapstructure:"attribute_source"`
+
+	// FromAttribute is the name of the attribute whose value is used to find the route.
+	FromAttribute string `mapstructure:"from_attribute"`
+
+	// DefaultExporters is the list of exporters used when the attribute is missing or its
+	// value is not in the routing table. Data without a route is dropped if it is empty.
+	DefaultExporters []string `mapstructure:"default_exporters"`
+
+	// Table maps attribute values to the exporters that receive the data.
+	Table []RoutingTableItem `mapstructure:"table"`
+}
+
+// RoutingTableItem maps an attribute value to a list of exporters.
+type RoutingTableItem struct {
+	// Value is the value of the attribute that selects this route.
+	Value string `mapstructure:"value"`
+
+	// Exporters is the list of exporters that receive the data for this route.
+	Exporters []string `mapstructure:"exporters"`
+}
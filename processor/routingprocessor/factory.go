@@ -0,0 +1,110 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "routing"
+)
+
+// NewFactory returns a new factory for the routing processor.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithTraces(createTraceProcessor),
+		processorhelper.WithMetrics(createMetricsProcessor),
+		processorhelper.WithLogs(createLogsProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		AttributeSource: ContextAttributeSource,
+	}
+}
+
+// The routing processors send the data directly to the exporters, the next consumer of
+// the pipeline is not used. It is the fan-out to the exporters of the pipeline unless the
+// routing processor is followed by another processor, whose data would be lost.
+
+func createTraceProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	next consumer.TracesConsumer,
+) (component.TracesProcessor, error) {
+	if err := checkLastProcessor(next); err != nil {
+		return nil, err
+	}
+	rp, err := newRoutingProcessor(params.Logger, cfg.(*Config), configmodels.TracesDataType)
+	if err != nil {
+		return nil, err
+	}
+	return &tracesProcessor{routingProcessor: rp}, nil
+}
+
+func createMetricsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	next consumer.MetricsConsumer,
+) (component.MetricsProcessor, error) {
+	if err := checkLastProcessor(next); err != nil {
+		return nil, err
+	}
+	rp, err := newRoutingProcessor(params.Logger, cfg.(*Config), configmodels.MetricsDataType)
+	if err != nil {
+		return nil, err
+	}
+	return &metricsProcessor{routingProcessor: rp}, nil
+}
+
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	next consumer.LogsConsumer,
+) (component.LogsProcessor, error) {
+	if err := checkLastProcessor(next); err != nil {
+		return nil, err
+	}
+	rp, err := newRoutingProcessor(params.Logger, cfg.(*Config), configmodels.LogsDataType)
+	if err != nil {
+		return nil, err
+	}
+	return &logsProcessor{routingProcessor: rp}, nil
+}
+
+// checkLastProcessor returns an error if the next consumer of the pipeline is a processor,
+// exporters and fan-out connectors do not have capabilities.
+func checkLastProcessor(next interface{}) error {
+	if _, ok := next.(component.Processor); ok {
+		return errNotLastProcessor
+	}
+	return nil
+}
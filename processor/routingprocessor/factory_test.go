@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: typeStr,
+			TypeVal: typeStr,
+		},
+		AttributeSource: ContextAttributeSource,
+	}, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessors(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.FromAttribute = "X-Tenant"
+	cfg.DefaultExporters = []string{"exampleexporter"}
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.NotNil(t, tp)
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, mp)
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.NotNil(t, lp)
+}
+
+func TestCreateProcessorsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "no_from_attribute",
+			modify: func(cfg *Config) { cfg.FromAttribute = "" },
+			err:    errNoFromAttribute.Error(),
+		},
+		{
+			name:   "invalid_attribute_source",
+			modify: func(cfg *Config) { cfg.AttributeSource = "span" },
+			err:    `invalid attribute_source "span"`,
+		},
+		{
+			name: "no_exporters",
+			modify: func(cfg *Config) {
+				cfg.DefaultExporters = nil
+				cfg.Table = nil
+			},
+			err: errNoExporters.Error(),
+		},
+		{
+			name:   "empty_value",
+			modify: func(cfg *Config) { cfg.Table[0].Value = "" },
+			err:    errEmptyRouteValue.Error(),
+		},
+		{
+			name:   "empty_exporters",
+			modify: func(cfg *Config) { cfg.Table[0].Exporters = nil },
+			err:    errEmptyRouteExporter.Error(),
+		},
+		{
+			name:   "duplicate_value",
+			modify: func(cfg *Config) { cfg.Table = append(cfg.Table, cfg.Table[0]) },
+			err:    `duplicate routing table value "acme"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.FromAttribute = "X-Tenant"
+			cfg.DefaultExporters = []string{"exampleexporter"}
+			cfg.Table = []RoutingTableItem{{Value: "acme", Exporters: []string{"exampleexporter/acme"}}}
+			test.modify(cfg)
+
+			_, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func TestCreateProcessorsNotLast(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.FromAttribute = "X-Tenant"
+	cfg.DefaultExporters = []string{"exampleexporter"}
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	lastTP, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	_, err = factory.CreateTracesProcessor(context.Background(), params, cfg, lastTP)
+	assert.Equal(t, errNotLastProcessor, err)
+
+	lastMP, err := factory.CreateMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	_, err = factory.CreateMetricsProcessor(context.Background(), params, cfg, lastMP)
+	assert.Equal(t, errNotLastProcessor, err)
+
+	lastLP, err := factory.CreateLogsProcessor(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	_, err = factory.CreateLogsProcessor(context.Background(), params, cfg, lastLP)
+	assert.Equal(t, errNotLastProcessor, err)
+}
@@ -0,0 +1,313 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// defaultRoute is the index of the route used when no item of the routing table matches.
+const defaultRoute = -1
+
+var (
+	errNoFromAttribute    = errors.New("the from_attribute property is required")
+	errNoExporters        = errors.New("at least one of default_exporters or table must be specified")
+	errEmptyRouteValue    = errors.New("the value of a routing table item cannot be empty")
+	errEmptyRouteExporter = errors.New("a routing table item must have at least one exporter")
+	errNotLastProcessor   = errors.New("the routing processor must be the last processor of the pipeline")
+)
+
+// routingProcessor holds the routes shared by the processors of all the data types. The
+// exporters of the routes are looked up when the processor starts.
+type routingProcessor struct {
+	logger   *zap.Logger
+	config   *Config
+	dataType configmodels.DataType
+
+	// routeByValue maps the attribute values to the index of their item in the routing table.
+	routeByValue     map[string]int
+	routeExporters   [][]component.Exporter
+	defaultExporters []component.Exporter
+}
+
+func newRoutingProcessor(logger *zap.Logger, cfg *Config, dataType configmodels.DataType) (*routingProcessor, error) {
+	if cfg.FromAttribute == "" {
+		return nil, errNoFromAttribute
+	}
+	if cfg.AttributeSource != ContextAttributeSource && cfg.AttributeSource != ResourceAttributeSource {
+		return nil, fmt.Errorf("invalid attribute_source %q, must be %q or %q", cfg.AttributeSource, ContextAttributeSource, ResourceAttributeSource)
+	}
+	if len(cfg.DefaultExporters) == 0 && len(cfg.Table) == 0 {
+		return nil, errNoExporters
+	}
+
+	routeByValue := make(map[string]int, len(cfg.Table))
+	for i, item := range cfg.Table {
+		if item.Value == "" {
+			return nil, errEmptyRouteValue
+		}
+		if len(item.Exporters) == 0 {
+			return nil, errEmptyRouteExporter
+		}
+		if _, ok := routeByValue[item.Value]; ok {
+			return nil, fmt.Errorf("duplicate routing table value %q", item.Value)
+		}
+		routeByValue[item.Value] = i
+	}
+
+	return &routingProcessor{
+		logger:       logger,
+		config:       cfg,
+		dataType:     dataType,
+		routeByValue: routeByValue,
+	}, nil
+}
+
+// Start looks up the exporters of all the routes, which must be enabled for the data type of
+// the processor.
+func (rp *routingProcessor) Start(_ context.Context, host component.Host) error {
+	available := make(map[string]component.Exporter)
+	for cfg, exp := range host.GetExporters()[rp.dataType] {
+		available[cfg.Name()] = exp
+	}
+	lookup := func(names []string) ([]component.Exporter, error) {
+		exporters := make([]component.Exporter, 0, len(names))
+		for _, name := range names {
+			exp, ok := available[name]
+			if !ok {
+				return nil, fmt.Errorf("exporter %q not found for data type %q, it must be used by a pipeline of this type", name, rp.dataType)
+			}
+			exporters = append(exporters, exp)
+		}
+		return exporters, nil
+	}
+
+	var err error
+	if rp.defaultExporters, err = lookup(rp.config.DefaultExporters); err != nil {
+		return err
+	}
+	rp.routeExporters = make([][]component.Exporter, len(rp.config.Table))
+	for i, item := range rp.config.Table {
+		if rp.routeExporters[i], err = lookup(item.Exporters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown is invoked during service shutdown.
+func (rp *routingProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// GetCapabilities returns the capabilities of the processor.
+func (rp *routingProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// contextRoute returns the route for the value of the attribute in the metadata of the request.
+func (rp *routingProcessor) contextRoute(ctx context.Context) int {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return defaultRoute
+	}
+	values := md.Get(strings.ToLower(rp.config.FromAttribute))
+	if len(values) == 0 {
+		return defaultRoute
+	}
+	return rp.route(values[0])
+}
+
+// resourceRoute returns the route for the value of the attribute in the resource.
+func (rp *routingProcessor) resourceRoute(resource pdata.Resource) int {
+	v, ok := resource.Attributes().Get(rp.config.FromAttribute)
+	if !ok {
+		return defaultRoute
+	}
+	return rp.route(tracetranslator.AttributeValueToString(v, false))
+}
+
+func (rp *routingProcessor) route(value string) int {
+	if idx, ok := rp.routeByValue[value]; ok {
+		return idx
+	}
+	return defaultRoute
+}
+
+func (rp *routingProcessor) exporters(route int) []component.Exporter {
+	if route == defaultRoute {
+		return rp.defaultExporters
+	}
+	return rp.routeExporters[route]
+}
+
+type tracesProcessor struct {
+	*routingProcessor
+}
+
+var _ component.TracesProcessor = (*tracesProcessor)(nil)
+
+// ConsumeTraces sends the traces to the exporters of their routes.
+func (tp *tracesProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	if tp.config.AttributeSource == ContextAttributeSource {
+		return tp.export(ctx, tp.contextRoute(ctx), td)
+	}
+
+	routed := make(map[int]pdata.Traces)
+	var routes []int
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		route := tp.resourceRoute(rs.Resource())
+		dest, ok := routed[route]
+		if !ok {
+			dest = pdata.NewTraces()
+			routed[route] = dest
+			routes = append(routes, route)
+		}
+		dest.ResourceSpans().Append(rs)
+	}
+
+	var errs []error
+	for _, route := range routes {
+		if err := tp.export(ctx, route, routed[route]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+func (tp *tracesProcessor) export(ctx context.Context, route int, td pdata.Traces) error {
+	var errs []error
+	for _, exp := range tp.exporters(route) {
+		if err := exp.(component.TracesExporter).ConsumeTraces(ctx, td); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+type metricsProcessor struct {
+	*routingProcessor
+}
+
+var _ component.MetricsProcessor = (*metricsProcessor)(nil)
+
+// ConsumeMetrics sends the metrics to the exporters of their routes.
+func (mp *metricsProcessor) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	if mp.config.AttributeSource == ContextAttributeSource {
+		return mp.export(ctx, mp.contextRoute(ctx), md)
+	}
+
+	routed := make(map[int]pdata.Metrics)
+	var routes []int
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		route := mp.resourceRoute(rm.Resource())
+		dest, ok := routed[route]
+		if !ok {
+			dest = pdata.NewMetrics()
+			routed[route] = dest
+			routes = append(routes, route)
+		}
+		dest.ResourceMetrics().Append(rm)
+	}
+
+	var errs []error
+	for _, route := range routes {
+		if err := mp.export(ctx, route, routed[route]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+func (mp *metricsProcessor) export(ctx context.Context, route int, md pdata.Metrics) error {
+	var errs []error
+	for _, exp := range mp.exporters(route) {
+		if err := exp.(component.MetricsExporter).ConsumeMetrics(ctx, md); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+type logsProcessor struct {
+	*routingProcessor
+}
+
+var _ component.LogsProcessor = (*logsProcessor)(nil)
+
+// ConsumeLogs sends the logs to the exporters of their routes.
+func (lp *logsProcessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	if lp.config.AttributeSource == ContextAttributeSource {
+		return lp.export(ctx, lp.contextRoute(ctx), ld)
+	}
+
+	routed := make(map[int]pdata.Logs)
+	var routes []int
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		route := lp.resourceRoute(rl.Resource())
+		dest, ok := routed[route]
+		if !ok {
+			dest = pdata.NewLogs()
+			routed[route] = dest
+			routes = append(routes, route)
+		}
+		dest.ResourceLogs().Append(rl)
+	}
+
+	var errs []error
+	for _, route := range routes {
+		if err := lp.export(ctx, route, routed[route]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+func (lp *logsProcessor) export(ctx context.Context, route int, ld pdata.Logs) error {
+	var errs []error
+	for _, exp := range lp.exporters(route) {
+		if err := exp.(component.LogsExporter).ConsumeLogs(ctx, ld); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routingprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+type mockHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+// newMockHost returns a host with an example exporter for each name, enabled for the data type.
+func newMockHost(dataType configmodels.DataType, names ...string) (*mockHost, map[string]*componenttest.ExampleExporterConsumer) {
+	consumers := make(map[string]*componenttest.ExampleExporterConsumer)
+	exporters := make(map[configmodels.Exporter]component.Exporter)
+	for _, name := range names {
+		exp := &componenttest.ExampleExporterConsumer{}
+		consumers[name] = exp
+		exporters[&componenttest.ExampleExporter{
+			ExporterSettings: configmodels.ExporterSettings{TypeVal: "exampleexporter", NameVal: name},
+		}] = exp
+	}
+	return &mockHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{dataType: exporters},
+	}, consumers
+}
+
+func newTestConfig(source AttributeSource) *Config {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.AttributeSource = source
+	cfg.FromAttribute = "X-Tenant"
+	cfg.DefaultExporters = []string{"default"}
+	cfg.Table = []RoutingTableItem{
+		{Value: "acme", Exporters: []string{"acme"}},
+		{Value: "globex", Exporters: []string{"globex", "default"}},
+	}
+	return cfg
+}
+
+func newTenantTraces(tenants ...string) pdata.Traces {
+	td := pdata.NewTraces()
+	for _, tenant := range tenants {
+		rs := testdata.GenerateTraceDataOneSpan().ResourceSpans().At(0)
+		if tenant != "" {
+			rs.Resource().Attributes().InsertString("X-Tenant", tenant)
+		}
+		td.ResourceSpans().Append(rs)
+	}
+	return td
+}
+
+func newTenantMetrics(tenants ...string) pdata.Metrics {
+	md := pdata.NewMetrics()
+	for _, tenant := range tenants {
+		rm := testdata.GenerateMetricsOneMetric().ResourceMetrics().At(0)
+		if tenant != "" {
+			rm.Resource().Attributes().InsertString("X-Tenant", tenant)
+		}
+		md.ResourceMetrics().Append(rm)
+	}
+	return md
+}
+
+func newTenantLogs(tenants ...string) pdata.Logs {
+	ld := pdata.NewLogs()
+	for _, tenant := range tenants {
+		rl := testdata.GenerateLogDataOneLog().ResourceLogs().At(0)
+		if tenant != "" {
+			rl.Resource().Attributes().InsertString("X-Tenant", tenant)
+		}
+		ld.ResourceLogs().Append(rl)
+	}
+	return ld
+}
+
+func spanCounts(traces []pdata.Traces) []int {
+	var counts []int
+	for _, td := range traces {
+		counts = append(counts, td.SpanCount())
+	}
+	return counts
+}
+
+func TestTracesProcessor_ContextRouting(t *testing.T) {
+	host, exporters := newMockHost(configmodels.TracesDataType, "default", "acme", "globex")
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ContextAttributeSource), consumertest.NewTracesNop())
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), host))
+
+	// The whole batch follows the route of the request, regardless of its resources.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme"))
+	require.NoError(t, tp.ConsumeTraces(ctx, newTenantTraces("globex", "")))
+	assert.Equal(t, []int{2}, spanCounts(exporters["acme"].Traces))
+	assert.Empty(t, exporters["default"].Traces)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "globex"))
+	require.NoError(t, tp.ConsumeTraces(ctx, newTenantTraces("")))
+	assert.Equal(t, []int{1}, spanCounts(exporters["globex"].Traces))
+	assert.Equal(t, []int{1}, spanCounts(exporters["default"].Traces))
+
+	// Unknown values and requests without the attribute use the default route.
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "initech"))
+	require.NoError(t, tp.ConsumeTraces(ctx, newTenantTraces("")))
+	require.NoError(t, tp.ConsumeTraces(context.Background(), newTenantTraces("")))
+	assert.Equal(t, []int{1, 1, 1}, spanCounts(exporters["default"].Traces))
+	assert.Len(t, exporters["acme"].Traces, 1)
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+}
+
+func TestTracesProcessor_ResourceRouting(t *testing.T) {
+	host, exporters := newMockHost(configmodels.TracesDataType, "default", "acme", "globex")
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ResourceAttributeSource), consumertest.NewTracesNop())
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), host))
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), newTenantTraces("acme", "globex", "acme", "", "initech")))
+	assert.Equal(t, []int{2}, spanCounts(exporters["acme"].Traces))
+	assert.Equal(t, []int{1}, spanCounts(exporters["globex"].Traces))
+	// The default exporter receives the batch of the globex route and the one of the default route.
+	assert.ElementsMatch(t, []int{1, 2}, spanCounts(exporters["default"].Traces))
+}
+
+func TestMetricsProcessor_ResourceRouting(t *testing.T) {
+	host, exporters := newMockHost(configmodels.MetricsDataType, "default", "acme", "globex")
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ResourceAttributeSource), consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), host))
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), newTenantMetrics("acme", "", "acme")))
+	require.Len(t, exporters["acme"].Metrics, 1)
+	assert.Equal(t, 2, exporters["acme"].Metrics[0].MetricCount())
+	require.Len(t, exporters["default"].Metrics, 1)
+	assert.Equal(t, 1, exporters["default"].Metrics[0].MetricCount())
+	assert.Empty(t, exporters["globex"].Metrics)
+}
+
+func TestMetricsProcessor_ContextRouting(t *testing.T) {
+	host, exporters := newMockHost(configmodels.MetricsDataType, "default", "acme", "globex")
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ContextAttributeSource), consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), host))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "acme"))
+	require.NoError(t, mp.ConsumeMetrics(ctx, newTenantMetrics("", "")))
+	require.Len(t, exporters["acme"].Metrics, 1)
+	assert.Equal(t, 2, exporters["acme"].Metrics[0].MetricCount())
+}
+
+func TestLogsProcessor_ResourceRouting(t *testing.T) {
+	host, exporters := newMockHost(configmodels.LogsDataType, "default", "acme", "globex")
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ResourceAttributeSource), consumertest.NewLogsNop())
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), host))
+
+	require.NoError(t, lp.ConsumeLogs(context.Background(), newTenantLogs("globex", "acme")))
+	require.Len(t, exporters["acme"].Logs, 1)
+	assert.Equal(t, 1, exporters["acme"].Logs[0].LogRecordCount())
+	require.Len(t, exporters["globex"].Logs, 1)
+	require.Len(t, exporters["default"].Logs, 1)
+	assert.Equal(t, 1, exporters["default"].Logs[0].LogRecordCount())
+}
+
+func TestLogsProcessor_ContextRouting(t *testing.T) {
+	host, exporters := newMockHost(configmodels.LogsDataType, "default", "acme", "globex")
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ContextAttributeSource), consumertest.NewLogsNop())
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), host))
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant", "globex"))
+	require.NoError(t, lp.ConsumeLogs(ctx, newTenantLogs("acme")))
+	assert.Len(t, exporters["globex"].Logs, 1)
+	assert.Len(t, exporters["default"].Logs, 1)
+	assert.Empty(t, exporters["acme"].Logs)
+}
+
+func TestProcessor_NoDefaultRoute(t *testing.T) {
+	host, exporters := newMockHost(configmodels.TracesDataType, "acme", "globex", "default")
+	cfg := newTestConfig(ResourceAttributeSource)
+	cfg.DefaultExporters = nil
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), host))
+
+	// Data without a route is dropped.
+	require.NoError(t, tp.ConsumeTraces(context.Background(), newTenantTraces("", "acme")))
+	assert.Equal(t, []int{1}, spanCounts(exporters["acme"].Traces))
+	assert.Empty(t, exporters["default"].Traces)
+}
+
+func TestProcessor_ExporterNotFound(t *testing.T) {
+	// The globex exporter is only available for metrics.
+	host, _ := newMockHost(configmodels.MetricsDataType, "default", "acme", "globex")
+	tp, err := NewFactory().CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, newTestConfig(ContextAttributeSource), consumertest.NewTracesNop())
+	require.NoError(t, err)
+
+	err = tp.Start(context.Background(), host)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `exporter "default" not found for data type "traces"`)
+}
@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// PolicyType indicates the type of sampling policy.
+type PolicyType string
+
+const (
+	// AlwaysSample samples all traces, typically used for debugging.
+	AlwaysSample PolicyType = "always_sample"
+	// StatusCode samples traces that have at least one span with one of the given status codes.
+	StatusCode PolicyType = "status_code"
+	// Latency samples traces whose duration, from the earliest span start to the latest span end,
+	// is longer than a threshold.
+	Latency PolicyType = "latency"
+	// NumericAttribute samples traces that have a span or resource with a numeric attribute in a given range.
+	NumericAttribute PolicyType = "numeric_attribute"
+	// StringAttribute samples traces that have a span or resource with a string attribute equal to one
+	// of the given values.
+	StringAttribute PolicyType = "string_attribute"
+	// RateLimiting samples traces up to a number of spans per second.
+	RateLimiting PolicyType = "rate_limiting"
+	// Probabilistic samples a percentage of the traces, based on the hash of the trace ID.
+	Probabilistic PolicyType = "probabilistic"
+	// And samples traces that are sampled by all of its sub-policies.
+	And PolicyType = "and"
+)
+
+// StatusCodeCfg holds the configurable settings to create a status code filter sampling policy evaluator.
+type StatusCodeCfg struct {
+	// StatusCodes is the list of status codes (OK, ERROR or UNSET) that cause a trace to be sampled.
+	StatusCodes []string `mapstructure:"status_codes"`
+}
+
+// LatencyCfg holds the configurable settings to create a latency filter sampling policy evaluator.
+type LatencyCfg struct {
+	// ThresholdMs is the minimum duration in milliseconds of the traces to be sampled.
+	ThresholdMs int64 `mapstructure:"threshold_ms"`
+}
+
+// NumericAttributeCfg holds the configurable settings to create a numeric attribute filter
+// sampling policy evaluator.
+type NumericAttributeCfg struct {
+	// Key is the attribute key to be matched.
+	Key string `mapstructure:"key"`
+	// MinValue is the minimum value of the attribute to be considered a match.
+	MinValue int64 `mapstructure:"min_value"`
+	// MaxValue is the maximum value of the attribute to be considered a match.
+	MaxValue int64 `mapstructure:"max_value"`
+}
+
+// StringAttributeCfg holds the configurable settings to create a string attribute filter
+// sampling policy evaluator.
+type StringAttributeCfg struct {
+	// Key is the attribute key to be matched.
+	Key string `mapstructure:"key"`
+	// Values is the set of attribute values that if any is equal to the actual attribute value to be considered a match.
+	Values []string `mapstructure:"values"`
+}
+
+// RateLimitingCfg holds the configurable settings to create a rate limiting sampling policy evaluator.
+type RateLimitingCfg struct {
+	// SpansPerSecond sets the limit on the maximum number of spans that can be processed each second.
+	SpansPerSecond int64 `mapstructure:"spans_per_second"`
+}
+
+// ProbabilisticCfg holds the configurable settings to create a probabilistic sampling policy evaluator.
+type ProbabilisticCfg struct {
+	// HashSalt allows one to configure the hashing salts, see the probabilistic_sampler processor for the
+	// reasons to use different salts at different collector tiers.
+	HashSalt string `mapstructure:"hash_salt"`
+	// SamplingPercentage is the percentage rate at which traces are going to be sampled. Values greater
+	// or equal 100 are treated as "sample all traces".
+	SamplingPercentage float64 `mapstructure:"sampling_percentage"`
+}
+
+// AndCfg holds the configurable settings to create an and sampling policy evaluator.
+type AndCfg struct {
+	// SubPolicyCfg is the list of policies that must all sample a trace for it to be sampled.
+	SubPolicyCfg []AndSubPolicyCfg `mapstructure:"and_sub_policy"`
+}
+
+// sharedPolicyCfg holds the configuration common to the top level policies and the sub-policies
+// of an and policy.
+type sharedPolicyCfg struct {
+	// Name given to the instance of the policy to make easy to identify it in metrics and logs.
+	Name string `mapstructure:"name"`
+	// Type of the policy this will be used to match the proper configuration of the policy.
+	Type PolicyType `mapstructure:"type"`
+	// Configs for status code filter sampling policy evaluator.
+	StatusCodeCfg StatusCodeCfg `mapstructure:"status_code"`
+	// Configs for latency filter sampling policy evaluator.
+	LatencyCfg LatencyCfg `mapstructure:"latency"`
+	// Configs for numeric attribute filter sampling policy evaluator.
+	NumericAttributeCfg NumericAttributeCfg `mapstructure:"numeric_attribute"`
+	// Configs for string attribute filter sampling policy evaluator.
+	StringAttributeCfg StringAttributeCfg `mapstructure:"string_attribute"`
+	// Configs for rate limiting sampling policy evaluator.
+	RateLimitingCfg RateLimitingCfg `mapstructure:"rate_limiting"`
+	// Configs for probabilistic sampling policy evaluator.
+	ProbabilisticCfg ProbabilisticCfg `mapstructure:"probabilistic"`
+}
+
+// AndSubPolicyCfg holds the configuration of a sub-policy of an and policy. Sub-policies
+// cannot be and policies themselves.
+type AndSubPolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+}
+
+// PolicyCfg holds the common configuration to all policies.
+type PolicyCfg struct {
+	sharedPolicyCfg `mapstructure:",squash"`
+	// Configs for and sampling policy evaluator.
+	AndCfg AndCfg `mapstructure:"and"`
+}
+
+// Config holds the configuration for tail-based sampling.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	// DecisionWait is the desired wait time from the arrival of the first span of
+	// trace until the decision about sampling it or not is evaluated.
+	DecisionWait time.Duration `mapstructure:"decision_wait"`
+	// NumTraces is the number of traces kept on memory. Typically most of the data
+	// of a trace is released after a sampling decision is taken.
+	NumTraces uint64 `mapstructure:"num_traces"`
+	// PolicyCfgs sets the tail-based sampling policy which makes a sampling decision
+	// for a given trace when requested. A trace is sampled if any of the policies samples it.
+	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
+}
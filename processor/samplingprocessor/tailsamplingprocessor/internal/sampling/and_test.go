@@ -0,0 +1,46 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestAndEvaluator(t *testing.T) {
+	trace := newTraceWithSpans(1, func(_ int, span pdata.Span) {
+		span.Attributes().InsertString("name", "value")
+		span.Attributes().InsertInt("count", 5)
+	})
+
+	and := NewAnd([]PolicyEvaluator{
+		NewStringAttributeFilter("name", []string{"value"}),
+		NewNumericAttributeFilter("count", 1, 10),
+	})
+	decision, err := and.Evaluate(testTraceID, trace)
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	and = NewAnd([]PolicyEvaluator{
+		NewStringAttributeFilter("name", []string{"value"}),
+		NewNumericAttributeFilter("count", 6, 10),
+	})
+	decision, err = and.Evaluate(testTraceID, trace)
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNumericAttributeFilter(t *testing.T) {
+	filter := NewNumericAttributeFilter("example", 100, 200)
+
+	cases := []struct {
+		desc     string
+		attrs    map[string]pdata.AttributeValue
+		decision Decision
+	}{
+		{"value in range", map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueInt(150)}, Sampled},
+		{"value at min", map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueInt(100)}, Sampled},
+		{"value below range", map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueInt(99)}, NotSampled},
+		{"value above range", map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueInt(201)}, NotSampled},
+		{"other type", map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueString("150")}, NotSampled},
+		{"missing attribute", map[string]pdata.AttributeValue{"other": pdata.NewAttributeValueInt(150)}, NotSampled},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			trace := newTraceWithSpans(1, func(_ int, span pdata.Span) {
+				span.Attributes().InitFromMap(c.attrs)
+			})
+			decision, err := filter.Evaluate(testTraceID, trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.decision, decision)
+		})
+	}
+}
+
+func TestStringAttributeFilter(t *testing.T) {
+	filter := NewStringAttributeFilter("example", []string{"value1", "value2"})
+
+	cases := []struct {
+		desc          string
+		spanAttrs     map[string]pdata.AttributeValue
+		resourceAttrs map[string]pdata.AttributeValue
+		decision      Decision
+	}{
+		{
+			desc:      "span attribute matches",
+			spanAttrs: map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueString("value2")},
+			decision:  Sampled,
+		},
+		{
+			desc:          "resource attribute matches",
+			resourceAttrs: map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueString("value1")},
+			decision:      Sampled,
+		},
+		{
+			desc:      "value does not match",
+			spanAttrs: map[string]pdata.AttributeValue{"example": pdata.NewAttributeValueString("value3")},
+			decision:  NotSampled,
+		},
+		{
+			desc:      "missing attribute",
+			spanAttrs: map[string]pdata.AttributeValue{"other": pdata.NewAttributeValueString("value1")},
+			decision:  NotSampled,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			trace := newTraceWithSpans(1, func(_ int, span pdata.Span) {
+				span.Attributes().InitFromMap(c.spanAttrs)
+			})
+			trace.ReceivedBatches[0].ResourceSpans().At(0).Resource().Attributes().InitFromMap(c.resourceAttrs)
+			decision, err := filter.Evaluate(testTraceID, trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.decision, decision)
+		})
+	}
+}
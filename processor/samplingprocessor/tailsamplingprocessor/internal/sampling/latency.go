@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type latency struct {
+	threshold time.Duration
+}
+
+var _ PolicyEvaluator = (*latency)(nil)
+
+// NewLatency creates a policy evaluator sampling traces with a duration higher than a configured threshold.
+// The duration of a trace is the time between the earliest span start and the latest span end.
+func NewLatency(threshold time.Duration) PolicyEvaluator {
+	return &latency{
+		threshold: threshold,
+	}
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (l *latency) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	var minStartTime, maxEndTime pdata.TimestampUnixNano
+	return hasSpanWithCondition(trace, func(span pdata.Span) bool {
+		if minStartTime == 0 || span.StartTime() < minStartTime {
+			minStartTime = span.StartTime()
+		}
+		if span.EndTime() > maxEndTime {
+			maxEndTime = span.EndTime()
+		}
+		return maxEndTime > minStartTime && time.Duration(maxEndTime-minStartTime) >= l.threshold
+	}), nil
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestLatencySampling(t *testing.T) {
+	start := time.Unix(1000, 0)
+	cases := []struct {
+		desc     string
+		spans    [][2]time.Duration
+		decision Decision
+	}{
+		{
+			desc:     "single span longer than threshold",
+			spans:    [][2]time.Duration{{0, 3 * time.Second}},
+			decision: Sampled,
+		},
+		{
+			desc:     "spans shorter than threshold",
+			spans:    [][2]time.Duration{{0, time.Second}, {500 * time.Millisecond, 1500 * time.Millisecond}},
+			decision: NotSampled,
+		},
+		{
+			desc:     "trace longer than threshold made of short spans",
+			spans:    [][2]time.Duration{{time.Second, 2 * time.Second}, {0, time.Second}, {2 * time.Second, 3 * time.Second}},
+			decision: Sampled,
+		},
+	}
+
+	filter := NewLatency(2 * time.Second)
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			trace := newTraceWithSpans(len(c.spans), func(i int, span pdata.Span) {
+				span.SetStartTime(pdata.TimestampUnixNano(start.Add(c.spans[i][0]).UnixNano()))
+				span.SetEndTime(pdata.TimestampUnixNano(start.Add(c.spans[i][1]).UnixNano()))
+			})
+			decision, err := filter.Evaluate(testTraceID, trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.decision, decision)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type numericAttributeFilter struct {
+	key                string
+	minValue, maxValue int64
+}
+
+var _ PolicyEvaluator = (*numericAttributeFilter)(nil)
+
+// NewNumericAttributeFilter creates a policy evaluator that samples all traces with
+// the given attribute in the given numeric range.
+func NewNumericAttributeFilter(key string, minValue, maxValue int64) PolicyEvaluator {
+	return &numericAttributeFilter{
+		key:      key,
+		minValue: minValue,
+		maxValue: maxValue,
+	}
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (naf *numericAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	return hasResourceOrSpanWithCondition(
+		trace,
+		func(resource pdata.Resource) bool {
+			return naf.matches(resource.Attributes())
+		},
+		func(span pdata.Span) bool {
+			return naf.matches(span.Attributes())
+		},
+	), nil
+}
+
+func (naf *numericAttributeFilter) matches(attrs pdata.AttributeMap) bool {
+	v, ok := attrs.Get(naf.key)
+	if !ok || v.Type() != pdata.AttributeValueINT {
+		return false
+	}
+	value := v.IntVal()
+	return value >= naf.minValue && value <= naf.maxValue
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sampling contains the interfaces and the evaluators of the tail-based sampling policies.
+package sampling
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Decision gives the status of sampling decision.
+type Decision int32
+
+const (
+	// Unspecified indicates that the status of the decision was not set yet.
+	Unspecified Decision = iota
+	// Pending indicates that the policy was not evaluated yet.
+	Pending
+	// Sampled is used to indicate that the decision was already taken
+	// to sample the data.
+	Sampled
+	// NotSampled is used to indicate that the decision was already taken
+	// to not sample the data.
+	NotSampled
+)
+
+// TraceData stores the sampling related trace data.
+type TraceData struct {
+	// ArrivalTime is the time when the first span of the trace was received.
+	ArrivalTime time.Time
+	// SpanCount is the number of spans received for the trace so far.
+	SpanCount int64
+	// ReceivedBatches stores all the batches received for the trace.
+	ReceivedBatches []pdata.Traces
+	// FinalDecision is the sampling decision taken for the trace, Pending until the policies are evaluated.
+	FinalDecision Decision
+}
+
+// PolicyEvaluator implements a tail-based sampling policy evaluator,
+// which makes a sampling decision for a given trace when requested.
+type PolicyEvaluator interface {
+	// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+	Evaluate(traceID pdata.TraceID, trace *TraceData) (Decision, error)
+}
+
+// hasSpanWithCondition iterates through all the spans of the trace and returns
+// Sampled if any of them satisfies the condition.
+func hasSpanWithCondition(trace *TraceData, shouldSample func(span pdata.Span) bool) Decision {
+	for _, batch := range trace.ReceivedBatches {
+		rss := batch.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			if rs.IsNil() {
+				continue
+			}
+			if hasInstrumentationLibrarySpanWithCondition(rs.InstrumentationLibrarySpans(), shouldSample) {
+				return Sampled
+			}
+		}
+	}
+	return NotSampled
+}
+
+// hasResourceOrSpanWithCondition iterates through all the resources and spans of the trace and
+// returns Sampled if any of them satisfies the respective condition.
+func hasResourceOrSpanWithCondition(
+	trace *TraceData,
+	shouldSampleResource func(resource pdata.Resource) bool,
+	shouldSampleSpan func(span pdata.Span) bool,
+) Decision {
+	for _, batch := range trace.ReceivedBatches {
+		rss := batch.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			rs := rss.At(i)
+			if rs.IsNil() {
+				continue
+			}
+			if shouldSampleResource(rs.Resource()) {
+				return Sampled
+			}
+			if hasInstrumentationLibrarySpanWithCondition(rs.InstrumentationLibrarySpans(), shouldSampleSpan) {
+				return Sampled
+			}
+		}
+	}
+	return NotSampled
+}
+
+func hasInstrumentationLibrarySpanWithCondition(ilss pdata.InstrumentationLibrarySpansSlice, check func(span pdata.Span) bool) bool {
+	for i := 0; i < ilss.Len(); i++ {
+		ils := ilss.At(i)
+		if ils.IsNil() {
+			continue
+		}
+		spans := ils.Spans()
+		for j := 0; j < spans.Len(); j++ {
+			span := spans.At(j)
+			if span.IsNil() {
+				continue
+			}
+			if check(span) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestProbabilisticSampling(t *testing.T) {
+	tests := []struct {
+		name               string
+		samplingPercentage float64
+		hashSalt           string
+	}{
+		{"100%", 100, ""},
+		{"0%", 0, ""},
+		{"25%", 25, ""},
+		{"33%", 33, ""},
+		{"33% - custom salt", 33, "test-salt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceCount := 10_000
+
+			probabilisticSampler := NewProbabilisticSampler(tt.hashSalt, tt.samplingPercentage)
+
+			sampled := 0
+			for _, traceID := range genRandomTraceIDs(traceCount) {
+				decision, err := probabilisticSampler.Evaluate(traceID, nil)
+				assert.NoError(t, err)
+				if decision == Sampled {
+					sampled++
+				}
+			}
+
+			effectivePercentage := float64(sampled) / float64(traceCount) * 100
+			assert.InDelta(t, tt.samplingPercentage, effectivePercentage, 0.5)
+		})
+	}
+}
+
+func TestProbabilisticSampling_Deterministic(t *testing.T) {
+	sampler := NewProbabilisticSampler("salt", 50)
+	for _, traceID := range genRandomTraceIDs(100) {
+		first, err := sampler.Evaluate(traceID, nil)
+		assert.NoError(t, err)
+		second, err := NewProbabilisticSampler("salt", 50).Evaluate(traceID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, first, second)
+	}
+}
+
+func genRandomTraceIDs(num int) (ids []pdata.TraceID) {
+	r := rand.New(rand.NewSource(1))
+	ids = make([]pdata.TraceID, 0, num)
+	for i := 0; i < num; i++ {
+		traceID := [16]byte{}
+		binary.BigEndian.PutUint64(traceID[:8], r.Uint64())
+		binary.BigEndian.PutUint64(traceID[8:], r.Uint64())
+		ids = append(ids, pdata.NewTraceID(traceID))
+	}
+	return ids
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type rateLimiting struct {
+	currentSecond        int64
+	spansInCurrentSecond int64
+	spansPerSecond       int64
+	now                  func() time.Time
+}
+
+var _ PolicyEvaluator = (*rateLimiting)(nil)
+
+// NewRateLimiting creates a policy evaluator the samples all traces while the number of
+// sampled spans in the current second is below the given limit.
+func NewRateLimiting(spansPerSecond int64) PolicyEvaluator {
+	return &rateLimiting{
+		spansPerSecond: spansPerSecond,
+		now:            time.Now,
+	}
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+// It is not safe for concurrent use, the processor evaluates all the traces from a single goroutine.
+func (r *rateLimiting) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	currSecond := r.now().Unix()
+	if r.currentSecond != currSecond {
+		r.currentSecond = currSecond
+		r.spansInCurrentSecond = 0
+	}
+
+	spansInSecondIfSampled := r.spansInCurrentSecond + trace.SpanCount
+	if spansInSecondIfSampled <= r.spansPerSecond {
+		r.spansInCurrentSecond = spansInSecondIfSampled
+		return Sampled, nil
+	}
+
+	return NotSampled, nil
+}
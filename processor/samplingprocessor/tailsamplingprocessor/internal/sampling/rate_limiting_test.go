@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rateLimiter := NewRateLimiting(3).(*rateLimiting)
+	rateLimiter.now = func() time.Time { return now }
+
+	// Trace span count greater than spans per second.
+	decision, err := rateLimiter.Evaluate(testTraceID, &TraceData{SpanCount: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	decision, err = rateLimiter.Evaluate(testTraceID, &TraceData{SpanCount: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// The budget of the current second is exhausted.
+	decision, err = rateLimiter.Evaluate(testTraceID, &TraceData{SpanCount: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, NotSampled, decision)
+
+	decision, err = rateLimiter.Evaluate(testTraceID, &TraceData{SpanCount: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+
+	// A new second resets the budget.
+	now = now.Add(time.Second)
+	decision, err = rateLimiter.Evaluate(testTraceID, &TraceData{SpanCount: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestNewStatusCodeFilter_errorHandling(t *testing.T) {
+	_, err := NewStatusCodeFilter([]string{})
+	assert.Error(t, err, "expected at least one status code to filter on")
+
+	_, err = NewStatusCodeFilter([]string{"OK", "ERR"})
+	assert.Error(t, err)
+}
+
+func TestStatusCodeSampling(t *testing.T) {
+	cases := []struct {
+		desc                     string
+		filterCfg                []string
+		spansWithStatus          []pdata.StatusCode
+		nilStatus                bool
+		expectedSamplingDecision Decision
+	}{
+		{
+			desc:                     "filter on ERROR - none match",
+			filterCfg:                []string{"ERROR"},
+			spansWithStatus:          []pdata.StatusCode{pdata.StatusCodeOk, pdata.StatusCodeUnset, pdata.StatusCodeOk},
+			expectedSamplingDecision: NotSampled,
+		},
+		{
+			desc:                     "filter on OK and ERROR - none match",
+			filterCfg:                []string{"OK", "ERROR"},
+			spansWithStatus:          []pdata.StatusCode{pdata.StatusCodeUnset, pdata.StatusCodeUnset},
+			expectedSamplingDecision: NotSampled,
+		},
+		{
+			desc:                     "filter on UNSET - matches",
+			filterCfg:                []string{"UNSET"},
+			spansWithStatus:          []pdata.StatusCode{pdata.StatusCodeUnset},
+			expectedSamplingDecision: Sampled,
+		},
+		{
+			desc:                     "filter on OK and UNSET - matches",
+			filterCfg:                []string{"OK", "UNSET"},
+			spansWithStatus:          []pdata.StatusCode{pdata.StatusCodeError, pdata.StatusCodeOk},
+			expectedSamplingDecision: Sampled,
+		},
+		{
+			desc:                     "filter on UNSET - missing status matches",
+			filterCfg:                []string{"UNSET"},
+			spansWithStatus:          []pdata.StatusCode{pdata.StatusCodeUnset},
+			nilStatus:                true,
+			expectedSamplingDecision: Sampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			trace := newTraceWithSpans(len(c.spansWithStatus), func(i int, span pdata.Span) {
+				if c.nilStatus {
+					return
+				}
+				span.Status().InitEmpty()
+				span.Status().SetCode(c.spansWithStatus[i])
+			})
+
+			statusCodeFilter, err := NewStatusCodeFilter(c.filterCfg)
+			require.NoError(t, err)
+
+			decision, err := statusCodeFilter.Evaluate(testTraceID, trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expectedSamplingDecision, decision)
+		})
+	}
+}
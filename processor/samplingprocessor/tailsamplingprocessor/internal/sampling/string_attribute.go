@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+type stringAttributeFilter struct {
+	key    string
+	values map[string]struct{}
+}
+
+var _ PolicyEvaluator = (*stringAttributeFilter)(nil)
+
+// NewStringAttributeFilter creates a policy evaluator that samples all traces with
+// the given attribute in the given set of values.
+func NewStringAttributeFilter(key string, values []string) PolicyEvaluator {
+	valuesMap := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		if value != "" {
+			valuesMap[value] = struct{}{}
+		}
+	}
+	return &stringAttributeFilter{
+		key:    key,
+		values: valuesMap,
+	}
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (saf *stringAttributeFilter) Evaluate(_ pdata.TraceID, trace *TraceData) (Decision, error) {
+	return hasResourceOrSpanWithCondition(
+		trace,
+		func(resource pdata.Resource) bool {
+			return saf.matches(resource.Attributes())
+		},
+		func(span pdata.Span) bool {
+			return saf.matches(span.Attributes())
+		},
+	), nil
+}
+
+func (saf *stringAttributeFilter) matches(attrs pdata.AttributeMap) bool {
+	v, ok := attrs.Get(saf.key)
+	if !ok || v.Type() != pdata.AttributeValueSTRING {
+		return false
+	}
+	_, matched := saf.values[v.StringVal()]
+	return matched
+}
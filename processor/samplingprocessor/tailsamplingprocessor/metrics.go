@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"go.opentelemetry.io/collector/config/configtelemetry"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/processor"
+)
+
+// Variables related to metrics specific to tail sampling.
+var (
+	tagPolicyKey, _  = tag.NewKey("policy")
+	tagSampledKey, _ = tag.NewKey("sampled")
+
+	statDecisionLatency             = stats.Int64("sampling_decision_latency", "Latency (in milliseconds) from the arrival of the first span of a trace to its sampling decision", stats.UnitMilliseconds)
+	statPolicyEvaluationErrorCount  = stats.Int64("sampling_policy_evaluation_error", "Count of sampling policy evaluation errors", stats.UnitDimensionless)
+	statCountTracesSampled          = stats.Int64("count_traces_sampled", "Count of traces that were sampled or not per policy", stats.UnitDimensionless)
+	statTracesEvictedBeforeDecision = stats.Int64("sampling_trace_dropped_too_early", "Count of traces that needed to be dropped before the configured wait time", stats.UnitDimensionless)
+	statLateSpansDropped            = stats.Int64("sampling_late_span_dropped", "Count of spans dropped because they arrived after their trace was not sampled", stats.UnitDimensionless)
+	statTracesOnMemory              = stats.Int64("sampling_traces_on_memory", "Tracks the number of traces current on memory", stats.UnitDimensionless)
+)
+
+// MetricViews returns the metrics views related to tail sampling.
+func MetricViews(level configtelemetry.Level) []*view.View {
+	if level == configtelemetry.LevelNone {
+		return nil
+	}
+
+	processorTagKeys := []tag.Key{processor.TagProcessorNameKey}
+	policyTagKeys := []tag.Key{processor.TagProcessorNameKey, tagPolicyKey}
+
+	decisionLatencyView := &view.View{
+		Name:        statDecisionLatency.Name(),
+		Measure:     statDecisionLatency,
+		Description: statDecisionLatency.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Distribution(1000, 2000, 5000, 10000, 20000, 30000, 50000, 60000, 120000, 300000),
+	}
+
+	policyEvaluationErrorView := &view.View{
+		Name:        statPolicyEvaluationErrorCount.Name(),
+		Measure:     statPolicyEvaluationErrorCount,
+		Description: statPolicyEvaluationErrorCount.Description(),
+		TagKeys:     policyTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countTracesSampledView := &view.View{
+		Name:        statCountTracesSampled.Name(),
+		Measure:     statCountTracesSampled,
+		Description: statCountTracesSampled.Description(),
+		TagKeys:     []tag.Key{processor.TagProcessorNameKey, tagPolicyKey, tagSampledKey},
+		Aggregation: view.Sum(),
+	}
+
+	countTracesEvictedView := &view.View{
+		Name:        statTracesEvictedBeforeDecision.Name(),
+		Measure:     statTracesEvictedBeforeDecision,
+		Description: statTracesEvictedBeforeDecision.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countLateSpansDroppedView := &view.View{
+		Name:        statLateSpansDropped.Name(),
+		Measure:     statLateSpansDropped,
+		Description: statLateSpansDropped.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	tracesOnMemoryView := &view.View{
+		Name:        statTracesOnMemory.Name(),
+		Measure:     statTracesOnMemory,
+		Description: statTracesOnMemory.Description(),
+		TagKeys:     processorTagKeys,
+		Aggregation: view.LastValue(),
+	}
+
+	legacyViews := []*view.View{
+		decisionLatencyView,
+		policyEvaluationErrorView,
+		countTracesSampledView,
+		countTracesEvictedView,
+		countLateSpansDroppedView,
+		tracesOnMemoryView,
+	}
+
+	return obsreport.ProcessorMetricViews(typeStr, legacyViews)
+}
@@ -0,0 +1,417 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor"
+	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor/internal/sampling"
+)
+
+// policy combines a sampling policy evaluator with the context used to record
+// the metrics of that policy.
+type policy struct {
+	// name used to identify this policy instance.
+	name string
+	// evaluator that decides if a trace is sampled or not by this policy instance.
+	evaluator sampling.PolicyEvaluator
+	// ctx used to carry metric tags of each policy.
+	ctx context.Context
+}
+
+// pendingTrace is a trace waiting for the sampling decision.
+type pendingTrace struct {
+	id          pdata.TraceID
+	decisionAt  time.Time
+	arrivalTime time.Time
+}
+
+// tailSamplingSpanProcessor handles the incoming trace data and uses the given sampling
+// policies to sample traces. Spans are kept in memory until DecisionWait elapsed since the
+// arrival of the first span of their trace, then all the policies are evaluated and the whole
+// trace is either forwarded or dropped.
+type tailSamplingSpanProcessor struct {
+	ctx          context.Context
+	nextConsumer consumer.TracesConsumer
+	logger       *zap.Logger
+	policies     []*policy
+	decisionWait time.Duration
+	maxNumTraces uint64
+	tickInterval time.Duration
+	now          func() time.Time
+
+	mu        sync.Mutex
+	idToTrace map[pdata.TraceID]*sampling.TraceData
+	// pending is the list of traces waiting for a decision, ordered by decision time.
+	pending []pendingTrace
+	// evictionRing holds the trace IDs kept in memory in arrival order, used to
+	// drop the oldest traces when maxNumTraces is reached.
+	evictionRing []pdata.TraceID
+	evictionPos  int
+
+	// startOnce guards the start of the ticker goroutine, which closes doneCh when it
+	// returns. Shutdown closes doneCh itself if the processor was never started.
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+}
+
+var _ component.TracesProcessor = (*tailSamplingSpanProcessor)(nil)
+
+// newTraceProcessor returns a processor.TracesProcessor that will perform tail sampling according to the given
+// configuration.
+func newTraceProcessor(logger *zap.Logger, nextConsumer consumer.TracesConsumer, cfg Config) (component.TracesProcessor, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if cfg.NumTraces == 0 {
+		return nil, errors.New("num_traces must be greater than zero")
+	}
+	if len(cfg.PolicyCfgs) == 0 {
+		return nil, errors.New("at least one sampling policy must be configured")
+	}
+
+	ctx, err := tag.New(context.Background(), tag.Upsert(processor.TagProcessorNameKey, cfg.Name()))
+	if err != nil {
+		return nil, err
+	}
+	var policies []*policy
+	for i := range cfg.PolicyCfgs {
+		policyCfg := &cfg.PolicyCfgs[i]
+		policyCtx, err := tag.New(ctx, tag.Upsert(tagPolicyKey, policyCfg.Name))
+		if err != nil {
+			return nil, err
+		}
+		eval, err := getPolicyEvaluator(policyCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create policy %q: %w", policyCfg.Name, err)
+		}
+		policies = append(policies, &policy{
+			name:      policyCfg.Name,
+			evaluator: eval,
+			ctx:       policyCtx,
+		})
+	}
+
+	return &tailSamplingSpanProcessor{
+		ctx:          ctx,
+		nextConsumer: nextConsumer,
+		logger:       logger,
+		policies:     policies,
+		decisionWait: cfg.DecisionWait,
+		maxNumTraces: cfg.NumTraces,
+		tickInterval: time.Second,
+		now:          time.Now,
+		idToTrace:    make(map[pdata.TraceID]*sampling.TraceData),
+		evictionRing: make([]pdata.TraceID, cfg.NumTraces),
+		stopCh:       make(chan struct{}),
+		doneCh:       make(chan struct{}),
+	}, nil
+}
+
+func getPolicyEvaluator(cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
+	if cfg.Type != And {
+		return getSharedPolicyEvaluator(&cfg.sharedPolicyCfg)
+	}
+
+	var subpolicies []sampling.PolicyEvaluator
+	for i := range cfg.AndCfg.SubPolicyCfg {
+		sub, err := getSharedPolicyEvaluator(&cfg.AndCfg.SubPolicyCfg[i].sharedPolicyCfg)
+		if err != nil {
+			return nil, err
+		}
+		subpolicies = append(subpolicies, sub)
+	}
+	if len(subpolicies) == 0 {
+		return nil, errors.New("and policy requires at least one sub-policy")
+	}
+	return sampling.NewAnd(subpolicies), nil
+}
+
+func getSharedPolicyEvaluator(cfg *sharedPolicyCfg) (sampling.PolicyEvaluator, error) {
+	switch cfg.Type {
+	case AlwaysSample:
+		return sampling.NewAlwaysSample(), nil
+	case StatusCode:
+		return sampling.NewStatusCodeFilter(cfg.StatusCodeCfg.StatusCodes)
+	case Latency:
+		return sampling.NewLatency(time.Duration(cfg.LatencyCfg.ThresholdMs) * time.Millisecond), nil
+	case NumericAttribute:
+		nafCfg := cfg.NumericAttributeCfg
+		return sampling.NewNumericAttributeFilter(nafCfg.Key, nafCfg.MinValue, nafCfg.MaxValue), nil
+	case StringAttribute:
+		safCfg := cfg.StringAttributeCfg
+		return sampling.NewStringAttributeFilter(safCfg.Key, safCfg.Values), nil
+	case RateLimiting:
+		return sampling.NewRateLimiting(cfg.RateLimitingCfg.SpansPerSecond), nil
+	case Probabilistic:
+		pCfg := cfg.ProbabilisticCfg
+		return sampling.NewProbabilisticSampler(pCfg.HashSalt, pCfg.SamplingPercentage), nil
+	default:
+		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
+	}
+}
+
+// ConsumeTraces is required by the component.TracesProcessor interface.
+func (tsp *tailSamplingSpanProcessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	var toForward []pdata.Traces
+	tsp.mu.Lock()
+	for id, batch := range groupSpansByTraceID(td) {
+		if forward, ok := tsp.addSpans(id, batch); ok {
+			toForward = append(toForward, forward)
+		}
+	}
+	tracesOnMemory := len(tsp.idToTrace)
+	tsp.mu.Unlock()
+
+	stats.Record(tsp.ctx, statTracesOnMemory.M(int64(tracesOnMemory)))
+
+	var errs []error
+	for _, forward := range toForward {
+		if err := tsp.nextConsumer.ConsumeTraces(ctx, forward); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
+
+// addSpans stores the spans of a trace waiting for its decision. If the decision was already taken, it returns
+// the spans and true when they must be forwarded immediately. Must be called holding the lock.
+func (tsp *tailSamplingSpanProcessor) addSpans(id pdata.TraceID, batch pdata.Traces) (pdata.Traces, bool) {
+	trace, ok := tsp.idToTrace[id]
+	if !ok {
+		now := tsp.now()
+		trace = &sampling.TraceData{
+			ArrivalTime:   now,
+			FinalDecision: sampling.Pending,
+		}
+		tsp.idToTrace[id] = trace
+		tsp.pending = append(tsp.pending, pendingTrace{id: id, decisionAt: now.Add(tsp.decisionWait), arrivalTime: now})
+		tsp.trackForEviction(id)
+	}
+
+	switch trace.FinalDecision {
+	case sampling.Sampled:
+		// Late arriving spans of a sampled trace are forwarded immediately.
+		return batch, true
+	case sampling.NotSampled:
+		stats.Record(tsp.ctx, statLateSpansDropped.M(int64(batch.SpanCount())))
+		return pdata.Traces{}, false
+	default:
+		trace.SpanCount += int64(batch.SpanCount())
+		trace.ReceivedBatches = append(trace.ReceivedBatches, batch)
+		return pdata.Traces{}, false
+	}
+}
+
+// trackForEviction records the new trace ID and drops the oldest trace if the maximum number of traces
+// in memory is reached. Must be called holding the lock.
+func (tsp *tailSamplingSpanProcessor) trackForEviction(id pdata.TraceID) {
+	// The ring has the same size as the maximum number of traces, once it is full the slot
+	// being overwritten holds the oldest trace in memory.
+	if uint64(len(tsp.idToTrace)) > tsp.maxNumTraces {
+		oldest := tsp.evictionRing[tsp.evictionPos]
+		if trace, ok := tsp.idToTrace[oldest]; ok {
+			if trace.FinalDecision == sampling.Pending {
+				stats.Record(tsp.ctx, statTracesEvictedBeforeDecision.M(1))
+			}
+			delete(tsp.idToTrace, oldest)
+		}
+	}
+	tsp.evictionRing[tsp.evictionPos] = id
+	tsp.evictionPos = (tsp.evictionPos + 1) % len(tsp.evictionRing)
+}
+
+// samplingPolicyOnTick takes the decision for all the traces whose decision wait elapsed and forwards
+// the sampled ones.
+func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
+	now := tsp.now()
+
+	var toForward []pdata.Traces
+	tsp.mu.Lock()
+	i := 0
+	for ; i < len(tsp.pending) && !tsp.pending[i].decisionAt.After(now); i++ {
+		id := tsp.pending[i].id
+		trace, ok := tsp.idToTrace[id]
+		if !ok || trace.FinalDecision != sampling.Pending || !trace.ArrivalTime.Equal(tsp.pending[i].arrivalTime) {
+			// Evicted before the decision, possibly followed by new spans for the same trace ID.
+			continue
+		}
+
+		trace.FinalDecision = tsp.makeDecision(id, trace)
+		stats.Record(tsp.ctx, statDecisionLatency.M(int64(now.Sub(tsp.pending[i].arrivalTime)/time.Millisecond)))
+		if trace.FinalDecision == sampling.Sampled {
+			toForward = append(toForward, combineTraces(trace.ReceivedBatches))
+		}
+		// The spans are not needed anymore, only the decision is kept for late arriving spans.
+		trace.ReceivedBatches = nil
+	}
+	tsp.pending = tsp.pending[i:]
+	tsp.mu.Unlock()
+
+	for _, td := range toForward {
+		if err := tsp.nextConsumer.ConsumeTraces(tsp.ctx, td); err != nil {
+			tsp.logger.Warn("Failed to forward sampled trace", zap.Error(err))
+		}
+	}
+}
+
+// makeDecision evaluates all the policies, the trace is sampled if any of them samples it.
+func (tsp *tailSamplingSpanProcessor) makeDecision(id pdata.TraceID, trace *sampling.TraceData) sampling.Decision {
+	finalDecision := sampling.NotSampled
+	for _, p := range tsp.policies {
+		decision, err := p.evaluator.Evaluate(id, trace)
+		if err != nil {
+			stats.Record(p.ctx, statPolicyEvaluationErrorCount.M(1))
+			tsp.logger.Debug("Sampling policy error", zap.String("policy", p.name), zap.Error(err))
+			continue
+		}
+
+		sampled := decision == sampling.Sampled
+		_ = stats.RecordWithTags(
+			p.ctx,
+			[]tag.Mutator{tag.Insert(tagSampledKey, fmt.Sprintf("%t", sampled))},
+			statCountTracesSampled.M(1),
+		)
+		if sampled {
+			finalDecision = sampling.Sampled
+		}
+	}
+	return finalDecision
+}
+
+// GetCapabilities returns the Capabilities assigned to this processor.
+func (tsp *tailSamplingSpanProcessor) GetCapabilities() component.ProcessorCapabilities {
+	return component.ProcessorCapabilities{MutatesConsumedData: false}
+}
+
+// Start is invoked during service startup.
+func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+	tsp.startOnce.Do(func() {
+		go func() {
+			defer close(tsp.doneCh)
+			ticker := time.NewTicker(tsp.tickInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					tsp.samplingPolicyOnTick()
+				case <-tsp.stopCh:
+					return
+				}
+			}
+		}()
+	})
+	return nil
+}
+
+// Shutdown is invoked during service shutdown. Traces waiting for a decision are dropped.
+// It can be called without a prior Start and more than once.
+func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+	tsp.stopOnce.Do(func() {
+		// Prevents a later start, and marks the ticker goroutine as done if it never ran.
+		tsp.startOnce.Do(func() { close(tsp.doneCh) })
+		close(tsp.stopCh)
+	})
+	<-tsp.doneCh
+	return nil
+}
+
+// groupSpansByTraceID splits the traces in one pdata.Traces per trace ID, keeping the resource
+// and instrumentation library of the spans.
+func groupSpansByTraceID(td pdata.Traces) map[pdata.TraceID]pdata.Traces {
+	result := make(map[pdata.TraceID]pdata.Traces)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			// Tracks the spans of the current instrumentation library per trace ID.
+			ilsByID := make(map[pdata.TraceID]pdata.InstrumentationLibrarySpans)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				id := span.TraceID()
+				dest, ok := ilsByID[id]
+				if !ok {
+					dest = appendResourceAndLibrary(result, id, rs, ils)
+					ilsByID[id] = dest
+				}
+				dest.Spans().Append(span)
+			}
+		}
+	}
+	return result
+}
+
+// appendResourceAndLibrary adds a copy of the resource and instrumentation library to the traces of
+// the given trace ID and returns the new instrumentation library spans.
+func appendResourceAndLibrary(
+	result map[pdata.TraceID]pdata.Traces,
+	id pdata.TraceID,
+	rs pdata.ResourceSpans,
+	ils pdata.InstrumentationLibrarySpans,
+) pdata.InstrumentationLibrarySpans {
+	td, ok := result[id]
+	if !ok {
+		td = pdata.NewTraces()
+		result[id] = td
+	}
+	destRss := td.ResourceSpans()
+	destRss.Resize(destRss.Len() + 1)
+	destRs := destRss.At(destRss.Len() - 1)
+	rs.Resource().CopyTo(destRs.Resource())
+	destIlss := destRs.InstrumentationLibrarySpans()
+	destIlss.Resize(1)
+	destIls := destIlss.At(0)
+	ils.InstrumentationLibrary().CopyTo(destIls.InstrumentationLibrary())
+	return destIls
+}
+
+// combineTraces merges all the batches received for a trace in a single pdata.Traces.
+func combineTraces(batches []pdata.Traces) pdata.Traces {
+	if len(batches) == 1 {
+		return batches[0]
+	}
+	combined := pdata.NewTraces()
+	for _, batch := range batches {
+		batch.ResourceSpans().MoveAndAppendTo(combined.ResourceSpans())
+	}
+	return combined
+}
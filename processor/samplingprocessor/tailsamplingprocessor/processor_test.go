@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tailsamplingprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const defaultTestDecisionWait = 10 * time.Second
+
+func newTestProcessor(t *testing.T, sink *consumertest.TracesSink, numTraces uint64, policies ...PolicyCfg) (*tailSamplingSpanProcessor, *time.Time) {
+	cfg := Config{
+		ProcessorSettings: configmodels.ProcessorSettings{NameVal: typeStr, TypeVal: typeStr},
+		DecisionWait:      defaultTestDecisionWait,
+		NumTraces:         numTraces,
+		PolicyCfgs:        policies,
+	}
+	tp, err := newTraceProcessor(zap.NewNop(), sink, cfg)
+	require.NoError(t, err)
+	tsp := tp.(*tailSamplingSpanProcessor)
+	now := time.Unix(1000, 0)
+	tsp.now = func() time.Time { return now }
+	return tsp, &now
+}
+
+// generateTraces creates a batch with one span per given trace ID, all spans have the given string attribute.
+func generateTraces(attrValue string, ids ...pdata.TraceID) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().Attributes().InsertString("service.name", "test-service")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(ids))
+	for i, id := range ids {
+		span := spans.At(i)
+		span.SetTraceID(id)
+		span.SetName("span")
+		span.Attributes().InsertString("tenant", attrValue)
+	}
+	return td
+}
+
+func traceID(b byte) pdata.TraceID {
+	return pdata.NewTraceID([16]byte{b, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15})
+}
+
+var sampleTenantA = PolicyCfg{
+	sharedPolicyCfg: sharedPolicyCfg{
+		Name:               "tenant-a",
+		Type:               StringAttribute,
+		StringAttributeCfg: StringAttributeCfg{Key: "tenant", Values: []string{"a"}},
+	},
+}
+
+func TestTailSampling_DecisionAfterWait(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tsp, now := newTestProcessor(t, sink, 100, sampleTenantA)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("a", traceID(1))))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("b", traceID(1), traceID(2))))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("b", traceID(2))))
+
+	// Nothing is forwarded before the decision wait elapsed.
+	*now = now.Add(defaultTestDecisionWait - time.Second)
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 0, sink.SpansCount())
+
+	*now = now.Add(time.Second)
+	tsp.samplingPolicyOnTick()
+	// The whole first trace is sampled, including the span without the attribute.
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 2, sink.SpansCount())
+	rss := sink.AllTraces()[0].ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		spans := rss.At(i).InstrumentationLibrarySpans().At(0).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			assert.Equal(t, traceID(1), spans.At(j).TraceID())
+		}
+	}
+	assert.Empty(t, tsp.pending)
+}
+
+func TestTailSampling_LateArrivingSpans(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tsp, now := newTestProcessor(t, sink, 100, sampleTenantA)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("a", traceID(1))))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("b", traceID(2))))
+	*now = now.Add(defaultTestDecisionWait)
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 1, sink.SpansCount())
+
+	// Spans of the sampled trace are forwarded immediately, spans of the dropped trace are dropped.
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("b", traceID(1), traceID(2))))
+	assert.Equal(t, 2, sink.SpansCount())
+	require.Len(t, sink.AllTraces(), 2)
+	assert.Equal(t, traceID(1), sink.AllTraces()[1].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+}
+
+func TestTailSampling_EvictOldestTraces(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tsp, now := newTestProcessor(t, sink, 2, PolicyCfg{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}})
+
+	for i := byte(1); i <= 3; i++ {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("a", traceID(i))))
+	}
+	assert.Len(t, tsp.idToTrace, 2)
+	assert.NotContains(t, tsp.idToTrace, traceID(1))
+
+	*now = now.Add(defaultTestDecisionWait)
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 2, sink.SpansCount())
+
+	// A new span for the evicted trace starts a new decision wait.
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("a", traceID(1))))
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 2, sink.SpansCount())
+	*now = now.Add(defaultTestDecisionWait)
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 3, sink.SpansCount())
+}
+
+func TestTailSampling_AnyPolicySamples(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tsp, now := newTestProcessor(t, sink, 100,
+		sampleTenantA,
+		PolicyCfg{
+			sharedPolicyCfg: sharedPolicyCfg{
+				Name:               "tenant-c",
+				Type:               StringAttribute,
+				StringAttributeCfg: StringAttributeCfg{Key: "tenant", Values: []string{"c"}},
+			},
+		},
+	)
+
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("a", traceID(1))))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("b", traceID(2))))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("c", traceID(3))))
+	*now = now.Add(defaultTestDecisionWait)
+	tsp.samplingPolicyOnTick()
+	assert.Equal(t, 2, sink.SpansCount())
+}
+
+func TestTailSampling_InvalidPolicies(t *testing.T) {
+	cfg := Config{NumTraces: 10}
+	_, err := newTraceProcessor(zap.NewNop(), consumertest.NewTracesNop(), cfg)
+	assert.Error(t, err)
+
+	for _, policyCfg := range []PolicyCfg{
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "unknown", Type: "unknown"}},
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "status", Type: StatusCode, StatusCodeCfg: StatusCodeCfg{StatusCodes: []string{"FAILED"}}}},
+		{sharedPolicyCfg: sharedPolicyCfg{Name: "and", Type: And}},
+		{
+			sharedPolicyCfg: sharedPolicyCfg{Name: "and-with-invalid-sub-policy", Type: And},
+			AndCfg:          AndCfg{SubPolicyCfg: []AndSubPolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "invalid", Type: And}}}},
+		},
+	} {
+		cfg.PolicyCfgs = []PolicyCfg{policyCfg}
+		_, err = newTraceProcessor(zap.NewNop(), consumertest.NewTracesNop(), cfg)
+		assert.Error(t, err, policyCfg.Name)
+	}
+
+	cfg.NumTraces = 0
+	cfg.PolicyCfgs = []PolicyCfg{{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}}}
+	_, err = newTraceProcessor(zap.NewNop(), consumertest.NewTracesNop(), cfg)
+	assert.Error(t, err)
+}
+
+func TestTailSampling_StartAndShutdown(t *testing.T) {
+	sink := new(consumertest.TracesSink)
+	tsp, _ := newTestProcessor(t, sink, 100, PolicyCfg{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}})
+	tsp.decisionWait = 0
+	tsp.tickInterval = time.Millisecond
+	tsp.now = time.Now
+
+	require.NoError(t, tsp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tsp.ConsumeTraces(context.Background(), generateTraces("a", traceID(1))))
+	assert.Eventually(t, func() bool {
+		return sink.SpansCount() == 1
+	}, time.Second, time.Millisecond)
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+}
+
+func TestTailSampling_ShutdownWithoutStart(t *testing.T) {
+	tsp, _ := newTestProcessor(t, new(consumertest.TracesSink), 100, PolicyCfg{sharedPolicyCfg: sharedPolicyCfg{Name: "all", Type: AlwaysSample}})
+	require.NoError(t, tsp.Shutdown(context.Background()))
+	require.NoError(t, tsp.Shutdown(context.Background()))
+}
+
+func TestGroupSpansByTraceID(t *testing.T) {
+	td := generateTraces("a", traceID(1), traceID(2), traceID(1))
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).InstrumentationLibrary().InitEmpty()
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).InstrumentationLibrary().SetName("lib")
+
+	grouped := groupSpansByTraceID(td)
+	require.Len(t, grouped, 2)
+	assert.Equal(t, 2, grouped[traceID(1)].SpanCount())
+	assert.Equal(t, 1, grouped[traceID(2)].SpanCount())
+
+	rs := grouped[traceID(1)].ResourceSpans().At(0)
+	v, ok := rs.Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "test-service", v.StringVal())
+	assert.Equal(t, "lib", rs.InstrumentationLibrarySpans().At(0).InstrumentationLibrary().Name())
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Dimension is an additional label of the generated metrics, whose value is read from the
+// span attributes or, if the span does not have it, from the resource attributes.
+type Dimension struct {
+	// Name is the name of the attribute, also used as the name of the label.
+	Name string `mapstructure:"name"`
+	// Default is the value of the label for the spans without the attribute. The label is
+	// omitted for those spans if it is not set.
+	Default *string `mapstructure:"default"`
+}
+
+// Config defines the configuration for the span metrics processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MetricsExporter is the name of the exporter that receives the generated metrics. It
+	// must be used by a metrics pipeline.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// LatencyHistogramBuckets are the upper bounds of the buckets of the latency histogram.
+	// Buckets from 2ms to 15s are used if not set.
+	LatencyHistogramBuckets []time.Duration `mapstructure:"latency_histogram_buckets"`
+
+	// Dimensions are the labels added to the generated metrics in addition to the default
+	// ones: service.name, operation, span.kind and status.code.
+	Dimensions []Dimension `mapstructure:"dimensions"`
+
+	// FlushInterval is how often the metrics are sent to the exporter.
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// MaxSeries is the maximum number of series aggregated at the same time. The least
+	// recently updated series is forgotten when a new one exceeds the limit.
+	MaxSeries int `mapstructure:"max_series"`
+
+	// MaxStaleness is how long a series is kept after its last span. A series that is not
+	// updated for longer is no longer emitted, and starts over if it is updated again.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Processors[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "spanmetrics",
+			TypeVal: typeStr,
+		},
+		MetricsExporter: "exampleexporter/metrics",
+		FlushInterval:   defaultFlushInterval,
+		MaxSeries:       defaultMaxSeries,
+		MaxStaleness:    defaultMaxStaleness,
+	}, cfg.Processors["spanmetrics"])
+
+	defaultMethod := "GET"
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: "spanmetrics/custom",
+			TypeVal: typeStr,
+		},
+		MetricsExporter:         "exampleexporter/metrics",
+		LatencyHistogramBuckets: []time.Duration{10 * time.Millisecond, 100 * time.Millisecond, time.Second},
+		Dimensions: []Dimension{
+			{Name: "http.method", Default: &defaultMethod},
+			{Name: "http.status_code"},
+		},
+		FlushInterval: time.Minute,
+		MaxSeries:     500,
+		MaxStaleness:  10 * time.Minute,
+	}, cfg.Processors["spanmetrics/custom"])
+}
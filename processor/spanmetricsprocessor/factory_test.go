@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: typeStr,
+			TypeVal: typeStr,
+		},
+		FlushInterval: defaultFlushInterval,
+		MaxSeries:     defaultMaxSeries,
+		MaxStaleness:  defaultMaxStaleness,
+	}, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateTraceProcessor(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetricsExporter = "exampleexporter"
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	assert.NotNil(t, tp)
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
+	assert.Error(t, err)
+	assert.Nil(t, mp)
+}
+
+func TestCreateTraceProcessorInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "no_metrics_exporter",
+			modify: func(cfg *Config) { cfg.MetricsExporter = "" },
+			err:    errMissingMetricsExporter.Error(),
+		},
+		{
+			name:   "zero_flush_interval",
+			modify: func(cfg *Config) { cfg.FlushInterval = 0 },
+			err:    "flush_interval must be positive",
+		},
+		{
+			name:   "zero_max_series",
+			modify: func(cfg *Config) { cfg.MaxSeries = 0 },
+			err:    "max_series must be positive",
+		},
+		{
+			name:   "zero_max_staleness",
+			modify: func(cfg *Config) { cfg.MaxStaleness = 0 },
+			err:    "max_staleness must be positive",
+		},
+		{
+			name:   "negative_bucket",
+			modify: func(cfg *Config) { cfg.LatencyHistogramBuckets = []time.Duration{-time.Second} },
+			err:    "latency histogram buckets must be positive",
+		},
+		{
+			name:   "unnamed_dimension",
+			modify: func(cfg *Config) { cfg.Dimensions = []Dimension{{}} },
+			err:    "dimensions must have a name",
+		},
+		{
+			name:   "duplicate_dimension",
+			modify: func(cfg *Config) { cfg.Dimensions = []Dimension{{Name: "operation"}} },
+			err:    `duplicate dimension "operation"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			cfg.MetricsExporter = "exampleexporter"
+			test.modify(cfg)
+
+			_, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
@@ -0,0 +1,396 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	serviceNameLabel = conventions.AttributeServiceName
+	operationLabel   = "operation"
+	spanKindLabel    = "span.kind"
+	statusCodeLabel  = "status.code"
+
+	callsMetricName   = "calls_total"
+	latencyMetricName = "latency"
+)
+
+var errMissingMetricsExporter = errors.New("the metrics_exporter setting is required")
+
+// series holds the aggregated calls and latencies of the spans sharing the same labels.
+type series struct {
+	key          string
+	startTime    pdata.TimestampUnixNano
+	lastSeen     time.Time
+	labels       map[string]string
+	calls        int64
+	latencySum   float64
+	bucketCounts []uint64
+}
+
+type spanMetricsProcessor struct {
+	logger *zap.Logger
+	config *Config
+
+	// bounds are the upper bounds of the latency histogram buckets, in milliseconds.
+	bounds []float64
+	// now returns the current time, used to expire the series.
+	now func() time.Time
+
+	metricsExporter consumer.MetricsConsumer
+
+	// The series are kept from the most to the least recently updated in lru, to evict
+	// the least recently updated one when there are too many.
+	mu     sync.Mutex
+	lru    *list.List
+	series map[string]*list.Element
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newSpanMetricsProcessor(logger *zap.Logger, cfg *Config) (*spanMetricsProcessor, error) {
+	if cfg.MetricsExporter == "" {
+		return nil, errMissingMetricsExporter
+	}
+	if cfg.FlushInterval <= 0 {
+		return nil, fmt.Errorf("flush_interval must be positive, got %v", cfg.FlushInterval)
+	}
+	if cfg.MaxSeries <= 0 {
+		return nil, fmt.Errorf("max_series must be positive, got %v", cfg.MaxSeries)
+	}
+	if cfg.MaxStaleness <= 0 {
+		return nil, fmt.Errorf("max_staleness must be positive, got %v", cfg.MaxStaleness)
+	}
+
+	buckets := cfg.LatencyHistogramBuckets
+	if len(buckets) == 0 {
+		buckets = defaultLatencyHistogramBuckets
+	}
+	bounds := make([]float64, 0, len(buckets))
+	for _, bucket := range buckets {
+		if bucket <= 0 {
+			return nil, fmt.Errorf("latency histogram buckets must be positive, got %v", bucket)
+		}
+		bounds = append(bounds, float64(bucket)/float64(time.Millisecond))
+	}
+	sort.Float64s(bounds)
+
+	seen := map[string]bool{
+		serviceNameLabel: true,
+		operationLabel:   true,
+		spanKindLabel:    true,
+		statusCodeLabel:  true,
+	}
+	for _, dim := range cfg.Dimensions {
+		if dim.Name == "" {
+			return nil, errors.New("dimensions must have a name")
+		}
+		if seen[dim.Name] {
+			return nil, fmt.Errorf("duplicate dimension %q", dim.Name)
+		}
+		seen[dim.Name] = true
+	}
+
+	return &spanMetricsProcessor{
+		logger: logger,
+		config: cfg,
+		bounds: bounds,
+		now:    time.Now,
+		lru:    list.New(),
+		series: make(map[string]*list.Element),
+		done:   make(chan struct{}),
+	}, nil
+}
+
+func (p *spanMetricsProcessor) start(_ context.Context, host component.Host) error {
+	for cfg, exp := range host.GetExporters()[configmodels.MetricsDataType] {
+		if cfg.Name() != p.config.MetricsExporter {
+			continue
+		}
+		metricsExporter, ok := exp.(consumer.MetricsConsumer)
+		if !ok {
+			return fmt.Errorf("exporter %q does not accept metrics", p.config.MetricsExporter)
+		}
+		p.metricsExporter = metricsExporter
+	}
+	if p.metricsExporter == nil {
+		return fmt.Errorf("metrics exporter %q not found, it must be used by a metrics pipeline", p.config.MetricsExporter)
+	}
+
+	p.wg.Add(1)
+	go p.flushLoop()
+	return nil
+}
+
+func (p *spanMetricsProcessor) shutdown(ctx context.Context) error {
+	if p.metricsExporter == nil {
+		return nil
+	}
+	close(p.done)
+	p.wg.Wait()
+	return p.flush(ctx)
+}
+
+func (p *spanMetricsProcessor) flushLoop() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.flush(context.Background()); err != nil {
+				p.logger.Warn("Failed to export span metrics", zap.Error(err))
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// flush sends the current value of the aggregated metrics to the metrics exporter. Metrics
+// are cumulative, so the aggregation is kept across flushes.
+func (p *spanMetricsProcessor) flush(ctx context.Context) error {
+	md, ok := p.buildMetrics()
+	if !ok {
+		return nil
+	}
+	return p.metricsExporter.ConsumeMetrics(ctx, md)
+}
+
+// ProcessTraces aggregates the calls and latencies of the spans and passes the traces through
+// unmodified.
+func (p *spanMetricsProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		resourceAttrs := rs.Resource().Attributes()
+		serviceName := ""
+		if attr, ok := resourceAttrs.Get(conventions.AttributeServiceName); ok {
+			serviceName = tracetranslator.AttributeValueToString(attr, false)
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				p.aggregate(serviceName, span, resourceAttrs, now)
+			}
+		}
+	}
+	return td, nil
+}
+
+func (p *spanMetricsProcessor) aggregate(serviceName string, span pdata.Span, resourceAttrs pdata.AttributeMap, now time.Time) {
+	labels := p.buildLabels(serviceName, span, resourceAttrs)
+	s := p.getSeries(seriesKey(labels, p.config.Dimensions), labels, now)
+
+	latency := 0.0
+	if span.EndTime() > span.StartTime() {
+		latency = float64(span.EndTime()-span.StartTime()) / float64(time.Millisecond)
+	}
+	s.calls++
+	s.latencySum += latency
+	// A latency equal to a bound is counted in the bucket of that bound.
+	s.bucketCounts[sort.SearchFloat64s(p.bounds, latency)]++
+}
+
+// getSeries returns the series with the given key, creating it if it is new or stale. A new
+// series evicts the least recently updated one when there are more than max_series.
+func (p *spanMetricsProcessor) getSeries(key string, labels map[string]string, now time.Time) *series {
+	if elem, ok := p.series[key]; ok {
+		s := elem.Value.(*series)
+		if now.Sub(s.lastSeen) <= p.config.MaxStaleness {
+			s.lastSeen = now
+			p.lru.MoveToFront(elem)
+			return s
+		}
+		p.removeSeries(elem)
+	}
+
+	s := &series{
+		key:          key,
+		startTime:    pdata.TimestampUnixNano(now.UnixNano()),
+		lastSeen:     now,
+		labels:       labels,
+		bucketCounts: make([]uint64, len(p.bounds)+1),
+	}
+	p.series[key] = p.lru.PushFront(s)
+	for p.lru.Len() > p.config.MaxSeries {
+		p.removeSeries(p.lru.Back())
+	}
+	return s
+}
+
+// expireSeries removes the series that were not updated for longer than max_staleness.
+func (p *spanMetricsProcessor) expireSeries(now time.Time) {
+	for elem := p.lru.Back(); elem != nil; elem = p.lru.Back() {
+		if now.Sub(elem.Value.(*series).lastSeen) <= p.config.MaxStaleness {
+			return
+		}
+		p.removeSeries(elem)
+	}
+}
+
+func (p *spanMetricsProcessor) removeSeries(elem *list.Element) {
+	p.lru.Remove(elem)
+	delete(p.series, elem.Value.(*series).key)
+}
+
+func (p *spanMetricsProcessor) buildLabels(serviceName string, span pdata.Span, resourceAttrs pdata.AttributeMap) map[string]string {
+	statusCode := pdata.StatusCodeUnset
+	if status := span.Status(); !status.IsNil() {
+		statusCode = status.Code()
+	}
+	labels := map[string]string{
+		serviceNameLabel: serviceName,
+		operationLabel:   span.Name(),
+		spanKindLabel:    span.Kind().String(),
+		statusCodeLabel:  statusCode.String(),
+	}
+
+	spanAttrs := span.Attributes()
+	for _, dim := range p.config.Dimensions {
+		if attr, ok := spanAttrs.Get(dim.Name); ok {
+			labels[dim.Name] = tracetranslator.AttributeValueToString(attr, false)
+		} else if attr, ok := resourceAttrs.Get(dim.Name); ok {
+			labels[dim.Name] = tracetranslator.AttributeValueToString(attr, false)
+		} else if dim.Default != nil {
+			labels[dim.Name] = *dim.Default
+		}
+	}
+	return labels
+}
+
+// seriesKey returns a key that identifies the labels of a series. Dimensions omitted for
+// a span are encoded differently from dimensions set to an empty value.
+func seriesKey(labels map[string]string, dimensions []Dimension) string {
+	var sb strings.Builder
+	for _, name := range []string{serviceNameLabel, operationLabel, spanKindLabel, statusCodeLabel} {
+		sb.WriteString(labels[name])
+		sb.WriteByte(0)
+	}
+	for _, dim := range dimensions {
+		if value, ok := labels[dim.Name]; ok {
+			sb.WriteString(value)
+			sb.WriteByte(0)
+		} else {
+			sb.WriteByte(1)
+		}
+	}
+	return sb.String()
+}
+
+// buildMetrics returns the calls and latency metrics of all the series that are not stale,
+// or false if there are none.
+func (p *spanMetricsProcessor) buildMetrics() (pdata.Metrics, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.expireSeries(now)
+	if len(p.series) == 0 {
+		return pdata.Metrics{}, false
+	}
+
+	keys := make([]string, 0, len(p.series))
+	for key := range p.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	timestamp := pdata.TimestampUnixNano(now.UnixNano())
+	md := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	rms.Resize(1)
+	ilms := rms.At(0).InstrumentationLibraryMetrics()
+	ilms.Resize(1)
+	metrics := ilms.At(0).Metrics()
+	metrics.Resize(2)
+
+	calls := metrics.At(0)
+	calls.SetName(callsMetricName)
+	calls.SetDescription("Number of spans")
+	calls.SetUnit("1")
+	calls.SetDataType(pdata.MetricDataTypeIntSum)
+	sum := calls.IntSum()
+	sum.InitEmpty()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	callsPoints := sum.DataPoints()
+	callsPoints.Resize(len(keys))
+
+	latency := metrics.At(1)
+	latency.SetName(latencyMetricName)
+	latency.SetDescription("Duration of the spans")
+	latency.SetUnit("ms")
+	latency.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	histogram := latency.DoubleHistogram()
+	histogram.InitEmpty()
+	histogram.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	latencyPoints := histogram.DataPoints()
+	latencyPoints.Resize(len(keys))
+
+	for i, key := range keys {
+		s := p.series[key].Value.(*series)
+
+		cp := callsPoints.At(i)
+		cp.LabelsMap().InitFromMap(s.labels)
+		cp.SetStartTime(s.startTime)
+		cp.SetTimestamp(timestamp)
+		cp.SetValue(s.calls)
+
+		lp := latencyPoints.At(i)
+		lp.LabelsMap().InitFromMap(s.labels)
+		lp.SetStartTime(s.startTime)
+		lp.SetTimestamp(timestamp)
+		lp.SetCount(uint64(s.calls))
+		lp.SetSum(s.latencySum)
+		lp.SetBucketCounts(append([]uint64(nil), s.bucketCounts...))
+		lp.SetExplicitBounds(append([]float64(nil), p.bounds...))
+	}
+	return md, true
+}
@@ -0,0 +1,368 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spanmetricsprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+type mockHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+// sinkExporter is a metrics exporter that keeps the received metrics and can be read
+// while the processor flushes.
+type sinkExporter struct {
+	consumertest.MetricsSink
+}
+
+func (e *sinkExporter) Start(context.Context, component.Host) error { return nil }
+
+func (e *sinkExporter) Shutdown(context.Context) error { return nil }
+
+func newMockHost(metricsExporterName string) (*mockHost, *sinkExporter) {
+	exp := &sinkExporter{}
+	return &mockHost{
+		Host: componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+			configmodels.MetricsDataType: {
+				&componenttest.ExampleExporter{
+					ExporterSettings: configmodels.ExporterSettings{TypeVal: "exampleexporter", NameVal: metricsExporterName},
+				}: exp,
+			},
+		},
+	}, exp
+}
+
+type testSpan struct {
+	service  string
+	name     string
+	kind     pdata.SpanKind
+	status   pdata.StatusCode
+	latency  time.Duration
+	attrs    map[string]string
+	resource map[string]string
+}
+
+func newTestTraces(spans ...testSpan) pdata.Traces {
+	td := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	rss.Resize(len(spans))
+	start := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i, ts := range spans {
+		rs := rss.At(i)
+		rs.Resource().Attributes().InsertString(conventions.AttributeServiceName, ts.service)
+		for k, v := range ts.resource {
+			rs.Resource().Attributes().InsertString(k, v)
+		}
+		rs.InstrumentationLibrarySpans().Resize(1)
+		ils := rs.InstrumentationLibrarySpans().At(0)
+		ils.Spans().Resize(1)
+		span := ils.Spans().At(0)
+		span.SetName(ts.name)
+		span.SetKind(ts.kind)
+		span.SetStartTime(pdata.TimestampUnixNano(start.UnixNano()))
+		span.SetEndTime(pdata.TimestampUnixNano(start.Add(ts.latency).UnixNano()))
+		if ts.status != pdata.StatusCodeUnset {
+			span.Status().InitEmpty()
+			span.Status().SetCode(ts.status)
+		}
+		for k, v := range ts.attrs {
+			span.Attributes().InsertString(k, v)
+		}
+	}
+	return td
+}
+
+func newTestProcessor(t *testing.T, cfg *Config) *spanMetricsProcessor {
+	if cfg == nil {
+		cfg = NewFactory().CreateDefaultConfig().(*Config)
+	}
+	cfg.MetricsExporter = "metrics"
+	cfg.LatencyHistogramBuckets = []time.Duration{10 * time.Millisecond, 100 * time.Millisecond}
+	p, err := newSpanMetricsProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	return p
+}
+
+// dataPoints returns the calls and the latency data points of the metrics keyed by operation.
+func dataPoints(t *testing.T, md pdata.Metrics) (map[string]pdata.IntDataPoint, map[string]pdata.DoubleHistogramDataPoint) {
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	calls := metrics.At(0)
+	assert.Equal(t, callsMetricName, calls.Name())
+	assert.True(t, calls.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, calls.IntSum().AggregationTemporality())
+	callsPoints := make(map[string]pdata.IntDataPoint)
+	for i := 0; i < calls.IntSum().DataPoints().Len(); i++ {
+		dp := calls.IntSum().DataPoints().At(i)
+		op, _ := dp.LabelsMap().Get(operationLabel)
+		callsPoints[op] = dp
+	}
+
+	latency := metrics.At(1)
+	assert.Equal(t, latencyMetricName, latency.Name())
+	assert.Equal(t, "ms", latency.Unit())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, latency.DoubleHistogram().AggregationTemporality())
+	latencyPoints := make(map[string]pdata.DoubleHistogramDataPoint)
+	for i := 0; i < latency.DoubleHistogram().DataPoints().Len(); i++ {
+		dp := latency.DoubleHistogram().DataPoints().At(i)
+		op, _ := dp.LabelsMap().Get(operationLabel)
+		latencyPoints[op] = dp
+	}
+	return callsPoints, latencyPoints
+}
+
+func TestProcessTraces_Aggregates(t *testing.T) {
+	p := newTestProcessor(t, nil)
+	startTime := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return startTime }
+
+	td := newTestTraces(
+		testSpan{service: "frontend", name: "GET /", kind: pdata.SpanKindSERVER, latency: 5 * time.Millisecond},
+		testSpan{service: "frontend", name: "GET /", kind: pdata.SpanKindSERVER, latency: 10 * time.Millisecond},
+		testSpan{service: "frontend", name: "GET /", kind: pdata.SpanKindSERVER, latency: 500 * time.Millisecond},
+		testSpan{service: "frontend", name: "POST /", kind: pdata.SpanKindSERVER, status: pdata.StatusCodeError, latency: 50 * time.Millisecond},
+	)
+	got, err := p.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+
+	md, ok := p.buildMetrics()
+	require.True(t, ok)
+	calls, latency := dataPoints(t, md)
+	require.Len(t, calls, 2)
+	require.Len(t, latency, 2)
+
+	assert.Equal(t, int64(3), calls["GET /"].Value())
+	assert.Equal(t, map[string]string{
+		serviceNameLabel: "frontend",
+		operationLabel:   "GET /",
+		spanKindLabel:    pdata.SpanKindSERVER.String(),
+		statusCodeLabel:  pdata.StatusCodeUnset.String(),
+	}, labels(calls["GET /"].LabelsMap()))
+	assert.Equal(t, uint64(3), latency["GET /"].Count())
+	assert.Equal(t, 515.0, latency["GET /"].Sum())
+	assert.Equal(t, []float64{10, 100}, latency["GET /"].ExplicitBounds())
+	assert.Equal(t, []uint64{2, 0, 1}, latency["GET /"].BucketCounts())
+
+	assert.Equal(t, int64(1), calls["POST /"].Value())
+	status, _ := calls["POST /"].LabelsMap().Get(statusCodeLabel)
+	assert.Equal(t, pdata.StatusCodeError.String(), status)
+	assert.Equal(t, []uint64{0, 1, 0}, latency["POST /"].BucketCounts())
+
+	// Metrics are cumulative.
+	_, err = p.ProcessTraces(context.Background(), newTestTraces(
+		testSpan{service: "frontend", name: "GET /", kind: pdata.SpanKindSERVER, latency: time.Millisecond},
+	))
+	require.NoError(t, err)
+	md, ok = p.buildMetrics()
+	require.True(t, ok)
+	calls, latency = dataPoints(t, md)
+	assert.Equal(t, int64(4), calls["GET /"].Value())
+	assert.Equal(t, []uint64{3, 0, 1}, latency["GET /"].BucketCounts())
+	assert.Equal(t, pdata.TimestampUnixNano(startTime.UnixNano()), calls["GET /"].StartTime())
+}
+
+func TestProcessTraces_MaxSeries(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.MaxSeries = 2
+	p := newTestProcessor(t, cfg)
+
+	for _, name := range []string{"a", "b", "a", "c"} {
+		_, err := p.ProcessTraces(context.Background(), newTestTraces(testSpan{service: "svc", name: name}))
+		require.NoError(t, err)
+	}
+
+	// The least recently updated series is evicted.
+	md, ok := p.buildMetrics()
+	require.True(t, ok)
+	calls, _ := dataPoints(t, md)
+	require.Len(t, calls, 2)
+	assert.Equal(t, int64(2), calls["a"].Value())
+	assert.Equal(t, int64(1), calls["c"].Value())
+}
+
+func TestProcessTraces_MaxStaleness(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.MaxStaleness = time.Minute
+	p := newTestProcessor(t, cfg)
+	now := time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)
+	p.now = func() time.Time { return now }
+
+	_, err := p.ProcessTraces(context.Background(), newTestTraces(
+		testSpan{service: "svc", name: "a"},
+		testSpan{service: "svc", name: "b"},
+	))
+	require.NoError(t, err)
+
+	now = now.Add(40 * time.Second)
+	_, err = p.ProcessTraces(context.Background(), newTestTraces(testSpan{service: "svc", name: "a"}))
+	require.NoError(t, err)
+
+	// The stale series is no longer emitted.
+	now = now.Add(40 * time.Second)
+	md, ok := p.buildMetrics()
+	require.True(t, ok)
+	calls, _ := dataPoints(t, md)
+	require.Len(t, calls, 1)
+	assert.Equal(t, int64(2), calls["a"].Value())
+
+	// A series updated after it became stale starts over.
+	now = now.Add(2 * time.Minute)
+	_, err = p.ProcessTraces(context.Background(), newTestTraces(testSpan{service: "svc", name: "a"}))
+	require.NoError(t, err)
+	md, ok = p.buildMetrics()
+	require.True(t, ok)
+	calls, _ = dataPoints(t, md)
+	assert.Equal(t, int64(1), calls["a"].Value())
+	assert.Equal(t, pdata.TimestampUnixNano(now.UnixNano()), calls["a"].StartTime())
+
+	now = now.Add(2 * time.Minute)
+	_, ok = p.buildMetrics()
+	assert.False(t, ok)
+}
+
+func TestBuildMetrics_BoundsNotShared(t *testing.T) {
+	p := newTestProcessor(t, nil)
+	_, err := p.ProcessTraces(context.Background(), newTestTraces(
+		testSpan{service: "svc", name: "a"},
+		testSpan{service: "svc", name: "b"},
+	))
+	require.NoError(t, err)
+
+	md, ok := p.buildMetrics()
+	require.True(t, ok)
+	_, latency := dataPoints(t, md)
+	latency["a"].ExplicitBounds()[0] = 42
+	assert.Equal(t, []float64{10, 100}, latency["b"].ExplicitBounds())
+	assert.Equal(t, []float64{10, 100}, p.bounds)
+}
+
+func TestProcessTraces_Dimensions(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	defaultMethod := "GET"
+	cfg.Dimensions = []Dimension{
+		{Name: "http.method", Default: &defaultMethod},
+		{Name: "region"},
+	}
+	p := newTestProcessor(t, cfg)
+
+	_, err := p.ProcessTraces(context.Background(), newTestTraces(
+		testSpan{service: "svc", name: "span", attrs: map[string]string{"http.method": "POST"}, resource: map[string]string{"region": "eu"}},
+		testSpan{service: "svc", name: "span", attrs: map[string]string{"region": "us"}, resource: map[string]string{"region": "eu"}},
+		testSpan{service: "svc", name: "span"},
+		testSpan{service: "svc", name: "span", attrs: map[string]string{"region": ""}},
+	))
+	require.NoError(t, err)
+
+	md, ok := p.buildMetrics()
+	require.True(t, ok)
+	dps := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints()
+	var got []map[string]string
+	for i := 0; i < dps.Len(); i++ {
+		l := labels(dps.At(i).LabelsMap())
+		delete(l, serviceNameLabel)
+		delete(l, operationLabel)
+		delete(l, spanKindLabel)
+		delete(l, statusCodeLabel)
+		got = append(got, l)
+	}
+	assert.ElementsMatch(t, []map[string]string{
+		{"http.method": "POST", "region": "eu"},
+		{"http.method": "GET", "region": "us"},
+		{"http.method": "GET"},
+		{"http.method": "GET", "region": ""},
+	}, got)
+}
+
+func TestStartAndShutdown(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.MetricsExporter = "metrics"
+	cfg.FlushInterval = time.Hour
+	tp, err := factory.CreateTracesProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+
+	host, exp := newMockHost("metrics")
+	require.NoError(t, tp.Start(context.Background(), host))
+	require.NoError(t, tp.ConsumeTraces(context.Background(), newTestTraces(
+		testSpan{service: "svc", name: "span", latency: time.Millisecond},
+	)))
+	assert.Empty(t, exp.AllMetrics())
+
+	// Pending metrics are flushed on shutdown.
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.Len(t, exp.AllMetrics(), 1)
+	calls, _ := dataPoints(t, exp.AllMetrics()[0])
+	assert.Equal(t, int64(1), calls["span"].Value())
+}
+
+func TestPeriodicFlush(t *testing.T) {
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	cfg.FlushInterval = 10 * time.Millisecond
+	p := newTestProcessor(t, cfg)
+
+	host, exp := newMockHost("metrics")
+	require.NoError(t, p.start(context.Background(), host))
+	_, err := p.ProcessTraces(context.Background(), newTestTraces(testSpan{service: "svc", name: "span"}))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(exp.AllMetrics()) > 0
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, p.shutdown(context.Background()))
+}
+
+func TestStart_MissingExporter(t *testing.T) {
+	p := newTestProcessor(t, nil)
+	host, _ := newMockHost("other")
+	err := p.start(context.Background(), host)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `metrics exporter "metrics" not found`)
+	assert.NoError(t, p.shutdown(context.Background()))
+}
+
+func TestBuildMetrics_NoSpans(t *testing.T) {
+	p := newTestProcessor(t, nil)
+	_, ok := p.buildMetrics()
+	assert.False(t, ok)
+}
+
+func labels(sm pdata.StringMap) map[string]string {
+	m := make(map[string]string)
+	sm.ForEach(func(k, v string) { m[k] = v })
+	return m
+}
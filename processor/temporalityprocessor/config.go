@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+// Conversion is the direction of the conversion between aggregation temporalities.
+type Conversion string
+
+const (
+	// CumulativeToDelta converts the cumulative sums and histograms to deltas.
+	CumulativeToDelta Conversion = "cumulative_to_delta"
+	// DeltaToCumulative converts the delta sums and histograms to cumulatives.
+	DeltaToCumulative Conversion = "delta_to_cumulative"
+)
+
+// MatchMetrics selects the metrics to convert by name.
+type MatchMetrics struct {
+	filterset.Config `mapstructure:",squash"`
+
+	// MetricNames is the list of names, or of regexp patterns with match_type regexp, of
+	// the metrics to convert.
+	MetricNames []string `mapstructure:"metric_names"`
+}
+
+// Config defines the configuration for the temporality processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Conversion is the direction of the conversion, either cumulative_to_delta or
+	// delta_to_cumulative.
+	Conversion Conversion `mapstructure:"conversion"`
+
+	// Include restricts the conversion to the matching metrics. All the sums and histograms
+	// are converted if not set.
+	Include *MatchMetrics `mapstructure:"include"`
+
+	// MaxStaleness is how long the state of a series is kept after its last data point.
+	// A series that is not updated for longer starts over.
+	MaxStaleness time.Duration `mapstructure:"max_staleness"`
+
+	// MaxStreams is the maximum number of series tracked at the same time. The least
+	// recently updated series is forgotten when a new one exceeds the limit.
+	MaxStreams int `mapstructure:"max_streams"`
+}
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "temporality"
+
+	defaultMaxStaleness = 5 * time.Minute
+	defaultMaxStreams   = 10000
+)
+
+var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: true}
+
+// NewFactory returns a new factory for the Temporality processor.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithMetrics(createMetricsProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		Conversion:   CumulativeToDelta,
+		MaxStaleness: defaultMaxStaleness,
+		MaxStreams:   defaultMaxStreams,
+	}
+}
+
+func createMetricsProcessor(
+	_ context.Context,
+	_ component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsProcessor, error) {
+	oCfg := cfg.(*Config)
+	if err := validateConfig(oCfg); err != nil {
+		return nil, fmt.Errorf("error creating %q processor: %w", cfg.Name(), err)
+	}
+	tp, err := newTemporalityProcessor(oCfg)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewMetricsProcessor(
+		cfg,
+		nextConsumer,
+		tp,
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func validateConfig(cfg *Config) error {
+	switch cfg.Conversion {
+	case CumulativeToDelta, DeltaToCumulative:
+	default:
+		return fmt.Errorf("unsupported conversion %q, must be %q or %q", cfg.Conversion, CumulativeToDelta, DeltaToCumulative)
+	}
+	if cfg.MaxStaleness <= 0 {
+		return errors.New("max_staleness must be positive")
+	}
+	if cfg.MaxStreams <= 0 {
+		return errors.New("max_streams must be positive")
+	}
+	return nil
+}
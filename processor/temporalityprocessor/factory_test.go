@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			NameVal: typeStr,
+			TypeVal: typeStr,
+		},
+		Conversion:   CumulativeToDelta,
+		MaxStaleness: 5 * time.Minute,
+		MaxStreams:   10000,
+	}, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateProcessor(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tp, err := factory.CreateTracesProcessor(context.Background(), params, cfg, consumertest.NewTracesNop())
+	assert.Error(t, err)
+	assert.Nil(t, tp)
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, mp)
+}
+
+func TestCreateProcessor_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		err    string
+	}{
+		{
+			name:   "invalid_conversion",
+			modify: func(cfg *Config) { cfg.Conversion = "invalid" },
+			err:    `error creating "temporality" processor: unsupported conversion "invalid", must be "cumulative_to_delta" or "delta_to_cumulative"`,
+		},
+		{
+			name:   "invalid_max_staleness",
+			modify: func(cfg *Config) { cfg.MaxStaleness = 0 },
+			err:    `error creating "temporality" processor: max_staleness must be positive`,
+		},
+		{
+			name:   "invalid_max_streams",
+			modify: func(cfg *Config) { cfg.MaxStreams = -1 },
+			err:    `error creating "temporality" processor: max_streams must be positive`,
+		},
+		{
+			name: "invalid_include",
+			modify: func(cfg *Config) {
+				cfg.Include = &MatchMetrics{MetricNames: []string{"name"}}
+			},
+			err: "error creating metric name filters: unrecognized match_type: '', valid types are: [regexp strict]",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			test.modify(cfg)
+			mp, err := factory.CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, consumertest.NewMetricsNop())
+			assert.Nil(t, mp)
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+type temporalityProcessor struct {
+	include filterset.FilterSet
+	// from and to are the temporalities converted from and to.
+	from pdata.AggregationTemporality
+	to   pdata.AggregationTemporality
+
+	// now returns the current time, used to expire the series.
+	now func() time.Time
+
+	mu      sync.Mutex
+	tracker *tracker
+}
+
+func newTemporalityProcessor(cfg *Config) (*temporalityProcessor, error) {
+	tp := &temporalityProcessor{
+		from:    pdata.AggregationTemporalityCumulative,
+		to:      pdata.AggregationTemporalityDelta,
+		now:     time.Now,
+		tracker: newTracker(cfg.MaxStreams, cfg.MaxStaleness),
+	}
+	if cfg.Conversion == DeltaToCumulative {
+		tp.from, tp.to = tp.to, tp.from
+	}
+	if cfg.Include != nil && len(cfg.Include.MetricNames) > 0 {
+		include, err := filterset.CreateFilterSet(cfg.Include.MetricNames, &cfg.Include.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating metric name filters: %v", err)
+		}
+		tp.include = include
+	}
+	return tp, nil
+}
+
+// ProcessMetrics implements the MProcessor interface.
+func (tp *temporalityProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	now := tp.now()
+	tp.tracker.expire(now)
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		resourceKey := attributesKey(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			library := ilm.InstrumentationLibrary()
+			libraryKey := resourceKey
+			if !library.IsNil() {
+				libraryKey += "\x00" + library.Name() + "\x00" + library.Version()
+			}
+
+			metrics := ilm.Metrics()
+			kept := pdata.NewMetricSlice()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() || (tp.include != nil && !tp.include.Matches(metric.Name())) ||
+					tp.convertMetric(libraryKey+"\x00"+metric.Name(), metric, now) {
+					kept.Append(metric)
+				}
+			}
+			if kept.Len() != metrics.Len() {
+				metrics.Resize(0)
+				kept.MoveAndAppendTo(metrics)
+			}
+		}
+	}
+	return md, nil
+}
+
+// convertMetric converts the data points of the metric if it is a sum or a histogram with the
+// temporality to convert from. False is returned if all the data points of the metric were
+// dropped.
+func (tp *temporalityProcessor) convertMetric(metricKey string, metric pdata.Metric, now time.Time) bool {
+	metricKey += "\x00" + metric.DataType().String() + "\x00"
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		sum := metric.IntSum()
+		if sum.IsNil() || sum.AggregationTemporality() != tp.from {
+			return true
+		}
+		sum.SetAggregationTemporality(tp.to)
+		return tp.convertIntPoints(metricKey, sum.DataPoints(), sum.IsMonotonic(), now)
+	case pdata.MetricDataTypeDoubleSum:
+		sum := metric.DoubleSum()
+		if sum.IsNil() || sum.AggregationTemporality() != tp.from {
+			return true
+		}
+		sum.SetAggregationTemporality(tp.to)
+		return tp.convertDoublePoints(metricKey, sum.DataPoints(), sum.IsMonotonic(), now)
+	case pdata.MetricDataTypeIntHistogram:
+		histogram := metric.IntHistogram()
+		if histogram.IsNil() || histogram.AggregationTemporality() != tp.from {
+			return true
+		}
+		histogram.SetAggregationTemporality(tp.to)
+		return tp.convertIntHistogramPoints(metricKey, histogram.DataPoints(), now)
+	case pdata.MetricDataTypeDoubleHistogram:
+		histogram := metric.DoubleHistogram()
+		if histogram.IsNil() || histogram.AggregationTemporality() != tp.from {
+			return true
+		}
+		histogram.SetAggregationTemporality(tp.to)
+		return tp.convertDoubleHistogramPoints(metricKey, histogram.DataPoints(), now)
+	}
+	return true
+}
+
+// convert converts a point of the series with the given key. False is returned if the point
+// must be dropped.
+func (tp *temporalityProcessor) convert(key string, p point, monotonic bool, now time.Time) (point, bool) {
+	s, isNew := tp.tracker.get(key, now)
+	if tp.to == pdata.AggregationTemporalityDelta {
+		return s.cumulativeToDelta(p, isNew, monotonic)
+	}
+	return s.deltaToCumulative(p, isNew)
+}
+
+func (tp *temporalityProcessor) convertIntPoints(metricKey string, dps pdata.IntDataPointSlice, monotonic bool, now time.Time) bool {
+	if dps.Len() == 0 {
+		return true
+	}
+	kept := pdata.NewIntDataPointSlice()
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		p := point{start: dp.StartTime(), timestamp: dp.Timestamp(), intValue: dp.Value()}
+		converted, ok := tp.convert(metricKey+labelsKey(dp.LabelsMap()), p, monotonic, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(converted.start)
+		dp.SetValue(converted.intValue)
+		kept.Append(dp)
+	}
+	dps.Resize(0)
+	kept.MoveAndAppendTo(dps)
+	return dps.Len() > 0
+}
+
+func (tp *temporalityProcessor) convertDoublePoints(metricKey string, dps pdata.DoubleDataPointSlice, monotonic bool, now time.Time) bool {
+	if dps.Len() == 0 {
+		return true
+	}
+	kept := pdata.NewDoubleDataPointSlice()
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		p := point{start: dp.StartTime(), timestamp: dp.Timestamp(), doubleValue: dp.Value()}
+		converted, ok := tp.convert(metricKey+labelsKey(dp.LabelsMap()), p, monotonic, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(converted.start)
+		dp.SetValue(converted.doubleValue)
+		kept.Append(dp)
+	}
+	dps.Resize(0)
+	kept.MoveAndAppendTo(dps)
+	return dps.Len() > 0
+}
+
+func (tp *temporalityProcessor) convertIntHistogramPoints(metricKey string, dps pdata.IntHistogramDataPointSlice, now time.Time) bool {
+	if dps.Len() == 0 {
+		return true
+	}
+	kept := pdata.NewIntHistogramDataPointSlice()
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		p := point{
+			start:     dp.StartTime(),
+			timestamp: dp.Timestamp(),
+			intValue:  dp.Sum(),
+			histogram: true,
+			count:     dp.Count(),
+			bounds:    dp.ExplicitBounds(),
+			buckets:   dp.BucketCounts(),
+		}
+		converted, ok := tp.convert(metricKey+labelsKey(dp.LabelsMap()), p, true, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(converted.start)
+		dp.SetSum(converted.intValue)
+		dp.SetCount(converted.count)
+		dp.SetBucketCounts(converted.buckets)
+		kept.Append(dp)
+	}
+	dps.Resize(0)
+	kept.MoveAndAppendTo(dps)
+	return dps.Len() > 0
+}
+
+func (tp *temporalityProcessor) convertDoubleHistogramPoints(metricKey string, dps pdata.DoubleHistogramDataPointSlice, now time.Time) bool {
+	if dps.Len() == 0 {
+		return true
+	}
+	kept := pdata.NewDoubleHistogramDataPointSlice()
+	for i := 0; i < dps.Len(); i++ {
+		dp := dps.At(i)
+		if dp.IsNil() {
+			continue
+		}
+		p := point{
+			start:       dp.StartTime(),
+			timestamp:   dp.Timestamp(),
+			doubleValue: dp.Sum(),
+			histogram:   true,
+			count:       dp.Count(),
+			bounds:      dp.ExplicitBounds(),
+			buckets:     dp.BucketCounts(),
+		}
+		converted, ok := tp.convert(metricKey+labelsKey(dp.LabelsMap()), p, true, now)
+		if !ok {
+			continue
+		}
+		dp.SetStartTime(converted.start)
+		dp.SetSum(converted.doubleValue)
+		dp.SetCount(converted.count)
+		dp.SetBucketCounts(converted.buckets)
+		kept.Append(dp)
+	}
+	dps.Resize(0)
+	kept.MoveAndAppendTo(dps)
+	return dps.Len() > 0
+}
+
+// attributesKey returns a string uniquely identifying the attributes of a resource.
+func attributesKey(resource pdata.Resource) string {
+	pairs := make([]string, 0, resource.Attributes().Len())
+	resource.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		pairs = append(pairs, k+"="+tracetranslator.AttributeValueToString(v, false))
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
+
+// labelsKey returns a string uniquely identifying the labels of a data point.
+func labelsKey(labels pdata.StringMap) string {
+	pairs := make([]string, 0, labels.Len())
+	labels.ForEach(func(k string, v string) {
+		pairs = append(pairs, k+"="+v)
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
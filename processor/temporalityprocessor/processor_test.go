@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+	"go.opentelemetry.io/collector/internal/processor/filterset"
+)
+
+func newTestProcessor(t *testing.T, cfg *Config) *temporalityProcessor {
+	if cfg.MaxStaleness == 0 {
+		cfg.MaxStaleness = time.Minute
+	}
+	if cfg.MaxStreams == 0 {
+		cfg.MaxStreams = 100
+	}
+	tp, err := newTemporalityProcessor(cfg)
+	require.NoError(t, err)
+	return tp
+}
+
+func newMetrics(metrics ...pdata.Metric) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "svc")
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	for _, metric := range metrics {
+		rm.InstrumentationLibraryMetrics().At(0).Metrics().Append(metric)
+	}
+	return md
+}
+
+func newIntSum(name string, temporality pdata.AggregationTemporality, start, timestamp pdata.TimestampUnixNano, values map[string]int64) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(name)
+	metric.SetDataType(pdata.MetricDataTypeIntSum)
+	metric.IntSum().InitEmpty()
+	metric.IntSum().SetAggregationTemporality(temporality)
+	metric.IntSum().SetIsMonotonic(true)
+	for host, value := range values {
+		dp := pdata.NewIntDataPoint()
+		dp.InitEmpty()
+		dp.LabelsMap().Insert("host", host)
+		dp.SetStartTime(start)
+		dp.SetTimestamp(timestamp)
+		dp.SetValue(value)
+		metric.IntSum().DataPoints().Append(dp)
+	}
+	return metric
+}
+
+func newDoubleHistogram(name string, temporality pdata.AggregationTemporality, start, timestamp pdata.TimestampUnixNano, count uint64, sum float64, buckets []uint64) pdata.Metric {
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(name)
+	metric.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	metric.DoubleHistogram().InitEmpty()
+	metric.DoubleHistogram().SetAggregationTemporality(temporality)
+	dp := pdata.NewDoubleHistogramDataPoint()
+	dp.InitEmpty()
+	dp.SetStartTime(start)
+	dp.SetTimestamp(timestamp)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetExplicitBounds([]float64{10})
+	dp.SetBucketCounts(buckets)
+	metric.DoubleHistogram().DataPoints().Append(dp)
+	return metric
+}
+
+func TestTemporalityProcessor_CumulativeToDelta(t *testing.T) {
+	tp := newTestProcessor(t, &Config{Conversion: CumulativeToDelta})
+
+	md, err := tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityCumulative, 1, 10, map[string]int64{"a": 5}),
+		newIntSum("already_delta", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	))
+	require.NoError(t, err)
+	// The first cumulative point of a series cannot be converted: the metric is dropped.
+	assert.Equal(t, newMetrics(
+		newIntSum("already_delta", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	), md)
+
+	md, err = tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityCumulative, 1, 20, map[string]int64{"a": 8}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 10, 20, map[string]int64{"a": 3}),
+	), md)
+
+	md, err = tp.ProcessMetrics(context.Background(), newMetrics(
+		newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, 1, 10, 2, 15, []uint64{1, 1}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().Len())
+
+	md, err = tp.ProcessMetrics(context.Background(), newMetrics(
+		newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, 1, 20, 5, 40, []uint64{2, 3}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics(
+		newDoubleHistogram("latency", pdata.AggregationTemporalityDelta, 10, 20, 3, 25, []uint64{1, 2}),
+	), md)
+}
+
+func TestTemporalityProcessor_DeltaToCumulative(t *testing.T) {
+	tp := newTestProcessor(t, &Config{Conversion: DeltaToCumulative})
+
+	md, err := tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityCumulative, 1, 10, map[string]int64{"a": 5}),
+	), md)
+
+	md, err = tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 10, 20, map[string]int64{"a": 3}),
+		newDoubleHistogram("latency", pdata.AggregationTemporalityDelta, 10, 20, 2, 15, []uint64{1, 1}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityCumulative, 1, 20, map[string]int64{"a": 8}),
+		newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, 10, 20, 2, 15, []uint64{1, 1}),
+	), md)
+
+	md, err = tp.ProcessMetrics(context.Background(), newMetrics(
+		newDoubleHistogram("latency", pdata.AggregationTemporalityDelta, 20, 30, 3, 25, []uint64{1, 2}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics(
+		newDoubleHistogram("latency", pdata.AggregationTemporalityCumulative, 10, 30, 5, 40, []uint64{2, 3}),
+	), md)
+}
+
+func TestTemporalityProcessor_SeriesIdentity(t *testing.T) {
+	tp := newTestProcessor(t, &Config{Conversion: DeltaToCumulative})
+
+	_, err := tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	))
+	require.NoError(t, err)
+
+	// Another resource is another series.
+	md := newMetrics(newIntSum("requests", pdata.AggregationTemporalityDelta, 10, 20, map[string]int64{"a": 3}))
+	md.ResourceMetrics().At(0).Resource().Attributes().UpsertString("service.name", "other")
+	md, err = tp.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+	dp := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints().At(0)
+	assert.Equal(t, int64(3), dp.Value())
+
+	// Another label value is another series.
+	md, err = tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 10, 20, map[string]int64{"b": 4}),
+	))
+	require.NoError(t, err)
+	dp = md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints().At(0)
+	assert.Equal(t, int64(4), dp.Value())
+	assert.Equal(t, 3, tp.tracker.len())
+}
+
+func TestTemporalityProcessor_MaxStaleness(t *testing.T) {
+	tp := newTestProcessor(t, &Config{Conversion: DeltaToCumulative, MaxStaleness: time.Minute})
+	now := time.Unix(1000, 0)
+	tp.now = func() time.Time { return now }
+
+	_, err := tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, 1, tp.tracker.len())
+
+	now = now.Add(2 * time.Minute)
+	md, err := tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("other", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 1}),
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 10, 20, map[string]int64{"a": 3}),
+	))
+	require.NoError(t, err)
+	// The stale series started over.
+	assert.Equal(t, newMetrics(
+		newIntSum("other", pdata.AggregationTemporalityCumulative, 1, 10, map[string]int64{"a": 1}),
+		newIntSum("requests", pdata.AggregationTemporalityCumulative, 10, 20, map[string]int64{"a": 3}),
+	), md)
+	assert.Equal(t, 2, tp.tracker.len())
+}
+
+func TestTemporalityProcessor_Include(t *testing.T) {
+	tp := newTestProcessor(t, &Config{
+		Conversion: DeltaToCumulative,
+		Include: &MatchMetrics{
+			Config:      filterset.Config{MatchType: filterset.Strict},
+			MetricNames: []string{"requests"},
+		},
+	})
+
+	md, err := tp.ProcessMetrics(context.Background(), newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+		newIntSum("other", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	))
+	require.NoError(t, err)
+	assert.Equal(t, newMetrics(
+		newIntSum("requests", pdata.AggregationTemporalityCumulative, 1, 10, map[string]int64{"a": 5}),
+		newIntSum("other", pdata.AggregationTemporalityDelta, 1, 10, map[string]int64{"a": 5}),
+	), md)
+}
+
+func TestTemporalityProcessor_NilEmptyData(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  pdata.Metrics
+		output pdata.Metrics
+	}{
+		{
+			name:   "empty",
+			input:  testdata.GenerateMetricsEmpty(),
+			output: testdata.GenerateMetricsEmpty(),
+		},
+		{
+			name:   "one-empty-one-nil-resource-metrics",
+			input:  testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+			output: testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+		},
+		{
+			name:   "one-empty-one-nil-instrumentation-library",
+			input:  testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+			output: testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+		},
+		{
+			name:   "all-types-no-data-points",
+			input:  testdata.GenerateMetricsAllTypesNoDataPoints(),
+			output: testdata.GenerateMetricsAllTypesNoDataPoints(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The test data are cumulative, and are left unchanged.
+			tp := newTestProcessor(t, &Config{Conversion: DeltaToCumulative})
+			md, err := tp.ProcessMetrics(context.Background(), test.input)
+			require.NoError(t, err)
+			assert.Equal(t, test.output, md)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"container/list"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// point holds the values of a sum or histogram data point that are converted.
+type point struct {
+	start     pdata.TimestampUnixNano
+	timestamp pdata.TimestampUnixNano
+
+	// intValue is the value of an int sum or the sum of an int histogram.
+	intValue int64
+	// doubleValue is the value of a double sum or the sum of a double histogram.
+	doubleValue float64
+
+	histogram bool
+	count     uint64
+	bounds    []float64
+	buckets   []uint64
+}
+
+// sub returns the difference between the point and an older point of the same series.
+func (p point) sub(older point) point {
+	diff := p
+	diff.intValue -= older.intValue
+	diff.doubleValue -= older.doubleValue
+	diff.count -= older.count
+	if p.histogram {
+		diff.buckets = make([]uint64, len(p.buckets))
+		for i := range p.buckets {
+			diff.buckets[i] = p.buckets[i] - older.buckets[i]
+		}
+	}
+	return diff
+}
+
+// add returns the sum of the point and a newer point of the same series.
+func (p point) add(newer point) point {
+	sum := newer
+	sum.intValue += p.intValue
+	sum.doubleValue += p.doubleValue
+	sum.count += p.count
+	if newer.histogram {
+		sum.buckets = make([]uint64, len(newer.buckets))
+		for i := range newer.buckets {
+			sum.buckets[i] = p.buckets[i] + newer.buckets[i]
+		}
+	}
+	return sum
+}
+
+// compatible returns true if the values of the points can be added or subtracted.
+func (p point) compatible(other point) bool {
+	if len(p.bounds) != len(other.bounds) || len(p.buckets) != len(other.buckets) {
+		return false
+	}
+	for i := range p.bounds {
+		if p.bounds[i] != other.bounds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// decreased returns true if a monotonic value of the point is lower than the one of an older
+// point of the same series, which means that the cumulative value was reset.
+func (p point) decreased(older point, monotonic bool) bool {
+	if p.histogram {
+		if p.count < older.count {
+			return true
+		}
+		for i := range p.buckets {
+			if p.buckets[i] < older.buckets[i] {
+				return true
+			}
+		}
+		return false
+	}
+	return monotonic && (p.intValue < older.intValue || p.doubleValue < older.doubleValue)
+}
+
+// series is the state of a series, identified by its resource, metric and labels.
+type series struct {
+	key      string
+	lastSeen time.Time
+	// last is the last point received for the series.
+	last point
+	// cumulative is the running total of a series converted to cumulative.
+	cumulative point
+}
+
+// cumulativeToDelta converts a cumulative point of the series to a delta. False is returned
+// if the point cannot be converted and must be dropped.
+func (s *series) cumulativeToDelta(p point, isNew bool, monotonic bool) (point, bool) {
+	if isNew {
+		// The value accumulated before the first point is unknown.
+		s.last = p
+		return point{}, false
+	}
+	if p.timestamp <= s.last.timestamp {
+		// Duplicate or out of order point.
+		return point{}, false
+	}
+
+	last := s.last
+	s.last = p
+	switch {
+	case p.start != 0 && p.start != last.start:
+		// The series restarted at a known time: the value accumulated since the new start is
+		// the delta, unless the restart overlaps with the previous point.
+		if p.start < last.timestamp {
+			return point{}, false
+		}
+		return p, true
+	case !p.compatible(last) || p.decreased(last, monotonic):
+		// The series restarted at an unknown time.
+		return point{}, false
+	}
+	delta := p.sub(last)
+	delta.start = last.timestamp
+	return delta, true
+}
+
+// deltaToCumulative converts a delta point of the series to a cumulative. False is returned
+// if the point cannot be converted and must be dropped.
+func (s *series) deltaToCumulative(p point, isNew bool) (point, bool) {
+	if !isNew {
+		if p.timestamp <= s.last.timestamp || (p.start != 0 && p.start < s.last.timestamp) {
+			// Duplicate, out of order or overlapping point.
+			return point{}, false
+		}
+	}
+
+	switch {
+	case isNew:
+		s.cumulative = p
+	case p.start != 0 && p.start > s.last.timestamp, !p.compatible(s.cumulative):
+		// Some deltas are missing, or the buckets changed: the series starts over so that
+		// the cumulative value is not wrong.
+		s.cumulative = p
+		if s.cumulative.start == 0 {
+			s.cumulative.start = s.last.timestamp
+		}
+	default:
+		start := s.cumulative.start
+		s.cumulative = s.cumulative.add(p)
+		s.cumulative.start = start
+	}
+	s.last = p
+	return s.cumulative, true
+}
+
+// tracker keeps the state of the series, evicting the least recently updated one when there
+// are too many and the ones that were not updated for too long.
+type tracker struct {
+	maxStreams   int
+	maxStaleness time.Duration
+	lru          *list.List
+	series       map[string]*list.Element
+}
+
+func newTracker(maxStreams int, maxStaleness time.Duration) *tracker {
+	return &tracker{
+		maxStreams:   maxStreams,
+		maxStaleness: maxStaleness,
+		lru:          list.New(),
+		series:       make(map[string]*list.Element),
+	}
+}
+
+// get returns the state of the series with the given key, creating it if it is new or
+// stale. True is returned for a new series.
+func (t *tracker) get(key string, now time.Time) (*series, bool) {
+	if elem, ok := t.series[key]; ok {
+		s := elem.Value.(*series)
+		if now.Sub(s.lastSeen) <= t.maxStaleness {
+			s.lastSeen = now
+			t.lru.MoveToFront(elem)
+			return s, false
+		}
+		t.remove(elem)
+	}
+
+	s := &series{key: key, lastSeen: now}
+	t.series[key] = t.lru.PushFront(s)
+	for t.lru.Len() > t.maxStreams {
+		t.remove(t.lru.Back())
+	}
+	return s, true
+}
+
+// expire removes the series that were not updated for longer than the max staleness.
+func (t *tracker) expire(now time.Time) {
+	for elem := t.lru.Back(); elem != nil; elem = t.lru.Back() {
+		if now.Sub(elem.Value.(*series).lastSeen) <= t.maxStaleness {
+			return
+		}
+		t.remove(elem)
+	}
+}
+
+func (t *tracker) remove(elem *list.Element) {
+	t.lru.Remove(elem)
+	delete(t.series, elem.Value.(*series).key)
+}
+
+func (t *tracker) len() int {
+	return t.lru.Len()
+}
@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package temporalityprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeries_CumulativeToDelta(t *testing.T) {
+	s := &series{}
+
+	_, ok := s.cumulativeToDelta(point{start: 1, timestamp: 10, intValue: 5}, true, true)
+	assert.False(t, ok, "the first point cannot be converted")
+
+	delta, ok := s.cumulativeToDelta(point{start: 1, timestamp: 20, intValue: 8}, false, true)
+	assert.True(t, ok)
+	assert.Equal(t, point{start: 10, timestamp: 20, intValue: 3}, delta)
+
+	_, ok = s.cumulativeToDelta(point{start: 1, timestamp: 20, intValue: 9}, false, true)
+	assert.False(t, ok, "duplicate point")
+
+	_, ok = s.cumulativeToDelta(point{start: 1, timestamp: 30, intValue: 2}, false, true)
+	assert.False(t, ok, "reset at an unknown time")
+
+	delta, ok = s.cumulativeToDelta(point{start: 1, timestamp: 40, intValue: 1}, false, false)
+	assert.True(t, ok, "non monotonic sums can decrease")
+	assert.Equal(t, int64(-1), delta.intValue)
+
+	delta, ok = s.cumulativeToDelta(point{start: 45, timestamp: 50, intValue: 4}, false, true)
+	assert.True(t, ok, "reset at a known time")
+	assert.Equal(t, point{start: 45, timestamp: 50, intValue: 4}, delta)
+
+	_, ok = s.cumulativeToDelta(point{start: 48, timestamp: 60, intValue: 7}, false, true)
+	assert.False(t, ok, "reset overlapping the previous point")
+}
+
+func TestSeries_CumulativeToDeltaHistogram(t *testing.T) {
+	s := &series{}
+	first := point{start: 1, timestamp: 10, doubleValue: 3, histogram: true, count: 2, bounds: []float64{1}, buckets: []uint64{1, 1}}
+	_, ok := s.cumulativeToDelta(first, true, true)
+	assert.False(t, ok)
+
+	second := point{start: 1, timestamp: 20, doubleValue: 7, histogram: true, count: 5, bounds: []float64{1}, buckets: []uint64{2, 3}}
+	delta, ok := s.cumulativeToDelta(second, false, true)
+	assert.True(t, ok)
+	assert.Equal(t, point{start: 10, timestamp: 20, doubleValue: 4, histogram: true, count: 3, bounds: []float64{1}, buckets: []uint64{1, 2}}, delta)
+
+	// A decreasing bucket means a reset even if the count increased.
+	third := point{start: 1, timestamp: 30, doubleValue: 8, histogram: true, count: 6, bounds: []float64{1}, buckets: []uint64{1, 5}}
+	_, ok = s.cumulativeToDelta(third, false, true)
+	assert.False(t, ok)
+
+	// Buckets with different bounds cannot be subtracted.
+	fourth := point{start: 1, timestamp: 40, doubleValue: 9, histogram: true, count: 7, bounds: []float64{2}, buckets: []uint64{2, 5}}
+	_, ok = s.cumulativeToDelta(fourth, false, true)
+	assert.False(t, ok)
+}
+
+func TestSeries_DeltaToCumulative(t *testing.T) {
+	s := &series{}
+
+	cumulative, ok := s.deltaToCumulative(point{start: 0, timestamp: 10, doubleValue: 1}, true)
+	assert.True(t, ok)
+	assert.Equal(t, point{start: 0, timestamp: 10, doubleValue: 1}, cumulative)
+
+	cumulative, ok = s.deltaToCumulative(point{start: 10, timestamp: 20, doubleValue: 2}, false)
+	assert.True(t, ok)
+	assert.Equal(t, point{start: 0, timestamp: 20, doubleValue: 3}, cumulative)
+
+	_, ok = s.deltaToCumulative(point{start: 10, timestamp: 20, doubleValue: 2}, false)
+	assert.False(t, ok, "duplicate point")
+
+	_, ok = s.deltaToCumulative(point{start: 15, timestamp: 30, doubleValue: 2}, false)
+	assert.False(t, ok, "overlapping point")
+
+	cumulative, ok = s.deltaToCumulative(point{timestamp: 30, doubleValue: 4}, false)
+	assert.True(t, ok, "point without start time")
+	assert.Equal(t, 7.0, cumulative.doubleValue)
+
+	cumulative, ok = s.deltaToCumulative(point{start: 40, timestamp: 50, doubleValue: 5}, false)
+	assert.True(t, ok, "gap")
+	assert.Equal(t, point{start: 40, timestamp: 50, doubleValue: 5}, cumulative)
+}
+
+func TestTracker(t *testing.T) {
+	tr := newTracker(2, time.Minute)
+	now := time.Unix(0, 0)
+
+	_, isNew := tr.get("a", now)
+	assert.True(t, isNew)
+	_, isNew = tr.get("b", now.Add(10*time.Second))
+	assert.True(t, isNew)
+	_, isNew = tr.get("a", now.Add(20*time.Second))
+	assert.False(t, isNew)
+
+	// The least recently updated series is evicted when the limit is exceeded.
+	_, isNew = tr.get("c", now.Add(30*time.Second))
+	assert.True(t, isNew)
+	assert.Equal(t, 2, tr.len())
+	_, isNew = tr.get("b", now.Add(40*time.Second))
+	assert.True(t, isNew)
+	assert.Equal(t, 2, tr.len())
+
+	// A stale series starts over.
+	_, isNew = tr.get("c", now.Add(2*time.Minute))
+	assert.True(t, isNew)
+
+	tr.expire(now.Add(2*time.Minute + 30*time.Second))
+	assert.Equal(t, 1, tr.len())
+	tr.expire(now.Add(4 * time.Minute))
+	assert.Equal(t, 0, tr.len())
+}
@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+)
+
+// Config defines configuration for the Carbon receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// TCPAddr is the TCP address listened on.
+	confignet.TCPAddr `mapstructure:",squash"`
+
+	// Separator joins the parts of the paths that make up the metric names and the
+	// parts that make up a same label value.
+	Separator string `mapstructure:"separator"`
+
+	// Templates convert the paths of the series that are not tagged to metric names
+	// and labels, e.g. "servers.* .host.measurement* region=us". The most specific
+	// template whose filter matches a path is applied, a template without filter matches
+	// all the paths.
+	Templates []string `mapstructure:"templates"`
+}
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// point is a point of the Carbon plaintext protocol, with its path converted to a
+// metric name and labels.
+type point struct {
+	name   string
+	labels map[string]string
+	value  float64
+	// timestamp is the timestamp of the point in nanoseconds, 0 if the point has none.
+	timestamp int64
+}
+
+// parser parses the lines of the Carbon plaintext protocol.
+type parser struct {
+	templates []*template
+	separator string
+}
+
+func newParser(templates []string, separator string) (*parser, error) {
+	p := &parser{separator: separator}
+	for _, s := range templates {
+		t, err := parseTemplate(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %q: %v", s, err)
+		}
+		p.templates = append(p.templates, t)
+	}
+	// The first matching template is the most specific one, the order of the templates
+	// that are as specific is kept.
+	sort.SliceStable(p.templates, func(i, j int) bool {
+		return p.templates[i].moreSpecific(p.templates[j])
+	})
+	return p, nil
+}
+
+// parse parses a line of the form "<path> <value> [<timestamp>]", the timestamp is a
+// number of seconds since the Unix epoch or -1 for the points without timestamp. The
+// path is either a tagged series of the form "<name>;<tag>=<value>[;<tag>=<value>...]",
+// whose name and tags are the metric name and labels, or a dotted path converted to a
+// metric name and labels by the most specific matching template. The paths matching no template,
+// or too short to have a metric name, are the metric names, without labels.
+func (p *parser) parse(line string) (point, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 && len(fields) != 3 {
+		return point{}, errors.New("a line must have a path, a value and a timestamp separated by spaces")
+	}
+	value, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return point{}, fmt.Errorf("invalid value %q", fields[1])
+	}
+	pt := point{value: value}
+	if len(fields) == 3 {
+		if pt.timestamp, err = parseTimestamp(fields[2]); err != nil {
+			return point{}, err
+		}
+	}
+
+	if strings.Contains(fields[0], ";") {
+		if pt.name, pt.labels, err = parseTaggedSeries(fields[0]); err != nil {
+			return point{}, err
+		}
+	} else {
+		pt.name, pt.labels = p.applyTemplates(fields[0])
+	}
+	return pt, nil
+}
+
+func (p *parser) applyTemplates(path string) (string, map[string]string) {
+	parts := strings.Split(path, ".")
+	for _, t := range p.templates {
+		if !t.matches(parts) {
+			continue
+		}
+		name, labels := t.apply(parts, p.separator)
+		// The path is shorter than the measurement parts of the template.
+		if name == "" {
+			return path, nil
+		}
+		return name, labels
+	}
+	return path, nil
+}
+
+// parseTaggedSeries parses a tagged series of Graphite 1.1.
+func parseTaggedSeries(series string) (string, map[string]string, error) {
+	tags := strings.Split(series, ";")
+	if tags[0] == "" {
+		return "", nil, fmt.Errorf("tagged series %q without name", series)
+	}
+	labels := make(map[string]string, len(tags)-1)
+	for _, tag := range tags[1:] {
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return "", nil, fmt.Errorf("invalid tag %q of tagged series %q", tag, series)
+		}
+		labels[kv[0]] = kv[1]
+	}
+	return tags[0], labels, nil
+}
+
+// maxTimestampSeconds is the largest timestamp in seconds whose nanoseconds fit in an int64.
+const maxTimestampSeconds = math.MaxInt64 / int64(1e9)
+
+func parseTimestamp(s string) (int64, error) {
+	if s == "-1" {
+		return 0, nil
+	}
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil && seconds >= 0 {
+		if seconds > maxTimestampSeconds {
+			return 0, fmt.Errorf("timestamp %q out of range", s)
+		}
+		return seconds * 1e9, nil
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil || seconds < 0 || math.IsInf(seconds, 0) || math.IsNaN(seconds) {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	// The fractional part is converted separately to keep the precision of the seconds.
+	whole, frac := math.Modf(seconds)
+	if whole > float64(maxTimestampSeconds) {
+		return 0, fmt.Errorf("timestamp %q out of range", s)
+	}
+	nanos := int64(math.Round(frac * 1e9))
+	if int64(whole)*1e9 > math.MaxInt64-nanos {
+		return 0, fmt.Errorf("timestamp %q out of range", s)
+	}
+	return int64(whole)*1e9 + nanos, nil
+}
+
+// toMetrics converts points to double gauges, the points of a same name are data points
+// of the same metric. The points without timestamp are timestamped with now.
+func toMetrics(points []point, now int64) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+
+	byName := map[string]pdata.Metric{}
+	for _, pt := range points {
+		metric, ok := byName[pt.name]
+		if !ok {
+			metric = pdata.NewMetric()
+			metric.InitEmpty()
+			metric.SetName(pt.name)
+			metric.SetDataType(pdata.MetricDataTypeDoubleGauge)
+			metric.DoubleGauge().InitEmpty()
+			metrics.Append(metric)
+			byName[pt.name] = metric
+		}
+
+		dp := pdata.NewDoubleDataPoint()
+		dp.InitEmpty()
+		keys := make([]string, 0, len(pt.labels))
+		for k := range pt.labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			dp.LabelsMap().Insert(k, pt.labels[k])
+		}
+		timestamp := pt.timestamp
+		if timestamp == 0 {
+			timestamp = now
+		}
+		dp.SetTimestamp(pdata.TimestampUnixNano(timestamp))
+		dp.SetValue(pt.value)
+		metric.DoubleGauge().DataPoints().Append(dp)
+	}
+	return md
+}
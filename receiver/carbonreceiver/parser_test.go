@@ -0,0 +1,223 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestParser_Parse(t *testing.T) {
+	p, err := newParser([]string{
+		"servers.* .host.measurement* env=prod",
+		"service.measurement*",
+	}, ".")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name     string
+		line     string
+		expected point
+	}{
+		{
+			name: "filtered_template",
+			line: "servers.host01.cpu.load 0.5 1604581200",
+			expected: point{
+				name:      "cpu.load",
+				labels:    map[string]string{"host": "host01", "env": "prod"},
+				value:     0.5,
+				timestamp: 1604581200e9,
+			},
+		},
+		{
+			name: "default_template",
+			line: "api.requests 42 1604581200.25",
+			expected: point{
+				name:      "requests",
+				labels:    map[string]string{"service": "api"},
+				value:     42,
+				timestamp: 1604581200250000000,
+			},
+		},
+		{
+			name: "unmatched_path",
+			line: "servers 1 -1",
+			expected: point{
+				name:  "servers",
+				value: 1,
+			},
+		},
+		{
+			name: "tagged_series",
+			line: "disk.used;datacenter=dc1;rack=a1 1e9",
+			expected: point{
+				name:   "disk.used",
+				labels: map[string]string{"datacenter": "dc1", "rack": "a1"},
+				value:  1e9,
+			},
+		},
+		{
+			name: "tabs",
+			line: "api.requests\t-3\t1604581200",
+			expected: point{
+				name:      "requests",
+				labels:    map[string]string{"service": "api"},
+				value:     -3,
+				timestamp: 1604581200e9,
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			pt, err := p.parse(tc.line)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, pt)
+		})
+	}
+}
+
+func TestParser_ParseMostSpecificTemplate(t *testing.T) {
+	p, err := newParser([]string{
+		"measurement*",
+		"servers.* .host.measurement*",
+		"servers.db01 ..measurement* role=db",
+	}, ".")
+	require.NoError(t, err)
+
+	pt, err := p.parse("servers.db01.cpu.load 1")
+	require.NoError(t, err)
+	assert.Equal(t, "cpu.load", pt.name)
+	assert.Equal(t, map[string]string{"role": "db"}, pt.labels)
+
+	pt, err = p.parse("servers.web01.cpu.load 1")
+	require.NoError(t, err)
+	assert.Equal(t, "cpu.load", pt.name)
+	assert.Equal(t, map[string]string{"host": "web01"}, pt.labels)
+
+	pt, err = p.parse("api.requests 1")
+	require.NoError(t, err)
+	assert.Equal(t, "api.requests", pt.name)
+	assert.Equal(t, map[string]string{}, pt.labels)
+}
+
+func TestParser_ParseNoTemplate(t *testing.T) {
+	p, err := newParser(nil, ".")
+	require.NoError(t, err)
+	pt, err := p.parse("api.requests 42 1604581200")
+	require.NoError(t, err)
+	assert.Equal(t, "api.requests", pt.name)
+	assert.Nil(t, pt.labels)
+}
+
+func TestParser_ParseInvalid(t *testing.T) {
+	p, err := newParser(nil, ".")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		line        string
+		errorString string
+	}{
+		{
+			line:        "api.requests",
+			errorString: "a line must have a path, a value and a timestamp separated by spaces",
+		},
+		{
+			line:        "api.requests 1 1604581200 extra",
+			errorString: "a line must have a path, a value and a timestamp separated by spaces",
+		},
+		{
+			line:        "api.requests one 1604581200",
+			errorString: `invalid value "one"`,
+		},
+		{
+			line:        "api.requests 1 yesterday",
+			errorString: `invalid timestamp "yesterday"`,
+		},
+		{
+			line:        "api.requests 1 -2",
+			errorString: `invalid timestamp "-2"`,
+		},
+		{
+			line:        "api.requests 1 9223372037",
+			errorString: `timestamp "9223372037" out of range`,
+		},
+		{
+			line:        "api.requests 1 9223372036.9",
+			errorString: `timestamp "9223372036.9" out of range`,
+		},
+		{
+			line:        "api.requests 1 1e300",
+			errorString: `timestamp "1e300" out of range`,
+		},
+		{
+			line:        ";env=prod 1 1604581200",
+			errorString: `tagged series ";env=prod" without name`,
+		},
+		{
+			line:        "api.requests;env 1 1604581200",
+			errorString: `invalid tag "env" of tagged series "api.requests;env"`,
+		},
+		{
+			line:        "api.requests;env= 1 1604581200",
+			errorString: `invalid tag "env=" of tagged series "api.requests;env="`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			_, err := p.parse(tc.line)
+			assert.EqualError(t, err, tc.errorString)
+		})
+	}
+}
+
+func TestNewParser_InvalidTemplate(t *testing.T) {
+	p, err := newParser([]string{"measurement*", "host.region"}, ".")
+	assert.EqualError(t, err, `invalid template "host.region": no "measurement" or "measurement*" part`)
+	assert.Nil(t, p)
+}
+
+func TestToMetrics(t *testing.T) {
+	md := toMetrics([]point{
+		{name: "cpu", labels: map[string]string{"host": "b", "env": "prod"}, value: 1, timestamp: 1e9},
+		{name: "memory", value: 2},
+		{name: "cpu", labels: map[string]string{"host": "a"}, value: 3, timestamp: 2e9},
+	}, 5e9)
+
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+
+	cpu := metrics.At(0)
+	assert.Equal(t, "cpu", cpu.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, cpu.DataType())
+	dps := cpu.DoubleGauge().DataPoints()
+	require.Equal(t, 2, dps.Len())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"env": "prod", "host": "b"}).Sort(), dps.At(0).LabelsMap().Sort())
+	assert.Equal(t, pdata.TimestampUnixNano(1e9), dps.At(0).Timestamp())
+	assert.Equal(t, 1.0, dps.At(0).Value())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"host": "a"}), dps.At(1).LabelsMap())
+	assert.Equal(t, 3.0, dps.At(1).Value())
+
+	memory := metrics.At(1)
+	assert.Equal(t, "memory", memory.Name())
+	dp := memory.DoubleGauge().DataPoints().At(0)
+	assert.Equal(t, 0, dp.LabelsMap().Len())
+	// The points without timestamp are timestamped with now.
+	assert.Equal(t, pdata.TimestampUnixNano(5e9), dp.Timestamp())
+	assert.Equal(t, 2.0, dp.Value())
+}
@@ -0,0 +1,219 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	transport = "tcp"
+
+	// format is the format reported by obsreport.
+	format = "carbon"
+
+	// maxBatchSize bounds the number of points of a connection consumed together.
+	maxBatchSize = 1000
+	// maxLineSize is the maximum size of a line.
+	maxLineSize = 65536
+)
+
+var errLineTooLong = errors.New("line too long")
+
+type carbonReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	parser       *parser
+	now          func() time.Time
+
+	listener net.Listener
+
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func newCarbonReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.MetricsConsumer) (*carbonReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint must be specified")
+	}
+	p, err := newParser(config.Templates, config.Separator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &carbonReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		parser:       p,
+		now:          time.Now,
+		conns:        make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// Start listens on the configured endpoint.
+func (r *carbonReceiver) Start(_ context.Context, _ component.Host) error {
+	listener, err := r.config.TCPAddr.Listen()
+	if err != nil {
+		return fmt.Errorf("error listening on carbon endpoint: %v", err)
+	}
+	r.listener = listener
+	r.wg.Add(1)
+	go r.acceptConnections()
+	return nil
+}
+
+// Shutdown closes the endpoint and the connections.
+func (r *carbonReceiver) Shutdown(context.Context) error {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil
+	}
+	r.stopped = true
+	var err error
+	if r.listener != nil {
+		err = r.listener.Close()
+	}
+	for conn := range r.conns {
+		conn.Close()
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	return err
+}
+
+func (r *carbonReceiver) acceptConnections() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		r.mu.Lock()
+		if r.stopped {
+			r.mu.Unlock()
+			conn.Close()
+			return
+		}
+		r.conns[conn] = struct{}{}
+		r.wg.Add(1)
+		r.mu.Unlock()
+		go r.handleConnection(conn)
+	}
+}
+
+// handleConnection consumes the points of a connection, one per line. The points read
+// without blocking are consumed together, the lines that cannot be parsed or that are too
+// long are dropped.
+func (r *carbonReceiver) handleConnection(conn net.Conn) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.conns, conn)
+		r.mu.Unlock()
+		conn.Close()
+		r.wg.Done()
+	}()
+
+	reader := bufio.NewReaderSize(conn, maxLineSize)
+	for {
+		var points []point
+		var err error
+		for len(points) < maxBatchSize {
+			var line string
+			if line, err = readLine(reader); err == errLineTooLong {
+				r.logger.Debug("Dropped too long carbon line", zap.Int("max_size", maxLineSize))
+				err = nil
+			} else if line != "" {
+				if pt, perr := r.parser.parse(line); perr != nil {
+					r.logger.Debug("Failed to parse carbon line", zap.String("line", line), zap.Error(perr))
+				} else {
+					points = append(points, pt)
+				}
+			}
+			if err != nil || reader.Buffered() == 0 {
+				break
+			}
+		}
+		if len(points) > 0 {
+			r.consume(points)
+		}
+		if err != nil {
+			if err != io.EOF && !r.isStopped() {
+				r.logger.Debug("Closing carbon connection", zap.String("remote", conn.RemoteAddr().String()), zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+// readLine reads a line without its line ending, the last line of a connection can have
+// no line ending. A line longer than maxLineSize is discarded up to its line ending, and
+// errLineTooLong returned.
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return strings.TrimSpace(string(line)), err
+	}
+	for err == bufio.ErrBufferFull {
+		_, err = reader.ReadSlice('\n')
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", errLineTooLong
+}
+
+func (r *carbonReceiver) consume(points []point) {
+	md := toMetrics(points, r.now().UnixNano())
+
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.Name(), transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+	err := r.nextConsumer.ConsumeMetrics(ctx, md)
+	obsreport.EndMetricsReceiveOp(ctx, format, len(points), err)
+	if err != nil {
+		r.logger.Error("Failed to consume carbon metrics", zap.Error(err))
+	}
+}
+
+func (r *carbonReceiver) isStopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
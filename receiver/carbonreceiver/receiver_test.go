@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func newTestReceiver(t *testing.T, modify func(cfg *Config)) (*carbonReceiver, *consumertest.MetricsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	if modify != nil {
+		modify(cfg)
+	}
+	sink := new(consumertest.MetricsSink)
+	r, err := newCarbonReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r.now = func() time.Time { return time.Unix(1604581260, 0) }
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r, sink
+}
+
+// receivedPoints returns the data points received by the sink, by metric name.
+func receivedPoints(sink *consumertest.MetricsSink) map[string][]pdata.DoubleDataPoint {
+	points := map[string][]pdata.DoubleDataPoint{}
+	for _, md := range sink.AllMetrics() {
+		metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			dps := metrics.At(i).DoubleGauge().DataPoints()
+			for j := 0; j < dps.Len(); j++ {
+				points[metrics.At(i).Name()] = append(points[metrics.At(i).Name()], dps.At(j))
+			}
+		}
+	}
+	return points
+}
+
+// pointCount returns the number of data points received by the sink.
+func pointCount(sink *consumertest.MetricsSink) int {
+	count := 0
+	for _, md := range sink.AllMetrics() {
+		_, numPoints := md.MetricAndDataPointCount()
+		count += numPoints
+	}
+	return count
+}
+
+func TestNewCarbonReceiver_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "empty_endpoint",
+			modify:      func(cfg *Config) { cfg.Endpoint = "" },
+			errorString: "endpoint must be specified",
+		},
+		{
+			name:        "invalid_template",
+			modify:      func(cfg *Config) { cfg.Templates = []string{"host.measurement*.region"} },
+			errorString: `invalid template "host.measurement*.region": "measurement*" must be the last part`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+			r, err := newCarbonReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestCarbonReceiver(t *testing.T) {
+	r, sink := newTestReceiver(t, func(cfg *Config) {
+		cfg.Templates = []string{"servers.* .host.measurement*", "measurement*"}
+	})
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", r.config.Endpoint)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("servers.host01.cpu.load 0.5 1604581200\r\n" +
+		"invalid\n" +
+		"disk.used;datacenter=dc1 1024 -1\n" +
+		"servers.host02.cpu.load 0.75 1604581200\n" +
+		"requests 42"))
+	require.NoError(t, err)
+	// The last line is terminated by the end of the connection.
+	require.NoError(t, conn.Close())
+
+	require.Eventually(t, func() bool {
+		return pointCount(sink) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	points := receivedPoints(sink)
+	require.Len(t, points["cpu.load"], 2)
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"host": "host01"}), points["cpu.load"][0].LabelsMap())
+	assert.Equal(t, 0.5, points["cpu.load"][0].Value())
+	assert.Equal(t, pdata.TimestampUnixNano(1604581200e9), points["cpu.load"][0].Timestamp())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"host": "host02"}), points["cpu.load"][1].LabelsMap())
+
+	require.Len(t, points["disk.used"], 1)
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"datacenter": "dc1"}), points["disk.used"][0].LabelsMap())
+	assert.Equal(t, pdata.TimestampUnixNano(1604581260e9), points["disk.used"][0].Timestamp())
+
+	require.Len(t, points["requests"], 1)
+	assert.Equal(t, 42.0, points["requests"][0].Value())
+}
+
+func TestCarbonReceiver_LineTooLong(t *testing.T) {
+	r, sink := newTestReceiver(t, nil)
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", r.config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("requests 1\n" + strings.Repeat("a", 2*maxLineSize) + " 1\nerrors 2\n"))
+	require.NoError(t, err)
+
+	// The long line is dropped, the connection is kept open.
+	require.Eventually(t, func() bool {
+		return pointCount(sink) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = conn.Write([]byte("requests 3\n"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return pointCount(sink) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	points := receivedPoints(sink)
+	require.Len(t, points["requests"], 2)
+	require.Len(t, points["errors"], 1)
+	assert.Equal(t, 2.0, points["errors"][0].Value())
+}
+
+func TestCarbonReceiver_Shutdown(t *testing.T) {
+	r, _ := newTestReceiver(t, nil)
+
+	conn, err := net.Dial("tcp", r.config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("requests 1\n"))
+	require.NoError(t, err)
+
+	// Shutdown closes the open connections.
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestCarbonReceiver_StartError(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = listener.Addr().String()
+	r, err := newCarbonReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
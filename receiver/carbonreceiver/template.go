@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// The parts of a template that make up the metric name, the other non empty parts are
+// label keys.
+const (
+	measurementPart         = "measurement"
+	measurementWildcardPart = "measurement*"
+)
+
+// template converts the paths of the series that are not tagged to a metric name and
+// labels. A template has the form:
+//
+//	[<filter>] <part>[.<part>...] [<label key>=<label value>[,<label key>=<label value>...]]
+//
+// Each part of the template applies to the part of the path at the same position:
+// "measurement" adds it to the metric name, "measurement*" adds it and the remaining
+// parts to the metric name, an empty part ignores it and any other part is the key of
+// a label whose value is the part of the path. The parts of the path beyond the ones
+// of the template are ignored. The filter is a path whose parts are glob patterns, it
+// matches the paths starting with parts matching its patterns.
+type template struct {
+	filter []string
+	parts  []string
+	labels map[string]string
+}
+
+func parseTemplate(s string) (*template, error) {
+	fields := strings.Fields(s)
+	t := &template{labels: map[string]string{}}
+	switch {
+	case len(fields) == 1:
+		t.parts = strings.Split(fields[0], ".")
+	case len(fields) == 2 && strings.Contains(fields[1], "="):
+		t.parts = strings.Split(fields[0], ".")
+		if err := t.parseLabels(fields[1]); err != nil {
+			return nil, err
+		}
+	case len(fields) == 2:
+		t.filter = strings.Split(fields[0], ".")
+		t.parts = strings.Split(fields[1], ".")
+	case len(fields) == 3:
+		t.filter = strings.Split(fields[0], ".")
+		t.parts = strings.Split(fields[1], ".")
+		if err := t.parseLabels(fields[2]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("a template must have a filter, parts and labels separated by spaces")
+	}
+
+	for _, pattern := range t.filter {
+		if pattern == "" {
+			return nil, errors.New("the filter has an empty part")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter part %q", pattern)
+		}
+	}
+	hasMeasurement := false
+	for i, part := range t.parts {
+		switch part {
+		case measurementPart:
+			hasMeasurement = true
+		case measurementWildcardPart:
+			if i != len(t.parts)-1 {
+				return nil, fmt.Errorf("%q must be the last part", measurementWildcardPart)
+			}
+			hasMeasurement = true
+		}
+	}
+	if !hasMeasurement {
+		return nil, fmt.Errorf("no %q or %q part", measurementPart, measurementWildcardPart)
+	}
+	return t, nil
+}
+
+func (t *template) parseLabels(s string) error {
+	for _, label := range strings.Split(s, ",") {
+		kv := strings.SplitN(label, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return fmt.Errorf("invalid label %q", label)
+		}
+		t.labels[kv[0]] = kv[1]
+	}
+	return nil
+}
+
+// moreSpecific returns whether the filter of the template is more specific than the filter
+// of other. The filters are compared part by part: at the first position where one part is
+// a glob pattern and the other one is not, the one without pattern is more specific. If
+// all the parts of the shortest filter are equally specific, the longest one is more
+// specific. A template without filter is the least specific.
+func (t *template) moreSpecific(other *template) bool {
+	for i := 0; i < len(t.filter) && i < len(other.filter); i++ {
+		glob, otherGlob := isGlob(t.filter[i]), isGlob(other.filter[i])
+		if glob != otherGlob {
+			return otherGlob
+		}
+	}
+	return len(t.filter) > len(other.filter)
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// matches returns whether the filter of the template matches the parts of a path.
+func (t *template) matches(pathParts []string) bool {
+	if len(pathParts) < len(t.filter) {
+		return false
+	}
+	for i, pattern := range t.filter {
+		if ok, _ := path.Match(pattern, pathParts[i]); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the metric name and the labels of the parts of a path. The parts of the
+// path that are values of a same label are joined with the separator, as are the parts
+// of the metric name. The labels of the path take precedence over the labels of the
+// template.
+func (t *template) apply(pathParts []string, separator string) (string, map[string]string) {
+	var name []string
+	values := map[string][]string{}
+	for i, part := range t.parts {
+		if i >= len(pathParts) {
+			break
+		}
+		switch part {
+		case "":
+		case measurementPart:
+			name = append(name, pathParts[i])
+		case measurementWildcardPart:
+			name = append(name, pathParts[i:]...)
+		default:
+			values[part] = append(values[part], pathParts[i])
+		}
+	}
+
+	labels := make(map[string]string, len(t.labels)+len(values))
+	for k, v := range t.labels {
+		labels[k] = v
+	}
+	for k, v := range values {
+		labels[k] = strings.Join(v, separator)
+	}
+	return strings.Join(name, separator), labels
+}
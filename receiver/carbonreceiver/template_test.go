@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_Apply(t *testing.T) {
+	testcases := []struct {
+		name           string
+		template       string
+		path           string
+		expectedName   string
+		expectedLabels map[string]string
+	}{
+		{
+			name:           "measurement_wildcard",
+			template:       "service.host.measurement*",
+			path:           "api.host01.cpu.load.1m",
+			expectedName:   "cpu_load_1m",
+			expectedLabels: map[string]string{"service": "api", "host": "host01"},
+		},
+		{
+			name:           "skipped_and_ignored_parts",
+			template:       ".host.measurement",
+			path:           "servers.host01.cpu.load",
+			expectedName:   "cpu",
+			expectedLabels: map[string]string{"host": "host01"},
+		},
+		{
+			name:           "joined_parts",
+			template:       "region.region.measurement.measurement",
+			path:           "us.west.cpu.load",
+			expectedName:   "cpu_load",
+			expectedLabels: map[string]string{"region": "us_west"},
+		},
+		{
+			name:           "default_labels",
+			template:       "host.measurement* env=prod,host=unknown",
+			path:           "host01.cpu",
+			expectedName:   "cpu",
+			expectedLabels: map[string]string{"env": "prod", "host": "host01"},
+		},
+		{
+			name:           "short_path",
+			template:       "host.measurement*",
+			path:           "host01",
+			expectedName:   "",
+			expectedLabels: map[string]string{"host": "host01"},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := parseTemplate(tc.template)
+			require.NoError(t, err)
+			name, labels := tmpl.apply(strings.Split(tc.path, "."), "_")
+			assert.Equal(t, tc.expectedName, name)
+			assert.Equal(t, tc.expectedLabels, labels)
+		})
+	}
+}
+
+func TestTemplate_Matches(t *testing.T) {
+	tmpl, err := parseTemplate("servers.web* .host.measurement*")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"servers", "web*"}, tmpl.filter)
+	assert.True(t, tmpl.matches([]string{"servers", "web01", "cpu"}))
+	assert.True(t, tmpl.matches([]string{"servers", "web"}))
+	assert.False(t, tmpl.matches([]string{"servers", "db01", "cpu"}))
+	assert.False(t, tmpl.matches([]string{"servers"}))
+
+	tmpl, err = parseTemplate("measurement*")
+	require.NoError(t, err)
+	assert.True(t, tmpl.matches([]string{"cpu"}))
+}
+
+func TestTemplate_MoreSpecific(t *testing.T) {
+	templates := map[string]*template{}
+	for _, s := range []string{
+		"measurement*",
+		"servers.* .host.measurement*",
+		"servers.web* .host.measurement*",
+		"servers.web01 .host.measurement*",
+		"servers.*.cpu .host.measurement*",
+		"*.web01.cpu .host.measurement*",
+	} {
+		tmpl, err := parseTemplate(s)
+		require.NoError(t, err)
+		templates[s] = tmpl
+	}
+	moreSpecific := func(a, b string) bool {
+		return templates[a].moreSpecific(templates[b])
+	}
+
+	assert.True(t, moreSpecific("servers.* .host.measurement*", "measurement*"))
+	assert.False(t, moreSpecific("measurement*", "servers.* .host.measurement*"))
+	assert.True(t, moreSpecific("servers.web01 .host.measurement*", "servers.web* .host.measurement*"))
+	assert.False(t, moreSpecific("servers.web* .host.measurement*", "servers.* .host.measurement*"))
+	assert.False(t, moreSpecific("servers.* .host.measurement*", "servers.web* .host.measurement*"))
+	assert.True(t, moreSpecific("servers.*.cpu .host.measurement*", "servers.* .host.measurement*"))
+	assert.True(t, moreSpecific("servers.web01 .host.measurement*", "servers.*.cpu .host.measurement*"))
+	assert.True(t, moreSpecific("servers.web01 .host.measurement*", "*.web01.cpu .host.measurement*"))
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	testcases := []struct {
+		template    string
+		errorString string
+	}{
+		{
+			template:    "",
+			errorString: "a template must have a filter, parts and labels separated by spaces",
+		},
+		{
+			template:    "a b c d",
+			errorString: "a template must have a filter, parts and labels separated by spaces",
+		},
+		{
+			template:    "host.region",
+			errorString: `no "measurement" or "measurement*" part`,
+		},
+		{
+			template:    "measurement*.host",
+			errorString: `"measurement*" must be the last part`,
+		},
+		{
+			template:    "servers..web measurement*",
+			errorString: "the filter has an empty part",
+		},
+		{
+			template:    "servers.[web measurement*",
+			errorString: `invalid filter part "[web"`,
+		},
+		{
+			template:    "measurement* env=",
+			errorString: `invalid label "env="`,
+		},
+		{
+			template:    "servers.* measurement* env",
+			errorString: `invalid label "env"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.template, func(t *testing.T) {
+			tmpl, err := parseTemplate(tc.template)
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, tmpl)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checkpoint is the persisted state of a reader.
+type checkpoint struct {
+	Path        string `json:"path"`
+	Fingerprint []byte `json:"fingerprint"`
+	Offset      int64  `json:"offset"`
+}
+
+// checkpointFile persists the offsets of the readers of a receiver.
+type checkpointFile struct {
+	path string
+}
+
+func newCheckpointFile(dir string, receiverName string) *checkpointFile {
+	name := strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(receiverName)
+	return &checkpointFile{path: filepath.Join(dir, name+".json")}
+}
+
+// load returns the persisted checkpoints. It returns false if the file does not exist,
+// that is the receiver never persisted its checkpoints.
+func (cf *checkpointFile) load() ([]checkpoint, bool, error) {
+	data, err := ioutil.ReadFile(cf.path)
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var checkpoints []checkpoint
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, false, err
+	}
+	return checkpoints, true, nil
+}
+
+// save replaces the persisted checkpoints. The file is written atomically, so that
+// the previous checkpoints are kept if the collector stops while writing.
+func (cf *checkpointFile) save(checkpoints []checkpoint) error {
+	data, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	tmp := cf.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cf.path)
+}
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointFile(t *testing.T) {
+	dir := newTempDir(t)
+	cf := newCheckpointFile(dir, "filelog/app")
+	assert.Equal(t, filepath.Join(dir, "filelog_app.json"), cf.path)
+
+	checkpoints, found, err := cf.load()
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Nil(t, checkpoints)
+
+	expected := []checkpoint{
+		{Path: "/var/log/a.log", Fingerprint: []byte("first line\n"), Offset: 11},
+		{Path: "/var/log/b.log", Fingerprint: []byte{0, 1, 2}, Offset: 3},
+	}
+	require.NoError(t, cf.save(expected))
+	checkpoints, found, err = cf.load()
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, expected, checkpoints)
+
+	require.NoError(t, cf.save([]checkpoint{}))
+	checkpoints, found, err = cf.load()
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Empty(t, checkpoints)
+
+	require.NoError(t, ioutil.WriteFile(cf.path, []byte("not json"), 0600))
+	_, _, err = cf.load()
+	assert.Error(t, err)
+}
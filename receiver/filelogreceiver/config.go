@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+)
+
+const (
+	// StartAtBeginning reads the files found at startup from their beginning.
+	StartAtBeginning = "beginning"
+	// StartAtEnd only reads the lines written after startup to the files found at startup.
+	StartAtEnd = "end"
+)
+
+// MultilineConfig defines how lines are grouped into multiline entries.
+type MultilineConfig struct {
+	// LineStartPattern is a regular expression matching the first line of each entry.
+	// The following lines that do not match are appended to the entry. Each line is
+	// an entry if it is not set.
+	LineStartPattern string `mapstructure:"line_start_pattern"`
+}
+
+// Config defines configuration for the file log receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Include is the list of glob patterns of the files to read.
+	Include []string `mapstructure:"include"`
+
+	// Exclude is the list of glob patterns of the files matched by Include that are not read.
+	Exclude []string `mapstructure:"exclude"`
+
+	// StartAt is where the files found at startup are read from, either "beginning" or "end".
+	// It only applies when no offsets were persisted by a previous run: the files found later,
+	// or when offsets were persisted, are read from their persisted offset or from their
+	// beginning.
+	StartAt string `mapstructure:"start_at"`
+
+	// PollInterval is the interval between two checks of the files for new data.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+
+	// FingerprintSize is the number of bytes at the beginning of a file used to identify
+	// it when it is renamed or copied by a rotation.
+	FingerprintSize int `mapstructure:"fingerprint_size"`
+
+	// MaxLogSize is the maximum size in bytes of an entry, longer entries are split.
+	MaxLogSize int `mapstructure:"max_log_size"`
+
+	// Multiline defines how lines are grouped into multiline entries.
+	Multiline MultilineConfig `mapstructure:"multiline"`
+
+	// ForceFlushPeriod is how long the last entry of a file, that is not terminated by
+	// a new line or by the start of the next multiline entry, must stay unchanged before
+	// it is emitted.
+	ForceFlushPeriod time.Duration `mapstructure:"force_flush_period"`
+
+	// IncludeFileName adds the "file.name" attribute, the base name of the file, to the
+	// log records.
+	IncludeFileName bool `mapstructure:"include_file_name"`
+
+	// IncludeFilePath adds the "file.path" attribute, the path of the file, to the log records.
+	IncludeFilePath bool `mapstructure:"include_file_path"`
+
+	// StorageDirectory is the directory where the read offsets are persisted, so that the
+	// files are read from where they were left after a restart. The offsets are not
+	// persisted if it is not set.
+	StorageDirectory string `mapstructure:"storage_directory"`
+
+	// Operators is the list of operators applied, in order, to each log record. See the
+	// logparser processor for the available operators.
+	Operators []logparser.OperatorConfig `mapstructure:"operators"`
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.NoError(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[typeStr] = factory
+	cfg, err := configtest.LoadConfigFile(t, path.Join(".", "testdata", "config.yaml"), factories)
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, factory.CreateDefaultConfig(), cfg.Receivers["filelog"])
+
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			NameVal: "filelog/app",
+			TypeVal: typeStr,
+		},
+		Include:         []string{"/var/log/app/*.log", "/var/log/app/*.log.1"},
+		Exclude:         []string{"/var/log/app/debug.log"},
+		StartAt:         StartAtBeginning,
+		PollInterval:    time.Second,
+		FingerprintSize: 500,
+		MaxLogSize:      65536,
+		Multiline: MultilineConfig{
+			LineStartPattern: `^\d{4}-\d{2}-\d{2} `,
+		},
+		ForceFlushPeriod: 2 * time.Second,
+		IncludeFileName:  false,
+		IncludeFilePath:  true,
+		StorageDirectory: "/var/lib/otelcol/filelog",
+		Operators: []logparser.OperatorConfig{
+			{
+				Type:  logparser.RegexParser,
+				Regex: `^(?P<time>\S+ \S+) (?P<level>\w+) (?P<message>.*)$`,
+			},
+			{
+				Type:      logparser.SeverityParser,
+				ParseFrom: "attributes.level",
+			},
+		},
+	}, cfg.Receivers["filelog/app"])
+}
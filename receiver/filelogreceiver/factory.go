@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "filelog"
+
+	defaultPollInterval     = 200 * time.Millisecond
+	defaultFingerprintSize  = 1000
+	defaultMaxLogSize       = 1024 * 1024
+	defaultForceFlushPeriod = 500 * time.Millisecond
+)
+
+// NewFactory creates a factory for the file log receiver.
+func NewFactory() component.ReceiverFactory {
+	return receiverhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		receiverhelper.WithLogs(createLogsReceiver))
+}
+
+func createDefaultConfig() configmodels.Receiver {
+	return &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		StartAt:          StartAtEnd,
+		PollInterval:     defaultPollInterval,
+		FingerprintSize:  defaultFingerprintSize,
+		MaxLogSize:       defaultMaxLogSize,
+		ForceFlushPeriod: defaultForceFlushPeriod,
+		IncludeFileName:  true,
+	}
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	r, err := newFileLogReceiver(params.Logger, cfg.(*Config), nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"time"
+)
+
+// maxReadPerPoll bounds the number of bytes read from a file at each poll, so that
+// a large backlog is emitted in several batches.
+const maxReadPerPoll = 4 * 1024 * 1024
+
+// splitConfig defines how the content of the files is split into entries.
+type splitConfig struct {
+	lineStart        *regexp.Regexp
+	maxLogSize       int
+	forceFlushPeriod time.Duration
+}
+
+// reader reads the entries of a file from the offset of the last emitted entry.
+type reader struct {
+	path        string
+	file        *os.File
+	fingerprint []byte
+	offset      int64
+
+	// pendingEnd is the end of the data, after offset, that is not emitted yet because
+	// it is not terminated. pendingSince is the time it was last seen changing.
+	pendingEnd   int64
+	pendingSince time.Time
+}
+
+// readFingerprint returns the first size bytes of the file, or less if it is smaller.
+func readFingerprint(file *os.File, size int) ([]byte, error) {
+	buf := make([]byte, size)
+	n, err := file.ReadAt(buf, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// matches returns true if the fingerprint identifies the same file as the reader,
+// that is the content known by the reader is a prefix of the fingerprint.
+func (r *reader) matches(fingerprint []byte) bool {
+	return len(r.fingerprint) > 0 && bytes.HasPrefix(fingerprint, r.fingerprint)
+}
+
+// read emits the entries written since the last emitted entry. The entry at the end
+// of the file that is not terminated is emitted once it did not change for the
+// force flush period. A file smaller than the offset is considered truncated and read
+// from its beginning.
+//
+// The final read of a file that disappeared emits all the remaining data, unless the
+// file was truncated or its content no longer matches the fingerprint: the new content
+// belongs to another reader.
+func (r *reader) read(cfg *splitConfig, now time.Time, final bool, emit func(entry []byte)) error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+	if final {
+		fingerprint, err := readFingerprint(r.file, len(r.fingerprint))
+		if err != nil || info.Size() < r.offset || !r.matches(fingerprint) {
+			return err
+		}
+	}
+	if info.Size() < r.offset {
+		r.offset = 0
+		r.pendingEnd = 0
+	}
+	if _, err = r.file.Seek(r.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	s := &splitter{cfg: cfg, emit: emit, offset: r.offset, pos: r.offset}
+	br := bufio.NewReader(r.file)
+	for s.pos-r.offset < maxReadPerPoll {
+		line, err := br.ReadBytes('\n')
+		if err == io.EOF {
+			s.endOfFile(line, r, now, final)
+			break
+		}
+		if err != nil {
+			r.offset = s.offset
+			return err
+		}
+		s.line(line)
+	}
+	r.offset = s.offset
+	return nil
+}
+
+// splitter groups the lines read from a file into entries.
+type splitter struct {
+	cfg *splitConfig
+	// emit is called with each entry, it must not retain the entry after returning.
+	emit func(entry []byte)
+	// offset is the end of the last emitted entry and pos the end of the last line read.
+	offset int64
+	pos    int64
+	// entry is the multiline entry that is not emitted yet, it starts at offset.
+	entry   []byte
+	started bool
+}
+
+// line handles a line terminated by a new line.
+func (s *splitter) line(line []byte) {
+	start := s.pos
+	s.pos += int64(len(line))
+	content := trimNewLine(line)
+
+	if s.cfg.lineStart == nil {
+		s.emitEntry(content)
+		s.offset = s.pos
+		return
+	}
+
+	if s.started && s.cfg.lineStart.Match(content) {
+		s.emitEntry(s.entry)
+		s.offset = start
+		s.entry = s.entry[:0]
+		s.started = false
+	}
+	s.appendLine(content)
+	if len(s.entry) >= s.cfg.maxLogSize {
+		s.emitEntry(s.entry)
+		s.offset = s.pos
+		s.entry = s.entry[:0]
+		s.started = false
+	}
+}
+
+// endOfFile handles the data after the last new line, if any, and the multiline entry
+// that is not emitted yet.
+func (s *splitter) endOfFile(remainder []byte, r *reader, now time.Time, final bool) {
+	end := s.pos + int64(len(remainder))
+	if end == s.offset {
+		r.pendingEnd = 0
+		return
+	}
+	if !final && (end != r.pendingEnd || now.Sub(r.pendingSince) < s.cfg.forceFlushPeriod) {
+		if end != r.pendingEnd {
+			r.pendingEnd = end
+			r.pendingSince = now
+		}
+		return
+	}
+
+	if len(remainder) > 0 {
+		if s.cfg.lineStart == nil {
+			s.emitEntry(remainder)
+		} else {
+			if s.started && s.cfg.lineStart.Match(remainder) {
+				s.emitEntry(s.entry)
+				s.entry = s.entry[:0]
+				s.started = false
+			}
+			s.appendLine(remainder)
+		}
+	}
+	if s.started {
+		s.emitEntry(s.entry)
+	}
+	s.offset = end
+	s.pos = end
+	r.pendingEnd = 0
+}
+
+func (s *splitter) appendLine(content []byte) {
+	if s.started {
+		s.entry = append(s.entry, '\n')
+	}
+	s.entry = append(s.entry, content...)
+	s.started = true
+}
+
+// emitEntry emits the entry, split in parts of at most max log size bytes. Empty
+// entries are ignored.
+func (s *splitter) emitEntry(entry []byte) {
+	for len(entry) > s.cfg.maxLogSize {
+		s.emit(entry[:s.cfg.maxLogSize])
+		entry = entry[s.cfg.maxLogSize:]
+	}
+	if len(entry) > 0 {
+		s.emit(entry)
+	}
+}
+
+func trimNewLine(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte{'\n'})
+	return bytes.TrimSuffix(line, []byte{'\r'})
+}
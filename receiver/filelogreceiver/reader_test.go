@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSplitConfig = splitConfig{
+	maxLogSize:       1024,
+	forceFlushPeriod: time.Second,
+}
+
+// testFile creates a file with the given content in a temporary directory.
+func testFile(t *testing.T, dir string, name string, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func appendToFile(t *testing.T, path string, content string) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(content)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+}
+
+func newTestReader(t *testing.T, path string) *reader {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	fingerprint, err := readFingerprint(file, 16)
+	require.NoError(t, err)
+	return &reader{path: path, file: file, fingerprint: fingerprint}
+}
+
+func readEntries(t *testing.T, rd *reader, cfg *splitConfig, now time.Time, final bool) []string {
+	var entries []string
+	require.NoError(t, rd.read(cfg, now, final, func(entry []byte) {
+		entries = append(entries, string(entry))
+	}))
+	return entries
+}
+
+func TestReader_Lines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := testFile(t, dir, "test.log", "first\r\n\nsecond\nthi")
+	rd := newTestReader(t, path)
+	now := time.Unix(1000, 0)
+
+	assert.Equal(t, []string{"first", "second"}, readEntries(t, rd, &testSplitConfig, now, false))
+	assert.Equal(t, int64(15), rd.offset)
+
+	// The unterminated line is kept until it does not change for the force flush period.
+	appendToFile(t, path, "rd")
+	assert.Empty(t, readEntries(t, rd, &testSplitConfig, now.Add(time.Second), false))
+	assert.Empty(t, readEntries(t, rd, &testSplitConfig, now.Add(1500*time.Millisecond), false))
+	assert.Equal(t, []string{"third"}, readEntries(t, rd, &testSplitConfig, now.Add(2*time.Second), false))
+	assert.Equal(t, int64(20), rd.offset)
+
+	appendToFile(t, path, "\nfourth\n")
+	assert.Equal(t, []string{"fourth"}, readEntries(t, rd, &testSplitConfig, now.Add(3*time.Second), false))
+	assert.Empty(t, readEntries(t, rd, &testSplitConfig, now.Add(4*time.Second), false))
+	assert.Equal(t, int64(28), rd.offset)
+}
+
+func TestReader_Multiline(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := testSplitConfig
+	cfg.lineStart = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} `)
+
+	path := testFile(t, dir, "test.log", "header\n2020-11-05 error\n  at main.go:10\n  at main.go:20\n2020-11-05 info\n")
+	rd := newTestReader(t, path)
+	now := time.Unix(1000, 0)
+
+	assert.Equal(t, []string{"header", "2020-11-05 error\n  at main.go:10\n  at main.go:20"}, readEntries(t, rd, &cfg, now, false))
+	assert.Equal(t, int64(56), rd.offset)
+
+	// The last entry is emitted when the next one starts.
+	appendToFile(t, path, "  details\n2020-11-05 warn\n")
+	assert.Equal(t, []string{"2020-11-05 info\n  details"}, readEntries(t, rd, &cfg, now.Add(100*time.Millisecond), false))
+
+	// Or when the file does not change for the force flush period.
+	assert.Empty(t, readEntries(t, rd, &cfg, now.Add(time.Second), false))
+	assert.Equal(t, []string{"2020-11-05 warn"}, readEntries(t, rd, &cfg, now.Add(1100*time.Millisecond), false))
+	assert.Empty(t, readEntries(t, rd, &cfg, now.Add(3*time.Second), false))
+}
+
+func TestReader_MultilineFinal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := testSplitConfig
+	cfg.lineStart = regexp.MustCompile(`^start`)
+
+	path := testFile(t, dir, "test.log", "start 1\ncontinued\nstart 2\ncontinued")
+	rd := newTestReader(t, path)
+	assert.Equal(t, []string{"start 1\ncontinued", "start 2\ncontinued"}, readEntries(t, rd, &cfg, time.Unix(1000, 0), true))
+	assert.Equal(t, int64(35), rd.offset)
+}
+
+func TestReader_MaxLogSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := testSplitConfig
+	cfg.maxLogSize = 4
+
+	path := testFile(t, dir, "test.log", "0123456789\nabc\n")
+	rd := newTestReader(t, path)
+	assert.Equal(t, []string{"0123", "4567", "89", "abc"}, readEntries(t, rd, &cfg, time.Unix(1000, 0), false))
+
+	cfg.lineStart = regexp.MustCompile(`^start`)
+	path = testFile(t, dir, "multiline.log", "start\nab\ncd\n")
+	rd = newTestReader(t, path)
+	assert.Equal(t, []string{"star", "t", "ab\nc", "d"}, readEntries(t, rd, &cfg, time.Unix(1000, 0), true))
+}
+
+func TestReader_Truncated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := testFile(t, dir, "test.log", "first line\nsecond line\n")
+	rd := newTestReader(t, path)
+	assert.Equal(t, []string{"first line", "second line"}, readEntries(t, rd, &testSplitConfig, time.Unix(1000, 0), false))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+	// The final read ignores the truncation, the new content belongs to another reader.
+	assert.Empty(t, readEntries(t, rd, &testSplitConfig, time.Unix(1001, 0), true))
+	assert.Equal(t, []string{"first"}, readEntries(t, rd, &testSplitConfig, time.Unix(1001, 0), false))
+}
+
+func TestReader_FinalReadOfOverwrittenFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := testFile(t, dir, "test.log", "old line\n")
+	rd := newTestReader(t, path)
+	assert.Equal(t, []string{"old line"}, readEntries(t, rd, &testSplitConfig, time.Unix(1000, 0), false))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("a new, longer, line\n"), 0600))
+	assert.Empty(t, readEntries(t, rd, &testSplitConfig, time.Unix(1001, 0), true))
+}
+
+func TestReader_Matches(t *testing.T) {
+	rd := &reader{fingerprint: []byte("abc")}
+	assert.True(t, rd.matches([]byte("abc")))
+	assert.True(t, rd.matches([]byte("abcdef")))
+	assert.False(t, rd.matches([]byte("ab")))
+	assert.False(t, rd.matches([]byte("xbcdef")))
+	assert.False(t, (&reader{}).matches([]byte("abc")))
+}
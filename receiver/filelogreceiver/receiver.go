@@ -0,0 +1,373 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	transport = "file"
+
+	fileNameAttribute = "file.name"
+	filePathAttribute = "file.path"
+)
+
+type fileLogReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.LogsConsumer
+	split        splitConfig
+	pipeline     *logparser.Pipeline
+	checkpoints  *checkpointFile
+	now          func() time.Time
+
+	// readers are the readers of the files found by the last poll. firstPoll is set
+	// until the first poll when no offsets were persisted, the new files found by the
+	// first poll are read from StartAt.
+	readers   []*reader
+	firstPoll bool
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newFileLogReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.LogsConsumer) (*fileLogReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if len(config.Include) == 0 {
+		return nil, errors.New("include must contain at least one pattern")
+	}
+	for _, pattern := range append(append([]string{}, config.Include...), config.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %v", pattern, err)
+		}
+	}
+	if config.StartAt != StartAtBeginning && config.StartAt != StartAtEnd {
+		return nil, fmt.Errorf("invalid start_at %q, must be %q or %q", config.StartAt, StartAtBeginning, StartAtEnd)
+	}
+	if config.PollInterval <= 0 {
+		return nil, errors.New("poll_interval must be positive")
+	}
+	if config.FingerprintSize <= 0 {
+		return nil, errors.New("fingerprint_size must be positive")
+	}
+	if config.MaxLogSize <= 0 {
+		return nil, errors.New("max_log_size must be positive")
+	}
+
+	r := &fileLogReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		split: splitConfig{
+			maxLogSize:       config.MaxLogSize,
+			forceFlushPeriod: config.ForceFlushPeriod,
+		},
+		now:       time.Now,
+		firstPoll: true,
+	}
+	if config.Multiline.LineStartPattern != "" {
+		re, err := regexp.Compile(config.Multiline.LineStartPattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid multiline line_start_pattern: %v", err)
+		}
+		r.split.lineStart = re
+	}
+	if len(config.Operators) > 0 {
+		pipeline, err := logparser.NewPipeline(config.Operators)
+		if err != nil {
+			return nil, err
+		}
+		r.pipeline = pipeline
+	}
+	if config.StorageDirectory != "" {
+		r.checkpoints = newCheckpointFile(config.StorageDirectory, config.Name())
+	}
+	return r, nil
+}
+
+// Start loads the persisted offsets and starts polling the files.
+func (r *fileLogReceiver) Start(_ context.Context, _ component.Host) error {
+	if r.checkpoints != nil {
+		if err := os.MkdirAll(r.config.StorageDirectory, 0700); err != nil {
+			return fmt.Errorf("error creating storage_directory: %v", err)
+		}
+		checkpoints, found, err := r.checkpoints.load()
+		if err != nil {
+			return fmt.Errorf("error loading the offsets from storage_directory: %v", err)
+		}
+		// The files without persisted offset were created while the receiver was stopped.
+		r.firstPoll = !found
+		// The readers without a file are matched with the files found by the first poll.
+		for _, cp := range checkpoints {
+			r.readers = append(r.readers, &reader{path: cp.Path, fingerprint: cp.Fingerprint, offset: cp.Offset})
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.config.PollInterval)
+		defer ticker.Stop()
+		r.poll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.poll(ctx)
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops polling the files and closes them.
+func (r *fileLogReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	for _, rd := range r.readers {
+		if rd.file != nil {
+			rd.file.Close()
+		}
+	}
+	return nil
+}
+
+// poll reads the files matching the configured patterns and emits their new entries.
+// The offsets are only advanced and saved once the entries are consumed, the entries
+// that failed to be consumed are read again at the next poll.
+func (r *fileLogReceiver) poll(ctx context.Context) {
+	now := r.now()
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+
+	matched := r.matchFiles()
+	previous := make(map[*reader]reader, len(r.readers))
+	for _, rd := range r.readers {
+		previous[rd] = *rd
+	}
+
+	var disappeared []*reader
+	for _, rd := range r.readers {
+		// The files that disappeared, e.g. renamed by a rotation to a name that is not
+		// included, are read one last time to not lose the entries written before.
+		if rd.file != nil && !matched[rd] {
+			if err := rd.read(&r.split, now, true, r.appendEntry(rd, now, logs)); err != nil {
+				r.logger.Warn("Failed to read file", zap.String("path", rd.path), zap.Error(err))
+			}
+			disappeared = append(disappeared, rd)
+		}
+	}
+	readers := make([]*reader, 0, len(matched))
+	for _, rd := range r.readers {
+		if matched[rd] {
+			readers = append(readers, rd)
+		}
+	}
+	sort.Slice(readers, func(i, j int) bool { return readers[i].path < readers[j].path })
+	r.readers = readers
+	r.firstPoll = false
+
+	for _, rd := range r.readers {
+		if err := rd.read(&r.split, now, false, r.appendEntry(rd, now, logs)); err != nil {
+			r.logger.Warn("Failed to read file", zap.String("path", rd.path), zap.Error(err))
+		}
+	}
+
+	if logs.Len() > 0 {
+		ctx = obsreport.ReceiverContext(ctx, r.config.Name(), transport)
+		ctx = obsreport.StartLogsReceiveOp(ctx, r.config.Name(), transport)
+		count := logs.Len()
+		err := r.nextConsumer.ConsumeLogs(ctx, ld)
+		obsreport.EndLogsReceiveOp(ctx, "", count, err)
+		if err != nil {
+			r.logger.Error("Failed to consume logs", zap.Error(err))
+			// Keeps the previous offsets, and the files that disappeared open to read
+			// them again.
+			for rd, state := range previous {
+				rd.offset = state.offset
+				rd.pendingEnd = state.pendingEnd
+				rd.pendingSince = state.pendingSince
+			}
+			r.readers = append(r.readers, disappeared...)
+			return
+		}
+	}
+	for _, rd := range disappeared {
+		rd.file.Close()
+	}
+	r.saveCheckpoints()
+}
+
+// matchFiles opens the files matching the configured patterns and associates each of
+// them with a reader, either an existing one with the same fingerprint or a new one.
+// It returns the readers of the files found.
+func (r *fileLogReceiver) matchFiles() map[*reader]bool {
+	matched := make(map[*reader]bool)
+	for _, path := range r.findPaths() {
+		file, err := os.Open(path)
+		if err != nil {
+			r.logger.Warn("Failed to open file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+		fingerprint, err := readFingerprint(file, r.config.FingerprintSize)
+		if err != nil || len(fingerprint) == 0 {
+			// Empty files are identified once they have content.
+			if err != nil {
+				r.logger.Warn("Failed to read file", zap.String("path", path), zap.Error(err))
+			}
+			file.Close()
+			continue
+		}
+
+		var rd *reader
+		duplicate := false
+		for _, candidate := range r.readers {
+			if !candidate.matches(fingerprint) {
+				continue
+			}
+			if matched[candidate] {
+				duplicate = true
+				continue
+			}
+			rd = candidate
+			break
+		}
+		switch {
+		case rd != nil:
+			// The file may have been renamed or copied, follow the file now found at path.
+			if rd.file != nil {
+				rd.file.Close()
+			}
+			rd.path = path
+			rd.file = file
+		case duplicate:
+			// A copy of a file being read, e.g. during a copytruncate rotation, is
+			// ignored until its content differs.
+			file.Close()
+			continue
+		default:
+			rd = &reader{path: path, file: file}
+			if r.firstPoll && r.config.StartAt == StartAtEnd {
+				if info, err := file.Stat(); err == nil {
+					rd.offset = info.Size()
+				}
+			}
+			r.readers = append(r.readers, rd)
+		}
+		rd.fingerprint = fingerprint
+		matched[rd] = true
+	}
+	return matched
+}
+
+// findPaths returns the sorted paths matching the include patterns and none of the
+// exclude patterns.
+func (r *fileLogReceiver) findPaths() []string {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, include := range r.config.Include {
+		matches, _ := filepath.Glob(include)
+		for _, path := range matches {
+			if seen[path] || r.excluded(path) {
+				continue
+			}
+			if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (r *fileLogReceiver) excluded(path string) bool {
+	for _, exclude := range r.config.Exclude {
+		if match, _ := filepath.Match(exclude, path); match {
+			return true
+		}
+	}
+	return false
+}
+
+// appendEntry returns the function appending the entries of a reader to the logs.
+func (r *fileLogReceiver) appendEntry(rd *reader, now time.Time, logs pdata.LogSlice) func(entry []byte) {
+	timestamp := pdata.TimestampUnixNano(now.UnixNano())
+	return func(entry []byte) {
+		lr := pdata.NewLogRecord()
+		lr.InitEmpty()
+		lr.SetTimestamp(timestamp)
+		lr.Body().SetStringVal(string(entry))
+		if r.config.IncludeFileName {
+			lr.Attributes().InsertString(fileNameAttribute, filepath.Base(rd.path))
+		}
+		if r.config.IncludeFilePath {
+			lr.Attributes().InsertString(filePathAttribute, rd.path)
+		}
+		if r.pipeline != nil {
+			keep, errs := r.pipeline.Process(lr)
+			for _, err := range errs {
+				r.logger.Debug("Failed to parse log record", zap.String("path", rd.path), zap.Error(err))
+			}
+			if !keep {
+				return
+			}
+		}
+		logs.Append(lr)
+	}
+}
+
+// saveCheckpoints persists the offsets of the readers.
+func (r *fileLogReceiver) saveCheckpoints() {
+	if r.checkpoints == nil {
+		return
+	}
+	checkpoints := make([]checkpoint, 0, len(r.readers))
+	for _, rd := range r.readers {
+		checkpoints = append(checkpoints, checkpoint{Path: rd.path, Fingerprint: rd.fingerprint, Offset: rd.offset})
+	}
+	if err := r.checkpoints.save(checkpoints); err != nil {
+		r.logger.Error("Failed to save the offsets to storage_directory", zap.Error(err))
+	}
+}
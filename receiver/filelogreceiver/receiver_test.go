@@ -0,0 +1,390 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogreceiver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/processor/logparser"
+)
+
+type testReceiver struct {
+	*fileLogReceiver
+	sink *consumertest.LogsSink
+	now  time.Time
+}
+
+func newTestReceiver(t *testing.T, modify func(cfg *Config)) *testReceiver {
+	cfg := createDefaultConfig().(*Config)
+	if modify != nil {
+		modify(cfg)
+	}
+	sink := new(consumertest.LogsSink)
+	r, err := newFileLogReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	tr := &testReceiver{fileLogReceiver: r, sink: sink, now: time.Unix(1000, 0)}
+	r.now = func() time.Time { return tr.now }
+	return tr
+}
+
+// pollBodies polls the files and returns the bodies of the emitted log records.
+func (tr *testReceiver) pollBodies() []string {
+	tr.sink.Reset()
+	tr.poll(context.Background())
+	tr.now = tr.now.Add(time.Second)
+	return tr.bodies()
+}
+
+// startShutdown starts and shuts down the receiver, which polls the files once, and
+// returns the bodies of the emitted log records.
+func (tr *testReceiver) startShutdown(t *testing.T) []string {
+	tr.config.PollInterval = time.Hour
+	require.NoError(t, tr.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, tr.Shutdown(context.Background()))
+	return tr.bodies()
+}
+
+func (tr *testReceiver) bodies() []string {
+	var bodies []string
+	for _, ld := range tr.sink.AllLogs() {
+		logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < logs.Len(); i++ {
+			bodies = append(bodies, logs.At(i).Body().StringVal())
+		}
+	}
+	return bodies
+}
+
+func newTempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "filelog")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func TestNewFileLogReceiver_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "no_include",
+			modify:      func(cfg *Config) { cfg.Include = nil },
+			errorString: "include must contain at least one pattern",
+		},
+		{
+			name:        "invalid_include",
+			modify:      func(cfg *Config) { cfg.Include = []string{"["} },
+			errorString: `invalid glob pattern "[": syntax error in pattern`,
+		},
+		{
+			name:        "invalid_exclude",
+			modify:      func(cfg *Config) { cfg.Exclude = []string{"a[\\"} },
+			errorString: `invalid glob pattern "a[\\": syntax error in pattern`,
+		},
+		{
+			name:        "invalid_start_at",
+			modify:      func(cfg *Config) { cfg.StartAt = "middle" },
+			errorString: `invalid start_at "middle", must be "beginning" or "end"`,
+		},
+		{
+			name:        "invalid_poll_interval",
+			modify:      func(cfg *Config) { cfg.PollInterval = 0 },
+			errorString: "poll_interval must be positive",
+		},
+		{
+			name:        "invalid_fingerprint_size",
+			modify:      func(cfg *Config) { cfg.FingerprintSize = 0 },
+			errorString: "fingerprint_size must be positive",
+		},
+		{
+			name:        "invalid_max_log_size",
+			modify:      func(cfg *Config) { cfg.MaxLogSize = -1 },
+			errorString: "max_log_size must be positive",
+		},
+		{
+			name:        "invalid_line_start_pattern",
+			modify:      func(cfg *Config) { cfg.Multiline.LineStartPattern = "(" },
+			errorString: "invalid multiline line_start_pattern: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "invalid_operator",
+			modify: func(cfg *Config) {
+				cfg.Operators = []logparser.OperatorConfig{{Type: "unknown"}}
+			},
+			errorString: `operator 0: unknown type "unknown"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Include = []string{"/var/log/*.log"}
+			tc.modify(cfg)
+			r, err := newFileLogReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, r)
+		})
+	}
+
+	r, err := newFileLogReceiver(zap.NewNop(), createDefaultConfig().(*Config), nil)
+	assert.Error(t, err)
+	assert.Nil(t, r)
+}
+
+func TestFileLogReceiver_StartAt(t *testing.T) {
+	dir := newTempDir(t)
+	path := testFile(t, dir, "a.log", "existing\n")
+
+	end := newTestReceiver(t, func(cfg *Config) { cfg.Include = []string{filepath.Join(dir, "*.log")} })
+	defer end.Shutdown(context.Background())
+	beginning := newTestReceiver(t, func(cfg *Config) {
+		cfg.Include = []string{filepath.Join(dir, "*.log")}
+		cfg.StartAt = StartAtBeginning
+	})
+	defer beginning.Shutdown(context.Background())
+
+	assert.Empty(t, end.pollBodies())
+	assert.Equal(t, []string{"existing"}, beginning.pollBodies())
+
+	appendToFile(t, path, "appended\n")
+	// The files created after the first poll are read from their beginning.
+	testFile(t, dir, "b.log", "new file\n")
+	assert.Equal(t, []string{"appended", "new file"}, end.pollBodies())
+	assert.Equal(t, []string{"appended", "new file"}, beginning.pollBodies())
+}
+
+func TestFileLogReceiver_Attributes(t *testing.T) {
+	dir := newTempDir(t)
+	path := testFile(t, dir, "app.log", "2020-11-05T10:15:30Z ERROR failed\nnot matching\n")
+
+	tr := newTestReceiver(t, func(cfg *Config) {
+		cfg.Include = []string{filepath.Join(dir, "*.log")}
+		cfg.StartAt = StartAtBeginning
+		cfg.IncludeFilePath = true
+		cfg.Operators = []logparser.OperatorConfig{
+			{Type: logparser.RegexParser, Regex: `^(?P<time>\S+) (?P<level>\w+) (?P<msg>.*)$`, OnError: logparser.OnErrorDrop},
+			{Type: logparser.SeverityParser, ParseFrom: "attributes.level"},
+			{Type: logparser.TimeParser, ParseFrom: "attributes.time", Layout: time.RFC3339},
+		}
+	})
+	defer tr.Shutdown(context.Background())
+
+	assert.Equal(t, []string{"2020-11-05T10:15:30Z ERROR failed"}, tr.pollBodies())
+	lr := tr.sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.SeverityNumberERROR, lr.SeverityNumber())
+	assert.Equal(t, pdata.TimestampUnixNano(1604571330000000000), lr.Timestamp())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"file.name": pdata.NewAttributeValueString("app.log"),
+		"file.path": pdata.NewAttributeValueString(path),
+		"time":      pdata.NewAttributeValueString("2020-11-05T10:15:30Z"),
+		"level":     pdata.NewAttributeValueString("ERROR"),
+		"msg":       pdata.NewAttributeValueString("failed"),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestFileLogReceiver_Exclude(t *testing.T) {
+	dir := newTempDir(t)
+	testFile(t, dir, "app.log", "included\n")
+	testFile(t, dir, "app.1.log", "excluded\n")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "dir.log"), 0700))
+
+	tr := newTestReceiver(t, func(cfg *Config) {
+		cfg.Include = []string{filepath.Join(dir, "*.log"), filepath.Join(dir, "app.log")}
+		cfg.Exclude = []string{filepath.Join(dir, "*.1.log")}
+		cfg.StartAt = StartAtBeginning
+	})
+	defer tr.Shutdown(context.Background())
+
+	assert.Equal(t, []string{"included"}, tr.pollBodies())
+}
+
+func TestFileLogReceiver_RenameRotation(t *testing.T) {
+	for _, include := range []string{"app.log", "app.log*"} {
+		t.Run(include, func(t *testing.T) {
+			dir := newTempDir(t)
+			path := testFile(t, dir, "app.log", "line 1\n")
+			tr := newTestReceiver(t, func(cfg *Config) {
+				cfg.Include = []string{filepath.Join(dir, include)}
+				cfg.StartAt = StartAtBeginning
+			})
+			defer tr.Shutdown(context.Background())
+			assert.Equal(t, []string{"line 1"}, tr.pollBodies())
+
+			appendToFile(t, path, "line 2\n")
+			require.NoError(t, os.Rename(path, path+".1"))
+			testFile(t, dir, "app.log", "line 3\n")
+			assert.ElementsMatch(t, []string{"line 2", "line 3"}, tr.pollBodies())
+
+			appendToFile(t, path, "line 4\n")
+			assert.Equal(t, []string{"line 4"}, tr.pollBodies())
+		})
+	}
+}
+
+func TestFileLogReceiver_CopyTruncateRotation(t *testing.T) {
+	for _, include := range []string{"app.log", "app.log*"} {
+		t.Run(include, func(t *testing.T) {
+			dir := newTempDir(t)
+			path := testFile(t, dir, "app.log", "line 1\n")
+			tr := newTestReceiver(t, func(cfg *Config) {
+				cfg.Include = []string{filepath.Join(dir, include)}
+				cfg.StartAt = StartAtBeginning
+			})
+			defer tr.Shutdown(context.Background())
+			assert.Equal(t, []string{"line 1"}, tr.pollBodies())
+
+			appendToFile(t, path, "line 2\n")
+			testFile(t, dir, "app.log.1", "line 1\nline 2\n")
+			require.NoError(t, os.Truncate(path, 0))
+			appendToFile(t, path, "line 3\n")
+
+			bodies := tr.pollBodies()
+			if include == "app.log*" {
+				// The copy is read from where the original file was left.
+				assert.Equal(t, []string{"line 3", "line 2"}, bodies)
+			} else {
+				// The lines written just before the copy are lost when the copy is not included.
+				assert.Equal(t, []string{"line 3"}, bodies)
+			}
+
+			appendToFile(t, path, "line 4\n")
+			assert.Equal(t, []string{"line 4"}, tr.pollBodies())
+		})
+	}
+}
+
+func TestFileLogReceiver_CopyBeforeTruncate(t *testing.T) {
+	dir := newTempDir(t)
+	path := testFile(t, dir, "app.log", "line 1\n")
+	tr := newTestReceiver(t, func(cfg *Config) {
+		cfg.Include = []string{filepath.Join(dir, "app.log*")}
+		cfg.StartAt = StartAtBeginning
+	})
+	defer tr.Shutdown(context.Background())
+	assert.Equal(t, []string{"line 1"}, tr.pollBodies())
+
+	// The copy is ignored while it is identical to the original file.
+	testFile(t, dir, "app.log.1", "line 1\n")
+	assert.Empty(t, tr.pollBodies())
+
+	require.NoError(t, os.Truncate(path, 0))
+	assert.Empty(t, tr.pollBodies())
+	appendToFile(t, path, "line 2\n")
+	assert.Equal(t, []string{"line 2"}, tr.pollBodies())
+}
+
+func TestFileLogReceiver_ConsumeError(t *testing.T) {
+	dir := newTempDir(t)
+	path := testFile(t, dir, "app.log", "line 1\n")
+	tr := newTestReceiver(t, func(cfg *Config) {
+		cfg.Include = []string{filepath.Join(dir, "app.log")}
+		cfg.StartAt = StartAtBeginning
+		cfg.StorageDirectory = newTempDir(t)
+	})
+	defer tr.Shutdown(context.Background())
+	assert.Equal(t, []string{"line 1"}, tr.pollBodies())
+	saved, _, err := tr.checkpoints.load()
+	require.NoError(t, err)
+
+	appendToFile(t, path, "line 2\n")
+	require.NoError(t, os.Rename(path, path+".1"))
+	testFile(t, dir, "app.log", "line 3\n")
+	tr.sink.SetConsumeError(errors.New("consume error"))
+	assert.Empty(t, tr.pollBodies())
+
+	// The offsets are neither advanced nor saved, the entries are read again, including
+	// the ones of the file that disappeared.
+	checkpoints, _, err := tr.checkpoints.load()
+	require.NoError(t, err)
+	assert.Equal(t, saved, checkpoints)
+	tr.sink.SetConsumeError(nil)
+	assert.ElementsMatch(t, []string{"line 2", "line 3"}, tr.pollBodies())
+	assert.Empty(t, tr.pollBodies())
+}
+
+func TestFileLogReceiver_Checkpoints(t *testing.T) {
+	dir := newTempDir(t)
+	storage := filepath.Join(newTempDir(t), "offsets")
+	path := testFile(t, dir, "app.log", "line 1\n")
+	modify := func(cfg *Config) {
+		cfg.Include = []string{filepath.Join(dir, "*.log")}
+		cfg.StorageDirectory = storage
+	}
+
+	assert.Empty(t, newTestReceiver(t, modify).startShutdown(t))
+
+	// The lines written while the receiver was stopped are read from the persisted offset.
+	appendToFile(t, path, "line 2\nline 3\npartial")
+	assert.Equal(t, []string{"line 2", "line 3"}, newTestReceiver(t, modify).startShutdown(t))
+
+	// Nothing is read again after a restart, except the unterminated line.
+	appendToFile(t, path, " line\n")
+	assert.Equal(t, []string{"partial line"}, newTestReceiver(t, modify).startShutdown(t))
+	assert.Empty(t, newTestReceiver(t, modify).startShutdown(t))
+
+	// A file truncated while the receiver was stopped is read from its beginning.
+	require.NoError(t, ioutil.WriteFile(path, []byte("line 1\n"), 0600))
+	assert.Equal(t, []string{"line 1"}, newTestReceiver(t, modify).startShutdown(t))
+
+	// The files created while the receiver was stopped are read from their beginning.
+	testFile(t, dir, "other.log", "other line\n")
+	assert.Equal(t, []string{"other line"}, newTestReceiver(t, modify).startShutdown(t))
+}
+
+func TestFileLogReceiver_InvalidCheckpoints(t *testing.T) {
+	storage := newTempDir(t)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(storage, "filelog.json"), []byte("{"), 0600))
+
+	tr := newTestReceiver(t, func(cfg *Config) {
+		cfg.Include = []string{"/nonexistent/*.log"}
+		cfg.StorageDirectory = storage
+	})
+	assert.Error(t, tr.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestFileLogReceiver_StartShutdown(t *testing.T) {
+	dir := newTempDir(t)
+	path := testFile(t, dir, "app.log", "")
+
+	sink := new(consumertest.LogsSink)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "*.log")}
+	cfg.StartAt = StartAtBeginning
+	cfg.PollInterval = 10 * time.Millisecond
+	r, err := NewFactory().CreateLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	appendToFile(t, path, "line 1\nline 2\n")
+	assert.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,33 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines the configuration for the InfluxDB receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// HTTPServerSettings are the settings of the HTTP server, the points are written to
+	// the /write and /api/v2/write paths.
+	confighttp.HTTPServerSettings `mapstructure:",squash"`
+
+	// MaxRequestBodySize is the maximum size in bytes of the body of a write request, once
+	// decompressed. The larger requests are rejected. The size is not limited if it is zero.
+	MaxRequestBodySize int64 `mapstructure:"max_request_body_size"`
+}
@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// fieldType is the type of the value of a field.
+type fieldType int
+
+const (
+	floatField fieldType = iota
+	intField
+	uintField
+	boolField
+	stringField
+)
+
+// The characters escaped with a backslash in the measurements, and in the keys and the
+// tag values.
+const (
+	measurementEscapes = ", "
+	keyEscapes         = ",= "
+)
+
+type tag struct {
+	key   string
+	value string
+}
+
+type field struct {
+	key       string
+	fieldType fieldType
+	floatVal  float64
+	intVal    int64
+	uintVal   uint64
+	boolVal   bool
+	stringVal string
+}
+
+// point is a line of the line protocol.
+type point struct {
+	measurement string
+	tags        []tag
+	fields      []field
+	// timestamp is in the precision of the request, it is only set if hasTimestamp.
+	timestamp    int64
+	hasTimestamp bool
+}
+
+// parsePoint parses a line of the form:
+//
+//	<measurement>[,<tag key>=<tag value>...] <field key>=<field value>[,<field key>=<field value>...] [<timestamp>]
+//
+// The commas and the spaces are escaped with a backslash in the measurements, and the
+// commas, the spaces and the equal signs in the keys and the tag values. The string field
+// values are between double quotes, their double quotes and backslashes are escaped.
+func parsePoint(line string) (point, error) {
+	var p point
+	var i int
+	p.measurement, i = scanUntil(line, 0, ", ", measurementEscapes)
+	if p.measurement == "" {
+		return p, errors.New("missing measurement")
+	}
+
+	for i < len(line) && line[i] == ',' {
+		var t tag
+		t.key, i = scanUntil(line, i+1, ",= ", keyEscapes)
+		if i >= len(line) || line[i] != '=' {
+			return p, fmt.Errorf("missing value of tag %q", t.key)
+		}
+		t.value, i = scanUntil(line, i+1, ", ", keyEscapes)
+		if t.key == "" || t.value == "" {
+			return p, errors.New("invalid tag")
+		}
+		p.tags = append(p.tags, t)
+	}
+
+	i = skipSpaces(line, i)
+	if i >= len(line) {
+		return p, errors.New("missing fields")
+	}
+	for {
+		var f field
+		var err error
+		f.key, i = scanUntil(line, i, ",= ", keyEscapes)
+		if i >= len(line) || line[i] != '=' {
+			return p, fmt.Errorf("missing value of field %q", f.key)
+		}
+		if f.key == "" {
+			return p, errors.New("missing field key")
+		}
+		if f, i, err = parseFieldValue(line, i+1, f); err != nil {
+			return p, err
+		}
+		p.fields = append(p.fields, f)
+		if i >= len(line) || line[i] != ',' {
+			break
+		}
+		i++
+	}
+
+	if rest := strings.TrimSpace(line[i:]); rest != "" {
+		ts, err := strconv.ParseInt(rest, 10, 64)
+		if err != nil {
+			return p, fmt.Errorf("invalid timestamp %q", rest)
+		}
+		p.timestamp, p.hasTimestamp = ts, true
+	}
+	return p, nil
+}
+
+// parseFieldValue parses the value of a field starting at i, and returns the index
+// following it.
+func parseFieldValue(line string, i int, f field) (field, int, error) {
+	if i < len(line) && line[i] == '"' {
+		var b strings.Builder
+		for i++; i < len(line); i++ {
+			switch {
+			case line[i] == '\\' && i+1 < len(line) && (line[i+1] == '"' || line[i+1] == '\\'):
+				i++
+				b.WriteByte(line[i])
+			case line[i] == '"':
+				f.fieldType, f.stringVal = stringField, b.String()
+				return f, i + 1, nil
+			default:
+				b.WriteByte(line[i])
+			}
+		}
+		return f, i, fmt.Errorf("unterminated string value of field %q", f.key)
+	}
+
+	end := i
+	for end < len(line) && line[end] != ',' && line[end] != ' ' {
+		end++
+	}
+	value := line[i:end]
+	var err error
+	switch value {
+	case "t", "T", "true", "True", "TRUE":
+		f.fieldType, f.boolVal = boolField, true
+	case "f", "F", "false", "False", "FALSE":
+		f.fieldType, f.boolVal = boolField, false
+	default:
+		switch {
+		case strings.HasSuffix(value, "i"):
+			f.fieldType = intField
+			f.intVal, err = strconv.ParseInt(value[:len(value)-1], 10, 64)
+		case strings.HasSuffix(value, "u"):
+			f.fieldType = uintField
+			f.uintVal, err = strconv.ParseUint(value[:len(value)-1], 10, 64)
+		default:
+			f.fieldType = floatField
+			f.floatVal, err = strconv.ParseFloat(value, 64)
+			if err == nil && (math.IsNaN(f.floatVal) || math.IsInf(f.floatVal, 0)) {
+				err = errors.New("not a finite number")
+			}
+		}
+	}
+	if err != nil {
+		return f, end, fmt.Errorf("invalid value %q of field %q", value, f.key)
+	}
+	return f, end, nil
+}
+
+// scanUntil returns the unescaped text starting at i until the first unescaped delimiter,
+// and the index of the delimiter, or the length of the line if there is none. Only the
+// characters of escapes are unescaped, the other backslashes are kept.
+func scanUntil(line string, i int, delimiters string, escapes string) (string, int) {
+	var b strings.Builder
+	for ; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) && strings.IndexByte(escapes, line[i+1]) >= 0 {
+			i++
+			b.WriteByte(line[i])
+			continue
+		}
+		if strings.IndexByte(delimiters, c) >= 0 {
+			break
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), i
+}
+
+func skipSpaces(line string, i int) int {
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePoint(t *testing.T) {
+	testcases := []struct {
+		line     string
+		expected point
+	}{
+		{
+			line: "cpu value=0.64",
+			expected: point{
+				measurement: "cpu",
+				fields:      []field{{key: "value", fieldType: floatField, floatVal: 0.64}},
+			},
+		},
+		{
+			line: "cpu,host=server01,region=us-west usage_idle=97.5,usage_user=1e1 1465839830100400200",
+			expected: point{
+				measurement:  "cpu",
+				tags:         []tag{{key: "host", value: "server01"}, {key: "region", value: "us-west"}},
+				fields:       []field{{key: "usage_idle", fieldType: floatField, floatVal: 97.5}, {key: "usage_user", fieldType: floatField, floatVal: 10}},
+				timestamp:    1465839830100400200,
+				hasTimestamp: true,
+			},
+		},
+		{
+			line: "disk free=-12i,total=100u,mounted=t,ro=FALSE,path=\"/var \\\"log\\\" \\\\ ,=\" -10",
+			expected: point{
+				measurement: "disk",
+				fields: []field{
+					{key: "free", fieldType: intField, intVal: -12},
+					{key: "total", fieldType: uintField, uintVal: 100},
+					{key: "mounted", fieldType: boolField, boolVal: true},
+					{key: "ro", fieldType: boolField, boolVal: false},
+					{key: "path", fieldType: stringField, stringVal: `/var "log" \ ,=`},
+				},
+				timestamp:    -10,
+				hasTimestamp: true,
+			},
+		},
+		{
+			line: `my\ measure\,ment,tag\ key\,\==tag\ value\,\= field\ key\,\==1`,
+			expected: point{
+				measurement: "my measure,ment",
+				tags:        []tag{{key: "tag key,=", value: "tag value,="}},
+				fields:      []field{{key: "field key,=", fieldType: floatField, floatVal: 1}},
+			},
+		},
+		{
+			line: `path\=x,dir=C:\temp value=1   1000  `,
+			expected: point{
+				measurement:  `path\=x`,
+				tags:         []tag{{key: "dir", value: `C:\temp`}},
+				fields:       []field{{key: "value", fieldType: floatField, floatVal: 1}},
+				timestamp:    1000,
+				hasTimestamp: true,
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			p, err := parsePoint(tc.line)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p)
+		})
+	}
+}
+
+func TestParsePoint_Errors(t *testing.T) {
+	testcases := []struct {
+		line        string
+		errorString string
+	}{
+		{line: ",host=a value=1", errorString: "missing measurement"},
+		{line: "cpu,host value=1", errorString: `missing value of tag "host"`},
+		{line: "cpu,host= value=1", errorString: "invalid tag"},
+		{line: "cpu,=a value=1", errorString: "invalid tag"},
+		{line: "cpu", errorString: "missing fields"},
+		{line: "cpu,host=a", errorString: "missing fields"},
+		{line: "cpu value", errorString: `missing value of field "value"`},
+		{line: "cpu =1", errorString: "missing field key"},
+		{line: "cpu value=1,", errorString: `missing value of field ""`},
+		{line: "cpu value=abc", errorString: `invalid value "abc" of field "value"`},
+		{line: "cpu value=NaN", errorString: `invalid value "NaN" of field "value"`},
+		{line: "cpu value=1.5i", errorString: `invalid value "1.5i" of field "value"`},
+		{line: "cpu value=-1u", errorString: `invalid value "-1u" of field "value"`},
+		{line: "cpu value=", errorString: `invalid value "" of field "value"`},
+		{line: `cpu value="abc`, errorString: `unterminated string value of field "value"`},
+		{line: "cpu value=1 now", errorString: `invalid timestamp "now"`},
+		{line: "cpu value=1 1000 2000", errorString: `invalid timestamp "1000 2000"`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			_, err := parsePoint(tc.line)
+			assert.EqualError(t, err, tc.errorString)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// valueField is the field whose metric is named after the measurement only.
+const valueField = "value"
+
+var errTimestampOutOfRange = errors.New("timestamp out of range")
+
+// metricsBuilder converts the fields of the points to gauges named <measurement>_<field>,
+// or <measurement> for the value fields. The fields with the same metric name and type
+// are data points of the same metric.
+type metricsBuilder struct {
+	metrics pdata.MetricSlice
+	byKey   map[string]pdata.Metric
+	// now is the timestamp of the points without timestamp.
+	now time.Time
+	// precision is the duration of a unit of the timestamps.
+	precision time.Duration
+}
+
+func newMetricsBuilder(now time.Time, precision time.Duration) *metricsBuilder {
+	return &metricsBuilder{
+		metrics:   pdata.NewMetricSlice(),
+		byKey:     map[string]pdata.Metric{},
+		now:       now,
+		precision: precision,
+	}
+}
+
+// addPoint adds a data point for each numeric or boolean field of the point, the string
+// fields are ignored. The point is rejected if its timestamp in nanoseconds does not fit
+// in 64 bits.
+func (b *metricsBuilder) addPoint(p point) error {
+	timestamp := pdata.TimestampUnixNano(b.now.UnixNano())
+	if p.hasTimestamp {
+		precision := int64(b.precision)
+		if p.timestamp > math.MaxInt64/precision || p.timestamp < math.MinInt64/precision {
+			return errTimestampOutOfRange
+		}
+		timestamp = pdata.TimestampUnixNano(p.timestamp * precision)
+	}
+	for _, f := range p.fields {
+		name := p.measurement
+		if f.key != valueField {
+			name += "_" + f.key
+		}
+		switch f.fieldType {
+		case floatField:
+			dp := pdata.NewDoubleDataPoint()
+			dp.InitEmpty()
+			fillLabels(dp.LabelsMap(), p.tags)
+			dp.SetTimestamp(timestamp)
+			dp.SetValue(f.floatVal)
+			b.metric(name, pdata.MetricDataTypeDoubleGauge).DoubleGauge().DataPoints().Append(dp)
+		case intField, uintField, boolField:
+			dp := pdata.NewIntDataPoint()
+			dp.InitEmpty()
+			fillLabels(dp.LabelsMap(), p.tags)
+			dp.SetTimestamp(timestamp)
+			dp.SetValue(intValue(f))
+			b.metric(name, pdata.MetricDataTypeIntGauge).IntGauge().DataPoints().Append(dp)
+		}
+	}
+	return nil
+}
+
+func (b *metricsBuilder) metric(name string, dataType pdata.MetricDataType) pdata.Metric {
+	key := dataType.String() + "/" + name
+	if metric, ok := b.byKey[key]; ok {
+		return metric
+	}
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeDoubleGauge:
+		metric.DoubleGauge().InitEmpty()
+	case pdata.MetricDataTypeIntGauge:
+		metric.IntGauge().InitEmpty()
+	}
+	b.metrics.Append(metric)
+	b.byKey[key] = metric
+	return metric
+}
+
+// build returns the metrics, or empty metrics if no point had a numeric field.
+func (b *metricsBuilder) build() pdata.Metrics {
+	md := pdata.NewMetrics()
+	if b.metrics.Len() == 0 {
+		return md
+	}
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	b.metrics.MoveAndAppendTo(rm.InstrumentationLibraryMetrics().At(0).Metrics())
+	return md
+}
+
+// intValue converts an integer or boolean field, the unsigned integers greater than the
+// maximum signed integer are capped.
+func intValue(f field) int64 {
+	switch f.fieldType {
+	case uintField:
+		if f.uintVal > math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return int64(f.uintVal)
+	case boolField:
+		if f.boolVal {
+			return 1
+		}
+		return 0
+	}
+	return f.intVal
+}
+
+func fillLabels(labels pdata.StringMap, tags []tag) {
+	for _, t := range tags {
+		labels.Upsert(t.key, t.value)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	transport = "http"
+	format    = "influxdb_line_protocol"
+
+	// maxParseErrors is the maximum number of lines that failed to be parsed reported
+	// in a response.
+	maxParseErrors = 10
+)
+
+// precisions are the durations of the units of the timestamps by value of the precision
+// parameter, of the InfluxDB 1.x and 2.x APIs.
+var precisions = map[string]time.Duration{
+	"":   time.Nanosecond,
+	"n":  time.Nanosecond,
+	"ns": time.Nanosecond,
+	"u":  time.Microsecond,
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+type influxDBReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	now          func() time.Time
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	server    *http.Server
+}
+
+func newInfluxDBReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.MetricsConsumer) (*influxDBReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint must be specified")
+	}
+	return &influxDBReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		now:          time.Now,
+	}, nil
+}
+
+// Start listens for the write requests.
+func (r *influxDBReceiver) Start(_ context.Context, host component.Host) error {
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		listener, lerr := r.config.HTTPServerSettings.ToListener()
+		if lerr != nil {
+			err = fmt.Errorf("error listening on influxdb endpoint: %v", lerr)
+			return
+		}
+		err = nil
+		r.server = r.config.HTTPServerSettings.ToServer(r.handler())
+		go func() {
+			if serr := r.server.Serve(listener); serr != nil && serr != http.ErrServerClosed {
+				host.ReportFatalError(serr)
+			}
+		}()
+	})
+	return err
+}
+
+// Shutdown stops the HTTP server.
+func (r *influxDBReceiver) Shutdown(context.Context) error {
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.server != nil {
+			err = r.server.Close()
+		}
+	})
+	return err
+}
+
+func (r *influxDBReceiver) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/write", r.handleWrite)
+	mux.HandleFunc("/api/v2/write", r.handleWrite)
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// handleWrite handles a write request. As InfluxDB does, the points that can be parsed
+// are written even if other lines of the request cannot, and the request is answered with
+// a bad request error reporting these lines.
+func (r *influxDBReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, "method not allowed, supported: [POST]", http.StatusMethodNotAllowed)
+		return
+	}
+	precision, ok := precisions[req.URL.Query().Get("precision")]
+	if !ok {
+		writeError(w, fmt.Sprintf("invalid precision %q", req.URL.Query().Get("precision")), http.StatusBadRequest)
+		return
+	}
+	reqBody := req.Body
+	if r.config.MaxRequestBodySize > 0 {
+		reqBody = http.MaxBytesReader(w, req.Body, r.config.MaxRequestBodySize)
+	}
+	body, err := ioutil.ReadAll(reqBody)
+	if err != nil {
+		// The reader only fails once the limit is reached if the body is too large.
+		if r.config.MaxRequestBodySize > 0 && int64(len(body)) >= r.config.MaxRequestBodySize {
+			writeError(w, fmt.Sprintf("request body larger than %d bytes", r.config.MaxRequestBodySize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeError(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := req.Context()
+	if c, ok := client.FromHTTP(req); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	ctx = obsreport.ReceiverContext(ctx, r.config.Name(), transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+
+	builder := newMetricsBuilder(r.now(), precision)
+	var parseErrors []string
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		p, perr := parsePoint(line)
+		if perr == nil {
+			perr = builder.addPoint(p)
+		}
+		if perr != nil {
+			parseErrors = append(parseErrors, fmt.Sprintf("unable to parse '%s': %v", line, perr))
+		}
+	}
+
+	md := builder.build()
+	_, numPoints := md.MetricAndDataPointCount()
+	if numPoints > 0 {
+		err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	obsreport.EndMetricsReceiveOp(ctx, format, numPoints, err)
+	if err != nil {
+		r.logger.Debug("Failed to consume influxdb points", zap.Error(err))
+		status := http.StatusInternalServerError
+		if consumererror.IsPermanent(err) {
+			status = http.StatusBadRequest
+		}
+		writeError(w, err.Error(), status)
+		return
+	}
+
+	if len(parseErrors) > 0 {
+		r.logger.Debug("Failed to parse influxdb lines", zap.Strings("errors", parseErrors))
+		if len(parseErrors) > maxParseErrors {
+			parseErrors = append(parseErrors[:maxParseErrors], fmt.Sprintf("and %d more", len(parseErrors)-maxParseErrors))
+		}
+		writeError(w, "partial write: "+strings.Join(parseErrors, "; "), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeError answers with an error in the format of the InfluxDB API.
+func writeError(w http.ResponseWriter, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Influxdb-Error", message)
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
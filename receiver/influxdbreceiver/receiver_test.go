@@ -0,0 +1,263 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package influxdbreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+var testNow = time.Date(2020, 11, 5, 12, 0, 0, 0, time.UTC)
+
+// newTestServer serves the receiver handler as the receiver does, with the decompression
+// of the requests.
+func newTestServer(t *testing.T) (*httptest.Server, *consumertest.MetricsSink) {
+	return newTestServerWithConfig(t, createDefaultConfig().(*Config))
+}
+
+func newTestServerWithConfig(t *testing.T, cfg *Config) (*httptest.Server, *consumertest.MetricsSink) {
+	sink := new(consumertest.MetricsSink)
+	r, err := newInfluxDBReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r.now = func() time.Time { return testNow }
+	server := httptest.NewServer(cfg.HTTPServerSettings.ToServer(r.handler()).Handler)
+	t.Cleanup(server.Close)
+	return server, sink
+}
+
+func write(t *testing.T, url string, body string) (int, string) {
+	resp, err := http.Post(url, "text/plain; charset=utf-8", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func receivedMetrics(t *testing.T, sink *consumertest.MetricsSink) pdata.MetricSlice {
+	require.Len(t, sink.AllMetrics(), 1)
+	return sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func TestInfluxDBReceiver_Write(t *testing.T) {
+	server, sink := newTestServer(t)
+
+	status, _ := write(t, server.URL+"/write?db=telegraf", strings.Join([]string{
+		"# comment",
+		"temperature,sensor=s1,room=kitchen value=21.5 1604577600000000000",
+		"",
+		"temperature,sensor=s2 value=19 1604577601000000000",
+		"battery,sensor=s1 level=87i,charging=true,voltage=3.7,model=\"x\"",
+	}, "\n"))
+	assert.Equal(t, http.StatusNoContent, status)
+
+	metrics := receivedMetrics(t, sink)
+	require.Equal(t, 4, metrics.Len())
+
+	temperature := metrics.At(0)
+	assert.Equal(t, "temperature", temperature.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, temperature.DataType())
+	points := temperature.DoubleGauge().DataPoints()
+	require.Equal(t, 2, points.Len())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"sensor": "s1", "room": "kitchen"}).Sort(), points.At(0).LabelsMap().Sort())
+	assert.Equal(t, pdata.TimestampUnixNano(1604577600000000000), points.At(0).Timestamp())
+	assert.Equal(t, 21.5, points.At(0).Value())
+	assert.Equal(t, pdata.TimestampUnixNano(1604577601000000000), points.At(1).Timestamp())
+
+	level := metrics.At(1)
+	assert.Equal(t, "battery_level", level.Name())
+	require.Equal(t, pdata.MetricDataTypeIntGauge, level.DataType())
+	assert.Equal(t, int64(87), level.IntGauge().DataPoints().At(0).Value())
+	// The points without timestamp are received now.
+	assert.Equal(t, pdata.TimestampUnixNano(testNow.UnixNano()), level.IntGauge().DataPoints().At(0).Timestamp())
+
+	charging := metrics.At(2)
+	assert.Equal(t, "battery_charging", charging.Name())
+	assert.Equal(t, int64(1), charging.IntGauge().DataPoints().At(0).Value())
+
+	voltage := metrics.At(3)
+	assert.Equal(t, "battery_voltage", voltage.Name())
+	assert.Equal(t, 3.7, voltage.DoubleGauge().DataPoints().At(0).Value())
+}
+
+func TestInfluxDBReceiver_Precision(t *testing.T) {
+	testcases := []struct {
+		precision string
+		timestamp int64
+	}{
+		{precision: "ns", timestamp: 1604581200000000000},
+		{precision: "n", timestamp: 1604581200000000000},
+		{precision: "us", timestamp: 1604581200000000},
+		{precision: "u", timestamp: 1604581200000000},
+		{precision: "ms", timestamp: 1604581200000},
+		{precision: "s", timestamp: 1604581200},
+		{precision: "m", timestamp: 26743020},
+		{precision: "h", timestamp: 445717},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.precision, func(t *testing.T) {
+			server, sink := newTestServer(t)
+			status, _ := write(t, server.URL+"/api/v2/write?precision="+tc.precision, "cpu value=1 "+strconv.FormatInt(tc.timestamp, 10))
+			assert.Equal(t, http.StatusNoContent, status)
+			point := receivedMetrics(t, sink).At(0).DoubleGauge().DataPoints().At(0)
+			assert.Equal(t, pdata.TimestampUnixNano(1604581200*time.Second), point.Timestamp())
+		})
+	}
+
+	server, _ := newTestServer(t)
+	status, body := write(t, server.URL+"/write?precision=d", "cpu value=1 1")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"error": "invalid precision \"d\""}`, body)
+}
+
+func TestInfluxDBReceiver_TimestampOutOfRange(t *testing.T) {
+	server, sink := newTestServer(t)
+
+	// The timestamp in nanoseconds would overflow.
+	status, body := write(t, server.URL+"/write?precision=s", "cpu value=1 1604581200\ncpu value=2 9223372037\ncpu value=3 -9223372037")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"error": "partial write: unable to parse 'cpu value=2 9223372037': timestamp out of range; unable to parse 'cpu value=3 -9223372037': timestamp out of range"}`, body)
+	points := receivedMetrics(t, sink).At(0).DoubleGauge().DataPoints()
+	require.Equal(t, 1, points.Len())
+	assert.Equal(t, 1.0, points.At(0).Value())
+}
+
+func TestInfluxDBReceiver_MaxRequestBodySize(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxRequestBodySize = 32
+	server, sink := newTestServerWithConfig(t, cfg)
+
+	status, _ := write(t, server.URL+"/write", "cpu value=1 1604581200000000000")
+	assert.Equal(t, http.StatusNoContent, status)
+
+	sink.Reset()
+	status, body := write(t, server.URL+"/write", "cpu value=1 1604581200000000000\ncpu value=2 1604581200000000000")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.JSONEq(t, `{"error": "request body larger than 32 bytes"}`, body)
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestInfluxDBReceiver_Gzip(t *testing.T) {
+	server, sink := newTestServer(t)
+
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	_, err := gw.Write([]byte("cpu,host=a value=1 1604577600000000000\n"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/write", &buf)
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 1.0, receivedMetrics(t, sink).At(0).DoubleGauge().DataPoints().At(0).Value())
+}
+
+func TestInfluxDBReceiver_PartialWrite(t *testing.T) {
+	server, sink := newTestServer(t)
+
+	status, body := write(t, server.URL+"/write", "cpu value=1\ncpu value=abc\nmem,host used=1")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.JSONEq(t, `{"error": "partial write: unable to parse 'cpu value=abc': invalid value \"abc\" of field \"value\"; unable to parse 'mem,host used=1': missing value of tag \"host\""}`, body)
+	assert.Equal(t, 1, receivedMetrics(t, sink).At(0).DoubleGauge().DataPoints().Len())
+
+	// The requests without points are not consumed.
+	sink.Reset()
+	status, _ = write(t, server.URL+"/write", "event message=\"started\"\n")
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestInfluxDBReceiver_ConsumerErrors(t *testing.T) {
+	server, sink := newTestServer(t)
+
+	sink.SetConsumeError(errors.New("queue is full"))
+	status, body := write(t, server.URL+"/write", "cpu value=1")
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.JSONEq(t, `{"error": "queue is full"}`, body)
+
+	sink.SetConsumeError(consumererror.Permanent(errors.New("invalid metric")))
+	status, _ = write(t, server.URL+"/write", "cpu value=1")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestInfluxDBReceiver_Endpoints(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, err = http.Get(server.URL + "/write")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	status, _ := write(t, server.URL+"/query", "cpu value=1")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestInfluxDBReceiver_StartShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	sink := new(consumertest.MetricsSink)
+	r, err := newInfluxDBReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	status, _ := write(t, "http://"+cfg.Endpoint+"/write", "cpu value=1")
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, 1, sink.MetricsCount())
+
+	// The endpoint is already in use.
+	other, err := newInfluxDBReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	assert.Error(t, other.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.Error(t, r.Shutdown(context.Background()))
+}
+
+func TestNewInfluxDBReceiver_InvalidConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ""
+	r, err := newInfluxDBReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	assert.EqualError(t, err, "endpoint must be specified")
+	assert.Nil(t, r)
+}
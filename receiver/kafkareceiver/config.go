@@ -15,6 +15,8 @@
 package kafkareceiver
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
 )
@@ -26,7 +28,8 @@ type Config struct {
 	Brokers []string `mapstructure:"brokers"`
 	// Kafka protocol version
 	ProtocolVersion string `mapstructure:"protocol_version"`
-	// The name of the kafka topic to consume from (default "otlp_spans")
+	// The name of the kafka topic to consume from (default "otlp_spans" for traces,
+	// "otlp_metrics" for metrics and "otlp_logs" for logs)
 	Topic string `mapstructure:"topic"`
 	// Encoding of the messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
@@ -34,6 +37,18 @@ type Config struct {
 	GroupID string `mapstructure:"group_id"`
 	// The consumer client ID that receiver will use (default "otel-collector")
 	ClientID string `mapstructure:"client_id"`
+	// The offset to start consuming from when the consumer group has no committed offset,
+	// "newest" or "oldest" (default "newest")
+	InitialOffset string `mapstructure:"initial_offset"`
+	// The timeout used to detect consumer failures, the consumer is removed from the group
+	// when the broker receives no heartbeat before it expires (default 10s)
+	SessionTimeout time.Duration `mapstructure:"session_timeout"`
+	// The strategy used to assign the partitions to the members of the consumer group,
+	// "range", "roundrobin" or "sticky" (default "range")
+	RebalanceStrategy string `mapstructure:"rebalance_strategy"`
+
+	// MessageMarking controls when the offsets of the consumed messages are marked.
+	MessageMarking MessageMarking `mapstructure:"message_marking"`
 
 	// Metadata is the namespace for metadata management properties used by the
 	// Client, and shared by the Producer/Consumer.
@@ -41,3 +56,14 @@ type Config struct {
 
 	Authentication kafkaexporter.Authentication `mapstructure:"auth"`
 }
+
+// MessageMarking defines when the offsets of the consumed messages are marked, marked
+// offsets are periodically committed to kafka.
+type MessageMarking struct {
+	// If true, the offset of a message is marked only after the data was accepted by the
+	// next consumer, providing at-least-once delivery: a message that fails with a retryable
+	// error is not marked and is received again after the consumer group session restarts.
+	// The messages that cannot be unmarshalled or that fail with a permanent error are marked.
+	// By default the offset is marked as soon as the message is received (at-most-once).
+	After bool `mapstructure:"after"`
+}
@@ -45,11 +45,17 @@ func TestLoadConfig(t *testing.T) {
 			NameVal: typeStr,
 			TypeVal: typeStr,
 		},
-		Topic:    "spans",
-		Encoding: "otlp_proto",
-		Brokers:  []string{"foo:123", "bar:456"},
-		ClientID: "otel-collector",
-		GroupID:  "otel-collector",
+		Topic:             "spans",
+		Encoding:          "otlp_proto",
+		Brokers:           []string{"foo:123", "bar:456"},
+		ClientID:          "otel-collector",
+		GroupID:           "otel-collector",
+		InitialOffset:     "oldest",
+		SessionTimeout:    30 * time.Second,
+		RebalanceStrategy: "sticky",
+		MessageMarking: MessageMarking{
+			After: true,
+		},
 		Authentication: kafkaexporter.Authentication{
 			TLS: &configtls.TLSClientSetting{
 				TLSSetting: configtls.TLSSetting{
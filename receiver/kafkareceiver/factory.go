@@ -18,6 +18,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/Shopify/sarama"
+
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
@@ -26,12 +28,21 @@ import (
 )
 
 const (
-	typeStr         = "kafka"
-	defaultTopic    = "otlp_spans"
-	defaultEncoding = "otlp_proto"
-	defaultBroker   = "localhost:9092"
-	defaultClientID = "otel-collector"
-	defaultGroupID  = defaultClientID
+	typeStr = "kafka"
+	// default topics, used when the topic is not configured, one for each signal.
+	defaultTracesTopic  = "otlp_spans"
+	defaultMetricsTopic = "otlp_metrics"
+	defaultLogsTopic    = "otlp_logs"
+	defaultEncoding     = "otlp_proto"
+	defaultBroker       = "localhost:9092"
+	defaultClientID     = "otel-collector"
+	defaultGroupID      = defaultClientID
+	// default from sarama.NewConfig()
+	defaultInitialOffset = offsetNewest
+	// default from sarama.NewConfig()
+	defaultSessionTimeout = 10 * time.Second
+	// default from sarama.NewConfig()
+	defaultRebalanceStrategy = sarama.RangeBalanceStrategyName
 
 	// default from sarama.NewConfig()
 	defaultMetadataRetryMax = 3
@@ -44,7 +55,7 @@ const (
 // FactoryOption applies changes to kafkaExporterFactory.
 type FactoryOption func(factory *kafkaReceiverFactory)
 
-// WithAddUnmarshallers adds marshallers.
+// WithAddUnmarshallers adds traces unmarshallers.
 func WithAddUnmarshallers(encodingMarshaller map[string]Unmarshaller) FactoryOption {
 	return func(factory *kafkaReceiverFactory) {
 		for encoding, unmarshaller := range encodingMarshaller {
@@ -53,10 +64,30 @@ func WithAddUnmarshallers(encodingMarshaller map[string]Unmarshaller) FactoryOpt
 	}
 }
 
+// WithAddMetricsUnmarshallers adds metrics unmarshallers.
+func WithAddMetricsUnmarshallers(encodingMarshaller map[string]MetricsUnmarshaller) FactoryOption {
+	return func(factory *kafkaReceiverFactory) {
+		for encoding, unmarshaller := range encodingMarshaller {
+			factory.metricsUnmarshalers[encoding] = unmarshaller
+		}
+	}
+}
+
+// WithAddLogsUnmarshallers adds logs unmarshallers.
+func WithAddLogsUnmarshallers(encodingMarshaller map[string]LogsUnmarshaller) FactoryOption {
+	return func(factory *kafkaReceiverFactory) {
+		for encoding, unmarshaller := range encodingMarshaller {
+			factory.logsUnmarshalers[encoding] = unmarshaller
+		}
+	}
+}
+
 // NewFactory creates Kafka receiver factory.
 func NewFactory(options ...FactoryOption) component.ReceiverFactory {
 	f := &kafkaReceiverFactory{
-		unmarshalers: defaultUnmarshallers(),
+		unmarshalers:        defaultUnmarshallers(),
+		metricsUnmarshalers: defaultMetricsUnmarshallers(),
+		logsUnmarshalers:    defaultLogsUnmarshallers(),
 	}
 	for _, o := range options {
 		o(f)
@@ -64,7 +95,9 @@ func NewFactory(options ...FactoryOption) component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithTraces(f.createTraceReceiver))
+		receiverhelper.WithTraces(f.createTraceReceiver),
+		receiverhelper.WithMetrics(f.createMetricsReceiver),
+		receiverhelper.WithLogs(f.createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -73,11 +106,14 @@ func createDefaultConfig() configmodels.Receiver {
 			TypeVal: typeStr,
 			NameVal: typeStr,
 		},
-		Topic:    defaultTopic,
-		Encoding: defaultEncoding,
-		Brokers:  []string{defaultBroker},
-		ClientID: defaultClientID,
-		GroupID:  defaultGroupID,
+		// Topic is left empty, so that the default topic of each signal is used.
+		Encoding:          defaultEncoding,
+		Brokers:           []string{defaultBroker},
+		ClientID:          defaultClientID,
+		GroupID:           defaultGroupID,
+		InitialOffset:     defaultInitialOffset,
+		SessionTimeout:    defaultSessionTimeout,
+		RebalanceStrategy: defaultRebalanceStrategy,
 		Metadata: kafkaexporter.Metadata{
 			Full: defaultMetadataFull,
 			Retry: kafkaexporter.MetadataRetry{
@@ -89,7 +125,9 @@ func createDefaultConfig() configmodels.Receiver {
 }
 
 type kafkaReceiverFactory struct {
-	unmarshalers map[string]Unmarshaller
+	unmarshalers        map[string]Unmarshaller
+	metricsUnmarshalers map[string]MetricsUnmarshaller
+	logsUnmarshalers    map[string]LogsUnmarshaller
 }
 
 func (f *kafkaReceiverFactory) createTraceReceiver(
@@ -99,9 +137,46 @@ func (f *kafkaReceiverFactory) createTraceReceiver(
 	nextConsumer consumer.TracesConsumer,
 ) (component.TracesReceiver, error) {
 	c := cfg.(*Config)
-	r, err := newReceiver(*c, params, f.unmarshalers, nextConsumer)
+	r, err := newTracesReceiver(withDefaultTopic(*c, defaultTracesTopic), params, f.unmarshalers, nextConsumer)
 	if err != nil {
 		return nil, err
 	}
 	return r, nil
 }
+
+func (f *kafkaReceiverFactory) createMetricsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	c := cfg.(*Config)
+	r, err := newMetricsReceiver(withDefaultTopic(*c, defaultMetricsTopic), params, f.metricsUnmarshalers, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (f *kafkaReceiverFactory) createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	c := cfg.(*Config)
+	r, err := newLogsReceiver(withDefaultTopic(*c, defaultLogsTopic), params, f.logsUnmarshalers, nextConsumer)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// withDefaultTopic returns a copy of the config using the given topic if none was configured.
+// The config is copied because the same config can be used by pipelines of different signals.
+func withDefaultTopic(cfg Config, topic string) Config {
+	if cfg.Topic == "" {
+		cfg.Topic = topic
+	}
+	return cfg
+}
@@ -31,9 +31,13 @@ func TestCreateDefaultConfig(t *testing.T) {
 	assert.NotNil(t, cfg, "failed to create default config")
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 	assert.Equal(t, []string{defaultBroker}, cfg.Brokers)
-	assert.Equal(t, defaultTopic, cfg.Topic)
+	assert.Empty(t, cfg.Topic)
 	assert.Equal(t, defaultGroupID, cfg.GroupID)
 	assert.Equal(t, defaultClientID, cfg.ClientID)
+	assert.Equal(t, defaultInitialOffset, cfg.InitialOffset)
+	assert.Equal(t, defaultSessionTimeout, cfg.SessionTimeout)
+	assert.Equal(t, defaultRebalanceStrategy, cfg.RebalanceStrategy)
+	assert.False(t, cfg.MessageMarking.After)
 }
 
 func TestCreateTraceReceiver(t *testing.T) {
@@ -58,6 +62,50 @@ func TestCreateTraceReceiver_error(t *testing.T) {
 	assert.NotNil(t, r)
 }
 
+func TestCreateMetricsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "2.0.0"
+	// disable contacting broker at startup
+	cfg.Metadata.Full = false
+	f := kafkaReceiverFactory{metricsUnmarshalers: defaultMetricsUnmarshallers()}
+	r, err := f.createMetricsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, []string{defaultMetricsTopic}, r.(*kafkaConsumer).topics)
+	// the config shared with other pipelines must not be modified
+	assert.Empty(t, cfg.Topic)
+}
+
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "2.0.0"
+	// disable contacting broker at startup
+	cfg.Metadata.Full = false
+	f := kafkaReceiverFactory{logsUnmarshalers: defaultLogsUnmarshallers()}
+	r, err := f.createLogsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	assert.Equal(t, []string{defaultLogsTopic}, r.(*kafkaConsumer).topics)
+}
+
+func TestCreateLogsReceiver_encoding_err(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.ProtocolVersion = "2.0.0"
+	cfg.Metadata.Full = false
+	cfg.Encoding = "jaeger_proto"
+	f := kafkaReceiverFactory{logsUnmarshalers: defaultLogsUnmarshallers()}
+	r, err := f.createLogsReceiver(context.Background(), component.ReceiverCreateParams{}, cfg, nil)
+	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+	assert.Nil(t, r)
+}
+
+func TestWithDefaultTopic(t *testing.T) {
+	cfg := Config{}
+	assert.Equal(t, defaultTracesTopic, withDefaultTopic(cfg, defaultTracesTopic).Topic)
+	cfg.Topic = "custom"
+	assert.Equal(t, "custom", withDefaultTopic(cfg, defaultTracesTopic).Topic)
+}
+
 func TestWithUnmarshallers(t *testing.T) {
 	cum := &customUnamarshaller{}
 	f := NewFactory(WithAddUnmarshallers(map[string]Unmarshaller{cum.Encoding(): cum}))
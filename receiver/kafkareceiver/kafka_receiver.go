@@ -26,41 +26,130 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
 	"go.opentelemetry.io/collector/obsreport"
 )
 
 const (
 	transport = "kafka"
+
+	offsetNewest = "newest"
+	offsetOldest = "oldest"
 )
 
-var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
+var (
+	errUnrecognizedEncoding          = fmt.Errorf("unrecognized encoding")
+	errUnrecognizedInitialOffset     = fmt.Errorf("unrecognized initial offset, must be %q or %q", offsetNewest, offsetOldest)
+	errUnrecognizedRebalanceStrategy = fmt.Errorf("unrecognized rebalance strategy, must be %q, %q or %q",
+		sarama.RangeBalanceStrategyName, sarama.RoundRobinBalanceStrategyName, sarama.StickyBalanceStrategyName)
+)
 
-// kafkaConsumer uses sarama to consume and handle messages from kafka.
+// kafkaConsumer uses sarama to consume messages from kafka, the messages are handled
+// by the consumer group handler of the signal the receiver was created for.
 type kafkaConsumer struct {
-	name              string
 	consumerGroup     sarama.ConsumerGroup
-	nextConsumer      consumer.TracesConsumer
 	topics            []string
+	handler           sarama.ConsumerGroupHandler
+	ready             <-chan bool
 	cancelConsumeLoop context.CancelFunc
-	unmarshaller      Unmarshaller
 
 	logger *zap.Logger
 }
 
 var _ component.Receiver = (*kafkaConsumer)(nil)
 
-func newReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]Unmarshaller, nextConsumer consumer.TracesConsumer) (*kafkaConsumer, error) {
+func newTracesReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]Unmarshaller, nextConsumer consumer.TracesConsumer) (*kafkaConsumer, error) {
+	unmarshaller := unmarshalers[config.Encoding]
+	if unmarshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	client, err := newSaramaConsumerGroup(config)
+	if err != nil {
+		return nil, err
+	}
+	handler := &tracesConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(config, params.Logger),
+		unmarshaller:             unmarshaller,
+		nextConsumer:             nextConsumer,
+	}
+	return newKafkaConsumer(config, client, handler, handler.ready, params.Logger), nil
+}
+
+func newMetricsReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]MetricsUnmarshaller, nextConsumer consumer.MetricsConsumer) (*kafkaConsumer, error) {
+	unmarshaller := unmarshalers[config.Encoding]
+	if unmarshaller == nil {
+		return nil, errUnrecognizedEncoding
+	}
+	client, err := newSaramaConsumerGroup(config)
+	if err != nil {
+		return nil, err
+	}
+	handler := &metricsConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(config, params.Logger),
+		unmarshaller:             unmarshaller,
+		nextConsumer:             nextConsumer,
+	}
+	return newKafkaConsumer(config, client, handler, handler.ready, params.Logger), nil
+}
+
+func newLogsReceiver(config Config, params component.ReceiverCreateParams, unmarshalers map[string]LogsUnmarshaller, nextConsumer consumer.LogsConsumer) (*kafkaConsumer, error) {
 	unmarshaller := unmarshalers[config.Encoding]
 	if unmarshaller == nil {
 		return nil, errUnrecognizedEncoding
 	}
+	client, err := newSaramaConsumerGroup(config)
+	if err != nil {
+		return nil, err
+	}
+	handler := &logsConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(config, params.Logger),
+		unmarshaller:             unmarshaller,
+		nextConsumer:             nextConsumer,
+	}
+	return newKafkaConsumer(config, client, handler, handler.ready, params.Logger), nil
+}
+
+func newKafkaConsumer(config Config, client sarama.ConsumerGroup, handler sarama.ConsumerGroupHandler, ready <-chan bool, logger *zap.Logger) *kafkaConsumer {
+	return &kafkaConsumer{
+		consumerGroup: client,
+		topics:        []string{config.Topic},
+		handler:       handler,
+		ready:         ready,
+		logger:        logger,
+	}
+}
 
+// newSaramaConsumerGroup creates the sarama consumer group client of a receiver. It is not shared:
+// the receivers of the different signals each create their own client, and join the group
+// as separate members.
+func newSaramaConsumerGroup(config Config) (sarama.ConsumerGroup, error) {
 	c := sarama.NewConfig()
 	c.ClientID = config.ClientID
 	c.Metadata.Full = config.Metadata.Full
 	c.Metadata.Retry.Max = config.Metadata.Retry.Max
 	c.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
+	if config.SessionTimeout != 0 {
+		c.Consumer.Group.Session.Timeout = config.SessionTimeout
+	}
+	switch config.InitialOffset {
+	case "", offsetNewest:
+		c.Consumer.Offsets.Initial = sarama.OffsetNewest
+	case offsetOldest:
+		c.Consumer.Offsets.Initial = sarama.OffsetOldest
+	default:
+		return nil, errUnrecognizedInitialOffset
+	}
+	switch config.RebalanceStrategy {
+	case "", sarama.RangeBalanceStrategyName:
+		c.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRange
+	case sarama.RoundRobinBalanceStrategyName:
+		c.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategyRoundRobin
+	case sarama.StickyBalanceStrategyName:
+		c.Consumer.Group.Rebalance.Strategy = sarama.BalanceStrategySticky
+	default:
+		return nil, errUnrecognizedRebalanceStrategy
+	}
 	if config.ProtocolVersion != "" {
 		version, err := sarama.ParseKafkaVersion(config.ProtocolVersion)
 		if err != nil {
@@ -71,32 +160,14 @@ func newReceiver(config Config, params component.ReceiverCreateParams, unmarshal
 	if err := kafkaexporter.ConfigureAuthentication(config.Authentication, c); err != nil {
 		return nil, err
 	}
-	client, err := sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
-	if err != nil {
-		return nil, err
-	}
-	return &kafkaConsumer{
-		name:          config.Name(),
-		consumerGroup: client,
-		topics:        []string{config.Topic},
-		nextConsumer:  nextConsumer,
-		unmarshaller:  unmarshaller,
-		logger:        params.Logger,
-	}, nil
+	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, c)
 }
 
 func (c *kafkaConsumer) Start(context.Context, component.Host) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
-	consumerGroup := &consumerGroupHandler{
-		name:         c.name,
-		logger:       c.logger,
-		unmarshaller: c.unmarshaller,
-		nextConsumer: c.nextConsumer,
-		ready:        make(chan bool),
-	}
-	go c.consumeLoop(ctx, consumerGroup)
-	<-consumerGroup.ready
+	go c.consumeLoop(ctx, c.handler)
+	<-c.ready
 	return nil
 }
 
@@ -121,19 +192,28 @@ func (c *kafkaConsumer) Shutdown(context.Context) error {
 	return c.consumerGroup.Close()
 }
 
-type consumerGroupHandler struct {
-	name         string
-	unmarshaller Unmarshaller
-	nextConsumer consumer.TracesConsumer
-	ready        chan bool
-	readyCloser  sync.Once
+// baseConsumerGroupHandler contains the setup and cleanup of the consumer group sessions,
+// common to the handlers of all signals.
+type baseConsumerGroupHandler struct {
+	name        string
+	ready       chan bool
+	readyCloser sync.Once
+	// markAfter marks the messages only after they are accepted by the next consumer.
+	markAfter bool
 
 	logger *zap.Logger
 }
 
-var _ sarama.ConsumerGroupHandler = (*consumerGroupHandler)(nil)
+func newBaseConsumerGroupHandler(config Config, logger *zap.Logger) baseConsumerGroupHandler {
+	return baseConsumerGroupHandler{
+		name:      config.Name(),
+		ready:     make(chan bool),
+		markAfter: config.MessageMarking.After,
+		logger:    logger,
+	}
+}
 
-func (c *consumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+func (c *baseConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
 	c.readyCloser.Do(func() {
 		close(c.ready)
 	})
@@ -142,40 +222,181 @@ func (c *consumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error
 	return nil
 }
 
-func (c *consumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+func (c *baseConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
 	statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.name)}
 	_ = stats.RecordWithTags(session.Context(), statsTags, statPartitionClose.M(1))
 	return nil
 }
 
-func (c *consumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+// recordMessage logs and records the stats of a claimed message, including the consumer lag
+// of its partition.
+func (c *baseConsumerGroupHandler) recordMessage(ctx context.Context, claim sarama.ConsumerGroupClaim, message *sarama.ConsumerMessage) {
+	c.logger.Debug("Kafka message claimed",
+		zap.String("value", string(message.Value)),
+		zap.Time("timestamp", message.Timestamp),
+		zap.String("topic", message.Topic))
+	lag := claim.HighWaterMarkOffset() - message.Offset - 1
+	statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.name)}
+	_ = stats.RecordWithTags(ctx, statsTags,
+		statMessageCount.M(1),
+		statMessageOffset.M(message.Offset),
+		statMessageOffsetLag.M(lag))
+	obsreport.RecordReceiverLag(ctx, fmt.Sprintf("%s/%d", message.Topic, message.Partition), lag)
+}
+
+// markBefore marks the message before it is sent to the next consumer, unless
+// the messages must be marked only once they are consumed.
+func (c *baseConsumerGroupHandler) markBefore(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) {
+	if !c.markAfter {
+		session.MarkMessage(message, "")
+	}
+}
+
+// markConsumed marks the message once it was passed to the next consumer, when
+// the messages are marked only after they are consumed.
+func (c *baseConsumerGroupHandler) markConsumed(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) {
+	if c.markAfter {
+		session.MarkMessage(message, "")
+	}
+}
+
+// messageError logs the error of a message that would fail every time it is received, because
+// it cannot be unmarshalled or the next consumer rejected it permanently, and marks it, so that
+// the claim moves on to the next messages.
+func (c *baseConsumerGroupHandler) messageError(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, msg string, err error) {
+	c.logMessageError(message, msg, err)
+	c.markConsumed(session, message)
+}
+
+// consumeError handles an error of the next consumer, it returns the error if the claim must
+// stop. When the messages are marked after they are consumed, a message that failed with a
+// retryable error is not marked, and the session is ended so that it is received again from
+// the last committed offset.
+func (c *baseConsumerGroupHandler) consumeError(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage, err error) error {
+	if !c.markAfter || consumererror.IsPermanent(err) {
+		c.messageError(session, message, "Failed to consume message", err)
+		return nil
+	}
+	c.logMessageError(message, "Failed to consume message, it will be received again", err)
+	return err
+}
+
+func (c *baseConsumerGroupHandler) logMessageError(message *sarama.ConsumerMessage, msg string, err error) {
+	c.logger.Error(msg,
+		zap.Error(err),
+		zap.String("topic", message.Topic),
+		zap.Int32("partition", message.Partition),
+		zap.Int64("offset", message.Offset))
+}
+
+type tracesConsumerGroupHandler struct {
+	baseConsumerGroupHandler
+	unmarshaller Unmarshaller
+	nextConsumer consumer.TracesConsumer
+}
+
+var _ sarama.ConsumerGroupHandler = (*tracesConsumerGroupHandler)(nil)
+
+func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
 	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
 	for message := range claim.Messages() {
-		c.logger.Debug("Kafka message claimed",
-			zap.String("value", string(message.Value)),
-			zap.Time("timestamp", message.Timestamp),
-			zap.String("topic", message.Topic))
-		session.MarkMessage(message, "")
+		c.markBefore(session, message)
 
 		ctx := obsreport.ReceiverContext(session.Context(), c.name, transport)
 		ctx = obsreport.StartTraceDataReceiveOp(ctx, c.name, transport)
-		statsTags := []tag.Mutator{tag.Insert(tagInstanceName, c.name)}
-		_ = stats.RecordWithTags(ctx, statsTags,
-			statMessageCount.M(1),
-			statMessageOffset.M(message.Offset),
-			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
+		c.recordMessage(ctx, claim, message)
 
 		traces, err := c.unmarshaller.Unmarshal(message.Value)
 		if err != nil {
-			c.logger.Error("failed to unmarshall message", zap.Error(err))
-			return err
+			obsreport.EndTraceDataReceiveOp(ctx, c.unmarshaller.Encoding(), 0, err)
+			c.messageError(session, message, "Failed to unmarshal message", err)
+			continue
 		}
 
 		err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
 		obsreport.EndTraceDataReceiveOp(ctx, c.unmarshaller.Encoding(), traces.SpanCount(), err)
 		if err != nil {
-			return err
+			if err = c.consumeError(session, message, err); err != nil {
+				return err
+			}
+			continue
+		}
+		c.markConsumed(session, message)
+	}
+	return nil
+}
+
+type metricsConsumerGroupHandler struct {
+	baseConsumerGroupHandler
+	unmarshaller MetricsUnmarshaller
+	nextConsumer consumer.MetricsConsumer
+}
+
+var _ sarama.ConsumerGroupHandler = (*metricsConsumerGroupHandler)(nil)
+
+func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	for message := range claim.Messages() {
+		c.markBefore(session, message)
+
+		ctx := obsreport.ReceiverContext(session.Context(), c.name, transport)
+		ctx = obsreport.StartMetricsReceiveOp(ctx, c.name, transport)
+		c.recordMessage(ctx, claim, message)
+
+		metrics, err := c.unmarshaller.Unmarshal(message.Value)
+		if err != nil {
+			obsreport.EndMetricsReceiveOp(ctx, c.unmarshaller.Encoding(), 0, err)
+			c.messageError(session, message, "Failed to unmarshal message", err)
+			continue
+		}
+
+		_, numPoints := metrics.MetricAndDataPointCount()
+		err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
+		obsreport.EndMetricsReceiveOp(ctx, c.unmarshaller.Encoding(), numPoints, err)
+		if err != nil {
+			if err = c.consumeError(session, message, err); err != nil {
+				return err
+			}
+			continue
+		}
+		c.markConsumed(session, message)
+	}
+	return nil
+}
+
+type logsConsumerGroupHandler struct {
+	baseConsumerGroupHandler
+	unmarshaller LogsUnmarshaller
+	nextConsumer consumer.LogsConsumer
+}
+
+var _ sarama.ConsumerGroupHandler = (*logsConsumerGroupHandler)(nil)
+
+func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	c.logger.Info("Starting consumer group", zap.Int32("partition", claim.Partition()))
+	for message := range claim.Messages() {
+		c.markBefore(session, message)
+
+		ctx := obsreport.ReceiverContext(session.Context(), c.name, transport)
+		ctx = obsreport.StartLogsReceiveOp(ctx, c.name, transport)
+		c.recordMessage(ctx, claim, message)
+
+		logs, err := c.unmarshaller.Unmarshal(message.Value)
+		if err != nil {
+			obsreport.EndLogsReceiveOp(ctx, c.unmarshaller.Encoding(), 0, err)
+			c.messageError(session, message, "Failed to unmarshal message", err)
+			continue
+		}
+
+		err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
+		obsreport.EndLogsReceiveOp(ctx, c.unmarshaller.Encoding(), logs.LogRecordCount(), err)
+		if err != nil {
+			if err = c.consumeError(session, message, err); err != nil {
+				return err
+			}
+			continue
 		}
+		c.markConsumed(session, message)
 	}
 	return nil
 }
@@ -31,10 +31,14 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
 	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 )
 
 func TestNewReceiver_version_err(t *testing.T) {
@@ -42,7 +46,7 @@ func TestNewReceiver_version_err(t *testing.T) {
 		Encoding:        defaultEncoding,
 		ProtocolVersion: "none",
 	}
-	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
 	assert.Error(t, err)
 	assert.Nil(t, r)
 }
@@ -51,7 +55,7 @@ func TestNewReceiver_encoding_err(t *testing.T) {
 	c := Config{
 		Encoding: "foo",
 	}
-	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
 	require.Error(t, err)
 	assert.Nil(t, r)
 	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
@@ -72,7 +76,7 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 			Full: false,
 		},
 	}
-	r, err := newReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to load TLS config")
 	assert.Nil(t, r)
@@ -80,11 +84,8 @@ func TestNewExporter_err_auth_type(t *testing.T) {
 
 func TestReceiverStart(t *testing.T) {
 	testClient := testConsumerGroup{once: &sync.Once{}}
-	c := kafkaConsumer{
-		nextConsumer:  consumertest.NewTracesNop(),
-		logger:        zap.NewNop(),
-		consumerGroup: testClient,
-	}
+	handler := newTestTracesConsumerGroupHandler(consumertest.NewTracesNop())
+	c := newKafkaConsumer(Config{}, testClient, handler, handler.ready, zap.NewNop())
 
 	err := c.Start(context.Background(), nil)
 	require.NoError(t, err)
@@ -93,17 +94,12 @@ func TestReceiverStart(t *testing.T) {
 
 func TestReceiverStartConsume(t *testing.T) {
 	testClient := testConsumerGroup{once: &sync.Once{}}
-	c := kafkaConsumer{
-		nextConsumer:  consumertest.NewTracesNop(),
-		logger:        zap.NewNop(),
-		consumerGroup: testClient,
-	}
+	handler := newTestTracesConsumerGroupHandler(consumertest.NewTracesNop())
+	c := newKafkaConsumer(Config{}, testClient, handler, handler.ready, zap.NewNop())
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancelFunc
 	c.Shutdown(context.Background())
-	err := c.consumeLoop(ctx, &consumerGroupHandler{
-		ready: make(chan bool),
-	})
+	err := c.consumeLoop(ctx, handler)
 	assert.EqualError(t, err, context.Canceled.Error())
 }
 
@@ -113,11 +109,8 @@ func TestReceiver_error(t *testing.T) {
 
 	expectedErr := fmt.Errorf("handler error")
 	testClient := testConsumerGroup{once: &sync.Once{}, err: expectedErr}
-	c := kafkaConsumer{
-		nextConsumer:  consumertest.NewTracesNop(),
-		logger:        logger,
-		consumerGroup: testClient,
-	}
+	handler := newTestTracesConsumerGroupHandler(consumertest.NewTracesNop())
+	c := newKafkaConsumer(Config{}, testClient, handler, handler.ready, logger)
 
 	err := c.Start(context.Background(), nil)
 	require.NoError(t, err)
@@ -133,12 +126,7 @@ func TestConsumerGroupHandler(t *testing.T) {
 	view.Register(views...)
 	defer view.Unregister(views...)
 
-	c := consumerGroupHandler{
-		unmarshaller: &otlpProtoUnmarshaller{},
-		logger:       zap.NewNop(),
-		ready:        make(chan bool),
-		nextConsumer: consumertest.NewTracesNop(),
-	}
+	c := newTestTracesConsumerGroupHandler(consumertest.NewTracesNop())
 
 	testSession := testConsumerGroupSession{}
 	err := c.Setup(testSession)
@@ -177,12 +165,7 @@ func TestConsumerGroupHandler(t *testing.T) {
 }
 
 func TestConsumerGroupHandler_error_unmarshall(t *testing.T) {
-	c := consumerGroupHandler{
-		unmarshaller: &otlpProtoUnmarshaller{},
-		logger:       zap.NewNop(),
-		ready:        make(chan bool),
-		nextConsumer: consumertest.NewTracesNop(),
-	}
+	c := newTestTracesConsumerGroupHandler(consumertest.NewTracesNop())
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -191,7 +174,7 @@ func TestConsumerGroupHandler_error_unmarshall(t *testing.T) {
 	}
 	go func() {
 		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
-		require.Error(t, err)
+		require.NoError(t, err)
 		wg.Done()
 	}()
 	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("!@#")}
@@ -203,12 +186,7 @@ func TestConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	nextConsumer := new(consumertest.TracesSink)
 	consumerError := fmt.Errorf("failed to consumer")
 	nextConsumer.SetConsumeError(consumerError)
-	c := consumerGroupHandler{
-		unmarshaller: &otlpProtoUnmarshaller{},
-		logger:       zap.NewNop(),
-		ready:        make(chan bool),
-		nextConsumer: nextConsumer,
-	}
+	c := newTestTracesConsumerGroupHandler(nextConsumer)
 
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -217,7 +195,7 @@ func TestConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	}
 	go func() {
 		e := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
-		assert.EqualError(t, e, consumerError.Error())
+		assert.NoError(t, e)
 		wg.Done()
 	}()
 
@@ -234,6 +212,308 @@ func TestConsumerGroupHandler_error_nextConsumer(t *testing.T) {
 	wg.Wait()
 }
 
+func TestNewMetricsReceiver_encoding_err(t *testing.T) {
+	c := Config{
+		Encoding: "foo",
+	}
+	r, err := newMetricsReceiver(c, component.ReceiverCreateParams{}, defaultMetricsUnmarshallers(), consumertest.NewMetricsNop())
+	require.Error(t, err)
+	assert.Nil(t, r)
+	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+}
+
+func TestNewLogsReceiver_encoding_err(t *testing.T) {
+	c := Config{
+		Encoding: "foo",
+	}
+	r, err := newLogsReceiver(c, component.ReceiverCreateParams{}, defaultLogsUnmarshallers(), consumertest.NewLogsNop())
+	require.Error(t, err)
+	assert.Nil(t, r)
+	assert.EqualError(t, err, errUnrecognizedEncoding.Error())
+}
+
+func TestMetricsConsumerGroupHandler(t *testing.T) {
+	nextConsumer := new(consumertest.MetricsSink)
+	c := &metricsConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(Config{}, zap.NewNop()),
+		unmarshaller:             &otlpMetricsProtoUnmarshaller{},
+		nextConsumer:             nextConsumer,
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, err)
+		wg.Done()
+	}()
+
+	md := testdata.GenerateMetricsOneMetric()
+	bts, err := md.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+	require.Len(t, nextConsumer.AllMetrics(), 1)
+	assert.Equal(t, md, nextConsumer.AllMetrics()[0])
+}
+
+func TestMetricsConsumerGroupHandler_error_unmarshall(t *testing.T) {
+	c := &metricsConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(Config{}, zap.NewNop()),
+		unmarshaller:             &otlpMetricsProtoUnmarshaller{},
+		nextConsumer:             consumertest.NewMetricsNop(),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, err)
+		wg.Done()
+	}()
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("!@#")}
+	close(groupClaim.messageChan)
+	wg.Wait()
+}
+
+func TestLogsConsumerGroupHandler(t *testing.T) {
+	nextConsumer := new(consumertest.LogsSink)
+	c := &logsConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(Config{}, zap.NewNop()),
+		unmarshaller:             &otlpLogsProtoUnmarshaller{},
+		nextConsumer:             nextConsumer,
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		err := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, err)
+		wg.Done()
+	}()
+
+	ld := testdata.GenerateLogDataOneLog()
+	bts, err := ld.ToOtlpProtoBytes()
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+	require.Len(t, nextConsumer.AllLogs(), 1)
+	assert.Equal(t, ld, nextConsumer.AllLogs()[0])
+}
+
+func TestLogsConsumerGroupHandler_error_nextConsumer(t *testing.T) {
+	nextConsumer := new(consumertest.LogsSink)
+	consumerError := fmt.Errorf("failed to consumer")
+	nextConsumer.SetConsumeError(consumerError)
+	c := &logsConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(Config{}, zap.NewNop()),
+		unmarshaller:             &otlpLogsProtoUnmarshaller{},
+		nextConsumer:             nextConsumer,
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	go func() {
+		e := c.ConsumeClaim(testConsumerGroupSession{}, groupClaim)
+		assert.NoError(t, e)
+		wg.Done()
+	}()
+
+	bts, err := testdata.GenerateLogDataOneLog().ToOtlpProtoBytes()
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: bts}
+	close(groupClaim.messageChan)
+	wg.Wait()
+}
+
+func TestNewReceiver_initial_offset_err(t *testing.T) {
+	c := Config{
+		Encoding:      defaultEncoding,
+		InitialOffset: "latest",
+	}
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	assert.EqualError(t, err, errUnrecognizedInitialOffset.Error())
+	assert.Nil(t, r)
+}
+
+func TestNewReceiver_rebalance_strategy_err(t *testing.T) {
+	c := Config{
+		Encoding:          defaultEncoding,
+		RebalanceStrategy: "random",
+	}
+	r, err := newTracesReceiver(c, component.ReceiverCreateParams{}, defaultUnmarshallers(), consumertest.NewTracesNop())
+	assert.EqualError(t, err, errUnrecognizedRebalanceStrategy.Error())
+	assert.Nil(t, r)
+}
+
+func TestNewReceiver_consumerGroupSettings(t *testing.T) {
+	for _, strategy := range []string{sarama.RangeBalanceStrategyName, sarama.RoundRobinBalanceStrategyName, sarama.StickyBalanceStrategyName} {
+		for _, offset := range []string{offsetNewest, offsetOldest} {
+			t.Run(strategy+"_"+offset, func(t *testing.T) {
+				c := createDefaultConfig().(*Config)
+				c.ProtocolVersion = "2.0.0"
+				// disable contacting broker at startup
+				c.Metadata.Full = false
+				c.InitialOffset = offset
+				c.RebalanceStrategy = strategy
+				c.SessionTimeout = 30 * time.Second
+				r, err := newTracesReceiver(*c, component.ReceiverCreateParams{Logger: zap.NewNop()}, defaultUnmarshallers(), consumertest.NewTracesNop())
+				require.NoError(t, err)
+				require.NotNil(t, r)
+				assert.NoError(t, r.consumerGroup.Close())
+			})
+		}
+	}
+}
+
+func TestConsumerGroupHandler_markAfter(t *testing.T) {
+	nextConsumer := new(consumertest.TracesSink)
+	c := newTestTracesConsumerGroupHandler(nextConsumer)
+	c.markAfter = true
+
+	bts, err := marshalTestTraces(testdata.GenerateTraceDataOneSpan())
+	require.NoError(t, err)
+
+	session := newTestMarkingSession()
+	err = consumeTestMessages(c, session, &sarama.ConsumerMessage{Offset: 1, Value: bts})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, session.marked())
+
+	// The messages that fail with a permanent error are marked, they would fail every time.
+	session = newTestMarkingSession()
+	nextConsumer.SetConsumeError(consumererror.Permanent(fmt.Errorf("failed to consume")))
+	err = consumeTestMessages(c, session, &sarama.ConsumerMessage{Offset: 2, Value: bts})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, session.marked())
+
+	session = newTestMarkingSession()
+	err = consumeTestMessages(c, session, &sarama.ConsumerMessage{Offset: 3, Value: []byte("!@#")})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, session.marked())
+}
+
+func TestConsumerGroupHandler_markAfterRetryableError(t *testing.T) {
+	nextConsumer := new(consumertest.TracesSink)
+	c := newTestTracesConsumerGroupHandler(nextConsumer)
+	c.markAfter = true
+
+	bts, err := marshalTestTraces(testdata.GenerateTraceDataOneSpan())
+	require.NoError(t, err)
+
+	// The message is not marked when the next consumer fails with a retryable error, and the
+	// session ends so that the message is received again.
+	session := newTestMarkingSession()
+	consumeErr := fmt.Errorf("queue is full")
+	nextConsumer.SetConsumeError(consumeErr)
+	err = consumeTestMessages(c, session,
+		&sarama.ConsumerMessage{Offset: 1, Value: bts},
+		&sarama.ConsumerMessage{Offset: 2, Value: bts})
+	assert.Equal(t, consumeErr, err)
+	assert.Empty(t, session.marked())
+
+	// The next session receives the message again from the last committed offset.
+	session = newTestMarkingSession()
+	nextConsumer.SetConsumeError(nil)
+	err = consumeTestMessages(c, session,
+		&sarama.ConsumerMessage{Offset: 1, Value: bts},
+		&sarama.ConsumerMessage{Offset: 2, Value: bts})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, session.marked())
+	assert.Len(t, nextConsumer.AllTraces(), 2)
+}
+
+func TestConsumerGroupHandler_markBefore(t *testing.T) {
+	nextConsumer := new(consumertest.TracesSink)
+	nextConsumer.SetConsumeError(fmt.Errorf("failed to consume"))
+	c := newTestTracesConsumerGroupHandler(nextConsumer)
+
+	bts, err := marshalTestTraces(testdata.GenerateTraceDataOneSpan())
+	require.NoError(t, err)
+	session := newTestMarkingSession()
+	err = consumeTestMessages(c, session, &sarama.ConsumerMessage{Offset: 1, Value: bts})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, session.marked())
+}
+
+func TestConsumerGroupHandler_continueAfterError(t *testing.T) {
+	zcore, logObserver := observer.New(zapcore.ErrorLevel)
+	nextConsumer := new(consumertest.TracesSink)
+	c := newTestTracesConsumerGroupHandler(nextConsumer)
+	c.logger = zap.New(zcore)
+	c.markAfter = true
+
+	bts, err := marshalTestTraces(testdata.GenerateTraceDataOneSpan())
+	require.NoError(t, err)
+	session := newTestMarkingSession()
+	err = consumeTestMessages(c, session,
+		&sarama.ConsumerMessage{Offset: 1, Value: []byte("!@#")},
+		&sarama.ConsumerMessage{Offset: 2, Value: bts})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, session.marked())
+	assert.Len(t, nextConsumer.AllTraces(), 1)
+	assert.Equal(t, 1, logObserver.FilterMessage("Failed to unmarshal message").Len())
+}
+
+func TestConsumerGroupHandler_lag(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	c := newTestTracesConsumerGroupHandler(consumertest.NewTracesNop())
+	c.name = "kafka/lag"
+	bts, err := marshalTestTraces(testdata.GenerateTraceDataOneSpan())
+	require.NoError(t, err)
+	err = consumeTestMessages(c, newTestMarkingSession(), &sarama.ConsumerMessage{
+		Topic:     testTopic,
+		Partition: testPartition,
+		Offset:    1,
+		Value:     bts,
+	})
+	require.NoError(t, err)
+	obsreporttest.CheckReceiverLagViews(t, "kafka/lag", transport, fmt.Sprintf("%s/%d", testTopic, testPartition), testHighWatermarkOffset-2)
+}
+
+// consumeTestMessages sends the messages to the handler and returns the result of ConsumeClaim.
+func consumeTestMessages(handler sarama.ConsumerGroupHandler, session sarama.ConsumerGroupSession, messages ...*sarama.ConsumerMessage) error {
+	groupClaim := &testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage, len(messages)),
+	}
+	for _, message := range messages {
+		groupClaim.messageChan <- message
+	}
+	close(groupClaim.messageChan)
+	return handler.ConsumeClaim(session, groupClaim)
+}
+
+func marshalTestTraces(td pdata.Traces) ([]byte, error) {
+	request := &otlptrace.ExportTraceServiceRequest{
+		ResourceSpans: pdata.TracesToOtlp(td),
+	}
+	return request.Marshal()
+}
+
+func newTestTracesConsumerGroupHandler(nextConsumer consumer.TracesConsumer) *tracesConsumerGroupHandler {
+	return &tracesConsumerGroupHandler{
+		baseConsumerGroupHandler: newBaseConsumerGroupHandler(Config{}, zap.NewNop()),
+		unmarshaller:             &otlpProtoUnmarshaller{},
+		nextConsumer:             nextConsumer,
+	}
+}
+
 type testConsumerGroupClaim struct {
 	messageChan chan *sarama.ConsumerMessage
 }
@@ -303,6 +583,29 @@ func (t testConsumerGroupSession) Context() context.Context {
 	return context.Background()
 }
 
+// testMarkingSession records the offsets of the marked messages.
+type testMarkingSession struct {
+	testConsumerGroupSession
+	mu      *sync.Mutex
+	offsets *[]int64
+}
+
+func newTestMarkingSession() testMarkingSession {
+	return testMarkingSession{mu: &sync.Mutex{}, offsets: &[]int64{}}
+}
+
+func (t testMarkingSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*t.offsets = append(*t.offsets, msg.Offset)
+}
+
+func (t testMarkingSession) marked() []int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return *t.offsets
+}
+
 type testConsumerGroup struct {
 	once *sync.Once
 	err  error
@@ -36,3 +36,33 @@ func (p *otlpProtoUnmarshaller) Unmarshal(bytes []byte) (pdata.Traces, error) {
 func (*otlpProtoUnmarshaller) Encoding() string {
 	return defaultEncoding
 }
+
+type otlpMetricsProtoUnmarshaller struct {
+}
+
+var _ MetricsUnmarshaller = (*otlpMetricsProtoUnmarshaller)(nil)
+
+func (p *otlpMetricsProtoUnmarshaller) Unmarshal(bytes []byte) (pdata.Metrics, error) {
+	md := pdata.NewMetrics()
+	err := md.FromOtlpProtoBytes(bytes)
+	return md, err
+}
+
+func (*otlpMetricsProtoUnmarshaller) Encoding() string {
+	return defaultEncoding
+}
+
+type otlpLogsProtoUnmarshaller struct {
+}
+
+var _ LogsUnmarshaller = (*otlpLogsProtoUnmarshaller)(nil)
+
+func (p *otlpLogsProtoUnmarshaller) Unmarshal(bytes []byte) (pdata.Logs, error) {
+	ld := pdata.NewLogs()
+	err := ld.FromOtlpProtoBytes(bytes)
+	return ld, err
+}
+
+func (*otlpLogsProtoUnmarshaller) Encoding() string {
+	return defaultEncoding
+}
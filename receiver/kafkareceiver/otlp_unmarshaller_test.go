@@ -22,6 +22,7 @@ import (
 
 	"go.opentelemetry.io/collector/consumer/pdata"
 	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/data/testdata"
 )
 
 func TestUnmarshallOTLP(t *testing.T) {
@@ -47,3 +48,39 @@ func TestUnmarshallOTLP_error(t *testing.T) {
 	assert.Equal(t, pdata.NewTraces(), got)
 	assert.Error(t, err)
 }
+
+func TestUnmarshallOTLPMetrics(t *testing.T) {
+	md := testdata.GenerateMetricsOneMetric()
+	expected, err := md.ToOtlpProtoBytes()
+	require.NoError(t, err)
+
+	p := otlpMetricsProtoUnmarshaller{}
+	got, err := p.Unmarshal(expected)
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+	assert.Equal(t, "otlp_proto", p.Encoding())
+}
+
+func TestUnmarshallOTLPMetrics_error(t *testing.T) {
+	p := otlpMetricsProtoUnmarshaller{}
+	_, err := p.Unmarshal([]byte("+$%"))
+	assert.Error(t, err)
+}
+
+func TestUnmarshallOTLPLogs(t *testing.T) {
+	ld := testdata.GenerateLogDataOneLog()
+	expected, err := ld.ToOtlpProtoBytes()
+	require.NoError(t, err)
+
+	p := otlpLogsProtoUnmarshaller{}
+	got, err := p.Unmarshal(expected)
+	require.NoError(t, err)
+	assert.Equal(t, ld, got)
+	assert.Equal(t, "otlp_proto", p.Encoding())
+}
+
+func TestUnmarshallOTLPLogs_error(t *testing.T) {
+	p := otlpLogsProtoUnmarshaller{}
+	_, err := p.Unmarshal([]byte("+$%"))
+	assert.Error(t, err)
+}
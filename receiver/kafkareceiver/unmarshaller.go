@@ -27,6 +27,24 @@ type Unmarshaller interface {
 	Encoding() string
 }
 
+// MetricsUnmarshaller deserializes the message body.
+type MetricsUnmarshaller interface {
+	// Unmarshal deserializes the message body into metrics.
+	Unmarshal([]byte) (pdata.Metrics, error)
+
+	// Encoding of the serialized messages.
+	Encoding() string
+}
+
+// LogsUnmarshaller deserializes the message body.
+type LogsUnmarshaller interface {
+	// Unmarshal deserializes the message body into logs.
+	Unmarshal([]byte) (pdata.Logs, error)
+
+	// Encoding of the serialized messages.
+	Encoding() string
+}
+
 // defaultUnmarshallers returns map of supported encodings with Unmarshaller.
 func defaultUnmarshallers() map[string]Unmarshaller {
 	otlp := &otlpProtoUnmarshaller{}
@@ -44,3 +62,19 @@ func defaultUnmarshallers() map[string]Unmarshaller {
 		zipkinThrift.Encoding(): zipkinThrift,
 	}
 }
+
+// defaultMetricsUnmarshallers returns map of supported encodings with MetricsUnmarshaller.
+func defaultMetricsUnmarshallers() map[string]MetricsUnmarshaller {
+	otlp := &otlpMetricsProtoUnmarshaller{}
+	return map[string]MetricsUnmarshaller{
+		otlp.Encoding(): otlp,
+	}
+}
+
+// defaultLogsUnmarshallers returns map of supported encodings with LogsUnmarshaller.
+func defaultLogsUnmarshallers() map[string]LogsUnmarshaller {
+	otlp := &otlpLogsProtoUnmarshaller{}
+	return map[string]LogsUnmarshaller{
+		otlp.Encoding(): otlp,
+	}
+}
@@ -40,3 +40,19 @@ func TestDefaultUnMarshaller(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultMetricsUnMarshaller(t *testing.T) {
+	marshallers := defaultMetricsUnmarshallers()
+	assert.Equal(t, 1, len(marshallers))
+	m, ok := marshallers["otlp_proto"]
+	require.True(t, ok)
+	assert.NotNil(t, m)
+}
+
+func TestDefaultLogsUnMarshaller(t *testing.T) {
+	marshallers := defaultLogsUnmarshallers()
+	assert.Equal(t, 1, len(marshallers))
+	m, ok := marshallers["otlp_proto"]
+	require.True(t, ok)
+	assert.NotNil(t, m)
+}
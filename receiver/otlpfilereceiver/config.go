@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+const (
+	// FormatJSON reads each line as a Protobuf JSON export request.
+	FormatJSON = "json"
+	// FormatProto reads binary Protobuf export requests prefixed by their length.
+	FormatProto = "proto"
+
+	// TimestampsPreserve replays the data with the timestamps written in the files.
+	TimestampsPreserve = "preserve"
+	// TimestampsShift shifts the timestamps of the data by the same offset, so that
+	// the earliest timestamp of the first replayed message is the time the replay started.
+	TimestampsShift = "shift"
+)
+
+// Config defines configuration for the OTLP file receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Path is the file, or the directory of the files, to replay. The files of a
+	// directory are replayed in the order of their names, the files compressed
+	// with gzip, ending with .gz, are decompressed.
+	Path string `mapstructure:"path"`
+
+	// Format is the encoding of the files, either "json" or "proto", as written by the
+	// file exporter.
+	Format string `mapstructure:"format"`
+
+	// Timestamps is how the timestamps of the data are replayed, either "preserve" or "shift".
+	Timestamps string `mapstructure:"timestamps"`
+
+	// MessagesPerSecond is the maximum number of export requests replayed per second.
+	// They are replayed as fast as they are consumed if it is 0.
+	MessagesPerSecond float64 `mapstructure:"messages_per_second"`
+}
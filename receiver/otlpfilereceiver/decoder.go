@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/metrics/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
+)
+
+// maxMessageSize is the maximum size of the binary Protobuf messages, a larger length
+// means that the file is not in the expected format.
+const maxMessageSize = 64 * 1024 * 1024
+
+// decoder decodes the export requests of a file. decode returns io.EOF at the end of
+// the file and an invalidMessageError when a message cannot be decoded but the next
+// ones can still be.
+type decoder interface {
+	// decode returns the next export request as pdata.Traces, pdata.Metrics or pdata.Logs.
+	decode() (interface{}, error)
+}
+
+// invalidMessageError is returned by the decoders for a message that cannot be decoded.
+type invalidMessageError struct {
+	err error
+}
+
+func (e *invalidMessageError) Error() string {
+	return e.err.Error()
+}
+
+// jsonDecoder decodes Protobuf JSON export requests, one per line. The type of each
+// request is detected from its field.
+type jsonDecoder struct {
+	reader *bufio.Reader
+	line   int
+}
+
+func newJSONDecoder(r io.Reader) *jsonDecoder {
+	return &jsonDecoder{reader: bufio.NewReader(r)}
+}
+
+func (d *jsonDecoder) decode() (interface{}, error) {
+	for {
+		line, err := d.reader.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+		d.line++
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		data, err := unmarshalJSON(line)
+		if err != nil {
+			return nil, &invalidMessageError{err: fmt.Errorf("line %d: %v", d.line, err)}
+		}
+		return data, nil
+	}
+}
+
+func unmarshalJSON(line []byte) (interface{}, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return nil, err
+	}
+	unmarshaler := &jsonpb.Unmarshaler{}
+	switch {
+	case hasField(fields, "resourceSpans", "resource_spans"):
+		var request otlptrace.ExportTraceServiceRequest
+		if err := unmarshaler.Unmarshal(bytes.NewReader(line), &request); err != nil {
+			return nil, err
+		}
+		return pdata.TracesFromOtlp(request.ResourceSpans), nil
+	case hasField(fields, "resourceMetrics", "resource_metrics"):
+		var request otlpmetrics.ExportMetricsServiceRequest
+		if err := unmarshaler.Unmarshal(bytes.NewReader(line), &request); err != nil {
+			return nil, err
+		}
+		return pdata.MetricsFromOtlp(request.ResourceMetrics), nil
+	case hasField(fields, "resourceLogs", "resource_logs"):
+		var request otlplogs.ExportLogsServiceRequest
+		if err := unmarshaler.Unmarshal(bytes.NewReader(line), &request); err != nil {
+			return nil, err
+		}
+		return pdata.LogsFromInternalRep(internal.LogsFromOtlp(request.ResourceLogs)), nil
+	}
+	return nil, errors.New("not a traces, metrics or logs export request")
+}
+
+func hasField(fields map[string]json.RawMessage, names ...string) bool {
+	for _, name := range names {
+		if _, ok := fields[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// protoDecoder decodes binary Protobuf export requests prefixed by their length as
+// a varint. The binary format does not record the type of the requests, they are all
+// decoded as the given type.
+type protoDecoder struct {
+	reader   *bufio.Reader
+	dataType configmodels.DataType
+	message  int
+}
+
+func newProtoDecoder(r io.Reader, dataType configmodels.DataType) *protoDecoder {
+	return &protoDecoder{reader: bufio.NewReader(r), dataType: dataType}
+}
+
+func (d *protoDecoder) decode() (interface{}, error) {
+	size, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, fmt.Errorf("error reading the size of message %d: %v", d.message+1, err)
+	}
+	d.message++
+	if size > maxMessageSize {
+		return nil, fmt.Errorf("message %d is too large (%d bytes)", d.message, size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(d.reader, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("error reading message %d: %v", d.message, err)
+	}
+
+	data, err := d.unmarshal(buf)
+	if err != nil {
+		return nil, &invalidMessageError{err: fmt.Errorf("message %d: %v", d.message, err)}
+	}
+	return data, nil
+}
+
+func (d *protoDecoder) unmarshal(buf []byte) (interface{}, error) {
+	switch d.dataType {
+	case configmodels.TracesDataType:
+		var request otlptrace.ExportTraceServiceRequest
+		if err := proto.Unmarshal(buf, &request); err != nil {
+			return nil, err
+		}
+		return pdata.TracesFromOtlp(request.ResourceSpans), nil
+	case configmodels.MetricsDataType:
+		var request otlpmetrics.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(buf, &request); err != nil {
+			return nil, err
+		}
+		return pdata.MetricsFromOtlp(request.ResourceMetrics), nil
+	default:
+		var request otlplogs.ExportLogsServiceRequest
+		if err := proto.Unmarshal(buf, &request); err != nil {
+			return nil, err
+		}
+		return pdata.LogsFromInternalRep(internal.LogsFromOtlp(request.ResourceLogs)), nil
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal"
+	otlplogs "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/logs/v1"
+	otlpmetrics "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/metrics/v1"
+	otlptrace "go.opentelemetry.io/collector/internal/data/opentelemetry-proto-gen/collector/trace/v1"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func marshalJSON(t *testing.T, message proto.Message) string {
+	line, err := (&jsonpb.Marshaler{}).MarshalToString(message)
+	require.NoError(t, err)
+	return line
+}
+
+func TestJSONDecoder(t *testing.T) {
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	md := testdata.GenerateMetricsAllTypesNoDataPoints()
+	ld := testdata.GenerateLogDataOneLog()
+	input := marshalJSON(t, &otlptrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(td)}) + "\n" +
+		"\n" +
+		`{"resource_spans": "invalid"}` + "\n" +
+		marshalJSON(t, &otlpmetrics.ExportMetricsServiceRequest{ResourceMetrics: pdata.MetricsToOtlp(md)}) + "\r\n" +
+		`{"spans": []}` + "\n" +
+		"not json\n" +
+		marshalJSON(t, &otlplogs.ExportLogsServiceRequest{ResourceLogs: internal.LogsToOtlp(ld.InternalRep())})
+
+	dec := newJSONDecoder(strings.NewReader(input))
+	data, err := dec.decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdata.TracesToOtlp(td), pdata.TracesToOtlp(data.(pdata.Traces)))
+
+	_, err = dec.decode()
+	require.IsType(t, &invalidMessageError{}, err)
+	assert.Contains(t, err.Error(), "line 3: ")
+
+	data, err = dec.decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdata.MetricsToOtlp(md), pdata.MetricsToOtlp(data.(pdata.Metrics)))
+
+	_, err = dec.decode()
+	assert.EqualError(t, err, "line 5: not a traces, metrics or logs export request")
+	_, err = dec.decode()
+	require.IsType(t, &invalidMessageError{}, err)
+	assert.Contains(t, err.Error(), "line 6: ")
+
+	// The last line has no new line.
+	data, err = dec.decode()
+	require.NoError(t, err)
+	assert.Equal(t, internal.LogsToOtlp(ld.InternalRep()), internal.LogsToOtlp(data.(pdata.Logs).InternalRep()))
+
+	_, err = dec.decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func encodeMessage(t *testing.T, buf *bytes.Buffer, message proto.Message) {
+	pb := proto.NewBuffer(nil)
+	require.NoError(t, pb.EncodeMessage(message))
+	buf.Write(pb.Bytes())
+}
+
+func TestProtoDecoder(t *testing.T) {
+	md := testdata.GenerateMetricsTwoMetrics()
+	var buf bytes.Buffer
+	encodeMessage(t, &buf, &otlpmetrics.ExportMetricsServiceRequest{ResourceMetrics: pdata.MetricsToOtlp(md)})
+	// A message that is not valid Protobuf, followed by a valid one.
+	buf.Write([]byte{2, 0xff, 0xff})
+	encodeMessage(t, &buf, &otlpmetrics.ExportMetricsServiceRequest{ResourceMetrics: pdata.MetricsToOtlp(md)})
+
+	dec := newProtoDecoder(&buf, configmodels.MetricsDataType)
+	data, err := dec.decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdata.MetricsToOtlp(md), pdata.MetricsToOtlp(data.(pdata.Metrics)))
+
+	_, err = dec.decode()
+	require.IsType(t, &invalidMessageError{}, err)
+	assert.Contains(t, err.Error(), "message 2: ")
+
+	data, err = dec.decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdata.MetricsToOtlp(md), pdata.MetricsToOtlp(data.(pdata.Metrics)))
+
+	_, err = dec.decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestProtoDecoder_DataTypes(t *testing.T) {
+	td := testdata.GenerateTraceDataOneSpan()
+	var buf bytes.Buffer
+	encodeMessage(t, &buf, &otlptrace.ExportTraceServiceRequest{ResourceSpans: pdata.TracesToOtlp(td)})
+	data, err := newProtoDecoder(&buf, configmodels.TracesDataType).decode()
+	require.NoError(t, err)
+	assert.Equal(t, pdata.TracesToOtlp(td), pdata.TracesToOtlp(data.(pdata.Traces)))
+
+	ld := testdata.GenerateLogDataOneLog()
+	encodeMessage(t, &buf, &otlplogs.ExportLogsServiceRequest{ResourceLogs: internal.LogsToOtlp(ld.InternalRep())})
+	data, err = newProtoDecoder(&buf, configmodels.LogsDataType).decode()
+	require.NoError(t, err)
+	assert.Equal(t, internal.LogsToOtlp(ld.InternalRep()), internal.LogsToOtlp(data.(pdata.Logs).InternalRep()))
+}
+
+func TestProtoDecoder_Errors(t *testing.T) {
+	testcases := []struct {
+		name        string
+		input       []byte
+		errorString string
+	}{
+		{
+			name:        "truncated_size",
+			input:       []byte{0x80},
+			errorString: "error reading the size of message 1: unexpected EOF",
+		},
+		{
+			name:        "truncated_message",
+			input:       []byte{3, 0},
+			errorString: "error reading message 1: unexpected EOF",
+		},
+		{
+			name:        "too_large",
+			input:       proto.EncodeVarint(maxMessageSize + 1),
+			errorString: "message 1 is too large (67108865 bytes)",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newProtoDecoder(bytes.NewReader(tc.input), configmodels.TracesDataType).decode()
+			assert.EqualError(t, err, tc.errorString)
+			assert.NotEqual(t, io.EOF, err)
+		})
+	}
+}
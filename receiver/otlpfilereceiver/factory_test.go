@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			NameVal: typeStr,
+			TypeVal: typeStr,
+		},
+		Format:     FormatJSON,
+		Timestamps: TimestampsPreserve,
+	}, cfg)
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateReceivers(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	tr, err := factory.CreateTracesReceiver(context.Background(), params, cfg, consumertest.NewTracesNop())
+	assert.EqualError(t, err, "path must be specified")
+	assert.Nil(t, tr)
+
+	cfg.Path = "data.json"
+	tr, err = factory.CreateTracesReceiver(context.Background(), params, cfg, consumertest.NewTracesNop())
+	require.NoError(t, err)
+	mr, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	lr, err := factory.CreateLogsReceiver(context.Background(), params, cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+
+	// The same receiver replays the data of all the pipelines.
+	assert.Same(t, tr, mr)
+	assert.Same(t, tr, lr)
+	r := tr.(*otlpFileReceiver)
+	assert.NotNil(t, r.tracesConsumer)
+	assert.NotNil(t, r.metricsConsumer)
+	assert.NotNil(t, r.logsConsumer)
+
+	lr, err = factory.CreateLogsReceiver(context.Background(), params, cfg, nil)
+	assert.Error(t, err)
+	assert.Nil(t, lr)
+}
@@ -0,0 +1,291 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const transport = "file"
+
+// otlpFileReceiver replays the export requests written to files by the file exporter.
+type otlpFileReceiver struct {
+	logger *zap.Logger
+	cfg    *Config
+	now    func() time.Time
+
+	tracesConsumer  consumer.TracesConsumer
+	metricsConsumer consumer.MetricsConsumer
+	logsConsumer    consumer.LogsConsumer
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	// startTime is the time the replay started, the timestamps are shifted relative to it.
+	startTime time.Time
+	// offset is added to the timestamps when they are shifted, it is set by the first
+	// replayed message that has a timestamp.
+	offset  int64
+	shifted bool
+}
+
+var _ component.TracesReceiver = (*otlpFileReceiver)(nil)
+var _ component.MetricsReceiver = (*otlpFileReceiver)(nil)
+var _ component.LogsReceiver = (*otlpFileReceiver)(nil)
+
+func newOtlpFileReceiver(logger *zap.Logger, cfg *Config) (*otlpFileReceiver, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path must be specified")
+	}
+	if cfg.Format != FormatJSON && cfg.Format != FormatProto {
+		return nil, fmt.Errorf("invalid format %q, must be %q or %q", cfg.Format, FormatJSON, FormatProto)
+	}
+	if cfg.Timestamps != TimestampsPreserve && cfg.Timestamps != TimestampsShift {
+		return nil, fmt.Errorf("invalid timestamps %q, must be %q or %q", cfg.Timestamps, TimestampsPreserve, TimestampsShift)
+	}
+	if cfg.MessagesPerSecond < 0 {
+		return nil, errors.New("messages_per_second must not be negative")
+	}
+	return &otlpFileReceiver{
+		logger: logger,
+		cfg:    cfg,
+		now:    time.Now,
+	}, nil
+}
+
+// Start starts replaying the files in the background.
+func (r *otlpFileReceiver) Start(_ context.Context, _ component.Host) error {
+	var err error
+	r.startOnce.Do(func() {
+		var files []string
+		if files, err = r.files(); err != nil {
+			return
+		}
+		var dataType configmodels.DataType
+		if r.cfg.Format == FormatProto {
+			if dataType, err = r.protoDataType(); err != nil {
+				return
+			}
+		}
+
+		var ctx context.Context
+		ctx, r.cancel = context.WithCancel(context.Background())
+		r.done = make(chan struct{})
+		r.startTime = r.now()
+		go r.replay(ctx, files, dataType)
+	})
+	return err
+}
+
+// Shutdown stops the replay.
+func (r *otlpFileReceiver) Shutdown(context.Context) error {
+	r.stopOnce.Do(func() {
+		if r.cancel != nil {
+			r.cancel()
+			<-r.done
+		}
+	})
+	return nil
+}
+
+// files returns the file to replay, or the files of the directory to replay
+// sorted by name.
+func (r *otlpFileReceiver) files() ([]string, error) {
+	info, err := os.Stat(r.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{r.cfg.Path}, nil
+	}
+	infos, err := ioutil.ReadDir(r.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, info := range infos {
+		if info.Mode().IsRegular() {
+			files = append(files, filepath.Join(r.cfg.Path, info.Name()))
+		}
+	}
+	return files, nil
+}
+
+// protoDataType returns the type of the binary Protobuf requests, which is not
+// recorded in the files: it is the type of the only pipeline of the receiver.
+func (r *otlpFileReceiver) protoDataType() (configmodels.DataType, error) {
+	var dataTypes []configmodels.DataType
+	if r.tracesConsumer != nil {
+		dataTypes = append(dataTypes, configmodels.TracesDataType)
+	}
+	if r.metricsConsumer != nil {
+		dataTypes = append(dataTypes, configmodels.MetricsDataType)
+	}
+	if r.logsConsumer != nil {
+		dataTypes = append(dataTypes, configmodels.LogsDataType)
+	}
+	if len(dataTypes) != 1 {
+		return "", fmt.Errorf("the %q format can only be replayed in pipelines of a single data type", FormatProto)
+	}
+	return dataTypes[0], nil
+}
+
+func (r *otlpFileReceiver) replay(ctx context.Context, files []string, dataType configmodels.DataType) {
+	defer close(r.done)
+
+	var throttle <-chan time.Time
+	if r.cfg.MessagesPerSecond > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / r.cfg.MessagesPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	for _, file := range files {
+		if err := r.replayFile(ctx, file, dataType, throttle); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Error("Failed to replay file", zap.String("path", file), zap.Error(err))
+		}
+	}
+	r.logger.Info("Replay finished", zap.Int("files", len(files)))
+}
+
+func (r *otlpFileReceiver) replayFile(ctx context.Context, path string, dataType configmodels.DataType, throttle <-chan time.Time) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var dec decoder
+	if r.cfg.Format == FormatProto {
+		dec = newProtoDecoder(reader, dataType)
+	} else {
+		dec = newJSONDecoder(reader)
+	}
+	for {
+		data, err := dec.decode()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if _, ok := err.(*invalidMessageError); ok {
+				r.logger.Warn("Skipping invalid message", zap.String("path", path), zap.Error(err))
+				continue
+			}
+			return err
+		}
+
+		if throttle != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle:
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		r.consume(ctx, data)
+	}
+}
+
+func (r *otlpFileReceiver) consume(ctx context.Context, data interface{}) {
+	if r.cfg.Timestamps == TimestampsShift {
+		r.shiftTimestamps(data)
+	}
+
+	ctx = obsreport.ReceiverContext(ctx, r.cfg.Name(), transport)
+	var err error
+	switch data := data.(type) {
+	case pdata.Traces:
+		if r.tracesConsumer == nil {
+			return
+		}
+		ctx = obsreport.StartTraceDataReceiveOp(ctx, r.cfg.Name(), transport)
+		err = r.tracesConsumer.ConsumeTraces(ctx, data)
+		obsreport.EndTraceDataReceiveOp(ctx, r.cfg.Format, data.SpanCount(), err)
+	case pdata.Metrics:
+		if r.metricsConsumer == nil {
+			return
+		}
+		ctx = obsreport.StartMetricsReceiveOp(ctx, r.cfg.Name(), transport)
+		_, numPoints := data.MetricAndDataPointCount()
+		err = r.metricsConsumer.ConsumeMetrics(ctx, data)
+		obsreport.EndMetricsReceiveOp(ctx, r.cfg.Format, numPoints, err)
+	case pdata.Logs:
+		if r.logsConsumer == nil {
+			return
+		}
+		ctx = obsreport.StartLogsReceiveOp(ctx, r.cfg.Name(), transport)
+		err = r.logsConsumer.ConsumeLogs(ctx, data)
+		obsreport.EndLogsReceiveOp(ctx, r.cfg.Format, data.LogRecordCount(), err)
+	}
+	if err != nil {
+		r.logger.Error("Failed to consume replayed data", zap.Error(err))
+	}
+}
+
+// shiftTimestamps adds the same offset to all the timestamps, so that the earliest
+// timestamp of the first message that has one is the time the replay started.
+func (r *otlpFileReceiver) shiftTimestamps(data interface{}) {
+	if !r.shifted {
+		var earliest pdata.TimestampUnixNano
+		updateTimestamps(data, func(ts pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+			if earliest == 0 || ts < earliest {
+				earliest = ts
+			}
+			return ts
+		})
+		if earliest == 0 {
+			return
+		}
+		r.offset = r.startTime.UnixNano() - int64(earliest)
+		r.shifted = true
+	}
+	updateTimestamps(data, func(ts pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+		return pdata.TimestampUnixNano(int64(ts) + r.offset)
+	})
+}
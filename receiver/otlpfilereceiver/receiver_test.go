@@ -0,0 +1,332 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/fileexporter"
+	"go.opentelemetry.io/collector/internal"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+type testSinks struct {
+	traces  *consumertest.TracesSink
+	metrics *consumertest.MetricsSink
+	logs    *consumertest.LogsSink
+}
+
+// exportToFile writes the data to path with the file exporter, which is only created for
+// the data types of the data.
+func exportToFile(t *testing.T, path string, format string, data ...interface{}) {
+	factory := fileexporter.NewFactory()
+	cfg := factory.CreateDefaultConfig().(*fileexporter.Config)
+	cfg.Path = path
+	cfg.Format = format
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+
+	var exp component.Exporter
+	for _, d := range data {
+		switch d := d.(type) {
+		case pdata.Traces:
+			te, err := factory.CreateTracesExporter(context.Background(), params, cfg)
+			require.NoError(t, err)
+			require.NoError(t, te.ConsumeTraces(context.Background(), d))
+			exp = te
+		case pdata.Metrics:
+			me, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+			require.NoError(t, err)
+			require.NoError(t, me.ConsumeMetrics(context.Background(), d))
+			exp = me
+		case pdata.Logs:
+			le, err := factory.CreateLogsExporter(context.Background(), params, cfg)
+			require.NoError(t, err)
+			require.NoError(t, le.ConsumeLogs(context.Background(), d))
+			exp = le
+		}
+	}
+	// The exporters of all the data types are the same.
+	require.NoError(t, exp.Shutdown(context.Background()))
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "otlpfilereceiver")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func newTestReceiver(t *testing.T, cfg *Config, sinks testSinks) *otlpFileReceiver {
+	r, err := newOtlpFileReceiver(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	if sinks.traces != nil {
+		r.tracesConsumer = sinks.traces
+	}
+	if sinks.metrics != nil {
+		r.metricsConsumer = sinks.metrics
+	}
+	if sinks.logs != nil {
+		r.logsConsumer = sinks.logs
+	}
+	return r
+}
+
+func replay(t *testing.T, r *otlpFileReceiver) {
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	select {
+	case <-r.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("replay did not finish")
+	}
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func newSinks() testSinks {
+	return testSinks{
+		traces:  new(consumertest.TracesSink),
+		metrics: new(consumertest.MetricsSink),
+		logs:    new(consumertest.LogsSink),
+	}
+}
+
+func TestNewOtlpFileReceiver_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "no_path",
+			modify:      func(cfg *Config) { cfg.Path = "" },
+			errorString: "path must be specified",
+		},
+		{
+			name:        "invalid_format",
+			modify:      func(cfg *Config) { cfg.Format = "xml" },
+			errorString: `invalid format "xml", must be "json" or "proto"`,
+		},
+		{
+			name:        "invalid_timestamps",
+			modify:      func(cfg *Config) { cfg.Timestamps = "now" },
+			errorString: `invalid timestamps "now", must be "preserve" or "shift"`,
+		},
+		{
+			name:        "negative_messages_per_second",
+			modify:      func(cfg *Config) { cfg.MessagesPerSecond = -1 },
+			errorString: "messages_per_second must not be negative",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Path = "data.json"
+			tc.modify(cfg)
+			r, err := newOtlpFileReceiver(zap.NewNop(), cfg)
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestOtlpFileReceiver_ReplayJSON(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.json")
+	td := testdata.GenerateTraceDataTwoSpansSameResource()
+	md := testdata.GenerateMetricsTwoMetrics()
+	ld := testdata.GenerateLogDataOneLog()
+	exportToFile(t, path, fileexporter.FormatJSON, td, md, ld, td)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = path
+	sinks := newSinks()
+	replay(t, newTestReceiver(t, cfg, sinks))
+
+	require.Len(t, sinks.traces.AllTraces(), 2)
+	for _, replayed := range sinks.traces.AllTraces() {
+		assert.Equal(t, pdata.TracesToOtlp(td), pdata.TracesToOtlp(replayed))
+	}
+	require.Len(t, sinks.metrics.AllMetrics(), 1)
+	assert.Equal(t, pdata.MetricsToOtlp(md), pdata.MetricsToOtlp(sinks.metrics.AllMetrics()[0]))
+	require.Len(t, sinks.logs.AllLogs(), 1)
+	assert.Equal(t, internal.LogsToOtlp(ld.InternalRep()), internal.LogsToOtlp(sinks.logs.AllLogs()[0].InternalRep()))
+}
+
+func TestOtlpFileReceiver_ReplayOnlyConsumedTypes(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.json")
+	exportToFile(t, path, fileexporter.FormatJSON,
+		testdata.GenerateTraceDataOneSpan(), testdata.GenerateMetricsOneMetric(), testdata.GenerateLogDataOneLog())
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = path
+	sink := new(consumertest.MetricsSink)
+	replay(t, newTestReceiver(t, cfg, testSinks{metrics: sink}))
+
+	assert.Len(t, sink.AllMetrics(), 1)
+}
+
+func TestOtlpFileReceiver_ReplayDirectory(t *testing.T) {
+	dir := tempDir(t)
+	first := testdata.GenerateLogDataOneLog()
+	first.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).SetName("first")
+	second := testdata.GenerateLogDataOneLog()
+	second.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).SetName("second")
+	third := testdata.GenerateLogDataOneLog()
+	third.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).SetName("third")
+
+	// Rotated files are named after the current file, followed by the rotation time.
+	exportToFile(t, filepath.Join(dir, "data.json"), fileexporter.FormatJSON, third)
+	exportToFile(t, filepath.Join(dir, "data-2020-11-05T12-00-01.000.json"), fileexporter.FormatJSON, second)
+	rotated := filepath.Join(dir, "data-2020-11-05T12-00-00.000.json")
+	exportToFile(t, rotated, fileexporter.FormatJSON, first)
+	gzipFile(t, rotated)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "subdirectory"), 0700))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = dir
+	sink := new(consumertest.LogsSink)
+	replay(t, newTestReceiver(t, cfg, testSinks{logs: sink}))
+
+	var names []string
+	for _, ld := range sink.AllLogs() {
+		names = append(names, ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Name())
+	}
+	assert.Equal(t, []string{"first", "second", "third"}, names)
+}
+
+func gzipFile(t *testing.T, path string) {
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	f, err := os.Create(path + ".gz")
+	require.NoError(t, err)
+	gz := gzip.NewWriter(f)
+	_, err = gz.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	require.NoError(t, f.Close())
+	require.NoError(t, os.Remove(path))
+}
+
+func TestOtlpFileReceiver_ReplayProto(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.pb")
+	md := testdata.GenerateMetricsOneCounterOneSummaryMetrics()
+	exportToFile(t, path, fileexporter.FormatProto, md, md)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = path
+	cfg.Format = FormatProto
+	sink := new(consumertest.MetricsSink)
+	replay(t, newTestReceiver(t, cfg, testSinks{metrics: sink}))
+
+	require.Len(t, sink.AllMetrics(), 2)
+	for _, replayed := range sink.AllMetrics() {
+		assert.Equal(t, pdata.MetricsToOtlp(md), pdata.MetricsToOtlp(replayed))
+	}
+
+	// The type of the messages is not recorded in binary Protobuf files.
+	r := newTestReceiver(t, cfg, newSinks())
+	assert.EqualError(t, r.Start(context.Background(), componenttest.NewNopHost()),
+		`the "proto" format can only be replayed in pipelines of a single data type`)
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestOtlpFileReceiver_ShiftTimestamps(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.json")
+	ld := testdata.GenerateLogDataOneLog()
+	td := testdata.GenerateTraceDataOneSpan()
+	exportToFile(t, path, fileexporter.FormatJSON, ld, td)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = path
+	cfg.Timestamps = TimestampsShift
+	sinks := newSinks()
+	r := newTestReceiver(t, cfg, sinks)
+	start := time.Date(2020, 11, 5, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return start }
+	replay(t, r)
+
+	// The first message is shifted to the start of the replay, the next ones by the same offset.
+	require.Len(t, sinks.logs.AllLogs(), 1)
+	lr := sinks.logs.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(start.UnixNano()), lr.Timestamp())
+	offset := start.UnixNano() - int64(testdata.TestLogTimestamp)
+	require.Len(t, sinks.traces.AllTraces(), 1)
+	span := sinks.traces.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, pdata.TimestampUnixNano(int64(testdata.TestSpanStartTimestamp)+offset), span.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(int64(testdata.TestSpanEndTimestamp)+offset), span.EndTime())
+}
+
+func TestOtlpFileReceiver_Throttle(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.json")
+	ld := testdata.GenerateLogDataOneLog()
+	exportToFile(t, path, fileexporter.FormatJSON, ld, ld, ld, ld)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = path
+	cfg.MessagesPerSecond = 100
+	sink := new(consumertest.LogsSink)
+	start := time.Now()
+	replay(t, newTestReceiver(t, cfg, testSinks{logs: sink}))
+
+	assert.Len(t, sink.AllLogs(), 4)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+}
+
+func TestOtlpFileReceiver_ShutdownDuringReplay(t *testing.T) {
+	path := filepath.Join(tempDir(t), "data.json")
+	ld := testdata.GenerateLogDataOneLog()
+	exportToFile(t, path, fileexporter.FormatJSON, ld, ld, ld)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = path
+	cfg.MessagesPerSecond = 0.1
+	sink := new(consumertest.LogsSink)
+	r := newTestReceiver(t, cfg, testSinks{logs: sink})
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Len(t, sink.AllLogs(), 0)
+}
+
+func TestOtlpFileReceiver_InvalidFiles(t *testing.T) {
+	dir := tempDir(t)
+	ld := testdata.GenerateLogDataOneLog()
+	// The invalid messages are skipped and the files that cannot be read are reported.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.json"), []byte("invalid\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.json.gz"), []byte("not gzip"), 0600))
+	exportToFile(t, filepath.Join(dir, "c.json"), fileexporter.FormatJSON, ld)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = dir
+	sink := new(consumertest.LogsSink)
+	replay(t, newTestReceiver(t, cfg, testSinks{logs: sink}))
+	assert.Len(t, sink.AllLogs(), 1)
+
+	cfg.Path = filepath.Join(dir, "nonexistent")
+	r := newTestReceiver(t, cfg, testSinks{logs: sink})
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,208 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// timestampFunc is applied to each non-zero timestamp of the data and returns its new value.
+type timestampFunc func(pdata.TimestampUnixNano) pdata.TimestampUnixNano
+
+// updateTimestamps applies fn to the timestamps of data, either pdata.Traces,
+// pdata.Metrics or pdata.Logs.
+func updateTimestamps(data interface{}, fn timestampFunc) {
+	update := func(ts pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+		if ts == 0 {
+			return 0
+		}
+		return fn(ts)
+	}
+	switch data := data.(type) {
+	case pdata.Traces:
+		updateTracesTimestamps(data, update)
+	case pdata.Metrics:
+		updateMetricsTimestamps(data, update)
+	case pdata.Logs:
+		updateLogsTimestamps(data, update)
+	}
+}
+
+func updateTracesTimestamps(td pdata.Traces, fn timestampFunc) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				span.SetStartTime(fn(span.StartTime()))
+				span.SetEndTime(fn(span.EndTime()))
+				events := span.Events()
+				for l := 0; l < events.Len(); l++ {
+					if event := events.At(l); !event.IsNil() {
+						event.SetTimestamp(fn(event.Timestamp()))
+					}
+				}
+			}
+		}
+	}
+}
+
+func updateLogsTimestamps(ld pdata.Logs, fn timestampFunc) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				if lr := logs.At(k); !lr.IsNil() {
+					lr.SetTimestamp(fn(lr.Timestamp()))
+				}
+			}
+		}
+	}
+}
+
+func updateMetricsTimestamps(md pdata.Metrics, fn timestampFunc) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				if metric := metrics.At(k); !metric.IsNil() {
+					updateMetricTimestamps(metric, fn)
+				}
+			}
+		}
+	}
+}
+
+func updateMetricTimestamps(metric pdata.Metric, fn timestampFunc) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if gauge := metric.IntGauge(); !gauge.IsNil() {
+			updateIntPointsTimestamps(gauge.DataPoints(), fn)
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if gauge := metric.DoubleGauge(); !gauge.IsNil() {
+			updateDoublePointsTimestamps(gauge.DataPoints(), fn)
+		}
+	case pdata.MetricDataTypeIntSum:
+		if sum := metric.IntSum(); !sum.IsNil() {
+			updateIntPointsTimestamps(sum.DataPoints(), fn)
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if sum := metric.DoubleSum(); !sum.IsNil() {
+			updateDoublePointsTimestamps(sum.DataPoints(), fn)
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if histogram := metric.IntHistogram(); !histogram.IsNil() {
+			points := histogram.DataPoints()
+			for i := 0; i < points.Len(); i++ {
+				if dp := points.At(i); !dp.IsNil() {
+					dp.SetStartTime(fn(dp.StartTime()))
+					dp.SetTimestamp(fn(dp.Timestamp()))
+					updateIntExemplarsTimestamps(dp.Exemplars(), fn)
+				}
+			}
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if histogram := metric.DoubleHistogram(); !histogram.IsNil() {
+			points := histogram.DataPoints()
+			for i := 0; i < points.Len(); i++ {
+				if dp := points.At(i); !dp.IsNil() {
+					dp.SetStartTime(fn(dp.StartTime()))
+					dp.SetTimestamp(fn(dp.Timestamp()))
+					updateDoubleExemplarsTimestamps(dp.Exemplars(), fn)
+				}
+			}
+		}
+	case pdata.MetricDataTypeDoubleSummary:
+		if summary := metric.DoubleSummary(); !summary.IsNil() {
+			points := summary.DataPoints()
+			for i := 0; i < points.Len(); i++ {
+				if dp := points.At(i); !dp.IsNil() {
+					dp.SetStartTime(fn(dp.StartTime()))
+					dp.SetTimestamp(fn(dp.Timestamp()))
+				}
+			}
+		}
+	}
+}
+
+func updateIntPointsTimestamps(points pdata.IntDataPointSlice, fn timestampFunc) {
+	for i := 0; i < points.Len(); i++ {
+		if dp := points.At(i); !dp.IsNil() {
+			dp.SetStartTime(fn(dp.StartTime()))
+			dp.SetTimestamp(fn(dp.Timestamp()))
+			updateIntExemplarsTimestamps(dp.Exemplars(), fn)
+		}
+	}
+}
+
+func updateDoublePointsTimestamps(points pdata.DoubleDataPointSlice, fn timestampFunc) {
+	for i := 0; i < points.Len(); i++ {
+		if dp := points.At(i); !dp.IsNil() {
+			dp.SetStartTime(fn(dp.StartTime()))
+			dp.SetTimestamp(fn(dp.Timestamp()))
+			updateDoubleExemplarsTimestamps(dp.Exemplars(), fn)
+		}
+	}
+}
+
+func updateIntExemplarsTimestamps(exemplars pdata.IntExemplarSlice, fn timestampFunc) {
+	for i := 0; i < exemplars.Len(); i++ {
+		if exemplar := exemplars.At(i); !exemplar.IsNil() {
+			exemplar.SetTimestamp(fn(exemplar.Timestamp()))
+		}
+	}
+}
+
+func updateDoubleExemplarsTimestamps(exemplars pdata.DoubleExemplarSlice, fn timestampFunc) {
+	for i := 0; i < exemplars.Len(); i++ {
+		if exemplar := exemplars.At(i); !exemplar.IsNil() {
+			exemplar.SetTimestamp(fn(exemplar.Timestamp()))
+		}
+	}
+}
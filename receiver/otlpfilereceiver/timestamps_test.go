@@ -0,0 +1,106 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package otlpfilereceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/internal/data/testdata"
+)
+
+func addSecond(ts pdata.TimestampUnixNano) pdata.TimestampUnixNano {
+	return ts + pdata.TimestampUnixNano(time.Second)
+}
+
+func TestUpdateTimestamps_Traces(t *testing.T) {
+	td := testdata.GenerateTraceDataOneSpanOneNil()
+	span := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.Events().At(1).SetTimestamp(0)
+
+	updateTimestamps(td, addSecond)
+
+	assert.Equal(t, addSecond(testdata.TestSpanStartTimestamp), span.StartTime())
+	assert.Equal(t, addSecond(testdata.TestSpanEndTimestamp), span.EndTime())
+	assert.Equal(t, addSecond(testdata.TestSpanEventTimestamp), span.Events().At(0).Timestamp())
+	// Zero timestamps are not updated.
+	assert.Equal(t, pdata.TimestampUnixNano(0), span.Events().At(1).Timestamp())
+}
+
+func TestUpdateTimestamps_Logs(t *testing.T) {
+	ld := testdata.GenerateLogDataOneLogOneNil()
+	updateTimestamps(ld, addSecond)
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, addSecond(testdata.TestLogTimestamp), lr.Timestamp())
+}
+
+func TestUpdateTimestamps_Metrics(t *testing.T) {
+	md := testdata.GenerateMetricsOneCounterOneSummaryMetrics()
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(4)
+
+	gauge := metrics.At(2)
+	gauge.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	gauge.DoubleGauge().InitEmpty()
+	gauge.DoubleGauge().DataPoints().Resize(1)
+	gaugePoint := gauge.DoubleGauge().DataPoints().At(0)
+	gaugePoint.SetTimestamp(testdata.TestMetricTimestamp)
+	gaugePoint.Exemplars().Resize(1)
+	gaugePoint.Exemplars().At(0).SetTimestamp(testdata.TestMetricExemplarTimestamp)
+
+	histogram := metrics.At(3)
+	histogram.SetDataType(pdata.MetricDataTypeIntHistogram)
+	histogram.IntHistogram().InitEmpty()
+	histogram.IntHistogram().DataPoints().Resize(1)
+	histogramPoint := histogram.IntHistogram().DataPoints().At(0)
+	histogramPoint.SetStartTime(testdata.TestMetricStartTimestamp)
+	histogramPoint.SetTimestamp(testdata.TestMetricTimestamp)
+	histogramPoint.Exemplars().Resize(1)
+	histogramPoint.Exemplars().At(0).SetTimestamp(testdata.TestMetricExemplarTimestamp)
+
+	updateTimestamps(md, addSecond)
+
+	sumPoint := metrics.At(0).IntSum().DataPoints().At(0)
+	assert.Equal(t, addSecond(testdata.TestMetricStartTimestamp), sumPoint.StartTime())
+	assert.Equal(t, addSecond(testdata.TestMetricTimestamp), sumPoint.Timestamp())
+	summaryPoint := metrics.At(1).DoubleSummary().DataPoints().At(0)
+	assert.Equal(t, addSecond(testdata.TestMetricStartTimestamp), summaryPoint.StartTime())
+	assert.Equal(t, addSecond(testdata.TestMetricTimestamp), summaryPoint.Timestamp())
+	assert.Equal(t, pdata.TimestampUnixNano(0), gaugePoint.StartTime())
+	assert.Equal(t, addSecond(testdata.TestMetricTimestamp), gaugePoint.Timestamp())
+	assert.Equal(t, addSecond(testdata.TestMetricExemplarTimestamp), gaugePoint.Exemplars().At(0).Timestamp())
+	assert.Equal(t, addSecond(testdata.TestMetricStartTimestamp), histogramPoint.StartTime())
+	assert.Equal(t, addSecond(testdata.TestMetricTimestamp), histogramPoint.Timestamp())
+	assert.Equal(t, addSecond(testdata.TestMetricExemplarTimestamp), histogramPoint.Exemplars().At(0).Timestamp())
+}
+
+func TestUpdateTimestamps_NilEmptyData(t *testing.T) {
+	for _, data := range []interface{}{
+		testdata.GenerateTraceDataOneEmptyOneNilResourceSpans(),
+		testdata.GenerateTraceDataOneEmptyOneNilInstrumentationLibrary(),
+		testdata.GenerateMetricsOneEmptyOneNilResourceMetrics(),
+		testdata.GenerateMetricsOneEmptyOneNilInstrumentationLibrary(),
+		testdata.GenerateMetricsOneMetricOneNil(),
+		testdata.GenerateMetricsAllTypesNilDataPoint(),
+		testdata.GenerateMetricsAllTypesEmptyDataPoint(),
+		testdata.GenerateLogDataOneEmptyOneNilResourceLogs(),
+		testdata.GenerateLogDataOneEmptyOneNilInstrumentationLibrary(),
+	} {
+		assert.NotPanics(t, func() { updateTimestamps(data, addSecond) })
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	transport = "http"
+	format    = "prometheus_remote_write"
+)
+
+type remoteWriteReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	server    *http.Server
+}
+
+var _ http.Handler = (*remoteWriteReceiver)(nil)
+
+func newRemoteWriteReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.MetricsConsumer) (*remoteWriteReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint must be specified")
+	}
+	return &remoteWriteReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+	}, nil
+}
+
+// Start listens for the remote write requests.
+func (r *remoteWriteReceiver) Start(_ context.Context, host component.Host) error {
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		listener, lerr := r.config.HTTPServerSettings.ToListener()
+		if lerr != nil {
+			err = fmt.Errorf("error listening on remote write endpoint: %v", lerr)
+			return
+		}
+		err = nil
+		r.server = r.config.HTTPServerSettings.ToServer(r)
+		go func() {
+			if serr := r.server.Serve(listener); serr != nil && serr != http.ErrServerClosed {
+				host.ReportFatalError(serr)
+			}
+		}()
+	})
+	return err
+}
+
+// Shutdown stops the HTTP server.
+func (r *remoteWriteReceiver) Shutdown(context.Context) error {
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.server != nil {
+			err = r.server.Close()
+		}
+	})
+	return err
+}
+
+// ServeHTTP handles a remote write request. As expected by the Prometheus remote write
+// clients, the invalid requests and the permanent errors are answered with a client
+// error so that they are not retried, and the other errors with a server error.
+func (r *remoteWriteReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed, supported: [POST]", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := req.Context()
+	if c, ok := client.FromHTTP(req); ok {
+		ctx = client.NewContext(ctx, c)
+	}
+	ctx = obsreport.ReceiverContext(ctx, r.config.Name(), transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
+
+	md, status, err := r.decodeRequest(w, req)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, format, 0, err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	_, numPoints := md.MetricAndDataPointCount()
+	if numPoints > 0 {
+		err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	obsreport.EndMetricsReceiveOp(ctx, format, numPoints, err)
+	if err != nil {
+		r.logger.Debug("Failed to consume remote write request", zap.Error(err))
+		status := http.StatusInternalServerError
+		if consumererror.IsPermanent(err) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeRequest decodes the snappy compressed protobuf write request of the body. It
+// returns the status code the request is answered with if it fails.
+func (r *remoteWriteReceiver) decodeRequest(w http.ResponseWriter, req *http.Request) (pdata.Metrics, int, error) {
+	limit := r.config.MaxRequestBodySize
+	body := req.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(w, req.Body, limit)
+	}
+	compressed, err := ioutil.ReadAll(body)
+	if err != nil {
+		// The reader only fails once the limit is reached if the body is too large.
+		if limit > 0 && int64(len(compressed)) >= limit {
+			return pdata.NewMetrics(), http.StatusRequestEntityTooLarge, fmt.Errorf("request body larger than %d bytes", limit)
+		}
+		return pdata.NewMetrics(), http.StatusBadRequest, fmt.Errorf("failed to read request body: %v", err)
+	}
+	// The decoded length is read from the header of the body, it is checked before the
+	// buffer it is decoded to is allocated.
+	decodedLen, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		return pdata.NewMetrics(), http.StatusBadRequest, fmt.Errorf("failed to decompress request body: %v", err)
+	}
+	if limit > 0 && int64(decodedLen) > limit {
+		return pdata.NewMetrics(), http.StatusRequestEntityTooLarge, fmt.Errorf("decompressed request body larger than %d bytes", limit)
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return pdata.NewMetrics(), http.StatusBadRequest, fmt.Errorf("failed to decompress request body: %v", err)
+	}
+	var wr prompb.WriteRequest
+	if err := wr.Unmarshal(data); err != nil {
+		return pdata.NewMetrics(), http.StatusBadRequest, fmt.Errorf("failed to unmarshal write request: %v", err)
+	}
+	md, err := writeRequestToMetrics(&wr)
+	if err != nil {
+		return md, http.StatusBadRequest, err
+	}
+	return md, 0, nil
+}
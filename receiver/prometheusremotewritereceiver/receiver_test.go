@@ -0,0 +1,292 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/prometheusremotewriteexporter"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func startTestReceiver(t *testing.T, nextConsumer consumer.MetricsConsumer) string {
+	return startTestReceiverWithConfig(t, createDefaultConfig().(*Config), nextConsumer)
+}
+
+func startTestReceiverWithConfig(t *testing.T, cfg *Config, nextConsumer consumer.MetricsConsumer) string {
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r, err := newRemoteWriteReceiver(zap.NewNop(), cfg, nextConsumer)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, r.Shutdown(context.Background())) })
+	return "http://" + cfg.Endpoint + "/api/v1/write"
+}
+
+func postWriteRequest(t *testing.T, url string, req *prompb.WriteRequest) *http.Response {
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader(snappy.Encode(nil, data)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp
+}
+
+// generateMetrics creates one metric of each type the remote write exporter supports,
+// as the receiver converts them back.
+func generateMetrics() pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(4)
+	timestamp := pdata.TimestampUnixNano(1604577600000 * 1e6)
+
+	latency := metrics.At(0)
+	latency.SetName("latency")
+	latency.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	latency.DoubleHistogram().InitEmpty()
+	latency.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	latency.DoubleHistogram().DataPoints().Resize(1)
+	hdp := latency.DoubleHistogram().DataPoints().At(0)
+	hdp.LabelsMap().InitFromMap(map[string]string{"path": "/users"})
+	hdp.SetTimestamp(timestamp)
+	hdp.SetCount(10)
+	hdp.SetSum(1234.5)
+	hdp.SetExplicitBounds([]float64{10, 100})
+	hdp.SetBucketCounts([]uint64{2, 5, 3})
+
+	requests := metrics.At(1)
+	requests.SetName("requests")
+	requests.SetDataType(pdata.MetricDataTypeDoubleSum)
+	requests.DoubleSum().InitEmpty()
+	requests.DoubleSum().SetIsMonotonic(true)
+	requests.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+	requests.DoubleSum().DataPoints().Resize(1)
+	sdp := requests.DoubleSum().DataPoints().At(0)
+	sdp.LabelsMap().InitFromMap(map[string]string{"code": "200"})
+	sdp.SetTimestamp(timestamp)
+	sdp.SetValue(42)
+
+	duration := metrics.At(2)
+	duration.SetName("rpc_duration")
+	duration.SetDataType(pdata.MetricDataTypeDoubleSummary)
+	duration.DoubleSummary().InitEmpty()
+	duration.DoubleSummary().DataPoints().Resize(1)
+	qdp := duration.DoubleSummary().DataPoints().At(0)
+	qdp.LabelsMap().InitFromMap(map[string]string{})
+	qdp.SetTimestamp(timestamp)
+	qdp.SetCount(25)
+	qdp.SetSum(100)
+	qdp.QuantileValues().Resize(2)
+	qdp.QuantileValues().At(0).SetQuantile(0.5)
+	qdp.QuantileValues().At(0).SetValue(3)
+	qdp.QuantileValues().At(1).SetQuantile(0.99)
+	qdp.QuantileValues().At(1).SetValue(9)
+
+	temperature := metrics.At(3)
+	temperature.SetName("temperature")
+	temperature.SetDataType(pdata.MetricDataTypeDoubleGauge)
+	temperature.DoubleGauge().InitEmpty()
+	temperature.DoubleGauge().DataPoints().Resize(2)
+	for i, room := range []string{"bedroom", "kitchen"} {
+		gdp := temperature.DoubleGauge().DataPoints().At(i)
+		gdp.LabelsMap().InitFromMap(map[string]string{"room": room})
+		gdp.SetTimestamp(timestamp)
+		gdp.SetValue(19 + float64(i)*2.5)
+	}
+	return md
+}
+
+func TestNewRemoteWriteReceiver_InvalidConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = ""
+	r, err := newRemoteWriteReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	assert.EqualError(t, err, "endpoint must be specified")
+	assert.Nil(t, r)
+}
+
+func TestRemoteWriteReceiver_RoundTrip(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	url := startTestReceiver(t, sink)
+
+	exporter, err := prometheusremotewriteexporter.NewPrwExporter("", url, http.DefaultClient, nil)
+	require.NoError(t, err)
+	dropped, err := exporter.PushMetrics(context.Background(), generateMetrics())
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, generateMetrics(), sink.AllMetrics()[0])
+}
+
+func TestRemoteWriteReceiver_RoundTripSums(t *testing.T) {
+	tests := []struct {
+		name         string
+		monotonic    bool
+		receivedName string
+	}{
+		// The exporter does not add a second _total suffix, the suffix is removed by the receiver.
+		{name: "requests_total", monotonic: true, receivedName: "requests"},
+		// The exporter adds the _total suffix to all the sums, they are received as monotonic.
+		{name: "queue_size", monotonic: false, receivedName: "queue_size"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sink := new(consumertest.MetricsSink)
+			url := startTestReceiver(t, sink)
+
+			md := pdata.NewMetrics()
+			md.ResourceMetrics().Resize(1)
+			md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Resize(1)
+			metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+			metrics.Resize(1)
+			sum := metrics.At(0)
+			sum.SetName(test.name)
+			sum.SetDataType(pdata.MetricDataTypeDoubleSum)
+			sum.DoubleSum().InitEmpty()
+			sum.DoubleSum().SetIsMonotonic(test.monotonic)
+			sum.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+			sum.DoubleSum().DataPoints().Resize(1)
+			sum.DoubleSum().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{})
+			sum.DoubleSum().DataPoints().At(0).SetTimestamp(pdata.TimestampUnixNano(1604577600000 * 1e6))
+			sum.DoubleSum().DataPoints().At(0).SetValue(7)
+
+			exporter, err := prometheusremotewriteexporter.NewPrwExporter("", url, http.DefaultClient, nil)
+			require.NoError(t, err)
+			_, err = exporter.PushMetrics(context.Background(), md)
+			require.NoError(t, err)
+
+			require.Len(t, sink.AllMetrics(), 1)
+			received := sink.AllMetrics()[0].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0)
+			assert.Equal(t, test.receivedName, received.Name())
+			require.Equal(t, pdata.MetricDataTypeDoubleSum, received.DataType())
+			assert.True(t, received.DoubleSum().IsMonotonic())
+			assert.Equal(t, 7.0, received.DoubleSum().DataPoints().At(0).Value())
+		})
+	}
+}
+
+func TestRemoteWriteReceiver_Responses(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	url := startTestReceiver(t, sink)
+
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{series("temperature", nil, 1000, 21)}}
+	resp := postWriteRequest(t, url, req)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 1, sink.MetricsCount())
+
+	// The requests without samples are not consumed.
+	resp = postWriteRequest(t, url, &prompb.WriteRequest{})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 1, sink.MetricsCount())
+
+	resp = postWriteRequest(t, url, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{}}})
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader([]byte("not snappy")))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(url, "application/x-protobuf", bytes.NewReader(snappy.Encode(nil, []byte("not protobuf"))))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestRemoteWriteReceiver_MaxRequestBodySize(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MaxRequestBodySize = 64
+	sink := new(consumertest.MetricsSink)
+	url := startTestReceiverWithConfig(t, cfg, sink)
+
+	resp := postWriteRequest(t, url, &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{series("temperature", nil, 1000, 21)}})
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, 1, sink.MetricsCount())
+
+	post := func(body []byte) *http.Response {
+		resp, err := http.Post(url, "application/x-protobuf", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	// The compressed body is larger than the limit.
+	resp = post(bytes.Repeat([]byte{0xff}, 65))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// The header of the body declares a decoded length larger than the limit.
+	header := make([]byte, binary.MaxVarintLen64)
+	resp = post(header[:binary.PutUvarint(header, 1<<30)])
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	// The body is small once compressed, but larger than the limit decompressed.
+	resp = post(snappy.Encode(nil, make([]byte, 65)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, 1, sink.MetricsCount())
+}
+
+func TestRemoteWriteReceiver_ConsumerErrors(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	url := startTestReceiver(t, sink)
+	req := &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{series("temperature", nil, 1000, 21)}}
+
+	sink.SetConsumeError(errors.New("queue is full"))
+	resp := postWriteRequest(t, url, req)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+
+	sink.SetConsumeError(consumererror.Permanent(errors.New("invalid metric")))
+	resp = postWriteRequest(t, url, req)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestRemoteWriteReceiver_StartShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	r, err := newRemoteWriteReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	// The endpoint is already in use.
+	other, err := newRemoteWriteReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.Error(t, other.Start(context.Background(), componenttest.NewNopHost()))
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.Error(t, r.Shutdown(context.Background()))
+}
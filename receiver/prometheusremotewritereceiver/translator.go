@@ -0,0 +1,366 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/prompb"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The conventions of the time series written by the Prometheus remote write exporter.
+const (
+	nameLabel     = "__name__"
+	leLabel       = "le"
+	quantileLabel = "quantile"
+
+	totalSuffix  = "_total"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+	bucketSuffix = "_bucket"
+
+	// labelSeparator separates the labels in the signature of a data point, it cannot be
+	// part of a valid UTF-8 label.
+	labelSeparator = "\xff"
+)
+
+// timeSeries is a time series of a remote write request, its name split from its labels.
+type timeSeries struct {
+	name    string
+	labels  map[string]string
+	samples []prompb.Sample
+}
+
+// familyKey identifies the metric the time series are converted to.
+type familyKey struct {
+	name     string
+	dataType pdata.MetricDataType
+}
+
+// dataPoint gathers the samples of the time series of a metric with the same labels and
+// timestamp.
+type dataPoint struct {
+	signature string
+	labels    map[string]string
+	// timestamp is in milliseconds.
+	timestamp int64
+
+	value    float64
+	sum      float64
+	count    float64
+	hasCount bool
+	// buckets are the cumulative counts of a histogram by upper bound, +Inf included.
+	buckets map[float64]float64
+	// quantiles are the values of a summary by quantile.
+	quantiles map[float64]float64
+}
+
+// writeRequestToMetrics converts the time series of a remote write request to metrics,
+// without the stale markers, following the conventions of the Prometheus remote write
+// exporter:
+//   - the <name>_bucket series with a le label, and the <name>_sum and <name>_count series
+//     along them, are a histogram named <name>,
+//   - the series with a quantile label, and the <name>_sum and <name>_count series along
+//     them, are a summary named <name>,
+//   - the <name>_total series are a monotonic cumulative sum named <name>, even if the sum
+//     sent was named <name>_total or was not monotonic,
+//   - the other series are gauges.
+func writeRequestToMetrics(req *prompb.WriteRequest) (pdata.Metrics, error) {
+	series := make([]timeSeries, 0, len(req.Timeseries))
+	histograms := map[string]bool{}
+	summaries := map[string]bool{}
+	for _, ts := range req.Timeseries {
+		s := timeSeries{labels: make(map[string]string, len(ts.Labels)), samples: ts.Samples}
+		for _, l := range ts.Labels {
+			if l.Name == nameLabel {
+				s.name = l.Value
+			} else {
+				s.labels[l.Name] = l.Value
+			}
+		}
+		if s.name == "" {
+			return pdata.NewMetrics(), errors.New("time series without a metric name")
+		}
+		if _, ok := s.labels[leLabel]; ok && strings.HasSuffix(s.name, bucketSuffix) {
+			histograms[strings.TrimSuffix(s.name, bucketSuffix)] = true
+		}
+		if _, ok := s.labels[quantileLabel]; ok {
+			summaries[s.name] = true
+		}
+		series = append(series, s)
+	}
+
+	families := map[familyKey]map[string]*dataPoint{}
+	for _, s := range series {
+		if err := addSeries(families, s, histograms, summaries); err != nil {
+			return pdata.NewMetrics(), err
+		}
+	}
+	return buildMetrics(families), nil
+}
+
+// addSeries adds the samples of a time series to the data points of its metric.
+func addSeries(families map[familyKey]map[string]*dataPoint, s timeSeries, histograms, summaries map[string]bool) error {
+	key := familyKey{name: s.name, dataType: pdata.MetricDataTypeDoubleGauge}
+	set := func(dp *dataPoint, v float64) { dp.value = v }
+
+	le, hasLe := s.labels[leLabel]
+	quantile, hasQuantile := s.labels[quantileLabel]
+	base := s.name
+	for _, suffix := range []string{sumSuffix, countSuffix} {
+		base = strings.TrimSuffix(base, suffix)
+	}
+	switch {
+	case hasLe && strings.HasSuffix(s.name, bucketSuffix) && histograms[strings.TrimSuffix(s.name, bucketSuffix)]:
+		bound, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s label %q of time series %q", leLabel, le, s.name)
+		}
+		delete(s.labels, leLabel)
+		key = familyKey{name: strings.TrimSuffix(s.name, bucketSuffix), dataType: pdata.MetricDataTypeDoubleHistogram}
+		set = func(dp *dataPoint, v float64) { dp.buckets[bound] = v }
+	case hasQuantile:
+		q, err := strconv.ParseFloat(quantile, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s label %q of time series %q", quantileLabel, quantile, s.name)
+		}
+		delete(s.labels, quantileLabel)
+		key.dataType = pdata.MetricDataTypeDoubleSummary
+		set = func(dp *dataPoint, v float64) { dp.quantiles[q] = v }
+	case base != s.name && (histograms[base] || summaries[base]):
+		key = familyKey{name: base, dataType: pdata.MetricDataTypeDoubleHistogram}
+		if !histograms[base] {
+			key.dataType = pdata.MetricDataTypeDoubleSummary
+		}
+		if strings.HasSuffix(s.name, sumSuffix) {
+			set = func(dp *dataPoint, v float64) { dp.sum = v }
+		} else {
+			set = func(dp *dataPoint, v float64) {
+				dp.count = v
+				dp.hasCount = true
+			}
+		}
+	case strings.HasSuffix(s.name, totalSuffix) && len(s.name) > len(totalSuffix):
+		key = familyKey{name: strings.TrimSuffix(s.name, totalSuffix), dataType: pdata.MetricDataTypeDoubleSum}
+	}
+
+	points, ok := families[key]
+	if !ok {
+		points = map[string]*dataPoint{}
+		families[key] = points
+	}
+	signature := labelsSignature(s.labels)
+	for _, sample := range s.samples {
+		if value.IsStaleNaN(sample.Value) {
+			continue
+		}
+		pointKey := signature + labelSeparator + strconv.FormatInt(sample.Timestamp, 10)
+		dp, ok := points[pointKey]
+		if !ok {
+			dp = &dataPoint{
+				signature: signature,
+				labels:    s.labels,
+				timestamp: sample.Timestamp,
+				buckets:   map[float64]float64{},
+				quantiles: map[float64]float64{},
+			}
+			points[pointKey] = dp
+		}
+		set(dp, sample.Value)
+	}
+	return nil
+}
+
+func labelsSignature(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(labelSeparator)
+		b.WriteString(labels[name])
+		b.WriteString(labelSeparator)
+	}
+	return b.String()
+}
+
+// buildMetrics creates the metrics sorted by name, and their data points sorted by labels
+// and timestamp.
+func buildMetrics(families map[familyKey]map[string]*dataPoint) pdata.Metrics {
+	md := pdata.NewMetrics()
+	keys := make([]familyKey, 0, len(families))
+	for key, points := range families {
+		if len(points) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return md
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name != keys[j].name {
+			return keys[i].name < keys[j].name
+		}
+		return keys[i].dataType < keys[j].dataType
+	})
+
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(len(keys))
+	for i, key := range keys {
+		metric := metrics.At(i)
+		metric.SetName(key.name)
+		metric.SetDataType(key.dataType)
+		points := sortedPoints(families[key])
+		switch key.dataType {
+		case pdata.MetricDataTypeDoubleGauge:
+			metric.DoubleGauge().InitEmpty()
+			dps := metric.DoubleGauge().DataPoints()
+			dps.Resize(len(points))
+			for j, p := range points {
+				fillDoubleDataPoint(dps.At(j), p)
+			}
+		case pdata.MetricDataTypeDoubleSum:
+			metric.DoubleSum().InitEmpty()
+			metric.DoubleSum().SetIsMonotonic(true)
+			metric.DoubleSum().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+			dps := metric.DoubleSum().DataPoints()
+			dps.Resize(len(points))
+			for j, p := range points {
+				fillDoubleDataPoint(dps.At(j), p)
+			}
+		case pdata.MetricDataTypeDoubleHistogram:
+			metric.DoubleHistogram().InitEmpty()
+			metric.DoubleHistogram().SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+			dps := metric.DoubleHistogram().DataPoints()
+			dps.Resize(len(points))
+			for j, p := range points {
+				fillHistogramDataPoint(dps.At(j), p)
+			}
+		case pdata.MetricDataTypeDoubleSummary:
+			metric.DoubleSummary().InitEmpty()
+			dps := metric.DoubleSummary().DataPoints()
+			dps.Resize(len(points))
+			for j, p := range points {
+				fillSummaryDataPoint(dps.At(j), p)
+			}
+		}
+	}
+	return md
+}
+
+func sortedPoints(points map[string]*dataPoint) []*dataPoint {
+	sorted := make([]*dataPoint, 0, len(points))
+	for _, p := range points {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].signature != sorted[j].signature {
+			return sorted[i].signature < sorted[j].signature
+		}
+		return sorted[i].timestamp < sorted[j].timestamp
+	})
+	return sorted
+}
+
+// toTimestamp converts a timestamp in milliseconds.
+func toTimestamp(ms int64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(ms * 1e6)
+}
+
+func fillDoubleDataPoint(dp pdata.DoubleDataPoint, p *dataPoint) {
+	dp.LabelsMap().InitFromMap(p.labels)
+	dp.SetTimestamp(toTimestamp(p.timestamp))
+	dp.SetValue(p.value)
+}
+
+// fillHistogramDataPoint converts the cumulative bucket counts to the counts of each
+// bucket. The count is the one of the +Inf bucket if there is no <name>_count series.
+func fillHistogramDataPoint(dp pdata.DoubleHistogramDataPoint, p *dataPoint) {
+	dp.LabelsMap().InitFromMap(p.labels)
+	dp.SetTimestamp(toTimestamp(p.timestamp))
+	dp.SetSum(p.sum)
+
+	bounds := make([]float64, 0, len(p.buckets))
+	for bound := range p.buckets {
+		if !math.IsInf(bound, 1) {
+			bounds = append(bounds, bound)
+		}
+	}
+	sort.Float64s(bounds)
+
+	count := p.count
+	if !p.hasCount {
+		if inf, ok := p.buckets[math.Inf(1)]; ok {
+			count = inf
+		} else if len(bounds) > 0 {
+			count = p.buckets[bounds[len(bounds)-1]]
+		}
+	}
+	dp.SetCount(toCount(count))
+	if len(p.buckets) == 0 {
+		return
+	}
+
+	bucketCounts := make([]uint64, len(bounds)+1)
+	var previous float64
+	for i, bound := range bounds {
+		bucketCounts[i] = toCount(p.buckets[bound] - previous)
+		previous = p.buckets[bound]
+	}
+	bucketCounts[len(bounds)] = toCount(count - previous)
+	dp.SetExplicitBounds(bounds)
+	dp.SetBucketCounts(bucketCounts)
+}
+
+func fillSummaryDataPoint(dp pdata.DoubleSummaryDataPoint, p *dataPoint) {
+	dp.LabelsMap().InitFromMap(p.labels)
+	dp.SetTimestamp(toTimestamp(p.timestamp))
+	dp.SetSum(p.sum)
+	dp.SetCount(toCount(p.count))
+
+	quantiles := make([]float64, 0, len(p.quantiles))
+	for q := range p.quantiles {
+		quantiles = append(quantiles, q)
+	}
+	sort.Float64s(quantiles)
+	values := dp.QuantileValues()
+	values.Resize(len(quantiles))
+	for i, q := range quantiles {
+		values.At(i).SetQuantile(q)
+		values.At(i).SetValue(p.quantiles[q])
+	}
+}
+
+// toCount converts a count sample, the counts of inconsistent buckets are set to 0.
+func toCount(v float64) uint64 {
+	if v <= 0 || math.IsNaN(v) {
+		return 0
+	}
+	return uint64(math.Round(v))
+}
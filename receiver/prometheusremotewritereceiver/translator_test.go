@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// series creates a time series from its name, its labels as name value pairs and samples
+// as timestamp value pairs.
+func series(name string, labels []string, samples ...float64) prompb.TimeSeries {
+	ts := prompb.TimeSeries{Labels: []prompb.Label{{Name: nameLabel, Value: name}}}
+	for i := 0; i+1 < len(labels); i += 2 {
+		ts.Labels = append(ts.Labels, prompb.Label{Name: labels[i], Value: labels[i+1]})
+	}
+	for i := 0; i+1 < len(samples); i += 2 {
+		ts.Samples = append(ts.Samples, prompb.Sample{Timestamp: int64(samples[i]), Value: samples[i+1]})
+	}
+	return ts
+}
+
+func convert(t *testing.T, timeseries ...prompb.TimeSeries) pdata.MetricSlice {
+	md, err := writeRequestToMetrics(&prompb.WriteRequest{Timeseries: timeseries})
+	require.NoError(t, err)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	return md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+}
+
+func labels(m map[string]string) pdata.StringMap {
+	return pdata.NewStringMap().InitFromMap(m)
+}
+
+func TestWriteRequestToMetrics_Gauges(t *testing.T) {
+	metrics := convert(t,
+		series("temperature", []string{"room", "kitchen"}, 1000, 21.5, 2000, 22),
+		series("temperature", []string{"room", "bedroom"}, 1000, 19),
+		series("memory_sum", nil, 1000, 512),
+	)
+
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, "memory_sum", metrics.At(0).Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, metrics.At(0).DataType())
+
+	temperature := metrics.At(1)
+	assert.Equal(t, "temperature", temperature.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, temperature.DataType())
+	points := temperature.DoubleGauge().DataPoints()
+	require.Equal(t, 3, points.Len())
+	assert.Equal(t, labels(map[string]string{"room": "bedroom"}), points.At(0).LabelsMap())
+	assert.Equal(t, 19.0, points.At(0).Value())
+	assert.Equal(t, labels(map[string]string{"room": "kitchen"}), points.At(1).LabelsMap())
+	assert.Equal(t, pdata.TimestampUnixNano(1e9), points.At(1).Timestamp())
+	assert.Equal(t, 21.5, points.At(1).Value())
+	assert.Equal(t, pdata.TimestampUnixNano(2e9), points.At(2).Timestamp())
+	assert.Equal(t, 22.0, points.At(2).Value())
+	assert.Equal(t, pdata.TimestampUnixNano(0), points.At(2).StartTime())
+}
+
+func TestWriteRequestToMetrics_Counters(t *testing.T) {
+	metrics := convert(t,
+		series("http_requests_total", []string{"code", "200"}, 1000, 10),
+		series("requests", nil, 1000, 5),
+		series("_total", nil, 1000, 1),
+	)
+
+	require.Equal(t, 3, metrics.Len())
+	assert.Equal(t, "_total", metrics.At(0).Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, metrics.At(0).DataType())
+
+	requests := metrics.At(1)
+	assert.Equal(t, "http_requests", requests.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleSum, requests.DataType())
+	assert.True(t, requests.DoubleSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, requests.DoubleSum().AggregationTemporality())
+	point := requests.DoubleSum().DataPoints().At(0)
+	assert.Equal(t, labels(map[string]string{"code": "200"}), point.LabelsMap())
+	assert.Equal(t, 10.0, point.Value())
+
+	assert.Equal(t, "requests", metrics.At(2).Name())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, metrics.At(2).DataType())
+}
+
+func TestWriteRequestToMetrics_Histograms(t *testing.T) {
+	metrics := convert(t,
+		series("latency_bucket", []string{"le", "+Inf", "path", "/"}, 1000, 10),
+		series("latency_bucket", []string{"le", "100", "path", "/"}, 1000, 7),
+		series("latency_bucket", []string{"le", "10", "path", "/"}, 1000, 2),
+		series("latency_sum", []string{"path", "/"}, 1000, 1234.5),
+		series("latency_count", []string{"path", "/"}, 1000, 10),
+		// Without count, the count is the one of the +Inf bucket.
+		series("size_bucket", []string{"le", "1"}, 1000, 1),
+		series("size_bucket", []string{"le", "+Inf"}, 1000, 3),
+	)
+
+	require.Equal(t, 2, metrics.Len())
+	latency := metrics.At(0)
+	assert.Equal(t, "latency", latency.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleHistogram, latency.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, latency.DoubleHistogram().AggregationTemporality())
+	require.Equal(t, 1, latency.DoubleHistogram().DataPoints().Len())
+	point := latency.DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, labels(map[string]string{"path": "/"}), point.LabelsMap())
+	assert.Equal(t, pdata.TimestampUnixNano(1e9), point.Timestamp())
+	assert.Equal(t, uint64(10), point.Count())
+	assert.Equal(t, 1234.5, point.Sum())
+	assert.Equal(t, []float64{10, 100}, point.ExplicitBounds())
+	assert.Equal(t, []uint64{2, 5, 3}, point.BucketCounts())
+
+	size := metrics.At(1).DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(3), size.Count())
+	assert.Equal(t, []float64{1}, size.ExplicitBounds())
+	assert.Equal(t, []uint64{1, 2}, size.BucketCounts())
+}
+
+func TestWriteRequestToMetrics_Summaries(t *testing.T) {
+	metrics := convert(t,
+		series("rpc_duration", []string{"quantile", "0.99"}, 1000, 9),
+		series("rpc_duration", []string{"quantile", "0.5"}, 1000, 3),
+		series("rpc_duration_sum", nil, 1000, 100),
+		series("rpc_duration_count", nil, 1000, 25),
+	)
+
+	require.Equal(t, 1, metrics.Len())
+	summary := metrics.At(0)
+	assert.Equal(t, "rpc_duration", summary.Name())
+	require.Equal(t, pdata.MetricDataTypeDoubleSummary, summary.DataType())
+	point := summary.DoubleSummary().DataPoints().At(0)
+	assert.Equal(t, 0, point.LabelsMap().Len())
+	assert.Equal(t, uint64(25), point.Count())
+	assert.Equal(t, 100.0, point.Sum())
+	require.Equal(t, 2, point.QuantileValues().Len())
+	assert.Equal(t, 0.5, point.QuantileValues().At(0).Quantile())
+	assert.Equal(t, 3.0, point.QuantileValues().At(0).Value())
+	assert.Equal(t, 0.99, point.QuantileValues().At(1).Quantile())
+	assert.Equal(t, 9.0, point.QuantileValues().At(1).Value())
+}
+
+func TestWriteRequestToMetrics_StaleMarkers(t *testing.T) {
+	stale := math.Float64frombits(value.StaleNaN)
+	metrics := convert(t,
+		series("temperature", nil, 1000, 21, 2000, stale),
+		series("requests_total", nil, 1000, stale),
+	)
+	require.Equal(t, 1, metrics.Len())
+	assert.Equal(t, 1, metrics.At(0).DoubleGauge().DataPoints().Len())
+
+	md, err := writeRequestToMetrics(&prompb.WriteRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, md.ResourceMetrics().Len())
+}
+
+func TestWriteRequestToMetrics_Errors(t *testing.T) {
+	testcases := []struct {
+		name        string
+		series      prompb.TimeSeries
+		errorString string
+	}{
+		{
+			name:        "no_name",
+			series:      prompb.TimeSeries{Labels: []prompb.Label{{Name: "code", Value: "200"}}},
+			errorString: "time series without a metric name",
+		},
+		{
+			name:        "invalid_le",
+			series:      series("latency_bucket", []string{"le", "ten"}, 1000, 1),
+			errorString: `invalid le label "ten" of time series "latency_bucket"`,
+		},
+		{
+			name:        "invalid_quantile",
+			series:      series("latency", []string{"quantile", "p99"}, 1000, 1),
+			errorString: `invalid quantile label "p99" of time series "latency"`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := writeRequestToMetrics(&prompb.WriteRequest{Timeseries: []prompb.TimeSeries{tc.series}})
+			assert.EqualError(t, err, tc.errorString)
+		})
+	}
+}
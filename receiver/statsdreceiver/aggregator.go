@@ -0,0 +1,339 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// series is the identity of an aggregated metric: its name and its labels.
+type series struct {
+	name   string
+	labels []label
+}
+
+// seriesKey returns the key of the series of m, the labels are sorted by key.
+func seriesKey(m statsdMetric) string {
+	var b strings.Builder
+	b.WriteString(m.name)
+	for _, l := range m.labels {
+		b.WriteByte(0)
+		b.WriteString(l.key)
+		b.WriteByte(0)
+		b.WriteString(l.value)
+	}
+	return b.String()
+}
+
+// The lastInterval of the counters, gauges and histograms is the index of the last interval
+// they were updated in, to expire the ones that are no longer updated.
+
+type counter struct {
+	series
+	startTime    time.Time
+	lastInterval uint64
+	value        float64
+	// nonMonotonic is set once a negative value is received.
+	nonMonotonic bool
+}
+
+type gauge struct {
+	series
+	lastInterval uint64
+	value        float64
+}
+
+type histogram struct {
+	series
+	startTime    time.Time
+	lastInterval uint64
+	count        float64
+	sum          float64
+	// bucketCounts has one more bucket than the bounds, for the values greater than the last bound.
+	bucketCounts []float64
+}
+
+type set struct {
+	series
+	values map[string]struct{}
+}
+
+// aggregator aggregates the StatsD metrics over an interval. The timers, histograms and
+// distributions are aggregated together into histograms.
+type aggregator struct {
+	cumulative bool
+	bounds     []float64
+	// expiration is the number of intervals without updates after which a series is
+	// removed, zero if the series never expire.
+	expiration uint64
+	// interval is the index of the current interval.
+	interval uint64
+	// intervalStart is the start time of the delta counters and histograms.
+	intervalStart time.Time
+
+	counters   map[string]*counter
+	gauges     map[string]*gauge
+	histograms map[string]*histogram
+	sets       map[string]*set
+}
+
+func newAggregator(cumulative bool, bounds []float64, expiration int, now time.Time) *aggregator {
+	return &aggregator{
+		cumulative:    cumulative,
+		bounds:        bounds,
+		expiration:    uint64(expiration),
+		intervalStart: now,
+		counters:      map[string]*counter{},
+		gauges:        map[string]*gauge{},
+		histograms:    map[string]*histogram{},
+		sets:          map[string]*set{},
+	}
+}
+
+// add aggregates m. The values of the sampled counters, timers, histograms and
+// distributions are weighted by the inverse of their sample rate.
+func (a *aggregator) add(m statsdMetric, now time.Time) {
+	key := seriesKey(m)
+	s := series{name: m.name, labels: m.labels}
+	weight := 1 / m.sampleRate
+
+	switch m.metricType {
+	case counterType:
+		c, ok := a.counters[key]
+		if !ok {
+			c = &counter{series: s, startTime: now}
+			a.counters[key] = c
+		}
+		c.value += m.value * weight
+		c.lastInterval = a.interval
+		if m.value < 0 {
+			c.nonMonotonic = true
+		}
+	case gaugeType:
+		g, ok := a.gauges[key]
+		if !ok {
+			g = &gauge{series: s}
+			a.gauges[key] = g
+		}
+		if m.relative {
+			g.value += m.value
+		} else {
+			g.value = m.value
+		}
+		g.lastInterval = a.interval
+	case timerType, histogramType, distributionType:
+		h, ok := a.histograms[key]
+		if !ok {
+			h = &histogram{series: s, startTime: now, bucketCounts: make([]float64, len(a.bounds)+1)}
+			a.histograms[key] = h
+		}
+		h.count += weight
+		h.lastInterval = a.interval
+		h.sum += m.value * weight
+		// The buckets include their upper bound.
+		h.bucketCounts[sort.SearchFloat64s(a.bounds, m.value)] += weight
+	case setType:
+		st, ok := a.sets[key]
+		if !ok {
+			st = &set{series: s, values: map[string]struct{}{}}
+			a.sets[key] = st
+		}
+		st.values[m.setValue] = struct{}{}
+	}
+}
+
+// flush returns the metrics aggregated since the previous flush and starts a new
+// interval. The delta counters and histograms, the sets and the gauges are only
+// emitted if they were received during the interval, the cumulative counters and
+// histograms are emitted until they expire. The last value of the gauges is kept
+// for the relative updates until they expire.
+func (a *aggregator) flush(now time.Time) pdata.Metrics {
+	a.expire()
+
+	md := pdata.NewMetrics()
+	ilm := pdata.NewInstrumentationLibraryMetrics()
+	ilm.InitEmpty()
+	metrics := newMetricsBuilder(ilm.Metrics(), a.temporality())
+	timestamp := pdata.TimestampUnixNano(now.UnixNano())
+
+	for _, key := range sortedKeys(a.counters) {
+		c := a.counters[key]
+		metric := metrics.metric(c.name, pdata.MetricDataTypeIntSum)
+		dp := pdata.NewIntDataPoint()
+		dp.InitEmpty()
+		fillLabels(dp.LabelsMap(), c.labels)
+		dp.SetStartTime(a.startTime(c.startTime))
+		dp.SetTimestamp(timestamp)
+		dp.SetValue(int64(math.Round(c.value)))
+		metric.IntSum().DataPoints().Append(dp)
+		if c.nonMonotonic {
+			metric.IntSum().SetIsMonotonic(false)
+		}
+	}
+	for _, key := range sortedKeys(a.gauges) {
+		g := a.gauges[key]
+		if g.lastInterval != a.interval {
+			continue
+		}
+		metric := metrics.metric(g.name, pdata.MetricDataTypeDoubleGauge)
+		dp := pdata.NewDoubleDataPoint()
+		dp.InitEmpty()
+		fillLabels(dp.LabelsMap(), g.labels)
+		dp.SetTimestamp(timestamp)
+		dp.SetValue(g.value)
+		metric.DoubleGauge().DataPoints().Append(dp)
+	}
+	for _, key := range sortedKeys(a.histograms) {
+		h := a.histograms[key]
+		metric := metrics.metric(h.name, pdata.MetricDataTypeDoubleHistogram)
+		dp := pdata.NewDoubleHistogramDataPoint()
+		dp.InitEmpty()
+		fillLabels(dp.LabelsMap(), h.labels)
+		dp.SetStartTime(a.startTime(h.startTime))
+		dp.SetTimestamp(timestamp)
+		dp.SetCount(uint64(math.Round(h.count)))
+		dp.SetSum(h.sum)
+		bucketCounts := make([]uint64, len(h.bucketCounts))
+		for i, count := range h.bucketCounts {
+			bucketCounts[i] = uint64(math.Round(count))
+		}
+		dp.SetBucketCounts(bucketCounts)
+		dp.SetExplicitBounds(append([]float64(nil), a.bounds...))
+		metric.DoubleHistogram().DataPoints().Append(dp)
+	}
+	for _, key := range sortedKeys(a.sets) {
+		st := a.sets[key]
+		metric := metrics.metric(st.name, pdata.MetricDataTypeIntGauge)
+		dp := pdata.NewIntDataPoint()
+		dp.InitEmpty()
+		fillLabels(dp.LabelsMap(), st.labels)
+		dp.SetTimestamp(timestamp)
+		dp.SetValue(int64(len(st.values)))
+		metric.IntGauge().DataPoints().Append(dp)
+	}
+
+	if !a.cumulative {
+		a.counters = map[string]*counter{}
+		a.histograms = map[string]*histogram{}
+	}
+	a.sets = map[string]*set{}
+	a.interval++
+	a.intervalStart = now
+
+	if ilm.Metrics().Len() > 0 {
+		md.ResourceMetrics().Resize(1)
+		md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().Append(ilm)
+	}
+	return md
+}
+
+// expire removes the counters, gauges and histograms that were not updated during the
+// last expiration intervals.
+func (a *aggregator) expire() {
+	if a.expiration == 0 {
+		return
+	}
+	for key, c := range a.counters {
+		if a.interval-c.lastInterval >= a.expiration {
+			delete(a.counters, key)
+		}
+	}
+	for key, g := range a.gauges {
+		if a.interval-g.lastInterval >= a.expiration {
+			delete(a.gauges, key)
+		}
+	}
+	for key, h := range a.histograms {
+		if a.interval-h.lastInterval >= a.expiration {
+			delete(a.histograms, key)
+		}
+	}
+}
+
+func (a *aggregator) startTime(seriesStart time.Time) pdata.TimestampUnixNano {
+	if a.cumulative {
+		return pdata.TimestampUnixNano(seriesStart.UnixNano())
+	}
+	return pdata.TimestampUnixNano(a.intervalStart.UnixNano())
+}
+
+func (a *aggregator) temporality() pdata.AggregationTemporality {
+	if a.cumulative {
+		return pdata.AggregationTemporalityCumulative
+	}
+	return pdata.AggregationTemporalityDelta
+}
+
+// metricsBuilder groups the data points of the same name and type into one metric.
+type metricsBuilder struct {
+	metrics     pdata.MetricSlice
+	temporality pdata.AggregationTemporality
+	byKey       map[string]pdata.Metric
+}
+
+func newMetricsBuilder(metrics pdata.MetricSlice, temporality pdata.AggregationTemporality) *metricsBuilder {
+	return &metricsBuilder{metrics: metrics, temporality: temporality, byKey: map[string]pdata.Metric{}}
+}
+
+func (b *metricsBuilder) metric(name string, dataType pdata.MetricDataType) pdata.Metric {
+	key := dataType.String() + "/" + name
+	if metric, ok := b.byKey[key]; ok {
+		return metric
+	}
+	metric := pdata.NewMetric()
+	metric.InitEmpty()
+	metric.SetName(name)
+	metric.SetDataType(dataType)
+	switch dataType {
+	case pdata.MetricDataTypeIntSum:
+		metric.IntSum().InitEmpty()
+		metric.IntSum().SetIsMonotonic(true)
+		metric.IntSum().SetAggregationTemporality(b.temporality)
+	case pdata.MetricDataTypeDoubleGauge:
+		metric.DoubleGauge().InitEmpty()
+	case pdata.MetricDataTypeDoubleHistogram:
+		metric.DoubleHistogram().InitEmpty()
+		metric.DoubleHistogram().SetAggregationTemporality(b.temporality)
+	case pdata.MetricDataTypeIntGauge:
+		metric.IntGauge().InitEmpty()
+	}
+	b.metrics.Append(metric)
+	b.byKey[key] = metric
+	return metric
+}
+
+// sortedKeys returns the keys of a map of series in order, so that the metrics are
+// emitted in a stable order.
+func sortedKeys(m interface{}) []string {
+	keys := reflect.ValueOf(m).MapKeys()
+	sorted := make([]string, len(keys))
+	for i, key := range keys {
+		sorted[i] = key.String()
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func fillLabels(labels pdata.StringMap, from []label) {
+	for _, l := range from {
+		labels.Insert(l.key, l.value)
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var (
+	testStart = time.Date(2020, 11, 5, 12, 0, 0, 0, time.UTC)
+	testFlush = testStart.Add(time.Minute)
+)
+
+func newTestAggregator(t *testing.T, cumulative bool, lines ...string) *aggregator {
+	a := newAggregator(cumulative, []float64{10, 100}, 0, testStart)
+	addLines(t, a, testStart.Add(time.Second), lines...)
+	return a
+}
+
+func addLines(t *testing.T, a *aggregator, now time.Time, lines ...string) {
+	for _, line := range lines {
+		m, err := parseMetric(line)
+		require.NoError(t, err)
+		a.add(m, now)
+	}
+}
+
+// metricsByName returns the metrics emitted by name.
+func metricsByName(md pdata.Metrics) map[string]pdata.Metric {
+	metrics := map[string]pdata.Metric{}
+	if md.ResourceMetrics().Len() == 0 {
+		return metrics
+	}
+	ms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		metrics[ms.At(i).Name()] = ms.At(i)
+	}
+	return metrics
+}
+
+func timestamp(t time.Time) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(t.UnixNano())
+}
+
+func TestAggregator_Counters(t *testing.T) {
+	a := newTestAggregator(t, false,
+		"requests:1|c|#code:200",
+		"requests:2|c|#code:200",
+		"requests:1|c|@0.1|#code:500",
+		"errors:0.4|c",
+		"errors:0.4|c",
+	)
+
+	metrics := metricsByName(a.flush(testFlush))
+	require.Len(t, metrics, 2)
+
+	requests := metrics["requests"]
+	require.Equal(t, pdata.MetricDataTypeIntSum, requests.DataType())
+	assert.True(t, requests.IntSum().IsMonotonic())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, requests.IntSum().AggregationTemporality())
+	points := requests.IntSum().DataPoints()
+	require.Equal(t, 2, points.Len())
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"code": "200"}), points.At(0).LabelsMap())
+	assert.Equal(t, int64(3), points.At(0).Value())
+	assert.Equal(t, timestamp(testStart), points.At(0).StartTime())
+	assert.Equal(t, timestamp(testFlush), points.At(0).Timestamp())
+	// The sampled counters are weighted by the inverse of their sample rate.
+	assert.Equal(t, pdata.NewStringMap().InitFromMap(map[string]string{"code": "500"}), points.At(1).LabelsMap())
+	assert.Equal(t, int64(10), points.At(1).Value())
+
+	// The sum is rounded when it is emitted.
+	assert.Equal(t, int64(1), metrics["errors"].IntSum().DataPoints().At(0).Value())
+
+	// The delta counters are reset at each interval.
+	assert.Len(t, metricsByName(a.flush(testFlush.Add(time.Minute))), 0)
+	addLines(t, a, testFlush.Add(90*time.Second), "requests:1|c|#code:200")
+	point := metricsByName(a.flush(testFlush.Add(2 * time.Minute)))["requests"].IntSum().DataPoints().At(0)
+	assert.Equal(t, int64(1), point.Value())
+	assert.Equal(t, timestamp(testFlush.Add(time.Minute)), point.StartTime())
+}
+
+func TestAggregator_CumulativeCounters(t *testing.T) {
+	a := newTestAggregator(t, true, "requests:1|c", "requests:2|c")
+
+	requests := metricsByName(a.flush(testFlush))["requests"]
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, requests.IntSum().AggregationTemporality())
+	assert.Equal(t, int64(3), requests.IntSum().DataPoints().At(0).Value())
+
+	// The cumulative counters are emitted at each interval, from the time they were first received.
+	point := metricsByName(a.flush(testFlush.Add(time.Minute)))["requests"].IntSum().DataPoints().At(0)
+	assert.Equal(t, int64(3), point.Value())
+	assert.Equal(t, timestamp(testStart.Add(time.Second)), point.StartTime())
+	assert.Equal(t, timestamp(testFlush.Add(time.Minute)), point.Timestamp())
+
+	addLines(t, a, testFlush.Add(90*time.Second), "requests:4|c")
+	point = metricsByName(a.flush(testFlush.Add(2 * time.Minute)))["requests"].IntSum().DataPoints().At(0)
+	assert.Equal(t, int64(7), point.Value())
+}
+
+func TestAggregator_NegativeCounters(t *testing.T) {
+	a := newTestAggregator(t, true, "queue:3|c|#queue:a", "queue:2|c|#queue:b")
+	assert.True(t, metricsByName(a.flush(testFlush))["queue"].IntSum().IsMonotonic())
+
+	// A counter that received a negative value is no longer monotonic.
+	addLines(t, a, testFlush.Add(time.Second), "queue:-1|c|#queue:b")
+	queue := metricsByName(a.flush(testFlush.Add(time.Minute)))["queue"]
+	assert.False(t, queue.IntSum().IsMonotonic())
+	assert.Equal(t, int64(1), queue.IntSum().DataPoints().At(1).Value())
+	assert.False(t, metricsByName(a.flush(testFlush.Add(2 * time.Minute)))["queue"].IntSum().IsMonotonic())
+}
+
+func TestAggregator_Expiration(t *testing.T) {
+	a := newAggregator(true, []float64{10, 100}, 2, testStart)
+	addLines(t, a, testStart.Add(time.Second), "requests:1|c", "latency:5|ms", "temperature:20|g")
+	assert.Len(t, metricsByName(a.flush(testFlush)), 3)
+
+	// The series are emitted until they are not updated for the expiration intervals.
+	addLines(t, a, testFlush.Add(time.Second), "requests:1|c")
+	assert.Len(t, metricsByName(a.flush(testFlush.Add(time.Minute))), 2)
+	metrics := metricsByName(a.flush(testFlush.Add(2 * time.Minute)))
+	require.Len(t, metrics, 1)
+	assert.Equal(t, int64(2), metrics["requests"].IntSum().DataPoints().At(0).Value())
+	assert.Len(t, metricsByName(a.flush(testFlush.Add(3*time.Minute))), 0)
+	assert.Empty(t, a.counters)
+	assert.Empty(t, a.gauges)
+	assert.Empty(t, a.histograms)
+
+	// An expired gauge starts again from zero.
+	addLines(t, a, testFlush.Add(3*time.Minute+time.Second), "temperature:+1|g")
+	point := metricsByName(a.flush(testFlush.Add(4 * time.Minute)))["temperature"].DoubleGauge().DataPoints().At(0)
+	assert.Equal(t, 1.0, point.Value())
+}
+
+func TestAggregator_Gauges(t *testing.T) {
+	a := newTestAggregator(t, false, "temperature:20|g", "temperature:+2.5|g", "temperature:-0.5|g", "pressure:1013|g")
+
+	metrics := metricsByName(a.flush(testFlush))
+	require.Len(t, metrics, 2)
+	temperature := metrics["temperature"]
+	require.Equal(t, pdata.MetricDataTypeDoubleGauge, temperature.DataType())
+	point := temperature.DoubleGauge().DataPoints().At(0)
+	assert.Equal(t, 22.0, point.Value())
+	assert.Equal(t, pdata.TimestampUnixNano(0), point.StartTime())
+	assert.Equal(t, timestamp(testFlush), point.Timestamp())
+
+	// The gauges are only emitted when they are updated, the relative updates apply to
+	// the last value.
+	addLines(t, a, testFlush.Add(time.Second), "temperature:+1|g")
+	metrics = metricsByName(a.flush(testFlush.Add(time.Minute)))
+	require.Len(t, metrics, 1)
+	assert.Equal(t, 23.0, metrics["temperature"].DoubleGauge().DataPoints().At(0).Value())
+}
+
+func TestAggregator_Histograms(t *testing.T) {
+	a := newTestAggregator(t, false,
+		"latency:5|ms",
+		"latency:10|h",
+		"latency:50|d|@0.5",
+		"latency:1000|ms",
+	)
+
+	latency := metricsByName(a.flush(testFlush))["latency"]
+	require.Equal(t, pdata.MetricDataTypeDoubleHistogram, latency.DataType())
+	assert.Equal(t, pdata.AggregationTemporalityDelta, latency.DoubleHistogram().AggregationTemporality())
+	point := latency.DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(5), point.Count())
+	assert.Equal(t, 1115.0, point.Sum())
+	assert.Equal(t, []float64{10, 100}, point.ExplicitBounds())
+	assert.Equal(t, []uint64{2, 2, 1}, point.BucketCounts())
+	assert.Equal(t, timestamp(testStart), point.StartTime())
+
+	assert.Len(t, metricsByName(a.flush(testFlush.Add(time.Minute))), 0)
+}
+
+func TestAggregator_CumulativeHistograms(t *testing.T) {
+	a := newTestAggregator(t, true, "latency:5|ms")
+	a.flush(testFlush)
+	addLines(t, a, testFlush.Add(time.Second), "latency:500|ms")
+
+	latency := metricsByName(a.flush(testFlush.Add(time.Minute)))["latency"]
+	assert.Equal(t, pdata.AggregationTemporalityCumulative, latency.DoubleHistogram().AggregationTemporality())
+	point := latency.DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, uint64(2), point.Count())
+	assert.Equal(t, []uint64{1, 0, 1}, point.BucketCounts())
+	assert.Equal(t, timestamp(testStart.Add(time.Second)), point.StartTime())
+}
+
+func TestAggregator_Sets(t *testing.T) {
+	a := newTestAggregator(t, true, "users:john|s", "users:jane|s", "users:john|s")
+
+	users := metricsByName(a.flush(testFlush))["users"]
+	require.Equal(t, pdata.MetricDataTypeIntGauge, users.DataType())
+	assert.Equal(t, int64(2), users.IntGauge().DataPoints().At(0).Value())
+
+	// The sets are reset at each interval.
+	assert.Len(t, metricsByName(a.flush(testFlush.Add(time.Minute))), 0)
+}
+
+func TestAggregator_SameNameDifferentTypes(t *testing.T) {
+	a := newTestAggregator(t, false, "requests:1|c", "requests:1|g")
+
+	md := a.flush(testFlush)
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, pdata.MetricDataTypeIntSum, metrics.At(0).DataType())
+	assert.Equal(t, pdata.MetricDataTypeDoubleGauge, metrics.At(1).DataType())
+}
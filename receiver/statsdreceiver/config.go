@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+)
+
+const (
+	// DeltaTemporality emits the sum of the counters and histograms over each interval.
+	DeltaTemporality = "delta"
+	// CumulativeTemporality emits the sum of the counters and histograms since they were
+	// first received.
+	CumulativeTemporality = "cumulative"
+)
+
+// Config defines configuration for the StatsD receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// NetAddr is the address listened on, its transport is either "udp" (default),
+	// "udp4", "udp6", "tcp", "tcp4" or "tcp6". Over TCP, the metrics are separated by
+	// new lines.
+	confignet.NetAddr `mapstructure:",squash"`
+
+	// AggregationInterval is the interval at which the aggregated metrics are emitted.
+	AggregationInterval time.Duration `mapstructure:"aggregation_interval"`
+
+	// Temporality is the aggregation temporality of the counters, timers and histograms,
+	// either "delta" or "cumulative".
+	Temporality string `mapstructure:"temporality"`
+
+	// HistogramBuckets are the explicit bounds of the buckets of the histograms the timers
+	// and the histograms are aggregated into, in increasing order. Defaults to
+	// 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000 and 10000.
+	HistogramBuckets []float64 `mapstructure:"histogram_buckets"`
+
+	// ExpirationIntervals is the number of intervals without updates after which the gauges,
+	// and the cumulative counters and histograms, are no longer emitted and forgotten. The
+	// series never expire if it is zero.
+	ExpirationIntervals int `mapstructure:"expiration_intervals"`
+}
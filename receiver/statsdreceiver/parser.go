@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricType is the type of a StatsD metric.
+type metricType string
+
+const (
+	counterType      metricType = "c"
+	gaugeType        metricType = "g"
+	timerType        metricType = "ms"
+	histogramType    metricType = "h"
+	distributionType metricType = "d"
+	setType          metricType = "s"
+)
+
+// label is a DogStatsD tag, the value of the tags without value is empty.
+type label struct {
+	key   string
+	value string
+}
+
+// statsdMetric is a metric line: <name>:<value>|<type>[|@<sample rate>][|#<tags>].
+type statsdMetric struct {
+	name       string
+	metricType metricType
+	// value is the value of the metrics other than sets.
+	value float64
+	// setValue is the value of the sets, which are not necessarily numbers.
+	setValue string
+	// relative is set for the gauges whose value has a sign, it is added to the
+	// current value of the gauge.
+	relative   bool
+	sampleRate float64
+	// labels are sorted by key.
+	labels []label
+}
+
+func parseMetric(line string) (statsdMetric, error) {
+	m := statsdMetric{sampleRate: 1}
+
+	sections := strings.Split(line, "|")
+	if len(sections) < 2 {
+		return m, errors.New("missing metric type")
+	}
+	sep := strings.IndexByte(sections[0], ':')
+	if sep < 0 {
+		return m, errors.New("missing metric value")
+	}
+	m.name = sections[0][:sep]
+	if m.name == "" {
+		return m, errors.New("empty metric name")
+	}
+	value := sections[0][sep+1:]
+
+	m.metricType = metricType(sections[1])
+	switch m.metricType {
+	case counterType, gaugeType, timerType, histogramType, distributionType:
+		var err error
+		if m.value, err = strconv.ParseFloat(value, 64); err != nil {
+			return m, fmt.Errorf("invalid metric value %q", value)
+		}
+		m.relative = m.metricType == gaugeType && (value[0] == '+' || value[0] == '-')
+	case setType:
+		if value == "" {
+			return m, errors.New("empty set value")
+		}
+		m.setValue = value
+	default:
+		return m, fmt.Errorf("unsupported metric type %q", sections[1])
+	}
+
+	for _, section := range sections[2:] {
+		switch {
+		case strings.HasPrefix(section, "@"):
+			rate, err := strconv.ParseFloat(section[1:], 64)
+			if err != nil || rate <= 0 || rate > 1 {
+				return m, fmt.Errorf("invalid sample rate %q", section[1:])
+			}
+			m.sampleRate = rate
+		case strings.HasPrefix(section, "#"):
+			m.labels = parseTags(section[1:])
+		}
+		// The other sections, such as the DogStatsD container ID, are ignored.
+	}
+	return m, nil
+}
+
+// parseTags parses comma separated key:value tags, the last value of a key is kept.
+func parseTags(tags string) []label {
+	values := map[string]string{}
+	for _, tag := range strings.Split(tags, ",") {
+		if tag == "" {
+			continue
+		}
+		if sep := strings.IndexByte(tag, ':'); sep >= 0 {
+			values[tag[:sep]] = tag[sep+1:]
+		} else {
+			values[tag] = ""
+		}
+	}
+	labels := make([]label, 0, len(values))
+	for key, value := range values {
+		labels = append(labels, label{key: key, value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].key < labels[j].key
+	})
+	return labels
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetric(t *testing.T) {
+	testcases := []struct {
+		line     string
+		expected statsdMetric
+	}{
+		{
+			line:     "requests:1|c",
+			expected: statsdMetric{name: "requests", metricType: counterType, value: 1, sampleRate: 1},
+		},
+		{
+			line:     "requests:2.5|c|@0.1",
+			expected: statsdMetric{name: "requests", metricType: counterType, value: 2.5, sampleRate: 0.1},
+		},
+		{
+			line:     "temperature:-3|g",
+			expected: statsdMetric{name: "temperature", metricType: gaugeType, value: -3, relative: true, sampleRate: 1},
+		},
+		{
+			line:     "temperature:+3|g",
+			expected: statsdMetric{name: "temperature", metricType: gaugeType, value: 3, relative: true, sampleRate: 1},
+		},
+		{
+			line:     "temperature:21.5|g",
+			expected: statsdMetric{name: "temperature", metricType: gaugeType, value: 21.5, sampleRate: 1},
+		},
+		{
+			line:     "latency:320|ms|@0.5|#region:eu,canary",
+			expected: statsdMetric{name: "latency", metricType: timerType, value: 320, sampleRate: 0.5, labels: []label{{"canary", ""}, {"region", "eu"}}},
+		},
+		{
+			line:     "size:1024|h|#host:a,host:b,url:http://example.com",
+			expected: statsdMetric{name: "size", metricType: histogramType, value: 1024, sampleRate: 1, labels: []label{{"host", "b"}, {"url", "http://example.com"}}},
+		},
+		{
+			line:     "latency:-1|d|c:83c0a99c0a54c0c187f461c7980e9b57f3f6a8b0c918c8d93df19a9de6f3fe1d",
+			expected: statsdMetric{name: "latency", metricType: distributionType, value: -1, sampleRate: 1},
+		},
+		{
+			line:     "users:john|s|#,",
+			expected: statsdMetric{name: "users", metricType: setType, setValue: "john", sampleRate: 1, labels: []label{}},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			m, err := parseMetric(tc.line)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestParseMetric_Errors(t *testing.T) {
+	testcases := []struct {
+		line        string
+		errorString string
+	}{
+		{line: "requests:1", errorString: "missing metric type"},
+		{line: "requests|c", errorString: "missing metric value"},
+		{line: ":1|c", errorString: "empty metric name"},
+		{line: "requests:one|c", errorString: `invalid metric value "one"`},
+		{line: "requests:|g", errorString: `invalid metric value ""`},
+		{line: "users:|s", errorString: "empty set value"},
+		{line: "requests:1|x", errorString: `unsupported metric type "x"`},
+		{line: "requests:1|c|@0", errorString: `invalid sample rate "0"`},
+		{line: "requests:1|c|@1.5", errorString: `invalid sample rate "1.5"`},
+		{line: "requests:1|c|@half", errorString: `invalid sample rate "half"`},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.line, func(t *testing.T) {
+			_, err := parseMetric(tc.line)
+			assert.EqualError(t, err, tc.errorString)
+		})
+	}
+}
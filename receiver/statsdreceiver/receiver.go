@@ -0,0 +1,291 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	tcpTransport = "tcp"
+	udpTransport = "udp"
+
+	// format is the format reported by obsreport.
+	format = "statsd"
+
+	// maxDatagramSize is the maximum size of a UDP datagram, and of a TCP line.
+	maxDatagramSize = 65535
+)
+
+type statsdReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	// transport is either udp or tcp, without the IP version.
+	transport string
+	now       func() time.Time
+
+	listener   net.Listener
+	packetConn net.PacketConn
+	stopCh     chan struct{}
+
+	mu         sync.Mutex
+	aggregator *aggregator
+	conns      map[net.Conn]struct{}
+	stopped    bool
+	wg         sync.WaitGroup
+}
+
+func newStatsdReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.MetricsConsumer) (*statsdReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint must be specified")
+	}
+	var transport string
+	switch config.Transport {
+	case "", "udp", "udp4", "udp6":
+		transport = udpTransport
+	case "tcp", "tcp4", "tcp6":
+		transport = tcpTransport
+	default:
+		return nil, fmt.Errorf("invalid transport %q, must be %q or %q", config.Transport, udpTransport, tcpTransport)
+	}
+	if config.AggregationInterval <= 0 {
+		return nil, errors.New("aggregation_interval must be positive")
+	}
+	if config.Temporality != DeltaTemporality && config.Temporality != CumulativeTemporality {
+		return nil, fmt.Errorf("invalid temporality %q, must be %q or %q", config.Temporality, DeltaTemporality, CumulativeTemporality)
+	}
+	if config.ExpirationIntervals < 0 {
+		return nil, errors.New("expiration_intervals must not be negative")
+	}
+	for i := 1; i < len(config.HistogramBuckets); i++ {
+		if config.HistogramBuckets[i] <= config.HistogramBuckets[i-1] {
+			return nil, errors.New("histogram_buckets must be in increasing order")
+		}
+	}
+
+	return &statsdReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		transport:    transport,
+		now:          time.Now,
+		stopCh:       make(chan struct{}),
+		conns:        make(map[net.Conn]struct{}),
+	}, nil
+}
+
+func (r *statsdReceiver) network() string {
+	if r.config.Transport == "" {
+		return udpTransport
+	}
+	return r.config.Transport
+}
+
+// Start listens on the configured endpoint and starts emitting the aggregated metrics
+// at each interval.
+func (r *statsdReceiver) Start(_ context.Context, _ component.Host) error {
+	bounds := r.config.HistogramBuckets
+	if len(bounds) == 0 {
+		bounds = defaultHistogramBuckets
+	}
+	r.aggregator = newAggregator(r.config.Temporality == CumulativeTemporality, bounds, r.config.ExpirationIntervals, r.now())
+	if r.transport == tcpTransport {
+		listener, err := net.Listen(r.network(), r.config.Endpoint)
+		if err != nil {
+			return fmt.Errorf("error listening on statsd endpoint: %v", err)
+		}
+		r.listener = listener
+		r.wg.Add(1)
+		go r.acceptConnections()
+	} else {
+		packetConn, err := net.ListenPacket(r.network(), r.config.Endpoint)
+		if err != nil {
+			return fmt.Errorf("error listening on statsd endpoint: %v", err)
+		}
+		r.packetConn = packetConn
+		r.wg.Add(1)
+		go r.readDatagrams()
+	}
+	r.wg.Add(1)
+	go r.flushPeriodically()
+	return nil
+}
+
+// Shutdown closes the endpoint and emits the metrics aggregated since the last interval.
+func (r *statsdReceiver) Shutdown(context.Context) error {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil
+	}
+	r.stopped = true
+	var err error
+	if r.listener != nil {
+		err = r.listener.Close()
+	}
+	if r.packetConn != nil {
+		err = r.packetConn.Close()
+	}
+	for conn := range r.conns {
+		conn.Close()
+	}
+	r.mu.Unlock()
+	close(r.stopCh)
+	r.wg.Wait()
+
+	if r.aggregator != nil {
+		r.flush()
+	}
+	return err
+}
+
+func (r *statsdReceiver) acceptConnections() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		r.mu.Lock()
+		if r.stopped {
+			r.mu.Unlock()
+			conn.Close()
+			return
+		}
+		r.conns[conn] = struct{}{}
+		r.wg.Add(1)
+		r.mu.Unlock()
+		go r.handleConnection(conn)
+	}
+}
+
+// handleConnection aggregates the metrics of a TCP connection, one per line.
+func (r *statsdReceiver) handleConnection(conn net.Conn) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.conns, conn)
+		r.mu.Unlock()
+		conn.Close()
+		r.wg.Done()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxDatagramSize)
+	for scanner.Scan() {
+		r.aggregate(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil && !r.isStopped() {
+		r.logger.Debug("Closing statsd connection", zap.String("remote", conn.RemoteAddr().String()), zap.Error(err))
+	}
+}
+
+// readDatagrams aggregates the metrics of the UDP datagrams, which can contain
+// several metrics separated by new lines.
+func (r *statsdReceiver) readDatagrams() {
+	defer r.wg.Done()
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, _, err := r.packetConn.ReadFrom(buf)
+		if n > 0 {
+			r.aggregate(string(buf[:n]))
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+	}
+}
+
+// aggregate parses and aggregates the metrics of data, separated by new lines. The
+// lines that cannot be parsed are ignored.
+func (r *statsdReceiver) aggregate(data string) {
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m, err := parseMetric(line)
+		if err != nil {
+			r.logger.Debug("Failed to parse statsd metric", zap.String("line", line), zap.Error(err))
+			continue
+		}
+		r.aggregator.add(m, now)
+	}
+}
+
+func (r *statsdReceiver) flushPeriodically() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.config.AggregationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.flush()
+		case <-r.stopCh:
+			return
+		}
+	}
+}
+
+// flush emits the metrics aggregated during the interval, if any.
+func (r *statsdReceiver) flush() {
+	r.mu.Lock()
+	md := r.aggregator.flush(r.now())
+	r.mu.Unlock()
+	if md.ResourceMetrics().Len() == 0 {
+		return
+	}
+
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.Name(), r.transport)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), r.transport)
+	_, numPoints := md.MetricAndDataPointCount()
+	err := r.nextConsumer.ConsumeMetrics(ctx, md)
+	obsreport.EndMetricsReceiveOp(ctx, format, numPoints, err)
+	if err != nil {
+		r.logger.Error("Failed to consume statsd metrics", zap.Error(err))
+	}
+}
+
+func (r *statsdReceiver) isStopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func newTestReceiver(t *testing.T, modify func(cfg *Config)) (*statsdReceiver, *consumertest.MetricsSink) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	// The metrics are flushed on shutdown.
+	cfg.AggregationInterval = time.Hour
+	if modify != nil {
+		modify(cfg)
+	}
+	sink := new(consumertest.MetricsSink)
+	r, err := newStatsdReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	return r, sink
+}
+
+// waitForSeries waits for the receiver to aggregate the given number of series.
+func waitForSeries(t *testing.T, r *statsdReceiver, count int) {
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return len(r.aggregator.counters)+len(r.aggregator.gauges)+len(r.aggregator.histograms)+len(r.aggregator.sets) == count
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewStatsdReceiver_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "empty_endpoint",
+			modify:      func(cfg *Config) { cfg.Endpoint = "" },
+			errorString: "endpoint must be specified",
+		},
+		{
+			name:        "invalid_transport",
+			modify:      func(cfg *Config) { cfg.Transport = "unix" },
+			errorString: `invalid transport "unix", must be "udp" or "tcp"`,
+		},
+		{
+			name:        "invalid_aggregation_interval",
+			modify:      func(cfg *Config) { cfg.AggregationInterval = 0 },
+			errorString: "aggregation_interval must be positive",
+		},
+		{
+			name:        "invalid_temporality",
+			modify:      func(cfg *Config) { cfg.Temporality = "monthly" },
+			errorString: `invalid temporality "monthly", must be "delta" or "cumulative"`,
+		},
+		{
+			name:        "unordered_histogram_buckets",
+			modify:      func(cfg *Config) { cfg.HistogramBuckets = []float64{10, 5} },
+			errorString: "histogram_buckets must be in increasing order",
+		},
+		{
+			name:        "negative_expiration_intervals",
+			modify:      func(cfg *Config) { cfg.ExpirationIntervals = -1 },
+			errorString: "expiration_intervals must not be negative",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			tc.modify(cfg)
+			r, err := newStatsdReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, r)
+		})
+	}
+}
+
+func TestStatsdReceiver_UDP(t *testing.T) {
+	r, sink := newTestReceiver(t, nil)
+
+	conn, err := net.Dial("udp", r.config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("requests:1|c|#code:200\nrequests:2|c|#code:200\ninvalid\n"))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("temperature:21.5|g\nlatency:12|ms|@0.5"))
+	require.NoError(t, err)
+	waitForSeries(t, r, 3)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	metrics := metricsByName(sink.AllMetrics()[0])
+	require.Len(t, metrics, 3)
+	assert.Equal(t, int64(3), metrics["requests"].IntSum().DataPoints().At(0).Value())
+	assert.Equal(t, 21.5, metrics["temperature"].DoubleGauge().DataPoints().At(0).Value())
+	assert.Equal(t, uint64(2), metrics["latency"].DoubleHistogram().DataPoints().At(0).Count())
+}
+
+func TestStatsdReceiver_TCP(t *testing.T) {
+	r, sink := newTestReceiver(t, func(cfg *Config) {
+		cfg.Transport = "tcp"
+	})
+
+	conn, err := net.Dial("tcp", r.config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	for i := 0; i < 10; i++ {
+		_, err = fmt.Fprintf(conn, "users:user%d|s\r\n", i%4)
+		require.NoError(t, err)
+	}
+	waitForSeries(t, r, 1)
+
+	// Shutdown closes the open connections.
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	users := metricsByName(sink.AllMetrics()[0])["users"]
+	require.Equal(t, pdata.MetricDataTypeIntGauge, users.DataType())
+	assert.Equal(t, int64(4), users.IntGauge().DataPoints().At(0).Value())
+}
+
+func TestStatsdReceiver_AggregationInterval(t *testing.T) {
+	r, sink := newTestReceiver(t, func(cfg *Config) {
+		cfg.AggregationInterval = 50 * time.Millisecond
+	})
+	defer r.Shutdown(context.Background())
+
+	conn, err := net.Dial("udp", r.config.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("requests:1|c"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.MetricsCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, sink.AllMetrics()[0].MetricCount())
+}
+
+func TestStatsdReceiver_StartError(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = listener.Addr().String()
+	cfg.Transport = "tcp"
+	r, err := newStatsdReceiver(zap.NewNop(), cfg, consumertest.NewMetricsNop())
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
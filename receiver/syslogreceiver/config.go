@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+const (
+	// RFC3164 is the BSD syslog protocol.
+	RFC3164 = "rfc3164"
+	// RFC5424 is the syslog protocol.
+	RFC5424 = "rfc5424"
+)
+
+// TCPConfig defines the TCP endpoint of the receiver. The messages are framed either
+// by octet counting or by new lines, as described by RFC 6587, which is detected for
+// each message.
+type TCPConfig struct {
+	// NetAddr is the address listened on, its transport is "tcp" (default), "tcp4" or "tcp6".
+	confignet.NetAddr `mapstructure:",squash"`
+
+	// TLSSetting configures TLS, the connections are not encrypted if it is not set.
+	TLSSetting *configtls.TLSServerSetting `mapstructure:"tls"`
+}
+
+// UDPConfig defines the UDP endpoint of the receiver. Each datagram is a message.
+type UDPConfig struct {
+	// NetAddr is the address listened on, its transport is "udp" (default), "udp4" or "udp6".
+	confignet.NetAddr `mapstructure:",squash"`
+}
+
+// Config defines configuration for the syslog receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// Protocol is the format of the messages, either "rfc5424" or "rfc3164".
+	Protocol string `mapstructure:"protocol"`
+
+	// TCP is the TCP endpoint, messages are not received over TCP if it is not set.
+	TCP *TCPConfig `mapstructure:"tcp"`
+
+	// UDP is the UDP endpoint, messages are not received over UDP if it is not set.
+	UDP *UDPConfig `mapstructure:"udp"`
+
+	// Location is the IANA time zone name of the RFC 3164 timestamps, that have no time zone.
+	Location string `mapstructure:"location"`
+
+	// MaxLogSize is the maximum size in bytes of a message received over TCP. Longer
+	// messages framed by new lines are truncated and the connections sending longer
+	// messages framed by octet counting are closed.
+	MaxLogSize int `mapstructure:"max_log_size"`
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxOctetCountDigits bounds the length of the message length of the octet counting framing.
+const maxOctetCountDigits = 10
+
+// frameReader reads the messages of a TCP stream. As described by RFC 6587, a message
+// starting with a digit is framed by octet counting, "<length> <message>", and the
+// other messages are terminated by a new line.
+type frameReader struct {
+	br         *bufio.Reader
+	maxLogSize int
+}
+
+func newFrameReader(r io.Reader, maxLogSize int) *frameReader {
+	return &frameReader{br: bufio.NewReader(r), maxLogSize: maxLogSize}
+}
+
+// buffered returns true if the next message may be read without blocking.
+func (fr *frameReader) buffered() bool {
+	return fr.br.Buffered() > 0
+}
+
+// next returns the next non-empty message. It returns io.EOF at the end of the stream.
+func (fr *frameReader) next() ([]byte, error) {
+	for {
+		first, err := fr.br.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		var frame []byte
+		if first[0] >= '0' && first[0] <= '9' {
+			frame, err = fr.nextOctetCounted()
+		} else {
+			frame, err = fr.nextLine()
+		}
+		if err != nil || len(frame) > 0 {
+			return frame, err
+		}
+	}
+}
+
+func (fr *frameReader) nextOctetCounted() ([]byte, error) {
+	length := 0
+	for digits := 0; ; digits++ {
+		c, err := fr.br.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if c == ' ' && digits > 0 {
+			break
+		}
+		if c < '0' || c > '9' || digits == maxOctetCountDigits {
+			return nil, errors.New("invalid octet counting frame length")
+		}
+		length = length*10 + int(c-'0')
+	}
+	if length > fr.maxLogSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds max_log_size", length)
+	}
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(fr.br, frame); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return bytes.TrimRight(frame, "\r\n"), nil
+}
+
+// nextLine reads the next line, truncated to the max log size. The last line of
+// the stream does not need to be terminated by a new line.
+func (fr *frameReader) nextLine() ([]byte, error) {
+	var frame []byte
+	for {
+		line, err := fr.br.ReadSlice('\n')
+		if len(frame) < fr.maxLogSize {
+			remaining := fr.maxLogSize - len(frame)
+			if len(line) < remaining {
+				remaining = len(line)
+			}
+			frame = append(frame, line[:remaining]...)
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(frame) > 0:
+			return bytes.TrimRight(frame, "\r\n"), nil
+		case err != nil:
+			return nil, err
+		}
+		return bytes.TrimRight(frame, "\r\n"), nil
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
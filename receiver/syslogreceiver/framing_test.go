@@ -0,0 +1,117 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFrames(fr *frameReader) ([]string, error) {
+	var frames []string
+	for {
+		frame, err := fr.next()
+		if err != nil {
+			return frames, err
+		}
+		frames = append(frames, string(frame))
+	}
+}
+
+func TestFrameReader(t *testing.T) {
+	testcases := []struct {
+		name           string
+		stream         string
+		expectedFrames []string
+		expectedErr    error
+	}{
+		{
+			name:           "newline",
+			stream:         "<34>first\r\n\n<34>second\n<34>last",
+			expectedFrames: []string{"<34>first", "<34>second", "<34>last"},
+			expectedErr:    io.EOF,
+		},
+		{
+			name:           "octet_counting",
+			stream:         "9 <34>first10 <34>second\n9 <34>wi\nlf",
+			expectedFrames: []string{"<34>first", "<34>second", "<34>wi\nlf"},
+			expectedErr:    io.EOF,
+		},
+		{
+			name:           "mixed",
+			stream:         "<34>first\n10 <34>second<34>third\n",
+			expectedFrames: []string{"<34>first", "<34>second", "<34>third"},
+			expectedErr:    io.EOF,
+		},
+		{
+			name:           "truncated_line",
+			stream:         "<34>0123456789abcdef\n<34>short\n",
+			expectedFrames: []string{"<34>012345", "<34>short"},
+			expectedErr:    io.EOF,
+		},
+		{
+			name:           "too_long_octet_counting",
+			stream:         "5 <34>a11 <34>0123456",
+			expectedFrames: []string{"<34>a"},
+			expectedErr:    assert.AnError,
+		},
+		{
+			name:           "invalid_length",
+			stream:         "12x <34>",
+			expectedFrames: nil,
+			expectedErr:    assert.AnError,
+		},
+		{
+			name:           "too_many_digits",
+			stream:         "00000000001 a",
+			expectedFrames: nil,
+			expectedErr:    assert.AnError,
+		},
+		{
+			name:           "unexpected_eof",
+			stream:         "9 <34>",
+			expectedFrames: nil,
+			expectedErr:    io.ErrUnexpectedEOF,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			frames, err := readFrames(newFrameReader(strings.NewReader(tc.stream), 10))
+			assert.Equal(t, tc.expectedFrames, frames)
+			if tc.expectedErr == assert.AnError {
+				require.Error(t, err)
+				assert.NotEqual(t, io.EOF, err)
+			} else {
+				assert.Equal(t, tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestFrameReader_LongLine(t *testing.T) {
+	// Lines longer than the buffer of the reader are read in several parts.
+	line := strings.Repeat("a", 10000)
+	frames, err := readFrames(newFrameReader(strings.NewReader(line+"\n"+line+"\n"), 5000))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{line[:5000], line[:5000]}, frames)
+
+	frames, err = readFrames(newFrameReader(strings.NewReader(line+"\n"), 20000))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, []string{line}, frames)
+}
@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	// nilValue is the RFC 5424 value of the missing fields.
+	nilValue = "-"
+
+	// defaultPriority is the priority of the RFC 3164 messages without one, user.notice.
+	defaultPriority = 13
+	maxPriority     = 191
+)
+
+// severities are the severity numbers and texts of the syslog severities.
+var severities = [...]struct {
+	number pdata.SeverityNumber
+	text   string
+}{
+	{pdata.SeverityNumberFATAL4, "emerg"},
+	{pdata.SeverityNumberFATAL3, "alert"},
+	{pdata.SeverityNumberFATAL, "crit"},
+	{pdata.SeverityNumberERROR, "err"},
+	{pdata.SeverityNumberWARN, "warning"},
+	{pdata.SeverityNumberINFO2, "notice"},
+	{pdata.SeverityNumberINFO, "info"},
+	{pdata.SeverityNumberDEBUG, "debug"},
+}
+
+// sdParam is a parameter of a structured data element.
+type sdParam struct {
+	name  string
+	value string
+}
+
+// sdElement is a structured data element.
+type sdElement struct {
+	id     string
+	params []sdParam
+}
+
+// message is a parsed syslog message. The empty fields are not set in the message.
+type message struct {
+	priority       int
+	version        int
+	timestamp      time.Time
+	hostname       string
+	appName        string
+	procID         string
+	msgID          string
+	structuredData []sdElement
+	msg            string
+}
+
+func (m *message) facility() int {
+	return m.priority / 8
+}
+
+func (m *message) severity() int {
+	return m.priority % 8
+}
+
+// parsePriority parses the "<PRI>" at the beginning of the data and returns the rest.
+func parsePriority(data string) (int, string, error) {
+	if len(data) == 0 || data[0] != '<' {
+		return 0, data, errors.New("missing priority")
+	}
+	end := 1
+	for end < len(data) && end <= 3 && data[end] >= '0' && data[end] <= '9' {
+		end++
+	}
+	if end == 1 || end == len(data) || data[end] != '>' {
+		return 0, data, errors.New("invalid priority")
+	}
+	priority, _ := strconv.Atoi(data[1:end])
+	if priority > maxPriority {
+		return 0, data, fmt.Errorf("invalid priority %d", priority)
+	}
+	return priority, data[end+1:], nil
+}
+
+// toLogRecord converts the message to a log record. The timestamp of the messages
+// without one is the time they were received.
+func (m *message) toLogRecord(received time.Time, lr pdata.LogRecord) {
+	timestamp := m.timestamp
+	if timestamp.IsZero() {
+		timestamp = received
+	}
+	lr.SetTimestamp(pdata.TimestampUnixNano(timestamp.UnixNano()))
+	lr.SetSeverityNumber(severities[m.severity()].number)
+	lr.SetSeverityText(severities[m.severity()].text)
+	lr.Body().SetStringVal(m.msg)
+
+	attrs := lr.Attributes()
+	attrs.InsertInt("priority", int64(m.priority))
+	attrs.InsertInt("facility", int64(m.facility()))
+	if m.version != 0 {
+		attrs.InsertInt("version", int64(m.version))
+	}
+	insertStringIfNotEmpty(attrs, "hostname", m.hostname)
+	insertStringIfNotEmpty(attrs, "appname", m.appName)
+	insertStringIfNotEmpty(attrs, "proc_id", m.procID)
+	insertStringIfNotEmpty(attrs, "msg_id", m.msgID)
+	if len(m.structuredData) > 0 {
+		sd := pdata.NewAttributeValueMap()
+		for _, element := range m.structuredData {
+			params := pdata.NewAttributeValueMap()
+			for _, param := range element.params {
+				params.MapVal().UpsertString(param.name, param.value)
+			}
+			sd.MapVal().Upsert(element.id, params)
+		}
+		attrs.Insert("structured_data", sd)
+	}
+}
+
+func insertStringIfNotEmpty(attrs pdata.AttributeMap, key string, value string) {
+	if value != "" {
+		attrs.InsertString(key, value)
+	}
+}
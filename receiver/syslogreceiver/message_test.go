@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestMessageToLogRecord(t *testing.T) {
+	received := time.Unix(2000, 0)
+	m := &message{
+		priority:  165,
+		version:   1,
+		timestamp: time.Unix(1000, 5),
+		hostname:  "mymachine.example.com",
+		appName:   "evntslog",
+		procID:    "1234",
+		msgID:     "ID47",
+		structuredData: []sdElement{
+			{id: "exampleSDID@32473", params: []sdParam{{"iut", "3"}, {"eventSource", "Application"}}},
+			{id: "empty@32473"},
+		},
+		msg: "An application event log entry",
+	}
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	m.toLogRecord(received, lr)
+
+	assert.Equal(t, pdata.TimestampUnixNano(1000000000005), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberINFO2, lr.SeverityNumber())
+	assert.Equal(t, "notice", lr.SeverityText())
+	assert.Equal(t, "An application event log entry", lr.Body().StringVal())
+
+	exampleSD := pdata.NewAttributeValueMap()
+	exampleSD.MapVal().InsertString("iut", "3")
+	exampleSD.MapVal().InsertString("eventSource", "Application")
+	sd := pdata.NewAttributeValueMap()
+	sd.MapVal().Insert("exampleSDID@32473", exampleSD)
+	sd.MapVal().Insert("empty@32473", pdata.NewAttributeValueMap())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"priority":        pdata.NewAttributeValueInt(165),
+		"facility":        pdata.NewAttributeValueInt(20),
+		"version":         pdata.NewAttributeValueInt(1),
+		"hostname":        pdata.NewAttributeValueString("mymachine.example.com"),
+		"appname":         pdata.NewAttributeValueString("evntslog"),
+		"proc_id":         pdata.NewAttributeValueString("1234"),
+		"msg_id":          pdata.NewAttributeValueString("ID47"),
+		"structured_data": sd,
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestMessageToLogRecord_Minimal(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	(&message{priority: 0}).toLogRecord(time.Unix(2000, 0), lr)
+
+	assert.Equal(t, pdata.TimestampUnixNano(2000000000000), lr.Timestamp())
+	assert.Equal(t, pdata.SeverityNumberFATAL4, lr.SeverityNumber())
+	assert.Equal(t, "emerg", lr.SeverityText())
+	assert.Equal(t, "", lr.Body().StringVal())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"priority": pdata.NewAttributeValueInt(0),
+		"facility": pdata.NewAttributeValueInt(0),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestSeverities(t *testing.T) {
+	for severity, expected := range []pdata.SeverityNumber{
+		pdata.SeverityNumberFATAL4,
+		pdata.SeverityNumberFATAL3,
+		pdata.SeverityNumberFATAL,
+		pdata.SeverityNumberERROR,
+		pdata.SeverityNumberWARN,
+		pdata.SeverityNumberINFO2,
+		pdata.SeverityNumberINFO,
+		pdata.SeverityNumberDEBUG,
+	} {
+		m := &message{priority: 8 + severity}
+		assert.Equal(t, 1, m.facility())
+		assert.Equal(t, expected, severities[m.severity()].number)
+	}
+}
@@ -0,0 +1,309 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	tcpTransport = "tcp"
+	udpTransport = "udp"
+
+	// maxBatchSize bounds the number of messages of a TCP connection consumed together.
+	maxBatchSize = 100
+	// maxDatagramSize is the maximum size of a UDP datagram.
+	maxDatagramSize = 65535
+)
+
+type syslogReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.LogsConsumer
+	location     *time.Location
+	tlsConfig    *tls.Config
+	now          func() time.Time
+
+	listener   net.Listener
+	packetConn net.PacketConn
+
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{}
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func newSyslogReceiver(logger *zap.Logger, config *Config, nextConsumer consumer.LogsConsumer) (*syslogReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.Protocol != RFC3164 && config.Protocol != RFC5424 {
+		return nil, fmt.Errorf("invalid protocol %q, must be %q or %q", config.Protocol, RFC3164, RFC5424)
+	}
+	if config.TCP == nil && config.UDP == nil {
+		return nil, errors.New("at least one of tcp or udp must be configured")
+	}
+	if config.TCP != nil {
+		if err := validateNetAddr(config.TCP.NetAddr, tcpTransport); err != nil {
+			return nil, err
+		}
+	}
+	if config.UDP != nil {
+		if err := validateNetAddr(config.UDP.NetAddr, udpTransport); err != nil {
+			return nil, err
+		}
+	}
+	if config.MaxLogSize <= 0 {
+		return nil, errors.New("max_log_size must be positive")
+	}
+	location, err := time.LoadLocation(config.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location: %v", err)
+	}
+
+	r := &syslogReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		location:     location,
+		now:          time.Now,
+		conns:        make(map[net.Conn]struct{}),
+	}
+	if config.TCP != nil && config.TCP.TLSSetting != nil {
+		if r.tlsConfig, err = config.TCP.TLSSetting.LoadTLSConfig(); err != nil {
+			return nil, fmt.Errorf("error loading tcp tls configuration: %v", err)
+		}
+	}
+	return r, nil
+}
+
+// validateNetAddr checks the endpoint and the transport of the tcp or udp configuration.
+func validateNetAddr(addr confignet.NetAddr, network string) error {
+	if addr.Endpoint == "" {
+		return fmt.Errorf("%s endpoint must be specified", network)
+	}
+	if addr.Transport != "" && addr.Transport != network && addr.Transport != network+"4" && addr.Transport != network+"6" {
+		return fmt.Errorf("invalid %s transport %q", network, addr.Transport)
+	}
+	return nil
+}
+
+func transportOrDefault(transport string, network string) string {
+	if transport == "" {
+		return network
+	}
+	return transport
+}
+
+// Start listens on the configured endpoints.
+func (r *syslogReceiver) Start(_ context.Context, _ component.Host) error {
+	if r.config.TCP != nil {
+		listener, err := net.Listen(transportOrDefault(r.config.TCP.Transport, tcpTransport), r.config.TCP.Endpoint)
+		if err != nil {
+			return fmt.Errorf("error listening on tcp endpoint: %v", err)
+		}
+		if r.tlsConfig != nil {
+			listener = tls.NewListener(listener, r.tlsConfig)
+		}
+		r.listener = listener
+		r.wg.Add(1)
+		go r.acceptConnections()
+	}
+	if r.config.UDP != nil {
+		packetConn, err := net.ListenPacket(transportOrDefault(r.config.UDP.Transport, udpTransport), r.config.UDP.Endpoint)
+		if err != nil {
+			if r.listener != nil {
+				r.listener.Close()
+			}
+			return fmt.Errorf("error listening on udp endpoint: %v", err)
+		}
+		r.packetConn = packetConn
+		r.wg.Add(1)
+		go r.readDatagrams()
+	}
+	return nil
+}
+
+// Shutdown closes the endpoints and the TCP connections.
+func (r *syslogReceiver) Shutdown(context.Context) error {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil
+	}
+	r.stopped = true
+	var errs []error
+	if r.listener != nil {
+		if err := r.listener.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if r.packetConn != nil {
+		if err := r.packetConn.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for conn := range r.conns {
+		conn.Close()
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+	return componenterror.CombineErrors(errs)
+}
+
+func (r *syslogReceiver) acceptConnections() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+		r.mu.Lock()
+		if r.stopped {
+			r.mu.Unlock()
+			conn.Close()
+			return
+		}
+		r.conns[conn] = struct{}{}
+		r.wg.Add(1)
+		r.mu.Unlock()
+		go r.handleConnection(conn)
+	}
+}
+
+// handleConnection consumes the messages of a TCP connection. The messages read
+// without blocking are consumed together.
+func (r *syslogReceiver) handleConnection(conn net.Conn) {
+	defer func() {
+		r.mu.Lock()
+		delete(r.conns, conn)
+		r.mu.Unlock()
+		conn.Close()
+		r.wg.Done()
+	}()
+
+	fr := newFrameReader(conn, r.config.MaxLogSize)
+	for {
+		logs := pdata.NewLogSlice()
+		var err error
+		for logs.Len() < maxBatchSize {
+			var frame []byte
+			if frame, err = fr.next(); err != nil {
+				break
+			}
+			logs.Append(r.parse(frame))
+			if !fr.buffered() {
+				break
+			}
+		}
+		if logs.Len() > 0 {
+			r.consume(logs, tcpTransport)
+		}
+		if err != nil {
+			if err != io.EOF && !r.isStopped() {
+				r.logger.Debug("Closing syslog connection", zap.String("remote", conn.RemoteAddr().String()), zap.Error(err))
+			}
+			return
+		}
+	}
+}
+
+func (r *syslogReceiver) readDatagrams() {
+	defer r.wg.Done()
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, _, err := r.packetConn.ReadFrom(buf)
+		if n > 0 {
+			if frame := strings.TrimRight(string(buf[:n]), "\r\n"); frame != "" {
+				logs := pdata.NewLogSlice()
+				logs.Append(r.parse([]byte(frame)))
+				r.consume(logs, udpTransport)
+			}
+		}
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				continue
+			}
+			return
+		}
+	}
+}
+
+// parse converts a message to a log record. The log record of a message that cannot
+// be parsed has the message as body.
+func (r *syslogReceiver) parse(frame []byte) pdata.LogRecord {
+	now := r.now()
+	data := string(frame)
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+
+	var m *message
+	if r.config.Protocol == RFC3164 {
+		m = parseRFC3164(data, r.location, now)
+	} else {
+		var err error
+		if m, err = parseRFC5424(data); err != nil {
+			r.logger.Debug("Failed to parse syslog message", zap.Error(err))
+			lr.SetTimestamp(pdata.TimestampUnixNano(now.UnixNano()))
+			lr.Body().SetStringVal(data)
+			return lr
+		}
+	}
+	m.toLogRecord(now, lr)
+	return lr
+}
+
+func (r *syslogReceiver) consume(logs pdata.LogSlice, transport string) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs.MoveAndAppendTo(rl.InstrumentationLibraryLogs().At(0).Logs())
+
+	ctx := obsreport.ReceiverContext(context.Background(), r.config.Name(), transport)
+	ctx = obsreport.StartLogsReceiveOp(ctx, r.config.Name(), transport)
+	count := ld.LogRecordCount()
+	err := r.nextConsumer.ConsumeLogs(ctx, ld)
+	obsreport.EndLogsReceiveOp(ctx, r.config.Protocol, count, err)
+	if err != nil {
+		r.logger.Error("Failed to consume syslog messages", zap.Error(err))
+	}
+}
+
+func (r *syslogReceiver) isStopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
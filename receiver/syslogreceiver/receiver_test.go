@@ -0,0 +1,271 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func startTestReceiver(t *testing.T, modify func(cfg *Config)) (*syslogReceiver, *consumertest.LogsSink) {
+	cfg := createDefaultConfig().(*Config)
+	modify(cfg)
+	sink := new(consumertest.LogsSink)
+	r, err := newSyslogReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r.now = func() time.Time { return time.Date(2020, 11, 5, 12, 0, 0, 0, time.UTC) }
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() { assert.NoError(t, r.Shutdown(context.Background())) })
+	return r, sink
+}
+
+// receivedRecords waits for count log records and returns them.
+func receivedRecords(t *testing.T, sink *consumertest.LogsSink, count int) []pdata.LogRecord {
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == count
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var records []pdata.LogRecord
+	for _, ld := range sink.AllLogs() {
+		logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+		for i := 0; i < logs.Len(); i++ {
+			records = append(records, logs.At(i))
+		}
+	}
+	return records
+}
+
+func TestNewSyslogReceiver_InvalidConfig(t *testing.T) {
+	testcases := []struct {
+		name        string
+		modify      func(cfg *Config)
+		errorString string
+	}{
+		{
+			name:        "invalid_protocol",
+			modify:      func(cfg *Config) { cfg.Protocol = "rfc1234" },
+			errorString: `invalid protocol "rfc1234", must be "rfc3164" or "rfc5424"`,
+		},
+		{
+			name:        "no_endpoint",
+			modify:      func(cfg *Config) { cfg.TCP = nil },
+			errorString: "at least one of tcp or udp must be configured",
+		},
+		{
+			name:        "empty_tcp_endpoint",
+			modify:      func(cfg *Config) { cfg.TCP.Endpoint = "" },
+			errorString: "tcp endpoint must be specified",
+		},
+		{
+			name:        "invalid_tcp_transport",
+			modify:      func(cfg *Config) { cfg.TCP.Transport = "udp" },
+			errorString: `invalid tcp transport "udp"`,
+		},
+		{
+			name: "empty_udp_endpoint",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{}
+			},
+			errorString: "udp endpoint must be specified",
+		},
+		{
+			name: "invalid_udp_transport",
+			modify: func(cfg *Config) {
+				cfg.UDP = &UDPConfig{NetAddr: confignet.NetAddr{Endpoint: "localhost:5140", Transport: "unix"}}
+			},
+			errorString: `invalid udp transport "unix"`,
+		},
+		{
+			name:        "invalid_max_log_size",
+			modify:      func(cfg *Config) { cfg.MaxLogSize = 0 },
+			errorString: "max_log_size must be positive",
+		},
+		{
+			name:        "invalid_location",
+			modify:      func(cfg *Config) { cfg.Location = "Mars/Olympus_Mons" },
+			errorString: "invalid location: unknown time zone Mars/Olympus_Mons",
+		},
+		{
+			name: "invalid_tls",
+			modify: func(cfg *Config) {
+				cfg.TCP.TLSSetting = &configtls.TLSServerSetting{
+					TLSSetting: configtls.TLSSetting{CertFile: "nonexistent.crt", KeyFile: "nonexistent.key"},
+				}
+			},
+			errorString: "error loading tcp tls configuration: failed to load TLS config: failed to load TLS cert and key: open nonexistent.crt: no such file or directory",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.TCP = &TCPConfig{NetAddr: confignet.NetAddr{Endpoint: "localhost:5140"}}
+			tc.modify(cfg)
+			r, err := newSyslogReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, r)
+		})
+	}
+
+	r, err := newSyslogReceiver(zap.NewNop(), createDefaultConfig().(*Config), nil)
+	assert.Error(t, err)
+	assert.Nil(t, r)
+}
+
+func TestSyslogReceiver_TCP(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	_, sink := startTestReceiver(t, func(cfg *Config) {
+		cfg.TCP = &TCPConfig{NetAddr: confignet.NetAddr{Endpoint: endpoint}}
+	})
+
+	conn, err := net.Dial("tcp", endpoint)
+	require.NoError(t, err)
+	framed := `<165>1 2003-10-11T22:14:15Z host app 1234 - [id@1 a="1"] multi` + "\nline"
+	_, err = conn.Write([]byte("<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - 'su root' failed\n" +
+		fmt.Sprintf("%d %s", len(framed), framed) +
+		"not a syslog message\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	records := receivedRecords(t, sink, 3)
+	assert.Equal(t, "'su root' failed", records[0].Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberFATAL, records[0].SeverityNumber())
+	assert.Equal(t, pdata.TimestampUnixNano(1065910455003000000), records[0].Timestamp())
+	hostname, _ := records[0].Attributes().Get("hostname")
+	assert.Equal(t, "mymachine.example.com", hostname.StringVal())
+
+	assert.Equal(t, "multi\nline", records[1].Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberINFO2, records[1].SeverityNumber())
+	procID, _ := records[1].Attributes().Get("proc_id")
+	assert.Equal(t, "1234", procID.StringVal())
+	sd, _ := records[1].Attributes().Get("structured_data")
+	assert.Equal(t, pdata.AttributeValueMAP, sd.Type())
+
+	// The messages that cannot be parsed are received as is.
+	assert.Equal(t, "not a syslog message", records[2].Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, records[2].SeverityNumber())
+	assert.Equal(t, pdata.TimestampUnixNano(1604577600000000000), records[2].Timestamp())
+	assert.Equal(t, 0, records[2].Attributes().Len())
+}
+
+func TestSyslogReceiver_UDP(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	_, sink := startTestReceiver(t, func(cfg *Config) {
+		cfg.Protocol = RFC3164
+		cfg.Location = "Europe/Paris"
+		cfg.UDP = &UDPConfig{NetAddr: confignet.NetAddr{Endpoint: endpoint}}
+	})
+
+	conn, err := net.Dial("udp", endpoint)
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("<13>Nov  5 10:15:30 host sshd[42]: Accepted publickey\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	records := receivedRecords(t, sink, 1)
+	lr := records[0]
+	assert.Equal(t, "Accepted publickey", lr.Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberINFO2, lr.SeverityNumber())
+	assert.Equal(t, "notice", lr.SeverityText())
+	assert.Equal(t, pdata.TimestampUnixNano(1604567730000000000), lr.Timestamp())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"priority": pdata.NewAttributeValueInt(13),
+		"facility": pdata.NewAttributeValueInt(1),
+		"hostname": pdata.NewAttributeValueString("host"),
+		"appname":  pdata.NewAttributeValueString("sshd"),
+		"proc_id":  pdata.NewAttributeValueString("42"),
+	}).Sort(), lr.Attributes().Sort())
+}
+
+func TestSyslogReceiver_TLS(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	_, sink := startTestReceiver(t, func(cfg *Config) {
+		cfg.TCP = &TCPConfig{
+			NetAddr: confignet.NetAddr{Endpoint: endpoint},
+			TLSSetting: &configtls.TLSServerSetting{
+				TLSSetting: configtls.TLSSetting{
+					CertFile: path.Join(".", "testdata", "server.crt"),
+					KeyFile:  path.Join(".", "testdata", "server.key"),
+				},
+			},
+		}
+	})
+
+	ca, err := ioutil.ReadFile(path.Join(".", "testdata", "ca.crt"))
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(ca))
+	conn, err := tls.Dial("tcp", endpoint, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("<34>1 - - - - - - encrypted\n"))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	records := receivedRecords(t, sink, 1)
+	assert.Equal(t, "encrypted", records[0].Body().StringVal())
+}
+
+func TestSyslogReceiver_ShutdownWithOpenConnection(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	r, sink := startTestReceiver(t, func(cfg *Config) {
+		cfg.TCP = &TCPConfig{NetAddr: confignet.NetAddr{Endpoint: endpoint}}
+		cfg.UDP = &UDPConfig{NetAddr: confignet.NetAddr{Endpoint: endpoint}}
+	})
+
+	conn, err := net.Dial("tcp", endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("<34>1 - - - - - - open\n"))
+	require.NoError(t, err)
+	receivedRecords(t, sink, 1)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	// The connection is closed by the receiver.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	if ne, ok := err.(net.Error); ok {
+		assert.False(t, ne.Timeout())
+	}
+}
+
+func TestSyslogReceiver_StartError(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.TCP = &TCPConfig{NetAddr: confignet.NetAddr{Endpoint: listener.Addr().String()}}
+	r, err := newSyslogReceiver(zap.NewNop(), cfg, consumertest.NewLogsNop())
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	rfc3164TimestampLayout = "Jan _2 15:04:05"
+	maxTagLength           = 32
+)
+
+// parseRFC3164 parses a message formatted as described by RFC 3164:
+// <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+// As the RFC recommends, the priority of the messages without one is user.notice and
+// the messages without a valid timestamp are entirely considered as MSG. An RFC 3339
+// timestamp is also accepted. The year of the timestamps is inferred from now.
+func parseRFC3164(data string, location *time.Location, now time.Time) *message {
+	m := &message{priority: defaultPriority}
+	if priority, rest, err := parsePriority(data); err == nil {
+		m.priority = priority
+		data = rest
+	}
+
+	var ok bool
+	if m.timestamp, data, ok = parseRFC3164Timestamp(data, location, now); !ok {
+		m.msg = data
+		return m
+	}
+	m.hostname, data = nextField(data)
+	m.appName, m.procID, data = parseTag(data)
+	m.msg = data
+	return m
+}
+
+// parseRFC3164Timestamp parses the timestamp at the beginning of the data and returns
+// the data after the following space.
+func parseRFC3164Timestamp(data string, location *time.Location, now time.Time) (time.Time, string, bool) {
+	if len(data) > len(rfc3164TimestampLayout) && data[len(rfc3164TimestampLayout)] == ' ' {
+		if t, err := time.ParseInLocation(rfc3164TimestampLayout, data[:len(rfc3164TimestampLayout)], location); err == nil {
+			return withYear(t, now.In(location)), data[len(rfc3164TimestampLayout)+1:], true
+		}
+	}
+	field, rest := nextField(data)
+	if t, err := time.Parse(time.RFC3339Nano, field); err == nil && rest != "" {
+		return t, rest, true
+	}
+	return time.Time{}, data, false
+}
+
+// withYear sets the year of the timestamp to the year of now, or to the previous year
+// if the timestamp would be more than a day in the future, e.g. a message of December 31
+// received on January 1.
+func withYear(t time.Time, now time.Time) time.Time {
+	t = t.AddDate(now.Year(), 0, 0)
+	if t.Sub(now) > 24*time.Hour {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t
+}
+
+// parseTag parses the tag at the beginning of the data, that is the application name
+// optionally followed by the process ID between brackets, and a colon. It returns the
+// data after the tag, or all the data if it does not start with a tag.
+func parseTag(data string) (string, string, string) {
+	end := 0
+	for end < len(data) && end < maxTagLength && isTagChar(data[end]) {
+		end++
+	}
+	if end == 0 || end == len(data) {
+		return "", "", data
+	}
+	appName, procID, rest := data[:end], "", data[end:]
+	if rest[0] == '[' {
+		closing := strings.IndexByte(rest, ']')
+		if closing < 0 {
+			return "", "", data
+		}
+		procID, rest = rest[1:closing], rest[closing+1:]
+	}
+	if strings.HasPrefix(rest, ":") {
+		rest = rest[1:]
+	} else if procID == "" {
+		return "", "", data
+	}
+	return appName, procID, strings.TrimPrefix(rest, " ")
+}
+
+func isTagChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '_' || c == '-' || c == '/'
+}
@@ -0,0 +1,145 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRFC3164(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	now := time.Date(2021, 1, 1, 0, 30, 0, 0, location)
+
+	testcases := []struct {
+		name     string
+		data     string
+		expected *message
+	}{
+		{
+			name: "rfc_example_1",
+			data: "<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8",
+			expected: &message{
+				priority:  34,
+				timestamp: time.Date(2020, 10, 11, 22, 14, 15, 0, location),
+				hostname:  "mymachine",
+				appName:   "su",
+				msg:       "'su root' failed for lonvick on /dev/pts/8",
+			},
+		},
+		{
+			name: "pid",
+			data: "<13>Jan  1 00:29:59 host sshd[1234]: Accepted publickey",
+			expected: &message{
+				priority:  13,
+				timestamp: time.Date(2021, 1, 1, 0, 29, 59, 0, location),
+				hostname:  "host",
+				appName:   "sshd",
+				procID:    "1234",
+				msg:       "Accepted publickey",
+			},
+		},
+		{
+			name: "pid_without_colon",
+			data: "<13>Dec 31 23:59:59 host cron[42] job started",
+			expected: &message{
+				priority:  13,
+				timestamp: time.Date(2020, 12, 31, 23, 59, 59, 0, location),
+				hostname:  "host",
+				appName:   "cron",
+				procID:    "42",
+				msg:       "job started",
+			},
+		},
+		{
+			name: "no_tag",
+			data: "<13>Jan  1 00:00:00 host just a message: with a colon",
+			expected: &message{
+				priority:  13,
+				timestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, location),
+				hostname:  "host",
+				msg:       "just a message: with a colon",
+			},
+		},
+		{
+			name: "rfc3339_timestamp",
+			data: "<14>2020-11-05T10:15:30.5Z host app: message",
+			expected: &message{
+				priority:  14,
+				timestamp: time.Date(2020, 11, 5, 10, 15, 30, 500000000, time.UTC),
+				hostname:  "host",
+				appName:   "app",
+				msg:       "message",
+			},
+		},
+		{
+			name: "no_priority",
+			data: "Jan  1 00:00:00 host app: message",
+			expected: &message{
+				priority:  13,
+				timestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, location),
+				hostname:  "host",
+				appName:   "app",
+				msg:       "message",
+			},
+		},
+		{
+			name: "no_timestamp",
+			data: "<11>host app: message",
+			expected: &message{
+				priority: 11,
+				msg:      "host app: message",
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parseRFC3164(tc.data, location, now)
+			assert.True(t, tc.expected.timestamp.Equal(m.timestamp), "expected %v, got %v", tc.expected.timestamp, m.timestamp)
+			m.timestamp = tc.expected.timestamp
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	testcases := []struct {
+		data    string
+		appName string
+		procID  string
+		rest    string
+	}{
+		{data: "app: msg", appName: "app", rest: "msg"},
+		{data: "app:msg", appName: "app", rest: "msg"},
+		{data: "app[1]: msg", appName: "app", procID: "1", rest: "msg"},
+		{data: "app[1 msg", rest: "app[1 msg"},
+		{data: "app msg", rest: "app msg"},
+		{data: "app", rest: "app"},
+		{data: ": msg", rest: ": msg"},
+		{data: "abcdefghijklmnopqrstuvwxyz0123456789: msg", rest: "abcdefghijklmnopqrstuvwxyz0123456789: msg"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.data, func(t *testing.T) {
+			appName, procID, rest := parseTag(tc.data)
+			assert.Equal(t, tc.appName, appName)
+			assert.Equal(t, tc.procID, procID)
+			assert.Equal(t, tc.rest, rest)
+		})
+	}
+}
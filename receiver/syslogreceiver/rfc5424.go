@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const byteOrderMark = "\xEF\xBB\xBF"
+
+// parseRFC5424 parses a message formatted as described by RFC 5424:
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]
+func parseRFC5424(data string) (*message, error) {
+	priority, rest, err := parsePriority(data)
+	if err != nil {
+		return nil, err
+	}
+	m := &message{priority: priority}
+
+	var version string
+	version, rest = nextField(rest)
+	if m.version, err = strconv.Atoi(version); err != nil || m.version <= 0 || len(version) > 2 {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+
+	var timestamp string
+	timestamp, rest = nextField(rest)
+	if timestamp != nilValue {
+		if m.timestamp, err = time.Parse(time.RFC3339Nano, timestamp); err != nil {
+			return nil, fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+	}
+
+	for _, field := range []*string{&m.hostname, &m.appName, &m.procID, &m.msgID} {
+		*field, rest = nextField(rest)
+		if *field == "" {
+			return nil, errors.New("missing header field")
+		}
+		if *field == nilValue {
+			*field = ""
+		}
+	}
+
+	if strings.HasPrefix(rest, nilValue) {
+		rest = rest[len(nilValue):]
+	} else if m.structuredData, rest, err = parseStructuredData(rest); err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		if rest[0] != ' ' {
+			return nil, errors.New("invalid structured data")
+		}
+		m.msg = strings.TrimPrefix(rest[1:], byteOrderMark)
+	}
+	return m, nil
+}
+
+// nextField returns the data until the next space, and the data after the space.
+func nextField(data string) (string, string) {
+	if i := strings.IndexByte(data, ' '); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+	return data, ""
+}
+
+// parseStructuredData parses the structured data elements at the beginning of the data,
+// e.g. [exampleSDID@32473 iut="3" eventSource="Application"], and returns the rest.
+func parseStructuredData(data string) ([]sdElement, string, error) {
+	var elements []sdElement
+	for len(data) > 0 && data[0] == '[' {
+		end := strings.IndexAny(data, " ]")
+		if end <= 1 {
+			return nil, data, errors.New("invalid structured data element ID")
+		}
+		element := sdElement{id: data[1:end]}
+		data = data[end:]
+		for data[0] == ' ' {
+			var param sdParam
+			var err error
+			if param, data, err = parseSDParam(data[1:]); err != nil {
+				return nil, data, err
+			}
+			element.params = append(element.params, param)
+			if data == "" {
+				return nil, data, errors.New("unterminated structured data element")
+			}
+		}
+		if data[0] != ']' {
+			return nil, data, errors.New("invalid structured data element")
+		}
+		data = data[1:]
+		elements = append(elements, element)
+	}
+	if len(elements) == 0 {
+		return nil, data, errors.New("invalid structured data")
+	}
+	return elements, data, nil
+}
+
+// parseSDParam parses a structured data parameter, e.g. name="value", where '"', '\'
+// and ']' are escaped by a '\' in the value.
+func parseSDParam(data string) (sdParam, string, error) {
+	eq := strings.IndexByte(data, '=')
+	if eq <= 0 || eq+1 >= len(data) || data[eq+1] != '"' {
+		return sdParam{}, data, errors.New("invalid structured data parameter")
+	}
+	param := sdParam{name: data[:eq]}
+	var value strings.Builder
+	for i := eq + 2; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '"':
+			param.value = value.String()
+			return param, data[i+1:], nil
+		case c == '\\' && i+1 < len(data) && (data[i+1] == '"' || data[i+1] == '\\' || data[i+1] == ']'):
+			i++
+			value.WriteByte(data[i])
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return sdParam{}, data, errors.New("unterminated structured data parameter value")
+}
@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package syslogreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRFC5424(t *testing.T) {
+	testcases := []struct {
+		name     string
+		data     string
+		expected *message
+	}{
+		{
+			name: "rfc_example_1",
+			data: "<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - \xEF\xBB\xBF'su root' failed for lonvick on /dev/pts/8",
+			expected: &message{
+				priority:  34,
+				version:   1,
+				timestamp: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+				hostname:  "mymachine.example.com",
+				appName:   "su",
+				msgID:     "ID47",
+				msg:       "'su root' failed for lonvick on /dev/pts/8",
+			},
+		},
+		{
+			name: "rfc_example_3",
+			data: `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"] An application event log entry...`,
+			expected: &message{
+				priority:  165,
+				version:   1,
+				timestamp: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+				hostname:  "mymachine.example.com",
+				appName:   "evntslog",
+				msgID:     "ID47",
+				structuredData: []sdElement{
+					{id: "exampleSDID@32473", params: []sdParam{{"iut", "3"}, {"eventSource", "Application"}, {"eventID", "1011"}}},
+				},
+				msg: "An application event log entry...",
+			},
+		},
+		{
+			name: "rfc_example_4",
+			data: `<165>1 2003-10-11T22:14:15.003-07:00 mymachine.example.com evntslog 1234 ID47 [exampleSDID@32473 iut="3"][examplePriority@32473 class="high" note="a \"quoted\\\] value"][empty@32473]`,
+			expected: &message{
+				priority:  165,
+				version:   1,
+				timestamp: time.Date(2003, 10, 12, 5, 14, 15, 3000000, time.UTC),
+				hostname:  "mymachine.example.com",
+				appName:   "evntslog",
+				procID:    "1234",
+				msgID:     "ID47",
+				structuredData: []sdElement{
+					{id: "exampleSDID@32473", params: []sdParam{{"iut", "3"}}},
+					{id: "examplePriority@32473", params: []sdParam{{"class", "high"}, {"note", `a "quoted\] value`}}},
+					{id: "empty@32473"},
+				},
+			},
+		},
+		{
+			name: "nil_values",
+			data: "<0>1 - - - - - -",
+			expected: &message{
+				version: 1,
+			},
+		},
+		{
+			name: "empty_message",
+			data: "<0>1 - - - - - - ",
+			expected: &message{
+				version: 1,
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := parseRFC5424(tc.data)
+			require.NoError(t, err)
+			assert.True(t, tc.expected.timestamp.Equal(m.timestamp))
+			m.timestamp = tc.expected.timestamp
+			assert.Equal(t, tc.expected, m)
+		})
+	}
+}
+
+func TestParseRFC5424_Invalid(t *testing.T) {
+	testcases := []struct {
+		data        string
+		errorString string
+	}{
+		{data: "", errorString: "missing priority"},
+		{data: "34>1 - - - - - -", errorString: "missing priority"},
+		{data: "<>1 - - - - - -", errorString: "invalid priority"},
+		{data: "<1234>1 - - - - - -", errorString: "invalid priority"},
+		{data: "<192>1 - - - - - -", errorString: "invalid priority 192"},
+		{data: "<34> - - - - - -", errorString: `invalid version ""`},
+		{data: "<34>0 - - - - - -", errorString: `invalid version "0"`},
+		{data: "<34>1 yesterday - - - - -", errorString: `invalid timestamp "yesterday"`},
+		{data: "<34>1 - host app", errorString: "missing header field"},
+		{data: "<34>1 - - - - -", errorString: "invalid structured data"},
+		{data: "<34>1 - - - - -msg", errorString: "invalid structured data"},
+		{data: "<34>1 - - - - - [] msg", errorString: "invalid structured data element ID"},
+		{data: "<34>1 - - - - - [id a=1] msg", errorString: "invalid structured data parameter"},
+		{data: `<34>1 - - - - - [id a="1] msg`, errorString: "unterminated structured data parameter value"},
+		{data: `<34>1 - - - - - [id a="1"`, errorString: "unterminated structured data element"},
+		{data: `<34>1 - - - - - [id a="1"x] msg`, errorString: "invalid structured data element"},
+		{data: `<34>1 - - - - - [id]msg`, errorString: "invalid structured data"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.data, func(t *testing.T) {
+			m, err := parseRFC5424(tc.data)
+			assert.EqualError(t, err, tc.errorString)
+			assert.Nil(t, m)
+		})
+	}
+}
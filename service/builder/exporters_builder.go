@@ -160,21 +160,48 @@ func NewExportersBuilder(
 
 // BuildExporters exporters from config.
 func (eb *ExportersBuilder) Build() (Exporters, error) {
+	return eb.build(func(string) *builtExporter { return nil })
+}
+
+// Rebuild builds the exporters that are in changes and reuses, for the rest, the exporters
+// previously built from previousConfig.
+func (eb *ExportersBuilder) Rebuild(previousConfig *configmodels.Config, previous Exporters, changes *ConfigChanges) (Exporters, error) {
+	return eb.build(func(name string) *builtExporter {
+		if changes.Exporters[name] {
+			return nil
+		}
+		return previous[previousConfig.Exporters[name]]
+	})
+}
+
+func (eb *ExportersBuilder) build(reuse func(name string) *builtExporter) (Exporters, error) {
 	exporters := make(Exporters)
+	// built are the exporters created by this call, as opposed to the reused ones.
+	built := make(Exporters)
 
 	// We need to calculate required input data types for each exporter so that we know
 	// which data type must be started for each exporter.
 	exporterInputDataTypes := eb.calcExportersRequiredDataTypes()
 
 	// BuildExporters exporters based on configuration and required input data types.
-	for _, cfg := range eb.config.Exporters {
+	for name, cfg := range eb.config.Exporters {
+		if exp := reuse(name); exp != nil {
+			exporters[cfg] = exp
+			continue
+		}
+
 		componentLogger := eb.logger.With(zap.String(typeLogKey, string(cfg.Type())), zap.String(nameLogKey, cfg.Name()))
 		exp, err := eb.buildExporter(context.Background(), componentLogger, eb.appInfo, cfg, exporterInputDataTypes)
 		if err != nil {
+			// The exporters created so far are not returned, release their resources.
+			if shutdownErr := built.ShutdownAll(context.Background()); shutdownErr != nil {
+				eb.logger.Warn("Failed to shutdown exporters", zap.Error(shutdownErr))
+			}
 			return nil, err
 		}
 
 		exporters[cfg] = exp
+		built[cfg] = exp
 	}
 
 	return exporters, nil
@@ -141,6 +141,10 @@ func (eb *ExtensionsBuilder) Build() (Extensions, error) {
 		componentLogger := eb.logger.With(zap.String(typeLogKey, string(extCfg.Type())), zap.String(nameLogKey, extCfg.Name()))
 		ext, err := eb.buildExtension(componentLogger, eb.appInfo, extCfg)
 		if err != nil {
+			// The extensions created so far are not returned, release their resources.
+			if shutdownErr := extensions.ShutdownAll(context.Background()); shutdownErr != nil {
+				eb.logger.Warn("Failed to shutdown extensions", zap.Error(shutdownErr))
+			}
 			return nil, err
 		}
 
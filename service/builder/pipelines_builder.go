@@ -101,9 +101,29 @@ func NewPipelinesBuilder(
 
 // BuildProcessors pipeline processors from config.
 func (pb *PipelinesBuilder) Build() (BuiltPipelines, error) {
+	return pb.build(func(string) *builtPipeline { return nil })
+}
+
+// Rebuild builds the pipelines that are in changes and reuses, for the rest, the pipelines
+// previously built from previousConfig.
+func (pb *PipelinesBuilder) Rebuild(previousConfig *configmodels.Config, previous BuiltPipelines, changes *ConfigChanges) (BuiltPipelines, error) {
+	return pb.build(func(name string) *builtPipeline {
+		if changes.Pipelines[name] {
+			return nil
+		}
+		return previous[previousConfig.Service.Pipelines[name]]
+	})
+}
+
+func (pb *PipelinesBuilder) build(reuse func(name string) *builtPipeline) (BuiltPipelines, error) {
 	pipelineProcessors := make(BuiltPipelines)
 
-	for _, pipeline := range pb.config.Service.Pipelines {
+	for name, pipeline := range pb.config.Service.Pipelines {
+		if bp := reuse(name); bp != nil {
+			pipelineProcessors[pipeline] = bp
+			continue
+		}
+
 		firstProcessor, err := pb.buildPipeline(context.Background(), pipeline)
 		if err != nil {
 			return nil, err
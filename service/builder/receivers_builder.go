@@ -99,10 +99,30 @@ func NewReceiversBuilder(
 
 // BuildProcessors receivers from config.
 func (rb *ReceiversBuilder) Build() (Receivers, error) {
+	return rb.build(func(string) *builtReceiver { return nil })
+}
+
+// Rebuild builds the receivers that are in changes and reuses, for the rest, the receivers
+// previously built from previousConfig.
+func (rb *ReceiversBuilder) Rebuild(previousConfig *configmodels.Config, previous Receivers, changes *ConfigChanges) (Receivers, error) {
+	return rb.build(func(name string) *builtReceiver {
+		if changes.Receivers[name] {
+			return nil
+		}
+		return previous[previousConfig.Receivers[name]]
+	})
+}
+
+func (rb *ReceiversBuilder) build(reuse func(name string) *builtReceiver) (Receivers, error) {
 	receivers := make(Receivers)
 
 	// BuildProcessors receivers based on configuration.
-	for _, cfg := range rb.config.Receivers {
+	for name, cfg := range rb.config.Receivers {
+		if rcv := reuse(name); rcv != nil {
+			receivers[cfg] = rcv
+			continue
+		}
+
 		logger := rb.logger.With(zap.String(typeLogKey, string(cfg.Type())), zap.String(nameLogKey, cfg.Name()))
 		rcv, err := rb.buildReceiver(context.Background(), logger, rb.appInfo, cfg)
 		if err != nil {
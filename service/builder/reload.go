@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"reflect"
+	"sort"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// ConfigChanges holds the names of the components that must be rebuilt to move from a
+// running configuration to a new one. The components not listed can keep running.
+type ConfigChanges struct {
+	// Exporters, Pipelines and Receivers hold the names of the components that were added,
+	// removed or modified, or that are connected to a component that was.
+	Exporters map[string]bool
+	Pipelines map[string]bool
+	Receivers map[string]bool
+	// Extensions is true if the configuration of any extension, or the list of enabled
+	// extensions, changed.
+	Extensions bool
+}
+
+// NewConfigChanges compares the current configuration with the next one and returns the
+// components that must be rebuilt to apply it.
+//
+// An exporter is rebuilt if its configuration or the data types it receives changed. A pipeline
+// is rebuilt if its processors, or the configuration of any of them, changed, or if any of its
+// exporters is rebuilt. A receiver is rebuilt if its configuration or the pipelines it is
+// attached to changed, or if any of those pipelines is rebuilt.
+func NewConfigChanges(current, next *configmodels.Config) *ConfigChanges {
+	changes := &ConfigChanges{
+		Exporters: make(map[string]bool),
+		Pipelines: make(map[string]bool),
+		Receivers: make(map[string]bool),
+		Extensions: !reflect.DeepEqual(current.Extensions, next.Extensions) ||
+			!reflect.DeepEqual(current.Service.Extensions, next.Service.Extensions),
+	}
+
+	currentDataTypes := exportersDataTypes(current)
+	nextDataTypes := exportersDataTypes(next)
+	for name := range unionKeys(exporterNames(current), exporterNames(next)) {
+		if !reflect.DeepEqual(current.Exporters[name], next.Exporters[name]) ||
+			!reflect.DeepEqual(currentDataTypes[name], nextDataTypes[name]) {
+			changes.Exporters[name] = true
+		}
+	}
+
+	for name := range unionKeys(pipelineNames(current), pipelineNames(next)) {
+		if changes.pipelineChanged(current, next, name) {
+			changes.Pipelines[name] = true
+		}
+	}
+
+	for name := range unionKeys(receiverNames(current), receiverNames(next)) {
+		if changes.receiverChanged(current, next, name) {
+			changes.Receivers[name] = true
+		}
+	}
+
+	return changes
+}
+
+// IsEmpty returns true if no component must be rebuilt.
+func (cc *ConfigChanges) IsEmpty() bool {
+	return len(cc.Exporters) == 0 && len(cc.Pipelines) == 0 && len(cc.Receivers) == 0 && !cc.Extensions
+}
+
+func (cc *ConfigChanges) pipelineChanged(current, next *configmodels.Config, name string) bool {
+	currentPipeline := current.Service.Pipelines[name]
+	nextPipeline := next.Service.Pipelines[name]
+	if currentPipeline == nil || nextPipeline == nil {
+		return true
+	}
+	if currentPipeline.InputType != nextPipeline.InputType ||
+		!reflect.DeepEqual(currentPipeline.Processors, nextPipeline.Processors) ||
+		!reflect.DeepEqual(currentPipeline.Exporters, nextPipeline.Exporters) {
+		return true
+	}
+	for _, procName := range nextPipeline.Processors {
+		if !reflect.DeepEqual(current.Processors[procName], next.Processors[procName]) {
+			return true
+		}
+	}
+	for _, expName := range nextPipeline.Exporters {
+		if cc.Exporters[expName] {
+			return true
+		}
+	}
+	return false
+}
+
+func (cc *ConfigChanges) receiverChanged(current, next *configmodels.Config, name string) bool {
+	if !reflect.DeepEqual(current.Receivers[name], next.Receivers[name]) {
+		return true
+	}
+	currentPipelines := attachedPipelineNames(current, name)
+	nextPipelines := attachedPipelineNames(next, name)
+	if !reflect.DeepEqual(currentPipelines, nextPipelines) {
+		return true
+	}
+	for _, pipelineName := range nextPipelines {
+		if cc.Pipelines[pipelineName] {
+			return true
+		}
+	}
+	return false
+}
+
+// exportersDataTypes returns, by exporter name, the data types that each exporter receives.
+func exportersDataTypes(config *configmodels.Config) map[string]map[configmodels.DataType]bool {
+	result := make(map[string]map[configmodels.DataType]bool)
+	for _, pipeline := range config.Service.Pipelines {
+		for _, expName := range pipeline.Exporters {
+			if result[expName] == nil {
+				result[expName] = make(map[configmodels.DataType]bool)
+			}
+			result[expName][pipeline.InputType] = true
+		}
+	}
+	return result
+}
+
+// attachedPipelineNames returns the sorted names of the pipelines that use the receiver.
+func attachedPipelineNames(config *configmodels.Config, receiverName string) []string {
+	var names []string
+	for name, pipeline := range config.Service.Pipelines {
+		if hasReceiver(pipeline, receiverName) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func exporterNames(config *configmodels.Config) map[string]bool {
+	names := make(map[string]bool, len(config.Exporters))
+	for name := range config.Exporters {
+		names[name] = true
+	}
+	return names
+}
+
+func pipelineNames(config *configmodels.Config) map[string]bool {
+	names := make(map[string]bool, len(config.Service.Pipelines))
+	for name := range config.Service.Pipelines {
+		names[name] = true
+	}
+	return names
+}
+
+func receiverNames(config *configmodels.Config) map[string]bool {
+	names := make(map[string]bool, len(config.Receivers))
+	for name := range config.Receivers {
+		names[name] = true
+	}
+	return names
+}
+
+func unionKeys(a, b map[string]bool) map[string]bool {
+	result := make(map[string]bool, len(a)+len(b))
+	for name := range a {
+		result[name] = true
+	}
+	for name := range b {
+		result[name] = true
+	}
+	return result
+}
+
+// Subset returns the exporters whose names are in names.
+func (exps Exporters) Subset(names map[string]bool) Exporters {
+	result := make(Exporters)
+	for cfg, exp := range exps {
+		if names[cfg.Name()] {
+			result[cfg] = exp
+		}
+	}
+	return result
+}
+
+// Subset returns the pipelines whose names are in names.
+func (bps BuiltPipelines) Subset(names map[string]bool) BuiltPipelines {
+	result := make(BuiltPipelines)
+	for cfg, bp := range bps {
+		if names[cfg.Name] {
+			result[cfg] = bp
+		}
+	}
+	return result
+}
+
+// Subset returns the receivers whose names are in names.
+func (rcvs Receivers) Subset(names map[string]bool) Receivers {
+	result := make(Receivers)
+	for cfg, rcv := range rcvs {
+		if names[cfg.Name()] {
+			result[cfg] = rcv
+		}
+	}
+	return result
+}
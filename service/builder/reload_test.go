@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func loadReloadTestConfigs(t *testing.T) (component.Factories, *configmodels.Config, *configmodels.Config) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	current, err := configtest.LoadConfigFile(t, "testdata/pipelines_builder.yaml", factories)
+	require.NoError(t, err)
+	next, err := configtest.LoadConfigFile(t, "testdata/pipelines_builder.yaml", factories)
+	require.NoError(t, err)
+	return factories, current, next
+}
+
+func TestNewConfigChanges_NoChanges(t *testing.T) {
+	_, current, next := loadReloadTestConfigs(t)
+	changes := NewConfigChanges(current, next)
+	assert.True(t, changes.IsEmpty())
+}
+
+func TestNewConfigChanges_ExporterChanged(t *testing.T) {
+	_, current, next := loadReloadTestConfigs(t)
+	next.Exporters["exampleexporter/2"].(*componenttest.ExampleExporter).ExtraSetting = "changed"
+
+	changes := NewConfigChanges(current, next)
+	assert.Equal(t, map[string]bool{"exampleexporter/2": true}, changes.Exporters)
+	assert.Equal(t, map[string]bool{"traces/2": true, "metrics/3": true, "logs": true}, changes.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver/2": true, "examplereceiver/3": true, "examplereceiver/multi": true}, changes.Receivers)
+	assert.False(t, changes.Extensions)
+}
+
+func TestNewConfigChanges_ProcessorChanged(t *testing.T) {
+	_, current, next := loadReloadTestConfigs(t)
+	next.Processors["exampleprocessor"].(*componenttest.ExampleProcessorCfg).ExtraSetting = "changed"
+
+	changes := NewConfigChanges(current, next)
+	assert.Empty(t, changes.Exporters)
+	assert.Equal(t, map[string]bool{"traces": true, "traces/2": true}, changes.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver": true, "examplereceiver/2": true, "examplereceiver/multi": true}, changes.Receivers)
+}
+
+func TestNewConfigChanges_ReceiverAttachedToNewPipeline(t *testing.T) {
+	_, current, next := loadReloadTestConfigs(t)
+	next.Service.Pipelines["metrics/2"].Receivers = []string{"examplereceiver/2", "examplereceiver/3"}
+
+	changes := NewConfigChanges(current, next)
+	assert.Empty(t, changes.Exporters)
+	assert.Empty(t, changes.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver/2": true}, changes.Receivers)
+}
+
+func TestNewConfigChanges_PipelineRemoved(t *testing.T) {
+	_, current, next := loadReloadTestConfigs(t)
+	delete(next.Service.Pipelines, "metrics/3")
+
+	changes := NewConfigChanges(current, next)
+	// The exporter no longer receives metrics.
+	assert.Equal(t, map[string]bool{"exampleexporter/2": true}, changes.Exporters)
+	assert.Equal(t, map[string]bool{"traces/2": true, "metrics/3": true, "logs": true}, changes.Pipelines)
+	assert.Equal(t, map[string]bool{"examplereceiver/2": true, "examplereceiver/3": true, "examplereceiver/multi": true}, changes.Receivers)
+}
+
+func TestNewConfigChanges_ExtensionsChanged(t *testing.T) {
+	_, current, next := loadReloadTestConfigs(t)
+	next.Service.Extensions = []string{"exampleextension"}
+
+	changes := NewConfigChanges(current, next)
+	assert.True(t, changes.Extensions)
+	assert.False(t, changes.IsEmpty())
+}
+
+func TestRebuild(t *testing.T) {
+	factories, current, next := loadReloadTestConfigs(t)
+	next.Exporters["exampleexporter/2"].(*componenttest.ExampleExporter).ExtraSetting = "changed"
+	changes := NewConfigChanges(current, next)
+
+	appInfo := componenttest.TestApplicationStartInfo()
+	exporters, err := NewExportersBuilder(zap.NewNop(), appInfo, current, factories.Exporters).Build()
+	require.NoError(t, err)
+	pipelines, err := NewPipelinesBuilder(zap.NewNop(), appInfo, current, exporters, factories.Processors).Build()
+	require.NoError(t, err)
+	receivers, err := NewReceiversBuilder(zap.NewNop(), appInfo, current, pipelines, factories.Receivers).Build()
+	require.NoError(t, err)
+
+	newExporters, err := NewExportersBuilder(zap.NewNop(), appInfo, next, factories.Exporters).Rebuild(current, exporters, changes)
+	require.NoError(t, err)
+	newPipelines, err := NewPipelinesBuilder(zap.NewNop(), appInfo, next, newExporters, factories.Processors).Rebuild(current, pipelines, changes)
+	require.NoError(t, err)
+	newReceivers, err := NewReceiversBuilder(zap.NewNop(), appInfo, next, newPipelines, factories.Receivers).Rebuild(current, receivers, changes)
+	require.NoError(t, err)
+
+	require.Len(t, newExporters, len(exporters))
+	assert.Same(t, exporters[current.Exporters["exampleexporter"]], newExporters[next.Exporters["exampleexporter"]])
+	assert.NotSame(t, exporters[current.Exporters["exampleexporter/2"]], newExporters[next.Exporters["exampleexporter/2"]])
+
+	require.Len(t, newPipelines, len(pipelines))
+	for name := range next.Service.Pipelines {
+		if changes.Pipelines[name] {
+			assert.NotSame(t, pipelines[current.Service.Pipelines[name]], newPipelines[next.Service.Pipelines[name]], name)
+		} else {
+			assert.Same(t, pipelines[current.Service.Pipelines[name]], newPipelines[next.Service.Pipelines[name]], name)
+		}
+	}
+
+	require.Len(t, newReceivers, len(receivers))
+	for name := range next.Receivers {
+		if changes.Receivers[name] {
+			assert.NotSame(t, receivers[current.Receivers[name]], newReceivers[next.Receivers[name]], name)
+		} else {
+			assert.Same(t, receivers[current.Receivers[name]], newReceivers[next.Receivers[name]], name)
+		}
+	}
+
+	assert.Len(t, newReceivers.Subset(changes.Receivers), 3)
+	assert.Len(t, newPipelines.Subset(changes.Pipelines), 3)
+	assert.Len(t, newExporters.Subset(changes.Exporters), 1)
+}
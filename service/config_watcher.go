@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// configWatcher notifies when the config file changes.
+type configWatcher struct {
+	watcher  *fsnotify.Watcher
+	file     string
+	realPath string
+	changed  chan struct{}
+	done     chan struct{}
+	logger   *zap.Logger
+}
+
+// newConfigWatcher starts watching the given config file. The directory of the file is watched,
+// instead of the file itself, so that files replaced by a rename, as done by most editors, or by
+// a symbolic link update, as done by Kubernetes for mounted ConfigMaps, are also detected.
+func newConfigWatcher(file string, logger *zap.Logger) (*configWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	file = filepath.Clean(file)
+	if err = watcher.Add(filepath.Dir(file)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	cw := &configWatcher{
+		watcher: watcher,
+		file:    file,
+		changed: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		logger:  logger,
+	}
+	cw.realPath, _ = filepath.EvalSymlinks(file)
+	go cw.run()
+	return cw, nil
+}
+
+// Changed returns a channel that receives a value when the config file changes. Changes that
+// happen before the previous one was received are coalesced.
+func (cw *configWatcher) Changed() <-chan struct{} {
+	return cw.changed
+}
+
+// Close stops watching the config file.
+func (cw *configWatcher) Close() error {
+	err := cw.watcher.Close()
+	<-cw.done
+	return err
+}
+
+func (cw *configWatcher) run() {
+	defer close(cw.done)
+	for {
+		select {
+		case event, ok := <-cw.watcher.Events:
+			if !ok {
+				return
+			}
+			if cw.isConfigChange(event) {
+				select {
+				case cw.changed <- struct{}{}:
+				default:
+				}
+			}
+		case err, ok := <-cw.watcher.Errors:
+			if !ok {
+				return
+			}
+			cw.logger.Warn("Error watching the config file", zap.String("file", cw.file), zap.Error(err))
+		}
+	}
+}
+
+func (cw *configWatcher) isConfigChange(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	realPath, _ := filepath.EvalSymlinks(cw.file)
+	if realPath != cw.realPath {
+		cw.realPath = realPath
+		return true
+	}
+	return filepath.Clean(event.Name) == cw.file
+}
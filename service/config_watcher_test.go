@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func waitForConfigChange(t *testing.T, cw *configWatcher) {
+	select {
+	case <-cw.Changed():
+	case <-time.After(5 * time.Second):
+		t.Fatal("config change was not detected")
+	}
+}
+
+func TestConfigWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(file, []byte("receivers:"), 0600))
+
+	cw, err := newConfigWatcher(file, zap.NewNop())
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, cw.Close())
+	}()
+
+	// Changes to other files in the directory are ignored.
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other.yaml"), []byte("other"), 0600))
+	select {
+	case <-cw.Changed():
+		t.Fatal("unexpected config change")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, ioutil.WriteFile(file, []byte("exporters:"), 0600))
+	waitForConfigChange(t, cw)
+
+	// Replace the file as editors do.
+	tmp := filepath.Join(dir, "config.yaml.tmp")
+	require.NoError(t, ioutil.WriteFile(tmp, []byte("processors:"), 0600))
+	require.NoError(t, os.Rename(tmp, file))
+	waitForConfigChange(t, cw)
+}
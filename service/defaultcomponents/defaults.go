@@ -19,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/exporter/fileexporter"
+	"go.opentelemetry.io/collector/exporter/influxdbexporter"
 	"go.opentelemetry.io/collector/exporter/jaegerexporter"
 	"go.opentelemetry.io/collector/exporter/kafkaexporter"
 	"go.opentelemetry.io/collector/exporter/loggingexporter"
@@ -35,18 +36,32 @@ import (
 	"go.opentelemetry.io/collector/processor/attributesprocessor"
 	"go.opentelemetry.io/collector/processor/batchprocessor"
 	"go.opentelemetry.io/collector/processor/filterprocessor"
+	"go.opentelemetry.io/collector/processor/logparserprocessor"
 	"go.opentelemetry.io/collector/processor/memorylimiter"
+	"go.opentelemetry.io/collector/processor/metricstransformprocessor"
 	"go.opentelemetry.io/collector/processor/queuedprocessor"
+	"go.opentelemetry.io/collector/processor/redactionprocessor"
 	"go.opentelemetry.io/collector/processor/resourceprocessor"
+	"go.opentelemetry.io/collector/processor/routingprocessor"
 	"go.opentelemetry.io/collector/processor/samplingprocessor/probabilisticsamplerprocessor"
+	"go.opentelemetry.io/collector/processor/samplingprocessor/tailsamplingprocessor"
+	"go.opentelemetry.io/collector/processor/spanmetricsprocessor"
 	"go.opentelemetry.io/collector/processor/spanprocessor"
+	"go.opentelemetry.io/collector/processor/temporalityprocessor"
+	"go.opentelemetry.io/collector/receiver/carbonreceiver"
+	"go.opentelemetry.io/collector/receiver/filelogreceiver"
 	"go.opentelemetry.io/collector/receiver/fluentforwardreceiver"
 	"go.opentelemetry.io/collector/receiver/hostmetricsreceiver"
+	"go.opentelemetry.io/collector/receiver/influxdbreceiver"
 	"go.opentelemetry.io/collector/receiver/jaegerreceiver"
 	"go.opentelemetry.io/collector/receiver/kafkareceiver"
 	"go.opentelemetry.io/collector/receiver/opencensusreceiver"
+	"go.opentelemetry.io/collector/receiver/otlpfilereceiver"
 	"go.opentelemetry.io/collector/receiver/otlpreceiver"
 	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
+	"go.opentelemetry.io/collector/receiver/prometheusremotewritereceiver"
+	"go.opentelemetry.io/collector/receiver/statsdreceiver"
+	"go.opentelemetry.io/collector/receiver/syslogreceiver"
 	"go.opentelemetry.io/collector/receiver/zipkinreceiver"
 )
 
@@ -77,6 +92,13 @@ func Components() (
 		otlpreceiver.NewFactory(),
 		hostmetricsreceiver.NewFactory(),
 		kafkareceiver.NewFactory(),
+		filelogreceiver.NewFactory(),
+		syslogreceiver.NewFactory(),
+		otlpfilereceiver.NewFactory(),
+		statsdreceiver.NewFactory(),
+		prometheusremotewritereceiver.NewFactory(),
+		influxdbreceiver.NewFactory(),
+		carbonreceiver.NewFactory(),
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -93,6 +115,7 @@ func Components() (
 		otlpexporter.NewFactory(),
 		otlphttpexporter.NewFactory(),
 		kafkaexporter.NewFactory(),
+		influxdbexporter.NewFactory(),
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -105,8 +128,15 @@ func Components() (
 		batchprocessor.NewFactory(),
 		memorylimiter.NewFactory(),
 		probabilisticsamplerprocessor.NewFactory(),
+		tailsamplingprocessor.NewFactory(),
 		spanprocessor.NewFactory(),
 		filterprocessor.NewFactory(),
+		routingprocessor.NewFactory(),
+		spanmetricsprocessor.NewFactory(),
+		metricstransformprocessor.NewFactory(),
+		temporalityprocessor.NewFactory(),
+		redactionprocessor.NewFactory(),
+		logparserprocessor.NewFactory(),
 	)
 	if err != nil {
 		errs = append(errs, err)
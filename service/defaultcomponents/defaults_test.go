@@ -41,6 +41,13 @@ func TestDefaultComponents(t *testing.T) {
 		"hostmetrics",
 		"fluentforward",
 		"kafka",
+		"filelog",
+		"syslog",
+		"otlpfile",
+		"statsd",
+		"prometheusremotewrite",
+		"influxdb",
+		"carbon",
 	}
 	expectedProcessors := []configmodels.Type{
 		"attributes",
@@ -49,8 +56,15 @@ func TestDefaultComponents(t *testing.T) {
 		"batch",
 		"memory_limiter",
 		"probabilistic_sampler",
+		"tail_sampling",
 		"span",
 		"filter",
+		"routing",
+		"spanmetrics",
+		"metricstransform",
+		"temporality",
+		"redaction",
+		"logparser",
 	}
 	expectedExporters := []configmodels.Type{
 		"opencensus",
@@ -63,6 +77,7 @@ func TestDefaultComponents(t *testing.T) {
 		"otlp",
 		"otlphttp",
 		"kafka",
+		"influxdb",
 	}
 
 	factories, err := Components()
@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/service/builder"
+)
+
+// reloadPlan holds the components built from a new configuration, ready to replace the
+// running ones. Components that did not change are shared with the running configuration.
+type reloadPlan struct {
+	config     *configmodels.Config
+	changes    *builder.ConfigChanges
+	extensions builder.Extensions
+	exporters  builder.Exporters
+	pipelines  builder.BuiltPipelines
+	receivers  builder.Receivers
+}
+
+// reloadConfiguration loads the configuration again and applies it, rebuilding only the
+// components that changed. If the new configuration cannot be loaded or built the current
+// one keeps running, and if its components cannot be started the previous configuration is
+// restored. An error is only returned if the previous configuration could not be restored
+// either, leaving the application in an inconsistent state.
+func (app *Application) reloadConfiguration(ctx context.Context) error {
+	plan, err := app.prepareReload()
+	if err != nil {
+		app.logger.Error("Cannot reload configuration, keeping the current one", zap.Error(err))
+		return nil
+	}
+	if plan.changes.IsEmpty() {
+		app.logger.Info("Configuration did not change")
+		return nil
+	}
+	return app.applyReload(ctx, plan)
+}
+
+// prepareReload loads the new configuration and builds the components that changed, without
+// starting them.
+func (app *Application) prepareReload() (*reloadPlan, error) {
+	cfg, err := app.loadConfiguration(app.configFactory)
+	if err != nil {
+		return nil, err
+	}
+	return app.buildReload(cfg)
+}
+
+// buildReload builds the components of cfg that differ from the running configuration,
+// without starting them. The running components are not modified. If a component cannot be
+// built, the components already built for cfg are shut down.
+func (app *Application) buildReload(cfg *configmodels.Config) (*reloadPlan, error) {
+	var err error
+	plan := &reloadPlan{
+		config:     cfg,
+		changes:    builder.NewConfigChanges(app.config, cfg),
+		extensions: app.builtExtensions,
+	}
+	if plan.changes.IsEmpty() {
+		return plan, nil
+	}
+
+	if plan.changes.Extensions {
+		plan.extensions, err = builder.NewExtensionsBuilder(app.logger, app.info, cfg, app.factories.Extensions).Build()
+		if err != nil {
+			return nil, fmt.Errorf("cannot build builtExtensions: %w", err)
+		}
+	}
+
+	plan.exporters, err = builder.NewExportersBuilder(app.logger, app.info, cfg, app.factories.Exporters).
+		Rebuild(app.config, app.builtExporters, plan.changes)
+	if err != nil {
+		app.discardReload(plan)
+		return nil, fmt.Errorf("cannot build builtExporters: %w", err)
+	}
+
+	plan.pipelines, err = builder.NewPipelinesBuilder(app.logger, app.info, cfg, plan.exporters, app.factories.Processors).
+		Rebuild(app.config, app.builtPipelines, plan.changes)
+	if err != nil {
+		app.discardReload(plan)
+		return nil, fmt.Errorf("cannot build pipelines: %w", err)
+	}
+
+	plan.receivers, err = builder.NewReceiversBuilder(app.logger, app.info, cfg, plan.pipelines, app.factories.Receivers).
+		Rebuild(app.config, app.builtReceivers, plan.changes)
+	if err != nil {
+		app.discardReload(plan)
+		return nil, fmt.Errorf("cannot build receivers: %w", err)
+	}
+
+	return plan, nil
+}
+
+// discardReload shuts down the components built for a plan that is not applied, to release
+// the resources acquired when they were created. The components shared with the running
+// configuration are not affected.
+func (app *Application) discardReload(plan *reloadPlan) {
+	ctx := context.Background()
+	if err := plan.pipelines.Subset(plan.changes.Pipelines).ShutdownProcessors(ctx); err != nil {
+		app.logger.Warn("Failed to shutdown processors", zap.Error(err))
+	}
+	if err := plan.exporters.Subset(plan.changes.Exporters).ShutdownAll(ctx); err != nil {
+		app.logger.Warn("Failed to shutdown exporters", zap.Error(err))
+	}
+	if plan.changes.Extensions {
+		if err := plan.extensions.ShutdownAll(ctx); err != nil {
+			app.logger.Warn("Failed to shutdown extensions", zap.Error(err))
+		}
+	}
+}
+
+// applyReload shuts down the running components that changed, in the same order used for
+// the shutdown of the application, and starts the ones built for the new configuration. If
+// they cannot be started, the previous configuration is restored.
+func (app *Application) applyReload(ctx context.Context, plan *reloadPlan) error {
+	changes := plan.changes
+	app.logger.Info("Applying new configuration...",
+		zap.Int("receivers", len(changes.Receivers)),
+		zap.Int("pipelines", len(changes.Pipelines)),
+		zap.Int("exporters", len(changes.Exporters)),
+		zap.Bool("extensions", changes.Extensions),
+	)
+
+	if err := app.builtExtensions.NotifyPipelineNotReady(); err != nil {
+		app.logger.Warn("Failed to notify that pipeline is not ready", zap.Error(err))
+	}
+
+	previous := app.config
+	app.shutdownChanged(ctx, changes)
+	app.useReload(plan)
+	if err := app.startChanged(ctx, changes); err != nil {
+		app.logger.Error("Cannot start the new configuration, restoring the previous one", zap.Error(err))
+		if err := app.restoreConfiguration(ctx, previous, changes); err != nil {
+			return err
+		}
+	} else {
+		app.logger.Info("New configuration applied.")
+	}
+
+	return app.builtExtensions.NotifyPipelineReady()
+}
+
+// restoreConfiguration shuts down the changed components of the configuration that failed
+// to start, and replaces them with components built again from the previous configuration,
+// since the components that were shut down cannot be started again.
+func (app *Application) restoreConfiguration(ctx context.Context, previous *configmodels.Config, changes *builder.ConfigChanges) error {
+	app.shutdownChanged(ctx, changes)
+	plan, err := app.buildReload(previous)
+	if err != nil {
+		return fmt.Errorf("cannot rebuild the previous configuration: %w", err)
+	}
+	app.useReload(plan)
+	if err := app.startChanged(ctx, plan.changes); err != nil {
+		return fmt.Errorf("cannot start the previous configuration: %w", err)
+	}
+	app.logger.Info("Previous configuration restored.")
+	return nil
+}
+
+// shutdownChanged shuts down the running components that changed, their errors are only
+// logged.
+func (app *Application) shutdownChanged(ctx context.Context, changes *builder.ConfigChanges) {
+	app.logger.Info("Stopping changed receivers...")
+	if err := app.builtReceivers.Subset(changes.Receivers).ShutdownAll(ctx); err != nil {
+		app.logger.Warn("Failed to stop receivers", zap.Error(err))
+	}
+
+	app.logger.Info("Stopping changed processors...")
+	if err := app.builtPipelines.Subset(changes.Pipelines).ShutdownProcessors(ctx); err != nil {
+		app.logger.Warn("Failed to shutdown processors", zap.Error(err))
+	}
+
+	app.logger.Info("Stopping changed exporters...")
+	if err := app.builtExporters.Subset(changes.Exporters).ShutdownAll(ctx); err != nil {
+		app.logger.Warn("Failed to shutdown exporters", zap.Error(err))
+	}
+
+	if changes.Extensions {
+		if err := app.shutdownExtensions(ctx); err != nil {
+			app.logger.Warn("Failed to shutdown extensions", zap.Error(err))
+		}
+	}
+}
+
+// useReload replaces the running configuration and components with the ones of the plan.
+func (app *Application) useReload(plan *reloadPlan) {
+	app.config = plan.config
+	app.builtExtensions = plan.extensions
+	app.builtExporters = plan.exporters
+	app.builtPipelines = plan.pipelines
+	app.builtReceivers = plan.receivers
+}
+
+// startChanged starts the components that changed, in the same order used for the start
+// of the application.
+func (app *Application) startChanged(ctx context.Context, changes *builder.ConfigChanges) error {
+	if changes.Extensions {
+		app.logger.Info("Starting extensions...")
+		if err := app.builtExtensions.StartAll(ctx, app); err != nil {
+			return fmt.Errorf("cannot start extensions: %w", err)
+		}
+	}
+
+	app.logger.Info("Starting changed exporters...")
+	if err := app.builtExporters.Subset(changes.Exporters).StartAll(ctx, app); err != nil {
+		return fmt.Errorf("cannot start builtExporters: %w", err)
+	}
+
+	app.logger.Info("Starting changed processors...")
+	if err := app.builtPipelines.Subset(changes.Pipelines).StartProcessors(ctx, app); err != nil {
+		return fmt.Errorf("cannot start processors: %w", err)
+	}
+
+	app.logger.Info("Starting changed receivers...")
+	if err := app.builtReceivers.Subset(changes.Receivers).StartAll(ctx, app); err != nil {
+		return fmt.Errorf("cannot start receivers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+// nopAppTelemetry does not serve the metrics of the application, so that the tests do not
+// bind the metrics endpoint.
+type nopAppTelemetry struct{}
+
+func (tel *nopAppTelemetry) init(chan<- error, uint64, *zap.Logger) error {
+	return nil
+}
+
+func (tel *nopAppTelemetry) shutdown() error {
+	return nil
+}
+
+// recordingExporterFactory records the metrics exporters it creates.
+type recordingExporterFactory struct {
+	componenttest.ExampleExporterFactory
+	metricsExporters []*componenttest.ExampleExporterConsumer
+}
+
+func (f *recordingExporterFactory) CreateMetricsExporter(
+	ctx context.Context,
+	params component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.MetricsExporter, error) {
+	exp, err := f.ExampleExporterFactory.CreateMetricsExporter(ctx, params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	f.metricsExporters = append(f.metricsExporters, exp.(*componenttest.ExampleExporterConsumer))
+	return exp, nil
+}
+
+// createReloadTestApplication creates an application with a traces and a metrics pipeline,
+// each with its own exporter. The extra setting of the metrics exporter is read from
+// metricsExporterSetting every time the configuration is loaded.
+func createReloadTestApplication(t *testing.T, metricsExporterSetting *string) *Application {
+	app, _ := createReloadTestApplicationWithFailure(t, metricsExporterSetting, new(bool))
+	return app
+}
+
+// createReloadTestApplicationWithFailure creates the application of createReloadTestApplication,
+// whose metrics receiver fails to be created when failMetricsReceiver is set. It also returns the
+// factory of the exporters.
+func createReloadTestApplicationWithFailure(t *testing.T, metricsExporterSetting *string, failMetricsReceiver *bool) (*Application, *recordingExporterFactory) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	exampleReceiverFactory := &componenttest.ExampleReceiverFactory{}
+	exampleExporterFactory := &recordingExporterFactory{}
+	factories.Exporters[exampleExporterFactory.Type()] = exampleExporterFactory
+
+	app, err := New(Parameters{
+		Factories: factories,
+		ConfigFactory: func(v *viper.Viper, factories component.Factories) (*configmodels.Config, error) {
+			metricsReceiver := exampleReceiverFactory.CreateDefaultConfig().(*componenttest.ExampleReceiver)
+			metricsReceiver.SetName("examplereceiver/metrics")
+			metricsReceiver.FailMetricsCreation = *failMetricsReceiver
+			metricsExporter := exampleExporterFactory.CreateDefaultConfig().(*componenttest.ExampleExporter)
+			metricsExporter.SetName("exampleexporter/metrics")
+			metricsExporter.ExtraSetting = *metricsExporterSetting
+
+			return &configmodels.Config{
+				Receivers: map[string]configmodels.Receiver{
+					"examplereceiver":         exampleReceiverFactory.CreateDefaultConfig(),
+					"examplereceiver/metrics": metricsReceiver,
+				},
+				Exporters: map[string]configmodels.Exporter{
+					"exampleexporter":         exampleExporterFactory.CreateDefaultConfig(),
+					"exampleexporter/metrics": metricsExporter,
+				},
+				Service: configmodels.Service{
+					Pipelines: map[string]*configmodels.Pipeline{
+						"traces": {
+							Name:      "traces",
+							InputType: configmodels.TracesDataType,
+							Receivers: []string{"examplereceiver"},
+							Exporters: []string{"exampleexporter"},
+						},
+						"metrics": {
+							Name:      "metrics",
+							InputType: configmodels.MetricsDataType,
+							Receivers: []string{"examplereceiver/metrics"},
+							Exporters: []string{"exampleexporter/metrics"},
+						},
+					},
+				},
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+	return app, exampleExporterFactory
+}
+
+func getExampleExporter(t *testing.T, app *Application, dataType configmodels.DataType) *componenttest.ExampleExporterConsumer {
+	exps := app.GetExporters()[dataType]
+	require.Len(t, exps, 1)
+	for _, exp := range exps {
+		return exp.(*componenttest.ExampleExporterConsumer)
+	}
+	return nil
+}
+
+func TestApplication_ReloadOnSIGHUP(t *testing.T) {
+	preservedAppTelemetry := applicationTelemetry
+	applicationTelemetry = &nopAppTelemetry{}
+	defer func() { applicationTelemetry = preservedAppTelemetry }()
+
+	setting := "initial"
+	app := createReloadTestApplication(t, &setting)
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+
+	tracesExporter := getExampleExporter(t, app, configmodels.TracesDataType)
+	metricsExporter := getExampleExporter(t, app, configmodels.MetricsDataType)
+	receivers := app.builtReceivers
+
+	setting = "changed"
+	app.signalsChannel <- syscall.SIGHUP
+	app.signalsChannel <- syscall.SIGTERM
+	<-appDone
+	assert.Equal(t, Closing, <-app.GetStateChannel())
+	assert.Equal(t, Closed, <-app.GetStateChannel())
+
+	// Only the metrics exporter, and the pipeline and receiver that depend on it, are rebuilt.
+	assert.Same(t, tracesExporter, getExampleExporter(t, app, configmodels.TracesDataType))
+	newMetricsExporter := getExampleExporter(t, app, configmodels.MetricsDataType)
+	assert.NotSame(t, metricsExporter, newMetricsExporter)
+	assert.True(t, metricsExporter.ExporterShutdown)
+	assert.True(t, newMetricsExporter.ExporterStarted)
+	assert.Equal(t, "changed", app.config.Exporters["exampleexporter/metrics"].(*componenttest.ExampleExporter).ExtraSetting)
+
+	for cfg, rcv := range app.builtReceivers {
+		previous := receivers.Subset(map[string]bool{cfg.Name(): true})
+		require.Len(t, previous, 1)
+		for _, previousRcv := range previous {
+			if cfg.Name() == "examplereceiver" {
+				assert.Same(t, previousRcv, rcv)
+			} else {
+				assert.NotSame(t, previousRcv, rcv)
+			}
+		}
+	}
+}
+
+func TestApplication_ReloadWithoutChanges(t *testing.T) {
+	preservedAppTelemetry := applicationTelemetry
+	applicationTelemetry = &nopAppTelemetry{}
+	defer func() { applicationTelemetry = preservedAppTelemetry }()
+
+	setting := "initial"
+	app := createReloadTestApplication(t, &setting)
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+	config := app.config
+	metricsExporter := getExampleExporter(t, app, configmodels.MetricsDataType)
+
+	app.signalsChannel <- syscall.SIGHUP
+	app.signalsChannel <- syscall.SIGTERM
+	<-appDone
+
+	assert.Same(t, config, app.config)
+	assert.Same(t, metricsExporter, getExampleExporter(t, app, configmodels.MetricsDataType))
+}
+
+func TestApplication_ReloadBuildError(t *testing.T) {
+	preservedAppTelemetry := applicationTelemetry
+	applicationTelemetry = &nopAppTelemetry{}
+	defer func() { applicationTelemetry = preservedAppTelemetry }()
+
+	setting := "initial"
+	failMetricsReceiver := false
+	app, exporterFactory := createReloadTestApplicationWithFailure(t, &setting, &failMetricsReceiver)
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+	config := app.config
+	metricsExporter := getExampleExporter(t, app, configmodels.MetricsDataType)
+
+	// The new metrics exporter is built before the metrics receiver fails to be built.
+	setting = "changed"
+	failMetricsReceiver = true
+	app.signalsChannel <- syscall.SIGHUP
+	app.signalsChannel <- syscall.SIGTERM
+	<-appDone
+
+	// The current configuration keeps running and the exporter built for the new one is shut down.
+	assert.Same(t, config, app.config)
+	assert.Same(t, metricsExporter, getExampleExporter(t, app, configmodels.MetricsDataType))
+	require.Len(t, exporterFactory.metricsExporters, 2)
+	assert.False(t, exporterFactory.metricsExporters[1].ExporterStarted)
+	assert.True(t, exporterFactory.metricsExporters[1].ExporterShutdown)
+}
+
+// listeningReceiverFactory creates metrics receivers that listen on their endpoint, and
+// records them.
+type listeningReceiverFactory struct {
+	componenttest.ExampleReceiverFactory
+	receivers []*listeningReceiver
+}
+
+func (f *listeningReceiverFactory) Type() configmodels.Type {
+	return "listeningreceiver"
+}
+
+func (f *listeningReceiverFactory) CreateDefaultConfig() configmodels.Receiver {
+	cfg := f.ExampleReceiverFactory.CreateDefaultConfig().(*componenttest.ExampleReceiver)
+	cfg.TypeVal = f.Type()
+	cfg.NameVal = string(f.Type())
+	return cfg
+}
+
+func (f *listeningReceiverFactory) CreateMetricsReceiver(
+	_ context.Context,
+	_ component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	_ consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	r := &listeningReceiver{endpoint: cfg.(*componenttest.ExampleReceiver).Endpoint}
+	f.receivers = append(f.receivers, r)
+	return r, nil
+}
+
+type listeningReceiver struct {
+	endpoint string
+	listener net.Listener
+	started  bool
+}
+
+func (r *listeningReceiver) Start(context.Context, component.Host) error {
+	var err error
+	r.listener, err = net.Listen("tcp", r.endpoint)
+	r.started = err == nil
+	return err
+}
+
+func (r *listeningReceiver) Shutdown(context.Context) error {
+	if r.listener == nil {
+		return nil
+	}
+	return r.listener.Close()
+}
+
+func TestApplication_ReloadStartError(t *testing.T) {
+	preservedAppTelemetry := applicationTelemetry
+	applicationTelemetry = &nopAppTelemetry{}
+	defer func() { applicationTelemetry = preservedAppTelemetry }()
+
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	receiverFactory := &listeningReceiverFactory{}
+	factories.Receivers[receiverFactory.Type()] = receiverFactory
+	exporterFactory := &recordingExporterFactory{}
+	factories.Exporters[exporterFactory.Type()] = exporterFactory
+
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	app, err := New(Parameters{
+		Factories: factories,
+		ConfigFactory: func(v *viper.Viper, factories component.Factories) (*configmodels.Config, error) {
+			receiver := receiverFactory.CreateDefaultConfig().(*componenttest.ExampleReceiver)
+			receiver.Endpoint = endpoint
+			return &configmodels.Config{
+				Receivers: map[string]configmodels.Receiver{"listeningreceiver": receiver},
+				Exporters: map[string]configmodels.Exporter{"exampleexporter": exporterFactory.CreateDefaultConfig()},
+				Service: configmodels.Service{
+					Pipelines: map[string]*configmodels.Pipeline{
+						"metrics": {
+							Name:      "metrics",
+							InputType: configmodels.MetricsDataType,
+							Receivers: []string{"listeningreceiver"},
+							Exporters: []string{"exampleexporter"},
+						},
+					},
+				},
+			}, nil
+		},
+	})
+	require.NoError(t, err)
+
+	appDone := make(chan struct{})
+	go func() {
+		defer close(appDone)
+		assert.NoError(t, app.Run())
+	}()
+
+	assert.Equal(t, Starting, <-app.GetStateChannel())
+	assert.Equal(t, Running, <-app.GetStateChannel())
+	config := app.config
+
+	// The new endpoint of the receiver is already in use, it fails to start.
+	inUse, err := net.Listen("tcp", testutil.GetAvailableLocalAddress(t))
+	require.NoError(t, err)
+	defer inUse.Close()
+	previousEndpoint := endpoint
+	endpoint = inUse.Addr().String()
+	app.signalsChannel <- syscall.SIGHUP
+	app.signalsChannel <- syscall.SIGTERM
+	<-appDone
+	assert.Equal(t, Closing, <-app.GetStateChannel())
+	assert.Equal(t, Closed, <-app.GetStateChannel())
+
+	// The previous configuration is restored with a new receiver started on its endpoint,
+	// and the application keeps running until it is stopped.
+	assert.Same(t, config, app.config)
+	require.Len(t, receiverFactory.receivers, 3)
+	assert.False(t, receiverFactory.receivers[1].started)
+	restored := receiverFactory.receivers[2]
+	assert.Equal(t, previousEndpoint, restored.endpoint)
+	assert.True(t, restored.started)
+	// The exporter did not change, it kept running.
+	require.Len(t, exporterFactory.metricsExporters, 1)
+	assert.True(t, exporterFactory.metricsExporters[0].ExporterStarted)
+	assert.True(t, exporterFactory.metricsExporters[0].ExporterShutdown)
+}
@@ -28,6 +28,7 @@ import (
 	"runtime"
 	"sort"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -39,12 +40,18 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configsource"
 	"go.opentelemetry.io/collector/internal/collector/telemetry"
 	"go.opentelemetry.io/collector/internal/version"
 	"go.opentelemetry.io/collector/service/builder"
 	"go.opentelemetry.io/collector/service/internal"
 )
 
+const (
+	// configSourcesPollInterval is the interval used to check if the content of the config sources changed.
+	configSourcesPollInterval = 30 * time.Second
+)
+
 const (
 	servicezPath   = "servicez"
 	pipelinezPath  = "pipelinez"
@@ -81,10 +88,17 @@ type Application struct {
 	factories component.Factories
 	config    *configmodels.Config
 
+	// configFactory is used to load the configuration again when it is reloaded.
+	configFactory ConfigFactory
+
+	// configSources resolves the references to config sources in the configuration loaded
+	// by the default config factory, and watches them for changes.
+	configSources *configsource.Manager
+
 	// stopTestChan is used to terminate the application in end to end tests.
 	stopTestChan chan struct{}
 
-	// signalsChannel is used to receive termination signals from the OS.
+	// signalsChannel is used to receive termination and reload signals from the OS.
 	signalsChannel chan os.Signal
 
 	// asyncErrorChannel is used to signal a fatal error from any component.
@@ -104,7 +118,8 @@ type Parameters struct {
 	ApplicationStartInfo component.ApplicationStartInfo
 	// ConfigFactory that creates the configuration.
 	// If it is not provided the default factory (FileLoaderConfigFactory) is used.
-	// The default factory loads the configuration specified as a command line flag.
+	// The default factory loads the configuration specified as a command line flag, and
+	// the config sources it references are watched for changes.
 	ConfigFactory ConfigFactory
 	// LoggingHooks provides a way to supply a hook into logging events
 	LoggingHooks []func(zapcore.Entry) error
@@ -114,13 +129,25 @@ type Parameters struct {
 type ConfigFactory func(v *viper.Viper, factories component.Factories) (*configmodels.Config, error)
 
 // FileLoaderConfigFactory implements ConfigFactory and it creates configuration from file.
+// The file can also be a directory of files merged in order, or an http(s) URL, and its values
+// can reference config sources, see configsource.Manager.ReadConfig.
 func FileLoaderConfigFactory(v *viper.Viper, factories component.Factories) (*configmodels.Config, error) {
+	return loadConfigFile(configsource.NewManager(configsource.DefaultSources()), v, factories)
+}
+
+// fileLoaderConfigFactory is the default config factory, it loads the configuration as
+// FileLoaderConfigFactory does and keeps track of the config sources of the application, so
+// that they are watched for changes.
+func (app *Application) fileLoaderConfigFactory(v *viper.Viper, factories component.Factories) (*configmodels.Config, error) {
+	return loadConfigFile(app.configSources, v, factories)
+}
+
+func loadConfigFile(configSources *configsource.Manager, v *viper.Viper, factories component.Factories) (*configmodels.Config, error) {
 	file := builder.GetConfigFile()
 	if file == "" {
 		return nil, errors.New("config file not specified")
 	}
-	v.SetConfigFile(file)
-	err := v.ReadInConfig()
+	err := configSources.ReadConfig(context.Background(), file, v)
 	if err != nil {
 		return nil, fmt.Errorf("error loading config file %q: %v", file, err)
 	}
@@ -134,12 +161,14 @@ func New(params Parameters) (*Application, error) {
 		v:            config.NewViper(),
 		factories:    params.Factories,
 		stateChannel: make(chan State, Closed+1),
+
+		configSources: configsource.NewManager(configsource.DefaultSources()),
 	}
 
 	factory := params.ConfigFactory
 	if factory == nil {
 		// use default factory that loads the configuration file
-		factory = FileLoaderConfigFactory
+		factory = app.fileLoaderConfigFactory
 	}
 
 	rootCmd := &cobra.Command{
@@ -247,44 +276,78 @@ func (app *Application) setupTelemetry(ballastSizeBytes uint64) error {
 	return nil
 }
 
-// runAndWaitForShutdownEvent waits for one of the shutdown events that can happen.
-func (app *Application) runAndWaitForShutdownEvent() {
+// runAndWaitForShutdownEvent waits for one of the shutdown events that can happen. The configuration
+// is reloaded on SIGHUP and every time a value is received from configChanged or sourcesChanged.
+func (app *Application) runAndWaitForShutdownEvent(ctx context.Context, configChanged, sourcesChanged <-chan struct{}) {
 	app.logger.Info("Everything is ready. Begin running and processing data.")
 
-	// plug SIGTERM signal into a channel.
+	// plug SIGTERM and SIGHUP signals into a channel.
 	app.signalsChannel = make(chan os.Signal, 1)
-	signal.Notify(app.signalsChannel, os.Interrupt, syscall.SIGTERM)
+	signal.Notify(app.signalsChannel, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
 
 	// set the channel to stop testing.
 	app.stopTestChan = make(chan struct{})
 	app.stateChannel <- Running
-	select {
-	case err := <-app.asyncErrorChannel:
-		app.logger.Error("Asynchronous error received, terminating process", zap.Error(err))
-	case s := <-app.signalsChannel:
-		app.logger.Info("Received signal from OS", zap.String("signal", s.String()))
-	case <-app.stopTestChan:
-		app.logger.Info("Received stop test request")
+loop:
+	for {
+		select {
+		case err := <-app.asyncErrorChannel:
+			app.logger.Error("Asynchronous error received, terminating process", zap.Error(err))
+			break loop
+		case s := <-app.signalsChannel:
+			app.logger.Info("Received signal from OS", zap.String("signal", s.String()))
+			if s != syscall.SIGHUP {
+				break loop
+			}
+			if err := app.reloadConfiguration(ctx); err != nil {
+				app.logger.Error("Failed to apply the new configuration, terminating process", zap.Error(err))
+				break loop
+			}
+		case <-configChanged:
+			app.logger.Info("Config file changed")
+			if err := app.reloadConfiguration(ctx); err != nil {
+				app.logger.Error("Failed to apply the new configuration, terminating process", zap.Error(err))
+				break loop
+			}
+		case <-sourcesChanged:
+			if err := app.reloadConfiguration(ctx); err != nil {
+				app.logger.Error("Failed to apply the new configuration, terminating process", zap.Error(err))
+				break loop
+			}
+		case <-app.stopTestChan:
+			app.logger.Info("Received stop test request")
+			break loop
+		}
 	}
 	app.stateChannel <- Closing
 }
 
-func (app *Application) setupConfigurationComponents(ctx context.Context, factory ConfigFactory) error {
-	if err := configcheck.ValidateConfigFromFactories(app.factories); err != nil {
-		return err
-	}
-
+// loadConfiguration loads the configuration using the given factory and validates it.
+func (app *Application) loadConfiguration(factory ConfigFactory) (*configmodels.Config, error) {
 	app.logger.Info("Loading configuration...")
 	cfg, err := factory(app.v, app.factories)
 	if err != nil {
-		return fmt.Errorf("cannot load configuration: %w", err)
+		return nil, fmt.Errorf("cannot load configuration: %w", err)
 	}
 	err = config.ValidateConfig(cfg, app.logger)
 	if err != nil {
-		return fmt.Errorf("cannot load configuration: %w", err)
+		return nil, fmt.Errorf("cannot load configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+func (app *Application) setupConfigurationComponents(ctx context.Context, factory ConfigFactory) error {
+	if err := configcheck.ValidateConfigFromFactories(app.factories); err != nil {
+		return err
+	}
+
+	cfg, err := app.loadConfiguration(factory)
+	if err != nil {
+		return err
 	}
 
 	app.config = cfg
+	app.configFactory = factory
 	app.logger.Info("Applying configuration...")
 
 	err = app.setupExtensions(ctx)
@@ -421,8 +484,41 @@ func (app *Application) execute(ctx context.Context, factory ConfigFactory) erro
 		return err
 	}
 
+	var configChanged <-chan struct{}
+	if builder.WatchConfigFile() {
+		// Changes to other locations, such as directories and URLs, are detected by polling the config sources.
+		if info, statErr := os.Stat(builder.GetConfigFile()); statErr == nil && info.Mode().IsRegular() {
+			watcher, err := newConfigWatcher(builder.GetConfigFile(), app.logger)
+			if err != nil {
+				return fmt.Errorf("cannot watch config file: %w", err)
+			}
+			defer watcher.Close()
+			configChanged = watcher.Changed()
+		}
+	}
+
+	sourcesChanged := make(chan struct{}, 1)
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	go app.configSources.WatchForUpdates(watchCtx, configSourcesPollInterval, func(location string, err error) {
+		if err != nil {
+			app.logger.Warn("Cannot retrieve config source", zap.String("location", location), zap.Error(err))
+			return
+		}
+		if !builder.WatchConfigFile() {
+			app.logger.Info("Config source changed, send SIGHUP to reload the configuration", zap.String("location", location))
+			return
+		}
+		app.logger.Info("Config source changed", zap.String("location", location))
+		// Changes that happen before the previous one was handled are coalesced.
+		select {
+		case sourcesChanged <- struct{}{}:
+		default:
+		}
+	})
+
 	// Everything is ready, now run until an event requiring shutdown happens.
-	app.runAndWaitForShutdownEvent()
+	app.runAndWaitForShutdownEvent(ctx, configChanged, sourcesChanged)
 
 	// Accumulate errors and proceed with shutting down remaining components.
 	var errs []error
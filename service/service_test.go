@@ -340,6 +340,16 @@ func (b badExtensionFactory) CreateExtension(_ context.Context, _ component.Exte
 	return nil, nil
 }
 
+func TestApplication_ConfigSourcesNotShared(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	require.NoError(t, err)
+	app1, err := New(Parameters{Factories: factories})
+	require.NoError(t, err)
+	app2, err := New(Parameters{Factories: factories})
+	require.NoError(t, err)
+	assert.NotSame(t, app1.configSources, app2.configSources)
+}
+
 func TestApplication_GetFactory(t *testing.T) {
 	// Create some factories.
 	exampleReceiverFactory := &componenttest.ExampleReceiverFactory{}